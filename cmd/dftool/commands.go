@@ -0,0 +1,305 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+)
+
+// DF is a shorter name for the dataframe type
+type DF = dataframe.DF
+
+// writers maps the names of the output formats to the functions which
+// write the dataframe in that format
+var writers = map[string]func(w io.Writer, df *DF, table string) error{
+	"aligned": func(w io.Writer, df *DF, _ string) error {
+		dfw, err := dataframe.NewDFWriter()
+		if err != nil {
+			return err
+		}
+		return dfw.WriteAligned(w, df)
+	},
+	"table": func(w io.Writer, df *DF, _ string) error {
+		dfw, err := dataframe.NewDFWriter()
+		if err != nil {
+			return err
+		}
+		return dfw.Write(w, df)
+	},
+	"csv": func(w io.Writer, df *DF, _ string) error {
+		return df.WriteCSV(w)
+	},
+	"json": func(w io.Writer, df *DF, _ string) error {
+		return df.WriteJSON(w, dataframe.OrientRecords)
+	},
+	"sql": func(w io.Writer, df *DF, table string) error {
+		return df.WriteSQLInsert(w, table)
+	},
+}
+
+// formatNames returns the names of the output formats
+func formatNames() string {
+	names := make([]string, 0, len(writers))
+	for name := range writers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// cmdFlags holds the flags common to all the commands
+type cmdFlags struct {
+	fs *flag.FlagSet
+
+	sep      string
+	noHeader bool
+	to       string
+	table    string
+}
+
+// newCmdFlags returns a cmdFlags with the flags for reading the file and
+// for writing the results in the given default format
+func newCmdFlags(name, defaultFormat string, s streams) *cmdFlags {
+	cf := &cmdFlags{fs: flag.NewFlagSet(name, flag.ContinueOnError)}
+	cf.fs.SetOutput(s.errOut)
+	cf.fs.StringVar(&cf.sep, "sep", "",
+		"the regular expression separating the columns")
+	cf.fs.BoolVar(&cf.noHeader, "no-header", false,
+		"the first line holds data rather than column names")
+	cf.fs.StringVar(&cf.to, "to", defaultFormat,
+		"the output format, one of: "+formatNames())
+	cf.fs.StringVar(&cf.table, "table", "df",
+		"the table name used for the sql output format")
+	return cf
+}
+
+// parse parses the arguments, returning errUsage if they are wrong (the
+// flag package will have reported the problem)
+func (cf *cmdFlags) parse(args []string) error {
+	err := cf.fs.Parse(args)
+	if err == flag.ErrHelp {
+		return err
+	}
+	if err != nil {
+		return errUsage
+	}
+
+	if _, ok := writers[cf.to]; !ok {
+		return fmt.Errorf("unknown output format: %q (expected one of: %s)",
+			cf.to, formatNames())
+	}
+	if cf.fs.NArg() > 1 {
+		return fmt.Errorf("too many files given: %q, at most one is allowed",
+			cf.fs.Args())
+	}
+	return nil
+}
+
+// read reads the file named in the arguments, or the standard input if no
+// file (or "-") is given, into a dataframe. Empty fields are read as NA.
+func (cf *cmdFlags) read(s streams) (*DF, error) {
+	filename := cf.fs.Arg(0)
+
+	opts := []dataframe.DFReaderOpt{
+		dataframe.DFREmptyFields(dataframe.EmptyAsNA),
+	}
+	if !cf.noHeader {
+		opts = append(opts, dataframe.HasHeader)
+	}
+	sep := cf.sep
+	if sep == "" && strings.HasSuffix(strings.ToLower(filename), ".csv") {
+		sep = ","
+	}
+	if sep != "" {
+		opts = append(opts, dataframe.SplitPattern(sep))
+	}
+
+	dfr, err := dataframe.NewDFReader(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if filename == "" || filename == "-" {
+		return dfr.Read(s.in, "standard input")
+	}
+	return dfr.ReadFile(filename)
+}
+
+// write writes the dataframe in the chosen output format
+func (cf *cmdFlags) write(s streams, df *DF) error {
+	return writers[cf.to](s.out, df, cf.table)
+}
+
+// cmdHead writes the first rows of the file
+func cmdHead(args []string, s streams) error {
+	cf := newCmdFlags("head", "aligned", s)
+	n := cf.fs.Int("n", 10, "the number of rows to show")
+	if err := cf.parse(args); err != nil {
+		return err
+	}
+	if *n < 0 {
+		return fmt.Errorf("the number of rows (%d) must not be negative", *n)
+	}
+
+	df, err := cf.read(s)
+	if err != nil {
+		return err
+	}
+
+	head := df.Clone()
+	if *n > 0 {
+		df.Chunks(*n)(func(c *DF) bool {
+			head = c
+			return false
+		})
+	}
+	return cf.write(s, head)
+}
+
+// colSummary holds the summary of a column written by describe
+type colSummary struct {
+	Name   string             `df:"column"`
+	Type   string             `df:"type"`
+	Count  int                `df:"count"`
+	NA     int                `df:"NA"`
+	Min    dataframe.FloatVal `df:"min"`
+	Max    dataframe.FloatVal `df:"max"`
+	Mean   dataframe.FloatVal `df:"mean"`
+	StdDev dataframe.FloatVal `df:"stddev"`
+}
+
+// emptySummary returns the summary of a column with no values
+func emptySummary(name string, ct dataframe.ColType) colSummary {
+	na := dataframe.FloatVal{IsNA: true}
+	return colSummary{
+		Name:   name,
+		Type:   ct.String(),
+		Min:    na,
+		Max:    na,
+		Mean:   na,
+		StdDev: na,
+	}
+}
+
+// summarise returns the summary of the column. The statistics are NA for
+// a column which is not numeric.
+func summarise(c dataframe.Column) colSummary {
+	name, ct := c.Info()
+	cs := emptySummary(name, ct)
+	cs.Count = c.RowCount()
+	cs.NA = c.NACount()
+
+	if ct != dataframe.ColTypeInt && ct != dataframe.ColTypeFloat {
+		return cs
+	}
+	cs.Min, _ = c.Min()
+	cs.Max, _ = c.Max()
+	cs.Mean, _ = c.Mean()
+	cs.StdDev, _ = c.StdDev()
+	return cs
+}
+
+// cmdDescribe writes the type and summary statistics of each column
+func cmdDescribe(args []string, s streams) error {
+	cf := newCmdFlags("describe", "aligned", s)
+	if err := cf.parse(args); err != nil {
+		return err
+	}
+
+	df, err := cf.read(s)
+	if err != nil {
+		return err
+	}
+
+	sums := make([]colSummary, 0, df.ColCount())
+	for i, ci := range df.Columns() {
+		if ci.ColType() == dataframe.ColTypeUnknown {
+			// a file with a header but no data gives columns with no type
+			sums = append(sums, emptySummary(ci.Name(), ci.ColType()))
+			continue
+		}
+		c, err := df.ColByIdx(i)
+		if err != nil {
+			return err
+		}
+		sums = append(sums, summarise(c))
+	}
+
+	desc, err := dataframe.FromStructs(sums)
+	if err != nil {
+		return err
+	}
+	return cf.write(s, desc)
+}
+
+// cmdConvert writes the file in the chosen format
+func cmdConvert(args []string, s streams) error {
+	cf := newCmdFlags("convert", "csv", s)
+	if err := cf.parse(args); err != nil {
+		return err
+	}
+
+	df, err := cf.read(s)
+	if err != nil {
+		return err
+	}
+	return cf.write(s, df)
+}
+
+// cmdFilter writes the rows of the file for which the expression is true
+func cmdFilter(args []string, s streams) error {
+	cf := newCmdFlags("filter", "aligned", s)
+	exprText := cf.fs.String("e", "",
+		"the expression selecting the rows, see dataframe.CompileExpr")
+	if err := cf.parse(args); err != nil {
+		return err
+	}
+	if *exprText == "" {
+		return errors.New("no filter expression has been given (use -e)")
+	}
+
+	e, err := dataframe.CompileExpr(*exprText)
+	if err != nil {
+		return err
+	}
+
+	df, err := cf.read(s)
+	if err != nil {
+		return err
+	}
+
+	rval, err := df.FilterExpr(e)
+	if err != nil {
+		return err
+	}
+	return cf.write(s, rval)
+}
+
+// cmdQuery runs the SQL query over the file and writes the results
+func cmdQuery(args []string, s streams) error {
+	cf := newCmdFlags("query", "aligned", s)
+	q := cf.fs.String("q", "",
+		"the query to run, the table name is ignored, see dataframe.Query")
+	if err := cf.parse(args); err != nil {
+		return err
+	}
+	if *q == "" {
+		return errors.New("no query has been given (use -q)")
+	}
+
+	df, err := cf.read(s)
+	if err != nil {
+		return err
+	}
+
+	rval, err := df.Query(*q)
+	if err != nil {
+		return err
+	}
+	return cf.write(s, rval)
+}
@@ -0,0 +1,131 @@
+/*
+dftool inspects, converts, filters and summarises tabular files using the
+dataframe package. It is run as
+
+	dftool <command> [flags] [file]
+
+where the command is one of:
+
+	head      show the first rows of the file
+	describe  show the type and summary statistics of each column
+	convert   write the file in another format (csv, json, sql, table)
+	filter    show the rows for which an expression is true
+	query     run a SQL query over the file
+
+The file is read from the standard input if it is not given or is "-";
+empty fields are read as NA values. Each command takes the following
+flags controlling how the file is read and the results written:
+
+	-sep pattern  the regular expression separating the columns; the
+	              default is "," for a file ending in .csv and white
+	              space otherwise
+	-no-header    the first line holds data rather than column names
+	-to format    the output format: aligned, csv, json, sql or table
+	-table name   the table name used for the sql format
+
+Run "dftool <command> -help" for the flags specific to a command.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// streams holds the standard input and output streams used by a command
+type streams struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+}
+
+// command describes one of the dftool commands
+type command struct {
+	desc string
+	run  func(args []string, s streams) error
+}
+
+var commands = map[string]command{
+	"head": {
+		desc: "show the first rows of the file",
+		run:  cmdHead,
+	},
+	"describe": {
+		desc: "show the type and summary statistics of each column",
+		run:  cmdDescribe,
+	},
+	"convert": {
+		desc: "write the file in another format",
+		run:  cmdConvert,
+	},
+	"filter": {
+		desc: "show the rows for which an expression is true",
+		run:  cmdFilter,
+	},
+	"query": {
+		desc: "run a SQL query over the file",
+		run:  cmdQuery,
+	},
+}
+
+// errUsage is returned when the command line is wrong and the usage
+// message has already been shown
+var errUsage = errors.New("bad usage")
+
+// usage writes the list of commands to the writer
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: dftool <command> [flags] [file]")
+	fmt.Fprintln(w, "\nThe commands are:")
+	for _, name := range names {
+		fmt.Fprintf(w, "\t%-10s%s\n", name, commands[name].desc)
+	}
+}
+
+// run runs the command given by the first argument
+func run(args []string, s streams) error {
+	if len(args) == 0 {
+		usage(s.errOut)
+		return errUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if strings.TrimLeft(args[0], "-") == "help" {
+			usage(s.out)
+			return nil
+		}
+		fmt.Fprintf(s.errOut, "dftool: unknown command: %q\n", args[0])
+		usage(s.errOut)
+		return errUsage
+	}
+
+	return cmd.run(args[1:], s)
+}
+
+func main() {
+	err := run(os.Args[1:], streams{
+		in:     os.Stdin,
+		out:    os.Stdout,
+		errOut: os.Stderr,
+	})
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		if err != errUsage {
+			fmt.Fprintln(os.Stderr, "dftool:", err)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestRun(t *testing.T) {
+	const input = `a,b,c
+x,1,2.5
+y,2,
+z,3,4
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		input  string
+		args   []string
+		expOut string
+	}{
+		{
+			ID:   testhelper.MkID("head"),
+			args: []string{"head", "-sep", ",", "-n", "2"},
+			expOut: "a  b  c\n" +
+				"x  1  2.5\n" +
+				"y  2  NA\n",
+		},
+		{
+			ID:   testhelper.MkID("describe"),
+			args: []string{"describe", "-sep", ",", "-to", "csv"},
+			expOut: "column,type,count,NA,min,max,mean,stddev\n" +
+				"a,String,3,0,,,,\n" +
+				"b,Int,3,0,1,3,2,1\n" +
+				"c,Float,3,1,2.5,4,3.25,1.0606601717798212\n",
+		},
+		{
+			ID:   testhelper.MkID("convert"),
+			args: []string{"convert", "-sep", ",", "-to", "json", "-"},
+			expOut: `[{"a":"x","b":1,"c":2.5},{"a":"y","b":2,"c":null},` +
+				`{"a":"z","b":3,"c":4}]` + "\n",
+		},
+		{
+			ID: testhelper.MkID("filter"),
+			args: []string{
+				"filter", "-sep", ",", "-to", "csv", "-e", "b > 1",
+			},
+			expOut: "a,b,c\ny,2,\nz,3,4\n",
+		},
+		{
+			ID: testhelper.MkID("query"),
+			args: []string{
+				"query", "-sep", ",", "-q", "SELECT a FROM t WHERE c < 3",
+			},
+			expOut: "a\nx\n",
+		},
+		{
+			ID:     testhelper.MkID("head, no data"),
+			input:  "a,b\n",
+			args:   []string{"head", "-sep", ","},
+			expOut: "a  b\n",
+		},
+		{
+			ID:    testhelper.MkID("describe, no data"),
+			input: "a,b\n",
+			args:  []string{"describe", "-sep", ",", "-to", "csv"},
+			expOut: "column,type,count,NA,min,max,mean,stddev\n" +
+				"a,Unknown,0,0,,,,\n" +
+				"b,Unknown,0,0,,,,\n",
+		},
+		{
+			ID:     testhelper.MkID("convert, no data"),
+			input:  "a,b\n",
+			args:   []string{"convert", "-sep", ",", "-to", "json"},
+			expOut: "[]\n",
+		},
+		{
+			ID:    testhelper.MkID("query, no data"),
+			input: "a,b\n",
+			args: []string{
+				"query", "-sep", ",", "-q", "SELECT a FROM t",
+			},
+			expOut: "a\n",
+		},
+		{
+			ID:    testhelper.MkID("bad: filter, no data"),
+			input: "a,b\n",
+			args:  []string{"filter", "-sep", ",", "-e", "b > 1"},
+			ExpErr: testhelper.MkExpErr(
+				`the column named "b" has no type (there is no data)`),
+		},
+		{
+			ID:     testhelper.MkID("bad: unknown command"),
+			args:   []string{"nonesuch"},
+			ExpErr: testhelper.MkExpErr("bad usage"),
+		},
+		{
+			ID:     testhelper.MkID("bad: unknown format"),
+			args:   []string{"convert", "-to", "xml"},
+			ExpErr: testhelper.MkExpErr(`unknown output format: "xml"`),
+		},
+		{
+			ID:     testhelper.MkID("bad: no expression"),
+			args:   []string{"filter", "-sep", ","},
+			ExpErr: testhelper.MkExpErr("no filter expression has been given"),
+		},
+		{
+			ID:     testhelper.MkID("bad: expression"),
+			args:   []string{"filter", "-sep", ",", "-e", "d > 1"},
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "d"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		in := input
+		if tc.input != "" {
+			in = tc.input
+		}
+		var out, errOut bytes.Buffer
+		err := run(tc.args, streams{
+			in:     strings.NewReader(in),
+			out:    &out,
+			errOut: &errOut,
+		})
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output",
+				out.String(), tc.expOut)
+		}
+	}
+}
@@ -0,0 +1,616 @@
+package dataframe
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+// Values from the Arrow format specification (Schema.fbs and Message.fbs)
+const (
+	arrowMagic        = "ARROW1"
+	arrowContinuation = 0xFFFFFFFF
+
+	arrowMetadataV5 = 4
+
+	arrowHdrSchema      = 1
+	arrowHdrRecordBatch = 3
+
+	arrowTypeInt       = 2
+	arrowTypeFloat     = 3
+	arrowTypeUtf8      = 5
+	arrowTypeBool      = 6
+	arrowTypeLargeUtf8 = 20
+
+	arrowPrecSingle = 1
+	arrowPrecDouble = 2
+)
+
+// arrowTypeTable returns the Arrow type union type and table for the
+// column type
+func arrowTypeTable(ct ColType) (uint64, fbTable) {
+	switch ct {
+	case ColTypeBool:
+		return arrowTypeBool, fbTable{}
+	case ColTypeInt:
+		return arrowTypeInt, fbTable{
+			fbScalar{size: 4, v: 64}, // bitWidth
+			fbScalar{size: 1, v: 1},  // is_signed
+		}
+	case ColTypeFloat:
+		return arrowTypeFloat, fbTable{
+			fbScalar{size: 2, v: arrowPrecDouble}, // precision
+		}
+	case ColTypeString:
+		return arrowTypeUtf8, fbTable{}
+	}
+	panic(dfErrorf("Unexpected column type: %q", ct))
+}
+
+// arrowSchema returns the Arrow Schema table for the dataframe. The
+// dataframe metadata is written as the schema's custom metadata.
+func (df *DF) arrowSchema() fbTable {
+	fields := make([]fbTable, 0, len(df.mci.info))
+	for _, ci := range df.mci.info {
+		tt, t := arrowTypeTable(ci.colType)
+		fields = append(fields, fbTable{
+			ci.name,                  // name
+			fbScalar{size: 1, v: 1},  // nullable
+			fbScalar{size: 1, v: tt}, // type_type
+			t,                        // type
+			nil,                      // dictionary
+			[]fbTable{},              // children
+		})
+	}
+
+	schema := fbTable{
+		fbScalar{size: 2, v: 0}, // endianness: Little
+		fields,                  // fields
+	}
+	if len(df.meta) > 0 {
+		kvs := make([]fbTable, 0, len(df.meta))
+		for _, k := range df.MetaKeys() {
+			kvs = append(kvs, fbTable{k, df.meta[k]})
+		}
+		schema = append(schema, kvs) // custom_metadata
+	}
+	return schema
+}
+
+// arrowMessage returns the flatbuffer for an Arrow Message with the given
+// header, padded so that the encapsulated message is 8-byte aligned
+func arrowMessage(hdrType uint64, hdr fbTable, bodyLen int) []byte {
+	var b fbBuilder
+	fb := b.finish(fbTable{
+		fbScalar{size: 2, v: arrowMetadataV5}, // version
+		fbScalar{size: 1, v: hdrType},         // header_type
+		hdr,                                   // header
+		fbScalar{size: 8, v: uint64(bodyLen)}, // bodyLength
+	})
+	for len(fb)%8 != 0 {
+		fb = append(fb, 0)
+	}
+	return fb
+}
+
+// appendU64 appends the little-endian encoding of v to b
+func appendU64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// appendU32 appends the little-endian encoding of v to b
+func appendU32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// arrowBody accumulates the buffers of a record batch
+type arrowBody struct {
+	data    []byte
+	buffers []byte // the Buffer structs
+}
+
+// add appends the buffer to the body, padded to a multiple of 8 bytes
+func (ab *arrowBody) add(buf []byte) {
+	ab.buffers = appendU64(ab.buffers, uint64(len(ab.data)))
+	ab.buffers = appendU64(ab.buffers, uint64(len(buf)))
+	ab.data = append(ab.data, buf...)
+	for len(ab.data)%8 != 0 {
+		ab.data = append(ab.data, 0)
+	}
+}
+
+// bitmap is a bit-packed slice of bools, least significant bit first, as
+// used by Arrow for validity and bool values
+type bitmap []byte
+
+// set sets the i'th bit
+func (bm bitmap) set(i int) { bm[i/8] |= 1 << (i % 8) }
+
+// isSet returns true if the i'th bit is set
+func (bm bitmap) isSet(i int) bool { return bm[i/8]&(1<<(i%8)) != 0 }
+
+// arrowCol adds the buffers for the indexed column to the body and returns
+// the null count
+func (df *DF) arrowCol(ab *arrowBody, colIdx int) int {
+	rows := df.RowCount()
+	valid := make(bitmap, (rows+7)/8)
+	nulls := 0
+	for r := 0; r < rows; r++ {
+		if _, isNA := df.valAt(colIdx, r); isNA {
+			nulls++
+		} else {
+			valid.set(r)
+		}
+	}
+	if nulls == 0 {
+		valid = nil
+	}
+	ab.add(valid)
+
+	vi := df.mci.valIdx[colIdx]
+	switch ct := df.mci.info[colIdx].colType; ct {
+	case ColTypeBool:
+		vals := make(bitmap, (rows+7)/8)
+		for r := 0; r < rows; r++ {
+			if v := df.boolAt(vi, r); v.Val && !v.IsNA {
+				vals.set(r)
+			}
+		}
+		ab.add(vals)
+	case ColTypeInt:
+		vals := make([]byte, 0, 8*rows)
+		for _, v := range df.intCols[vi] {
+			vals = appendU64(vals, uint64(v.Val))
+		}
+		ab.add(vals)
+	case ColTypeFloat:
+		vals := make([]byte, 0, 8*rows)
+		for _, v := range df.floatCols[vi] {
+			vals = appendU64(vals, math.Float64bits(v.Val))
+		}
+		ab.add(vals)
+	case ColTypeString:
+		offsets := make([]byte, 0, 4*(rows+1))
+		var vals []byte
+		offsets = appendU32(offsets, 0)
+		for r := 0; r < rows; r++ {
+			if v := df.stringAt(vi, r); !v.IsNA {
+				vals = append(vals, v.Val...)
+			}
+			offsets = appendU32(offsets, uint32(len(vals)))
+		}
+		ab.add(offsets)
+		ab.add(vals)
+	default:
+		panic(dfErrorf("Unexpected column type: %q", ct))
+	}
+	return nulls
+}
+
+// arrowBlock returns the Arrow Block struct for a message written at the
+// offset
+func arrowBlock(offset, metaLen, bodyLen int) []byte {
+	b := appendU64(nil, uint64(offset))
+	b = appendU32(b, uint32(metaLen))
+	b = append(b, 0, 0, 0, 0)
+	return appendU64(b, uint64(bodyLen))
+}
+
+// ipcWriter writes the parts of an Arrow IPC file, keeping track of the
+// offset and the first error
+type ipcWriter struct {
+	bw     *bufio.Writer
+	offset int
+	err    error
+}
+
+// write writes the bytes unless there has already been an error
+func (iw *ipcWriter) write(b []byte) {
+	if iw.err != nil {
+		return
+	}
+	_, iw.err = iw.bw.Write(b)
+	iw.offset += len(b)
+}
+
+// message writes an encapsulated message and returns its Block struct
+func (iw *ipcWriter) message(meta, body []byte) []byte {
+	block := arrowBlock(iw.offset, 8+len(meta), len(body))
+	prefix := appendU32(nil, arrowContinuation)
+	prefix = appendU32(prefix, uint32(len(meta)))
+	iw.write(prefix)
+	iw.write(meta)
+	iw.write(body)
+	return block
+}
+
+// WriteIPC writes the dataframe to the io.Writer in the Arrow IPC file
+// format (also known as Feather version 2) so that it can be read by other
+// Arrow implementations such as pyarrow or the R arrow package. Bool
+// columns are written as Arrow Bool, int columns as Int64, float columns
+// as Float64 and string columns as Utf8; NA values are written as nulls.
+// The dataframe metadata (see SetMeta) is written as the schema's custom
+// metadata. All the rows are written in a single record batch and the
+// buffers are not compressed. It returns an error if any column has no
+// type, as for a dataframe read from input with a header but no data.
+func (df *DF) WriteIPC(w io.Writer) error {
+	for _, ci := range df.mci.info {
+		if ci.colType == ColTypeUnknown {
+			return dfWrapf(errUntypedCol(ci.name),
+				"cannot write the dataframe as Arrow IPC")
+		}
+	}
+
+	schema := df.arrowSchema()
+
+	var ab arrowBody
+	var nodes []byte
+	for c := range df.mci.info {
+		nulls := df.arrowCol(&ab, c)
+		nodes = appendU64(nodes, uint64(df.RowCount()))
+		nodes = appendU64(nodes, uint64(nulls))
+	}
+	batch := fbTable{
+		fbScalar{size: 8, v: uint64(df.RowCount())},          // length
+		fbStructs{n: len(df.mci.info), data: nodes},          // nodes
+		fbStructs{n: len(ab.buffers) / 16, data: ab.buffers}, // buffers
+	}
+
+	iw := &ipcWriter{bw: bufio.NewWriter(w)}
+	iw.write([]byte(arrowMagic + "\x00\x00"))
+	iw.message(arrowMessage(arrowHdrSchema, schema, 0), nil)
+	batchBlock := iw.message(
+		arrowMessage(arrowHdrRecordBatch, batch, len(ab.data)), ab.data)
+	iw.write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}) // end of stream
+
+	var b fbBuilder
+	footer := b.finish(fbTable{
+		fbScalar{size: 2, v: arrowMetadataV5}, // version
+		schema,                                // schema
+		fbStructs{},                           // dictionaries
+		fbStructs{n: 1, data: batchBlock},     // recordBatches
+	})
+	iw.write(footer)
+	iw.write(appendU32(nil, uint32(len(footer))))
+	iw.write([]byte(arrowMagic))
+
+	if iw.err == nil {
+		iw.err = iw.bw.Flush()
+	}
+	if iw.err != nil {
+		return dfWrapf(iw.err, "cannot write the dataframe as Arrow IPC")
+	}
+	return nil
+}
+
+// arrowField records the details of a column read from an Arrow schema
+type arrowField struct {
+	ci        ColInfo
+	typeType  uint64
+	bitWidth  int
+	signed    bool
+	precision int
+}
+
+// buffers returns the number of buffers the field has in a record batch
+func (af arrowField) buffers() int {
+	if af.ci.colType == ColTypeString {
+		return 3
+	}
+	return 2
+}
+
+// arrowFieldFromTable returns the details of the Arrow field or an error
+// if its type is not supported
+func arrowFieldFromTable(f fbTab) (arrowField, error) {
+	af := arrowField{typeType: f.scalar(2, 1, 0)}
+	af.ci.name = f.str(0)
+
+	if _, ok := f.table(4); ok {
+		return af, dfErrorf("column %q: dictionary encoding is not supported",
+			af.ci.name)
+	}
+	t, ok := f.table(3)
+	if !ok {
+		return af, dfErrorf("column %q: no type is given", af.ci.name)
+	}
+
+	switch af.typeType {
+	case arrowTypeBool:
+		af.ci.colType = ColTypeBool
+	case arrowTypeInt:
+		af.ci.colType = ColTypeInt
+		af.bitWidth = int(t.scalar(0, 4, 0))
+		af.signed = t.scalar(1, 1, 0) != 0
+		switch af.bitWidth {
+		case 8, 16, 32, 64:
+		default:
+			return af, dfErrorf("column %q: bad int bit width: %d",
+				af.ci.name, af.bitWidth)
+		}
+	case arrowTypeFloat:
+		af.ci.colType = ColTypeFloat
+		af.precision = int(t.scalar(0, 2, 0))
+		if af.precision != arrowPrecSingle && af.precision != arrowPrecDouble {
+			return af, dfErrorf("column %q: unsupported float precision: %d",
+				af.ci.name, af.precision)
+		}
+	case arrowTypeUtf8, arrowTypeLargeUtf8:
+		af.ci.colType = ColTypeString
+	default:
+		return af, dfErrorf("column %q: unsupported Arrow type: %d",
+			af.ci.name, af.typeType)
+	}
+	return af, nil
+}
+
+// arrowBatch holds the parts of a record batch being read
+type arrowBatch struct {
+	r       fbReader
+	rows    int
+	nodes   int // the position of the first FieldNode
+	nNodes  int
+	buffers int // the position of the first Buffer
+	nBufs   int
+	body    []byte
+}
+
+// buffer returns the i'th buffer of the record batch
+func (ab arrowBatch) buffer(i int) []byte {
+	if i >= ab.nBufs {
+		panic(fbError{msg: "too few buffers in the record batch"})
+	}
+	off := int(ab.r.uint(ab.buffers+16*i, 8))
+	n := int(ab.r.uint(ab.buffers+16*i+8, 8))
+	if off < 0 || n < 0 || off > len(ab.body) || n > len(ab.body)-off {
+		panic(fbError{msg: "a buffer is outside the record batch body"})
+	}
+	return ab.body[off : off+n]
+}
+
+// need panics with an fbError if the buffer is shorter than n bytes
+func need(buf []byte, n int) {
+	if len(buf) < n {
+		panic(fbError{msg: "a buffer is too short"})
+	}
+}
+
+// readArrowCol appends the values of the column from the record batch to the
+// dataframe. The column uses the buffers starting at bufIdx.
+func (df *DF) readArrowCol(ab arrowBatch, af arrowField, colIdx, bufIdx int) {
+	n := ab.rows
+	valid := bitmap(ab.buffer(bufIdx))
+	nulls := int(ab.r.uint(ab.nodes+16*colIdx+8, 8))
+	if nulls == 0 {
+		valid = nil
+	} else {
+		need(valid, (n+7)/8)
+	}
+	isNA := func(r int) bool { return valid != nil && !valid.isSet(r) }
+
+	data := ab.buffer(bufIdx + 1)
+	vi := df.mci.valIdx[colIdx]
+
+	switch af.ci.colType {
+	case ColTypeBool:
+		need(data, (n+7)/8)
+		for r := 0; r < n; r++ {
+			df.boolCols[vi] = append(df.boolCols[vi],
+				BoolVal{Val: bitmap(data).isSet(r), IsNA: isNA(r)})
+		}
+	case ColTypeInt:
+		width := af.bitWidth / 8
+		need(data, n*width)
+		for r := 0; r < n; r++ {
+			u := fbReader{buf: data}.uint(r*width, width)
+			v := int64(u)
+			if af.signed {
+				shift := 64 - af.bitWidth
+				v = int64(u<<shift) >> shift
+			} else if v < 0 {
+				panic(fbError{msg: "an unsigned value is too large"})
+			}
+			df.intCols[vi] = append(df.intCols[vi],
+				IntVal{Val: v, IsNA: isNA(r)})
+		}
+	case ColTypeFloat:
+		if af.precision == arrowPrecSingle {
+			need(data, n*4)
+		} else {
+			need(data, n*8)
+		}
+		for r := 0; r < n; r++ {
+			var v float64
+			if af.precision == arrowPrecSingle {
+				v = float64(math.Float32frombits(
+					binary.LittleEndian.Uint32(data[4*r:])))
+			} else {
+				v = math.Float64frombits(binary.LittleEndian.Uint64(data[8*r:]))
+			}
+			df.floatCols[vi] = append(df.floatCols[vi],
+				FloatVal{Val: v, IsNA: isNA(r)})
+		}
+	case ColTypeString:
+		width := 4
+		if af.typeType == arrowTypeLargeUtf8 {
+			width = 8
+		}
+		need(data, (n+1)*width)
+		strs := ab.buffer(bufIdx + 2)
+		offsets := fbReader{buf: data}
+		for r := 0; r < n; r++ {
+			start := int(offsets.uint(r*width, width))
+			end := int(offsets.uint((r+1)*width, width))
+			if start < 0 || end < start || end > len(strs) {
+				panic(fbError{msg: "a string offset is out of range"})
+			}
+			s := string(strs[start:end])
+			if !utf8.ValidString(s) {
+				panic(fbError{msg: "a string is not valid UTF-8"})
+			}
+			if isNA(r) {
+				s = ""
+			}
+			df.stringCols[vi] = append(df.stringCols[vi],
+				StringVal{Val: s, IsNA: isNA(r)})
+		}
+	}
+}
+
+// readArrowBatch reads the record batch described by the Block struct at
+// the given position in the footer and appends its rows to the dataframe
+func (df *DF) readArrowBatch(buf []byte, footer fbReader, blockPos int,
+	fields []arrowField,
+) error {
+	offset := int(footer.uint(blockPos, 8))
+	metaLen := int(int32(footer.uint(blockPos+8, 4)))
+	bodyLen := int(footer.uint(blockPos+16, 8))
+
+	file := fbReader{buf: buf}
+	if metaLen < 8 {
+		return dfErrorf("bad message metadata length: %d", metaLen)
+	}
+	file.check(offset, metaLen)
+	fbStart := offset + 4
+	if file.uint(offset, 4) == arrowContinuation {
+		fbStart = offset + 8
+	}
+	msg := fbReader{buf: buf[fbStart : offset+metaLen]}
+	file.check(offset+metaLen, bodyLen)
+
+	m := msg.root()
+	if ht := m.scalar(1, 1, 0); ht != arrowHdrRecordBatch {
+		return dfErrorf("unexpected message type: %d", ht)
+	}
+	rb, ok := m.table(2)
+	if !ok {
+		return dfErrorf("the record batch message has no header")
+	}
+	if _, ok := rb.table(3); ok {
+		return dfErrorf("compressed record batches are not supported")
+	}
+
+	ab := arrowBatch{
+		r:    msg,
+		rows: int(rb.scalar(0, 8, 0)),
+		body: buf[offset+metaLen : offset+metaLen+bodyLen],
+	}
+	ab.nodes, ab.nNodes = rb.vec(1, 16)
+	ab.buffers, ab.nBufs = rb.vec(2, 16)
+	if ab.rows < 0 || ab.rows > 8*len(ab.body) {
+		return dfErrorf("bad record batch length: %d", ab.rows)
+	}
+	if ab.nNodes != len(fields) {
+		return dfErrorf("the record batch has %d columns, expected %d",
+			ab.nNodes, len(fields))
+	}
+
+	bufIdx := 0
+	for c, af := range fields {
+		if int(msg.uint(ab.nodes+16*c, 8)) != ab.rows {
+			return dfErrorf("column %q has the wrong length", af.ci.name)
+		}
+		df.readArrowCol(ab, af, c, bufIdx)
+		bufIdx += af.buffers()
+	}
+	return nil
+}
+
+// readIPC reads the Arrow IPC file held in the buffer
+func readIPC(buf []byte) (*DF, error) {
+	const magicLen = len(arrowMagic)
+	if len(buf) < 2*magicLen+6 ||
+		string(buf[:magicLen]) != arrowMagic ||
+		string(buf[len(buf)-magicLen:]) != arrowMagic {
+		return nil, dfErrorf("not an Arrow IPC file")
+	}
+	footerLen := int(int32(binary.LittleEndian.Uint32(
+		buf[len(buf)-magicLen-4:])))
+	footerEnd := len(buf) - magicLen - 4
+	if footerLen <= 0 || footerLen > footerEnd-magicLen {
+		return nil, dfErrorf("bad footer length: %d", footerLen)
+	}
+	footer := fbReader{buf: buf[footerEnd-footerLen : footerEnd]}
+
+	f := footer.root()
+	schema, ok := f.table(1)
+	if !ok {
+		return nil, dfErrorf("the footer has no schema")
+	}
+	if schema.scalar(0, 2, 0) != 0 {
+		return nil, dfErrorf("big-endian data is not supported")
+	}
+
+	fieldsPos, nFields := schema.vec(1, 4)
+	fields := make([]arrowField, 0, nFields)
+	cis := make([]ColInfo, 0, nFields)
+	for i := 0; i < nFields; i++ {
+		af, err := arrowFieldFromTable(footer.vecTable(fieldsPos, i))
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, af)
+		cis = append(cis, af.ci)
+	}
+
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	kvPos, nKV := schema.vec(2, 4)
+	for i := 0; i < nKV; i++ {
+		kv := footer.vecTable(kvPos, i)
+		if err := df.SetMeta(kv.str(0), kv.str(1)); err != nil {
+			return nil, err
+		}
+	}
+
+	batchesPos, nBatches := f.vec(3, 24)
+	for i := 0; i < nBatches; i++ {
+		err := df.readArrowBatch(buf, footer, batchesPos+24*i, fields)
+		if err != nil {
+			return nil, dfWrapf(err, "record batch %d", i)
+		}
+	}
+	return df, nil
+}
+
+// ReadIPC reads a dataframe from data in the Arrow IPC file format (also
+// known as Feather version 2), such as that written by WriteIPC or by other
+// Arrow implementations. Arrow Bool columns are read as bool columns; Int8
+// to Int64 and UInt8 to UInt64 as int columns (it is an error if an
+// unsigned value is too large); Float32 and Float64 as float columns and
+// Utf8 and LargeUtf8 as string columns. Null values are read as NA. Any
+// other types, dictionary-encoded columns and compressed record batches
+// are not supported and give an error. The schema's custom metadata is
+// read as the dataframe metadata. The whole of the data is read into
+// memory before it is decoded.
+func ReadIPC(r io.Reader) (df *DF, err error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the Arrow IPC data")
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			fbErr, ok := p.(fbError)
+			if !ok {
+				panic(p)
+			}
+			df, err = nil, dfErrorf("corrupt Arrow IPC data: %s", fbErr.msg)
+		}
+	}()
+
+	df, err = readIPC(buf)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the Arrow IPC data")
+	}
+	return df, nil
+}
@@ -0,0 +1,127 @@
+package dataframe_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestIPCRoundTrip(t *testing.T) {
+	bTrue, i, f := true, int64(-42), 1.0/3
+	strs := []string{"", "NA", "a b", "ünïcödé", "line1\nline2"}
+
+	recs := []roundTripRec{
+		{B: &bTrue, I: &i, F: &f, S: &strs[0], S2: strs[1]},
+		{S: &strs[1], S2: strs[0]},
+	}
+	for _, s := range strs[2:] {
+		s := s
+		recs = append(recs, roundTripRec{B: &bTrue, S: &s, S2: s})
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		df func() (*dataframe.DF, error)
+	}{
+		{
+			ID: testhelper.MkID("all types, NA values"),
+			df: func() (*dataframe.DF, error) {
+				return dataframe.FromStructs(recs)
+			},
+		},
+		{
+			ID: testhelper.MkID("compressed columns, metadata"),
+			df: func() (*dataframe.DF, error) {
+				df, err := dataframe.FromStructs(recs)
+				if err != nil {
+					return nil, err
+				}
+				if err := df.Compress("a bool", "str"); err != nil {
+					return nil, err
+				}
+				return df, df.SetMeta("source", "test")
+			},
+		},
+		{
+			ID: testhelper.MkID("no rows"),
+			df: func() (*dataframe.DF, error) {
+				return dataframe.FromStructs([]roundTripRec{})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := tc.df()
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+		}
+
+		var buf bytes.Buffer
+		if err := df.WriteIPC(&buf); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot write the dataframe: %s", err)
+			continue
+		}
+
+		rtDF, err := dataframe.ReadIPC(&buf)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot read the dataframe back: %s", err)
+			continue
+		}
+
+		if err := df.Equal(rtDF); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the dataframe read back differs: %s", err)
+		}
+		testhelper.DiffStringSlice(t, tc.IDStr(), "meta keys",
+			rtDF.MetaKeys(), df.MetaKeys())
+	}
+	var buf bytes.Buffer
+	err := mkTestDF(t, "a b\n", dataframe.HasHeader).WriteIPC(&buf)
+	testhelper.CheckExpErrWithID(t, "header only", err,
+		testhelper.MkExpErr("cannot write the dataframe as Arrow IPC",
+			`the column named "a" has no type`))
+	testhelper.DiffInt(t, "header only", "bytes written", buf.Len(), 0)
+}
+
+func TestReadIPCErrors(t *testing.T) {
+	df, err := dataframe.NewDFFromCols(mkIntCol("i", 1, 2, 3))
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+	}
+	var buf bytes.Buffer
+	if err := df.WriteIPC(&buf); err != nil {
+		t.Fatal("BAD TEST - cannot write the dataframe: ", err)
+	}
+	good := buf.Bytes()
+
+	// corrupt the footer length so that it points to the wrong place
+	badFooter := append([]byte{}, good...)
+	badFooter[len(badFooter)-10] -= 8
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data string
+	}{
+		{
+			ID:     testhelper.MkID("not Arrow"),
+			ExpErr: testhelper.MkExpErr("not an Arrow IPC file"),
+			data:   "i\n1\n2\n3\n",
+		},
+		{
+			ID:     testhelper.MkID("bad footer"),
+			ExpErr: testhelper.MkExpErr("corrupt Arrow IPC data"),
+			data:   string(badFooter),
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := dataframe.ReadIPC(strings.NewReader(tc.data))
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
@@ -0,0 +1,87 @@
+package dataframe
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// errNotBoolToken is the error reported when a value is not one of the
+// bool tokens
+var errNotBoolToken = errors.New("not one of the bool tokens")
+
+// BoolFormat gives the values which are accepted as true and false when
+// parsing bool values. The values are matched ignoring case. The zero
+// value accepts the values allowed by strconv.ParseBool ("true", "false",
+// "1", "0", "T", "F" and so on).
+type BoolFormat struct {
+	TrueVals  []string
+	FalseVals []string
+}
+
+// ParseBool parses the string as a bool according to the format
+func (bf BoolFormat) ParseBool(s string) (bool, error) {
+	if len(bf.TrueVals) == 0 && len(bf.FalseVals) == 0 {
+		return strconv.ParseBool(s)
+	}
+
+	for _, tv := range bf.TrueVals {
+		if strings.EqualFold(s, tv) {
+			return true, nil
+		}
+	}
+	for _, fv := range bf.FalseVals {
+		if strings.EqualFold(s, fv) {
+			return false, nil
+		}
+	}
+	return false,
+		&strconv.NumError{Func: "ParseBool", Num: s, Err: errNotBoolToken}
+}
+
+// BoolTokens returns a function which will cause the DFReader to accept
+// the given values, and only those values, as true and false when guessing
+// the column types and when parsing bool values, for instance:
+//
+//	BoolTokens([]string{"yes", "y"}, []string{"no", "n"})
+//
+// The values are matched ignoring case. Note that, with the default
+// values, columns holding only 0 and 1 are read as bool columns; if the
+// tokens are given they will be read as int columns unless "0" and "1"
+// are among the tokens.
+//
+// It is an error if either list is empty, if a value is empty or if a
+// value is in both lists.
+func BoolTokens(trueVals, falseVals []string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if len(trueVals) == 0 || len(falseVals) == 0 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"both true and false bool tokens must be given")
+		}
+		for _, tv := range trueVals {
+			if tv == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"a bool token must not be empty")
+			}
+			for _, fv := range falseVals {
+				if strings.EqualFold(tv, fv) {
+					return dfCodeErrorf(ErrCodeBadOption,
+						"%q is both a true and a false bool token",
+						tv)
+				}
+			}
+		}
+		for _, fv := range falseVals {
+			if fv == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"a bool token must not be empty")
+			}
+		}
+
+		dfr.parseOpts.boolFmt = BoolFormat{
+			TrueVals:  append([]string(nil), trueVals...),
+			FalseVals: append([]string(nil), falseVals...),
+		}
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestBoolFormatParseBool(t *testing.T) {
+	yesNo := dataframe.BoolFormat{
+		TrueVals:  []string{"yes", "y"},
+		FalseVals: []string{"no", "n"},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		bf     dataframe.BoolFormat
+		s      string
+		expVal bool
+	}{
+		{ID: testhelper.MkID("default: true"), s: "true", expVal: true},
+		{ID: testhelper.MkID("default: 0"), s: "0", expVal: false},
+		{
+			ID:     testhelper.MkID("default: yes"),
+			ExpErr: testhelper.MkExpErr(`parsing "yes": invalid syntax`),
+			s:      "yes",
+		},
+		{ID: testhelper.MkID("tokens: yes"), bf: yesNo, s: "yes", expVal: true},
+		{ID: testhelper.MkID("tokens: Y"), bf: yesNo, s: "Y", expVal: true},
+		{ID: testhelper.MkID("tokens: NO"), bf: yesNo, s: "NO", expVal: false},
+		{
+			ID: testhelper.MkID("tokens: true"),
+			ExpErr: testhelper.MkExpErr(
+				`parsing "true": not one of the bool tokens`),
+			bf: yesNo,
+			s:  "true",
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.bf.ParseBool(tc.s)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffBool(t, tc.IDStr(), "value", v, tc.expVal)
+		}
+	}
+}
+
+func TestBoolTokens(t *testing.T) {
+	const content = `name  member flag
+alice Yes    1
+bob   no     0
+carol Y      1
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		trueVals  []string
+		falseVals []string
+		expCols   []dataframe.ColInfo
+		expVals   [][]string
+	}{
+		{
+			ID:        testhelper.MkID("yes/no"),
+			trueVals:  []string{"yes", "y"},
+			falseVals: []string{"no", "n"},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("member", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("flag", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"alice", "true", "1"},
+				{"bob", "false", "0"},
+				{"carol", "true", "1"},
+			},
+		},
+		{
+			ID:        testhelper.MkID("incomplete tokens"),
+			trueVals:  []string{"yes"},
+			falseVals: []string{"no"},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("member", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("flag", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"alice", "Yes", "1"},
+				{"bob", "no", "0"},
+				{"carol", "Y", "1"},
+			},
+		},
+		{
+			ID: testhelper.MkID("no false tokens"),
+			ExpErr: testhelper.MkExpErr(
+				"both true and false bool tokens must be given"),
+			trueVals: []string{"yes"},
+		},
+		{
+			ID:        testhelper.MkID("empty token"),
+			ExpErr:    testhelper.MkExpErr("a bool token must not be empty"),
+			trueVals:  []string{"yes"},
+			falseVals: []string{"no", ""},
+		},
+		{
+			ID: testhelper.MkID("token in both lists"),
+			ExpErr: testhelper.MkExpErr(
+				`"Y" is both a true and a false bool token`),
+			trueVals:  []string{"Y"},
+			falseVals: []string{"n", "y"},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(dataframe.HasHeader,
+			dataframe.BoolTokens(tc.trueVals, tc.falseVals))
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+		df, err := dfr.Read(strings.NewReader(content), "test data")
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+		checkColDetails(t, tc.IDStr(), df, tc.expCols)
+		checkDFVals(t, tc.IDStr(), df, tc.expVals)
+	}
+}
@@ -0,0 +1,62 @@
+package dataframe
+
+import "strings"
+
+// SetCaseInsensitiveNames sets whether or not column names given to the
+// dataframe's methods, such as ColByName, ColInfoByName, Sort, GroupBy and
+// BuildIndex, and to the methods of its Rows are matched ignoring case.
+// An exact match is always preferred. The setting is kept by dataframes
+// made with Clone and so by Filter, Where and similar methods. Column
+// names in the dataframe which differ only in case would make the lookup
+// ambiguous so, if there are any, an error is returned and the setting is
+// not changed. Once it is set, such names cannot be added.
+func (df *DF) SetCaseInsensitiveNames(on bool) error {
+	if on {
+		names := make([]string, 0, len(df.mci.info))
+		for _, ci := range df.mci.info {
+			names = append(names, ci.name)
+		}
+		if i, j, clash := foldCaseClash(names); clash {
+			return dfErrorf("cannot match names ignoring case:"+
+				" %s and %s differ only in case",
+				df.mci.ColDesc(i), df.mci.ColDesc(j))
+		}
+	}
+	df.mci.foldCase = on
+	return nil
+}
+
+// CaseInsensitiveNames is a DFOpt which will cause the column names to be
+// matched ignoring case; see SetCaseInsensitiveNames
+func CaseInsensitiveNames(df *DF) error {
+	return df.SetCaseInsensitiveNames(true)
+}
+
+// DFRCaseInsensitiveNames will cause the dataframe to be read with the
+// column names matched ignoring case (see SetCaseInsensitiveNames). This
+// also applies to the names given to other options, such as DFRRequireCols,
+// DFRColMeta, DFRPercentages or DFRCurrency, and to the names in any
+// schema. It is an error if two columns in the data read have names which
+// differ only in case.
+func DFRCaseInsensitiveNames(dfr *DFReader) error {
+	dfr.parseOpts.foldCase = true
+	return nil
+}
+
+// lookupName returns the value in the map for the column name and true if
+// there is one. An exact match is preferred but if foldCase is set a key
+// which differs only in case will be found.
+func lookupName[V any](m map[string]V, name string, foldCase bool) (V, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	if foldCase {
+		for k, v := range m {
+			if strings.EqualFold(k, name) {
+				return v, true
+			}
+		}
+	}
+	var zero V
+	return zero, false
+}
@@ -0,0 +1,159 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSetCaseInsensitiveNames(t *testing.T) {
+	df := mkTestDF(t, "Name Price\nabc 1.5\nxyz 2.5\n", dataframe.HasHeader)
+
+	_, err := df.ColInfoByName("price")
+	testhelper.CheckExpErrWithID(t, "case sensitive", err,
+		testhelper.MkExpErr(`Unknown column name: "price"`))
+
+	if err := df.SetCaseInsensitiveNames(true); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	id := "ColInfoByName"
+	ci, err := df.ColInfoByName("PRICE")
+	testhelper.CheckExpErrWithID(t, id, err, testhelper.ExpErr{})
+	testhelper.DiffString(t, id, "name", ci.Name(), "Price")
+
+	id = "ColByName"
+	c, err := df.ColByName("name")
+	testhelper.CheckExpErrWithID(t, id, err, testhelper.ExpErr{})
+	testhelper.DiffStringSlice(t, id, "values", colVals(c),
+		[]string{"abc", "xyz"})
+
+	id = "Row.ValByName"
+	v, _, err := df.Row(1).ValByName("pRiCe")
+	testhelper.CheckExpErrWithID(t, id, err, testhelper.ExpErr{})
+	testhelper.DiffString(t, id, "value", v.(dataframe.FloatVal).String(),
+		"2.5")
+
+	id = "GroupBy"
+	gdf, err := df.GroupBy("NAME")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	adf, err := gdf.Agg(dataframe.Agg{Func: dataframe.AggCount})
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	checkColDetails(t, id, adf, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("Name", dataframe.ColTypeString),
+		dataframe.MustNewColInfo("Count", dataframe.ColTypeInt),
+	})
+
+	id = "kept by Clone"
+	_, err = df.Clone().ColInfoByName("price")
+	testhelper.CheckExpErrWithID(t, id, err, testhelper.ExpErr{})
+
+	id = "add a clashing column"
+	err = df.AddCol(mkStringCol("NAME", "a", "b"))
+	testhelper.CheckExpErrWithID(t, id, err,
+		testhelper.MkExpErr("Column name differs only in case from",
+			`Column 0 ("Name": "String")`))
+
+	id = "turned off"
+	if err := df.SetCaseInsensitiveNames(false); err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	_, err = df.ColInfoByName("price")
+	testhelper.CheckExpErrWithID(t, id, err,
+		testhelper.MkExpErr(`Unknown column name: "price"`))
+
+	id = "names which clash"
+	cdf := mkTestDF(t, "a A\nx y\n", dataframe.HasHeader)
+	err = cdf.SetCaseInsensitiveNames(true)
+	testhelper.CheckExpErrWithID(t, id, err,
+		testhelper.MkExpErr("cannot match names ignoring case:",
+			`Column 0 ("a": "String") and Column 1 ("A": "String")`+
+				" differ only in case"))
+
+	id = "exact match preferred"
+	edf, err := dataframe.NewDFFromCols(
+		mkIntCol("ab", 11), mkIntCol("xy", 22))
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	if err := edf.SetCaseInsensitiveNames(true); err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	ci, err = edf.ColInfoByName("xy")
+	testhelper.CheckExpErrWithID(t, id, err, testhelper.ExpErr{})
+	testhelper.DiffString(t, id, "name", ci.Name(), "xy")
+}
+
+func TestDFRCaseInsensitiveNames(t *testing.T) {
+	const content = `Region,SHARE,Cost
+north,45%,$10
+south,55%,$20
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		content string
+		opts    []dataframe.DFReaderOpt
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:      testhelper.MkID("options with names in other cases"),
+			content: content,
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCaseInsensitiveNames,
+				dataframe.DFRRequireCols("region", "share"),
+				dataframe.DFRPercentages("share"),
+				dataframe.DFRCurrency("COST", "$", ","),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("Region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("SHARE", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("Cost", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"north", "0.45", "10"},
+				{"south", "0.55", "20"},
+			},
+		},
+		{
+			ID: testhelper.MkID("case sensitive"),
+			ExpErr: testhelper.MkExpErr(
+				`2 required columns are missing: "region", "share"`),
+			content: content,
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRRequireCols("region", "share"),
+			},
+		},
+		{
+			ID:      testhelper.MkID("names which clash"),
+			ExpErr:  testhelper.MkExpErr(`column names "a" and "A"`),
+			content: "a,b,A\n1,2,3\n",
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCaseInsensitiveNames,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DFReaderOpt{
+			dataframe.HasHeader,
+			dataframe.SplitPattern(","),
+		}, tc.opts...)
+		dfr, err := dataframe.NewDFReader(opts...)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(tc.content), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
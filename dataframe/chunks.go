@@ -0,0 +1,69 @@
+package dataframe
+
+// rowRange returns a dataframe holding rows lo to hi-1 of df. The columns
+// are views on the dataframe's own storage, capped so that adding rows to
+// the new dataframe will not change df. Compressed columns cannot be shared
+// in this way and so their values are copied.
+func (df *DF) rowRange(lo, hi int) *DF {
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+
+	for vi := range df.floatCols {
+		rval.floatCols[vi] = df.floatCols[vi][lo:hi:hi]
+	}
+	for vi := range df.intCols {
+		rval.intCols[vi] = df.intCols[vi][lo:hi:hi]
+	}
+	for vi := range df.boolCols {
+		if _, ok := df.rleBoolCols[vi]; !ok {
+			rval.boolCols[vi] = df.boolCols[vi][lo:hi:hi]
+			continue
+		}
+		for i := lo; i < hi; i++ {
+			rval.appendBoolVal(vi, df.boolAt(vi, i))
+		}
+	}
+	for vi := range df.stringCols {
+		if _, ok := df.rleStringCols[vi]; !ok {
+			rval.stringCols[vi] = df.stringCols[vi][lo:hi:hi]
+			continue
+		}
+		for i := lo; i < hi; i++ {
+			rval.appendStringVal(vi, df.stringAt(vi, i))
+		}
+	}
+
+	return rval
+}
+
+// Chunks returns a function which calls yield with successive dataframes
+// each holding the next n rows of df, the last holding any remaining rows,
+// until every row has been given or yield returns false. This is useful,
+// for instance, for inserting the rows into a database in batches. If n is
+// less than 1 the whole dataframe is given as a single chunk. No chunks are
+// given if the dataframe has no rows.
+//
+// The function has the same form as an iter.Seq[*DF] so it can be used with
+// a range statement in later versions of Go.
+//
+// Each chunk has the same columns and metadata as df and shares its
+// storage, so no copy of the values is made (except for compressed
+// columns). Any changes made to the values in df will be seen in the
+// chunks but rows added to either will not affect the other.
+func (df *DF) Chunks(n int) func(yield func(*DF) bool) {
+	return func(yield func(*DF) bool) {
+		rows := df.RowCount()
+		if n < 1 {
+			n = rows
+		}
+		for lo := 0; lo < rows; lo += n {
+			hi := lo + n
+			if hi > rows {
+				hi = rows
+			}
+			if !yield(df.rowRange(lo, hi)) {
+				return
+			}
+		}
+	}
+}
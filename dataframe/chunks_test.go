@@ -0,0 +1,120 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+const chunksTestData = `flag state n x
+true on 10 1.5
+true on 20 2.5
+true off 30 3.5
+false off 40 4.5
+false off 50 5.5
+`
+
+func TestChunks(t *testing.T) {
+	df := mkTestDF(t, chunksTestData, dataframe.HasHeader)
+	if err := df.Compress("state"); err != nil {
+		t.Fatal("unexpected error compressing the column: ", err)
+	}
+	rows := [][]string{
+		{"true", "on", "10", "1.5"},
+		{"true", "on", "20", "2.5"},
+		{"true", "off", "30", "3.5"},
+		{"false", "off", "40", "4.5"},
+		{"false", "off", "50", "5.5"},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		n         int
+		maxChunks int
+		expChunks [][][]string
+	}{
+		{
+			ID: testhelper.MkID("chunks of 2"),
+			n:  2,
+			expChunks: [][][]string{
+				rows[0:2],
+				rows[2:4],
+				rows[4:5],
+			},
+		},
+		{
+			ID:        testhelper.MkID("one exact chunk"),
+			n:         5,
+			expChunks: [][][]string{rows},
+		},
+		{
+			ID:        testhelper.MkID("chunk bigger than the dataframe"),
+			n:         10,
+			expChunks: [][][]string{rows},
+		},
+		{
+			ID:        testhelper.MkID("bad chunk size"),
+			n:         0,
+			expChunks: [][][]string{rows},
+		},
+		{
+			ID:        testhelper.MkID("stop early"),
+			n:         1,
+			maxChunks: 2,
+			expChunks: [][][]string{
+				rows[0:1],
+				rows[1:2],
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var chunks []*dataframe.DF
+		df.Chunks(tc.n)(func(c *dataframe.DF) bool {
+			chunks = append(chunks, c)
+			return tc.maxChunks == 0 || len(chunks) < tc.maxChunks
+		})
+
+		if testhelper.DiffInt(t, tc.IDStr(), "chunk count",
+			len(chunks), len(tc.expChunks)) {
+			continue
+		}
+		for i, c := range chunks {
+			checkColDetails(t, tc.IDStr(), c, df.Columns())
+			checkDFVals(t, tc.IDStr(), c, tc.expChunks[i])
+		}
+	}
+}
+
+func TestChunksIndependent(t *testing.T) {
+	df := mkTestDF(t, chunksTestData, dataframe.HasHeader)
+
+	var first *dataframe.DF
+	df.Chunks(2)(func(c *dataframe.DF) bool {
+		first = c
+		return false
+	})
+	first.AddRowFromText([]string{"false", "new", "99", "9.5"})
+
+	checkDFVals(t, "chunk with a row added", first, [][]string{
+		{"true", "on", "10", "1.5"},
+		{"true", "on", "20", "2.5"},
+		{"false", "new", "99", "9.5"},
+	})
+	checkDFVals(t, "original after the chunk has changed", df, [][]string{
+		{"true", "on", "10", "1.5"},
+		{"true", "on", "20", "2.5"},
+		{"true", "off", "30", "3.5"},
+		{"false", "off", "40", "4.5"},
+		{"false", "off", "50", "5.5"},
+	})
+
+	empty := df.Clone()
+	count := 0
+	empty.Chunks(2)(func(*dataframe.DF) bool {
+		count++
+		return true
+	})
+	testhelper.DiffInt(t, "empty dataframe", "chunk count", count, 0)
+}
@@ -0,0 +1,163 @@
+package dataframe
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/nickwells/check.mod/v2/check"
+)
+
+// ColCheckable is the set of types of value that can be checked by
+// DFRColCheck. An int or int64 check is applied to an int column, a float64
+// check to a float column and so on.
+type ColCheckable interface {
+	~bool | ~int | ~int64 | ~float64 | ~string
+}
+
+// colCheck records a check to be applied to the values in a column
+type colCheck struct {
+	name    string
+	colType ColType
+	check   func(v any) error
+}
+
+// colCheckAt records a check together with the index of the column it
+// applies to
+type colCheckAt struct {
+	colCheck
+	idx int
+}
+
+// colTypeOfKind returns the ColType corresponding to the kind
+func colTypeOfKind(k reflect.Kind) ColType {
+	switch k {
+	case reflect.Bool:
+		return ColTypeBool
+	case reflect.Int, reflect.Int64:
+		return ColTypeInt
+	case reflect.Float64:
+		return ColTypeFloat
+	case reflect.String:
+		return ColTypeString
+	}
+	return ColTypeUnknown
+}
+
+// underlyingVal returns the value held in the Val type
+func underlyingVal(v any) any {
+	switch v := v.(type) {
+	case BoolVal:
+		return v.Val
+	case IntVal:
+		return v.Val
+	case FloatVal:
+		return v.Val
+	case StringVal:
+		return v.Val
+	}
+	return v
+}
+
+// DFRColCheck returns a function which will cause the DFReader to apply
+// the check to each value in the named column as it is read, for instance:
+//
+//	DFRColCheck("age", check.ValGE(0))
+//
+// A value which fails the check is recorded as an error in the same way as
+// a value that cannot be parsed; the error will match ErrCheckFailed when
+// tested using errors.Is. Unless AllowErrors is given the Read will then
+// fail. NA values are not checked. Rows having values which fail a check
+// can be dropped from the dataframe using DFRDropFailedRows. If the column
+// is missing or is of the wrong type for the check, the Read fails.
+func DFRColCheck[T ColCheckable](name string, ck check.ValCk[T]) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if name == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the column name for the check is empty")
+		}
+		if ck == nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"column %q: the check function is nil", name)
+		}
+
+		rt := reflect.TypeOf((*T)(nil)).Elem()
+		dfr.colChecks = append(dfr.colChecks, colCheck{
+			name:    name,
+			colType: colTypeOfKind(rt.Kind()),
+			check: func(v any) error {
+				return ck(reflect.ValueOf(v).Convert(rt).Interface().(T))
+			},
+		})
+		return nil
+	}
+}
+
+// DFRDropFailedRows will cause the DFReader to leave out of the dataframe
+// any row having a value which fails a check given by DFRColCheck. The
+// failures are still recorded as errors and so AllowErrors should also be
+// given if the Read is to succeed.
+func DFRDropFailedRows(dfr *DFReader) error {
+	dfr.dropFailedRows = true
+	return nil
+}
+
+// findColChecks finds the columns for the DFReader's column checks,
+// recording them in the state. It returns an error if any column is
+// missing or of the wrong type.
+func (dfr *DFReader) findColChecks(state *dfReadState, df *DF) error {
+	for _, ck := range dfr.colChecks {
+		idx, ok := df.mci.colIdx(ck.name)
+		if !ok {
+			return dfWrapf(errUnknownColName(ck.name), "%s: bad column check",
+				state.loc.Source())
+		}
+		if err := assertTypeByName(df.mci.info[idx].colType, ck.colType,
+			ck.name); err != nil {
+			return dfWrapf(err, "%s: bad column check", state.loc.Source())
+		}
+		state.colChecks = append(state.colChecks,
+			colCheckAt{colCheck: ck, idx: idx})
+	}
+	return nil
+}
+
+// applyColChecks applies the column checks to the last row of the
+// dataframe, recording an error for each value that fails. If any value
+// fails and the DFReader drops failed rows then the row is removed. The
+// first error (if any) is returned.
+func (dfr *DFReader) applyColChecks(state *dfReadState, df *DF,
+	cols []string, line int64, text string,
+) error {
+	if len(state.colChecks) == 0 {
+		return nil
+	}
+
+	row := df.RowCount() - 1
+	var firstErr error
+	for _, ck := range state.colChecks {
+		v, isNA := df.valAt(ck.idx, row)
+		if isNA {
+			continue
+		}
+		if ckErr := ck.check(underlyingVal(v)); ckErr != nil {
+			err := ParseError{
+				Source: state.loc.Source(),
+				Line:   line,
+				Text:   text,
+				Col:    ck.idx,
+				Field:  cols[ck.idx],
+				Msg:    fmt.Sprintf("column %q: %s", ck.name, ckErr),
+				kind:   ErrCheckFailed,
+			}
+			df.addError(err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if firstErr != nil && dfr.dropFailedRows {
+		df.truncateRows(row)
+	}
+	return firstErr
+}
@@ -0,0 +1,106 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/check.mod/v2/check"
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRColCheck(t *testing.T) {
+	const data = "name age score\n" +
+		"ann 30 1.5\n" +
+		"bob -1 2.5\n" +
+		"cat 40 150\n" +
+		"dan NA 3.5\n"
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts      []dataframe.DFReaderOpt
+		expVals   [][]string
+		expErrCnt int64
+	}{
+		{
+			ID: testhelper.MkID("checks flagged, errors allowed"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.AllowErrors,
+				dataframe.DFRColCheck("age", check.ValGE(0)),
+				dataframe.DFRColCheck("score", check.ValLT(100.0)),
+			},
+			expVals: [][]string{
+				{"ann", "30", "1.5"},
+				{"bob", "-1", "2.5"},
+				{"cat", "40", "150"},
+				{"dan", "NA", "3.5"},
+			},
+			expErrCnt: 3, // includes the NA age
+		},
+		{
+			ID: testhelper.MkID("failed rows dropped"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.AllowErrors,
+				dataframe.DFRDropFailedRows,
+				dataframe.DFRColCheck("age", check.ValGE(0)),
+				dataframe.DFRColCheck("score", check.ValLT(100.0)),
+				dataframe.DFRColCheck("name",
+					check.StringHasPrefix[string]("a")),
+			},
+			expVals: [][]string{
+				{"ann", "30", "1.5"},
+			},
+			expErrCnt: 6,
+		},
+		{
+			ID: testhelper.MkID("check failed, errors not allowed"),
+			ExpErr: testhelper.MkExpErr(
+				`test data:3: column "age": the value (-1)`,
+				"must be greater than or equal to 0"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColCheck("age", check.ValGE(0)),
+			},
+		},
+		{
+			ID: testhelper.MkID("check on a missing column"),
+			ExpErr: testhelper.MkExpErr(
+				"bad column check", `Unknown column name: "nonesuch"`),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.AllowErrors,
+				dataframe.DFRColCheck("nonesuch", check.ValGE(0)),
+			},
+		},
+		{
+			ID: testhelper.MkID("check of the wrong type"),
+			ExpErr: testhelper.MkExpErr(
+				"bad column check",
+				`The column named "name" is of type "String" not "Int"`),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColCheck("name", check.ValGE(0)),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(
+			append(tc.opts, dataframe.HasHeader,
+				dataframe.DFRColTypes(dataframe.ColTypeString,
+					dataframe.ColTypeInt, dataframe.ColTypeFloat))...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(data), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+			testhelper.DiffInt(t, tc.IDStr(), "error count",
+				df.ErrCount(), tc.expErrCnt)
+		}
+		if err != nil &&
+			strings.Contains(err.Error(), "must be") {
+			testhelper.DiffBool(t, tc.IDStr(), "check failed error",
+				errors.Is(err, dataframe.ErrCheckFailed), true)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package dataframe
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ColType records the type of the data in the column and hence
 // which set of Values holds the column data
@@ -29,8 +32,9 @@ const (
 
 // ColInfo records information about an individual column
 type ColInfo struct {
-	name    string  // column name
-	colType ColType // data type
+	name    string   // column name
+	colType ColType  // data type
+	meta    *colMeta // optional metadata, see Meta
 }
 
 // String returns a formatted string describing the ColInfo value
@@ -53,11 +57,44 @@ func (ci ColInfo) Check() error {
 // MultiColInfo records information about a collection of columns. Each entry
 // in info corresponds to a column in the RowData. The corresponding entry
 // in valIdx gives the index into the slice of data of that type in the
-// type-specific slice in the associated RowData
+// type-specific slice in the associated RowData. If foldCase is true then
+// column names are matched ignoring case.
 type MultiColInfo struct {
 	info      []ColInfo
 	valIdx    []int
 	nameToCol map[string]int
+	foldCase  bool
+}
+
+// colIdx returns the index of the named column and true or false if there
+// is no such column. An exact match is preferred but if foldCase is set a
+// column whose name differs only in case will be found.
+func (mci MultiColInfo) colIdx(name string) (int, bool) {
+	if i, ok := mci.nameToCol[name]; ok {
+		return i, true
+	}
+	if mci.foldCase {
+		for i, ci := range mci.info {
+			if strings.EqualFold(ci.name, name) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// foldCaseClash returns the indexes of two of the names which differ only
+// in case and true or false if there are no such names
+func foldCaseClash(names []string) (int, int, bool) {
+	seen := make(map[string]int, len(names))
+	for i, name := range names {
+		lc := strings.ToLower(name)
+		if j, ok := seen[lc]; ok {
+			return j, i, true
+		}
+		seen[lc] = i
+	}
+	return 0, 0, false
 }
 
 // Add checks that the ColInfo is valid and that the name is unique and then
@@ -69,7 +106,12 @@ func (mci *MultiColInfo) Add(ci ColInfo) error {
 		return err
 	}
 	if otherIdx, exists := mci.nameToCol[ci.name]; exists {
-		return dfErrorf("Column name already used: %s", mci.ColDesc(otherIdx))
+		return dfCodeErrorf(ErrCodeDuplicateColName,
+			"Column name already used: %s", mci.ColDesc(otherIdx))
+	}
+	if otherIdx, exists := mci.colIdx(ci.name); exists {
+		return dfCodeErrorf(ErrCodeDuplicateColName,
+			"Column name differs only in case from %s", mci.ColDesc(otherIdx))
 	}
 
 	count := 0
@@ -99,7 +141,7 @@ func NewMultiColInfo(cis ...ColInfo) (*MultiColInfo, error) {
 	for i, ci := range cis {
 		err := mci.Add(ci)
 		if err != nil {
-			return nil, dfErrorf("Column %d (%q): %s", i, ci.name, err)
+			return nil, dfWrapf(err, "Column %d (%q)", i, ci.name)
 		}
 	}
 
@@ -110,22 +152,24 @@ func NewMultiColInfo(cis ...ColInfo) (*MultiColInfo, error) {
 // and other or nil if they are identical
 func (mci MultiColInfo) Match(other MultiColInfo) error {
 	if len(mci.info) != len(other.info) {
-		return fmt.Errorf("Differing numbers of columns: %d != %d",
+		return dfKindErrorf(ErrDimensionMismatch,
+			"Differing numbers of columns: %d != %d",
 			len(mci.info), len(other.info))
 	}
 	for i, ci := range mci.info {
 		if ci.colType != other.info[i].colType {
-			return fmt.Errorf("%s has a different type: %s != %s",
+			return dfKindErrorf(ErrTypeMismatch,
+				"%s has a different type: %s != %s",
 				mci.ColDesc(i), ci.colType, other.info[i].colType)
 		}
 		if ci.name != other.info[i].name {
-			return fmt.Errorf("%s has a different name: %q != %q",
+			return dfErrorf("%s has a different name: %q != %q",
 				mci.ColDesc(i), ci.name, other.info[i].name)
 		}
 	}
 	for i, idx := range mci.valIdx {
 		if idx != other.valIdx[i] {
-			return fmt.Errorf("%s has a different value idx: %d != %d",
+			return dfErrorf("%s has a different value idx: %d != %d",
 				mci.ColDesc(i), idx, other.valIdx[i])
 		}
 	}
@@ -147,6 +191,7 @@ func (mci MultiColInfo) Clone() MultiColInfo {
 		info:      cloneColInfoSlice(mci.info),
 		valIdx:    cloneIntSlice(mci.valIdx),
 		nameToCol: cloneColNameMap(mci.nameToCol),
+		foldCase:  mci.foldCase,
 	}
 }
 
@@ -187,15 +232,26 @@ func (ci *ColInfo) Diff(ci2 *ColInfo) bool {
 	return false
 }
 
-// NewColInfo returns a column with the name and type set
-func NewColInfo(name string, colType ColType) ColInfo {
+// NewColInfo returns a column with the name and type set. It will return
+// an error if the column type is unknown or invalid
+func NewColInfo(name string, colType ColType) (ColInfo, error) {
 	if colType <= ColTypeUnknown || colType >= ColTypeMaxVal {
-		panic(dfErrorf("Unexpected column type: %q", colType))
+		return ColInfo{}, dfErrorf("Unexpected column type: %q", colType)
 	}
 	return ColInfo{
 		name:    name,
 		colType: colType,
+	}, nil
+}
+
+// MustNewColInfo calls NewColInfo and returns the column or panics if a
+// non-nil error is returned
+func MustNewColInfo(name string, colType ColType) ColInfo {
+	ci, err := NewColInfo(name, colType)
+	if err != nil {
+		panic(err)
 	}
+	return ci
 }
 
 // Name returns the column name
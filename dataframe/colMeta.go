@@ -0,0 +1,142 @@
+package dataframe
+
+import "sort"
+
+// Well-known column metadata keys. Any key may be used but these are the
+// ones expected to be most common.
+const (
+	MetaUnit     = "unit"
+	MetaLabel    = "label"
+	MetaSource   = "source"
+	MetaCurrency = "currency"
+	MetaTimeZone = "timezone"
+)
+
+// colMeta holds the metadata for a column. It is never changed once it has
+// been created so it can be shared between copies of a ColInfo; setting a
+// value makes a new colMeta. Holding it by pointer also keeps ColInfo
+// comparable.
+type colMeta struct {
+	vals map[string]string
+}
+
+// with returns a new colMeta having the values of cm (which may be nil)
+// plus the given key and value
+func (cm *colMeta) with(key, val string) *colMeta {
+	rval := &colMeta{vals: map[string]string{key: val}}
+	if cm != nil {
+		for k, v := range cm.vals {
+			if k != key {
+				rval.vals[k] = v
+			}
+		}
+	}
+	return rval
+}
+
+// Meta returns the value of the metadata with the given key and true if
+// it has been set, or the empty string and false otherwise
+func (ci ColInfo) Meta(key string) (string, bool) {
+	if ci.meta == nil {
+		return "", false
+	}
+	v, ok := ci.meta.vals[key]
+	return v, ok
+}
+
+// MetaKeys returns the keys of the column metadata in sorted order
+func (ci ColInfo) MetaKeys() []string {
+	if ci.meta == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(ci.meta.vals))
+	for k := range ci.meta.vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WithMeta returns a copy of the ColInfo with the metadata key set to the
+// value. The original ColInfo is unchanged.
+func (ci ColInfo) WithMeta(key, val string) ColInfo {
+	ci.meta = ci.meta.with(key, val)
+	return ci
+}
+
+// checkMetaKey returns an error if the metadata key is invalid
+func checkMetaKey(key string) error {
+	if key == "" {
+		return dfErrorf("the column metadata key must not be empty")
+	}
+	return nil
+}
+
+// SetColMeta sets the metadata key on the named column to the value. The
+// metadata is carried through to any dataframe made from this one, for
+// instance by Clone, Select, Filter or Sort. It returns an error if the
+// column does not exist or the key is empty.
+func (df *DF) SetColMeta(name, key, val string) error {
+	if err := checkMetaKey(key); err != nil {
+		return err
+	}
+	idx, ok := df.mci.colIdx(name)
+	if !ok {
+		return errUnknownColName(name)
+	}
+	df.mci.info[idx] = df.mci.info[idx].WithMeta(key, val)
+	return nil
+}
+
+// ColMeta returns the value of the metadata key on the named column and
+// true if it has been set. It returns an error if the column does not
+// exist.
+func (df *DF) ColMeta(name, key string) (string, bool, error) {
+	ci, err := df.ColInfoByName(name)
+	if err != nil {
+		return "", false, err
+	}
+	v, ok := ci.Meta(key)
+	return v, ok, nil
+}
+
+// colMetaSetting records a metadata value to be set on a column when it
+// is read
+type colMetaSetting struct {
+	col string
+	key string
+	val string
+}
+
+// DFRColMeta returns a function which will cause the DFReader to set the
+// metadata key on the named column to the value. It is an error if the
+// data read has no column with that name.
+func DFRColMeta(name, key, val string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if name == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the column name must not be empty")
+		}
+		if err := checkMetaKey(key); err != nil {
+			return err
+		}
+		dfr.colMeta = append(dfr.colMeta,
+			colMetaSetting{col: name, key: key, val: val})
+		return nil
+	}
+}
+
+// setColMeta sets any metadata taken from the header lines (see
+// DFRHeaderLines) or given through DFRColMeta on the columns of the
+// dataframe. Values given through DFRColMeta take precedence.
+func (dfr *DFReader) setColMeta(state *dfReadState, df *DF) error {
+	settings := append(append([]colMetaSetting(nil), state.headerMeta...),
+		dfr.colMeta...)
+	for _, cms := range settings {
+		if err := df.SetColMeta(cms.col, cms.key, cms.val); err != nil {
+			return dfWrapf(err, "%s: cannot set the column metadata",
+				state.loc.Source())
+		}
+	}
+	return nil
+}
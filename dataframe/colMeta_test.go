@@ -0,0 +1,119 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRColMeta(t *testing.T) {
+	const content = "a b\n1 x\n2 y\n"
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		expUnit string
+		expSet  bool
+	}{
+		{
+			ID: testhelper.MkID("unit set"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColMeta("a", dataframe.MetaUnit, "kg"),
+			},
+			expUnit: "kg",
+			expSet:  true,
+		},
+		{
+			ID: testhelper.MkID("later setting wins"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColMeta("a", dataframe.MetaUnit, "kg"),
+				dataframe.DFRColMeta("a", dataframe.MetaUnit, "lb"),
+			},
+			expUnit: "lb",
+			expSet:  true,
+		},
+		{
+			ID: testhelper.MkID("other column"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColMeta("b", dataframe.MetaUnit, "kg"),
+			},
+		},
+		{
+			ID: testhelper.MkID("unknown column"),
+			ExpErr: testhelper.MkExpErr(
+				"cannot set the column metadata",
+				`Unknown column name: "x"`),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColMeta("x", dataframe.MetaUnit, "kg"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DFReaderOpt{dataframe.HasHeader},
+			tc.opts...)
+		dfr, err := dataframe.NewDFReader(opts...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+		}
+
+		df, err := dfr.Read(strings.NewReader(content), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			unit, ok, err := df.ColMeta("a", dataframe.MetaUnit)
+			if err != nil {
+				t.Fatal("BAD TEST - cannot get the column metadata: ", err)
+			}
+			testhelper.DiffBool(t, tc.IDStr(), "unit set", ok, tc.expSet)
+			testhelper.DiffString(t, tc.IDStr(), "unit", unit, tc.expUnit)
+		}
+	}
+}
+
+func TestColMetaPreserved(t *testing.T) {
+	df := mkTestDF(t, "a b\n1 x\n2 y\n", dataframe.HasHeader,
+		dataframe.DFRColMeta("a", dataframe.MetaUnit, "kg"),
+		dataframe.DFRColMeta("a", dataframe.MetaSource, "scales"))
+
+	sel, err := df.Select("b", "a")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot select the columns: ", err)
+	}
+	sorted, err := df.Sort(dataframe.SortKey{Col: "b", Desc: true})
+	if err != nil {
+		t.Fatal("BAD TEST - cannot sort the dataframe: ", err)
+	}
+
+	derived := map[string]*dataframe.DF{
+		"Clone":  df.Clone(),
+		"Select": sel,
+		"Filter": df.Filter(func(*dataframe.Row) bool { return true }),
+		"Sort":   sorted,
+	}
+	for name, d := range derived {
+		ci, err := d.ColInfoByName("a")
+		if err != nil {
+			t.Fatal("BAD TEST - cannot get the column: ", err)
+		}
+		testhelper.DiffStringSlice(t, name, "meta keys", ci.MetaKeys(),
+			[]string{dataframe.MetaSource, dataframe.MetaUnit})
+		unit, _ := ci.Meta(dataframe.MetaUnit)
+		testhelper.DiffString(t, name, "unit", unit, "kg")
+	}
+
+	c := df.Clone()
+	if err := c.SetColMeta("a", dataframe.MetaUnit, "lb"); err != nil {
+		t.Fatal("unexpected error setting the metadata: ", err)
+	}
+	unit, _, _ := df.ColMeta("a", dataframe.MetaUnit)
+	testhelper.DiffString(t, "original after SetColMeta on clone", "unit",
+		unit, "kg")
+	unit, _, _ = c.ColMeta("a", dataframe.MetaUnit)
+	testhelper.DiffString(t, "clone after SetColMeta", "unit", unit, "lb")
+
+	err = df.SetColMeta("a", "", "x")
+	testhelper.CheckExpErrWithID(t, "empty key", err, testhelper.MkExpErr(
+		"the column metadata key must not be empty"))
+}
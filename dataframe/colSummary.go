@@ -0,0 +1,147 @@
+package dataframe
+
+// ColSummary holds simple statistics of the values in a column. Count is
+// the number of values, including NA values, and NACount is the number of
+// NA values. Min and Max hold the smallest and largest values, as Val
+// types (IntVal, FloatVal etc) matching the type of the column; they are
+// NA if the column has no values other than NA.
+type ColSummary struct {
+	Count   int
+	NACount int
+	Min     any
+	Max     any
+}
+
+// colSummary holds the summary of a column. The rowCount records the number
+// of rows that have been summarised.
+type colSummary struct {
+	sum      ColSummary
+	rowCount int
+}
+
+// naValOf returns the NA value of the given column type
+func naValOf(ct ColType) any {
+	switch ct {
+	case ColTypeBool:
+		return BoolVal{IsNA: true}
+	case ColTypeInt:
+		return IntVal{IsNA: true}
+	case ColTypeFloat:
+		return FloatVal{IsNA: true}
+	}
+	return StringVal{IsNA: true}
+}
+
+// newColSummary returns an empty summary of the indexed column
+func newColSummary(df *DF, colIdx int) *colSummary {
+	na := naValOf(df.mci.info[colIdx].colType)
+	return &colSummary{sum: ColSummary{Min: na, Max: na}}
+}
+
+// update adds any rows not yet summarised to the summary. The summary is
+// not changed if it is up to date so that it may be safely called by many
+// goroutines at once (see SyncDF).
+func (cs *colSummary) update(df *DF, colIdx int) {
+	if cs.rowCount == df.RowCount() {
+		return
+	}
+	for i := cs.rowCount; i < df.RowCount(); i++ {
+		v, isNA := df.valAt(colIdx, i)
+		cs.sum.Count++
+		if isNA {
+			cs.sum.NACount++
+			continue
+		}
+		if cmpVals(v, cs.sum.Min) < 0 {
+			cs.sum.Min = v
+		}
+		if _, maxIsNA := keyOf(cs.sum.Max); maxIsNA ||
+			cmpVals(v, cs.sum.Max) > 0 {
+			cs.sum.Max = v
+		}
+	}
+	cs.rowCount = df.RowCount()
+}
+
+// updateSummaries adds any rows not yet summarised to all the column
+// summaries
+func (df *DF) updateSummaries() {
+	for i, cs := range df.summaries {
+		cs.update(df, i)
+	}
+}
+
+// TrackColSummaries starts keeping a summary (see ColSummary) of each of
+// the named columns, or of every column if no names are given. The
+// summaries are kept up to date as rows are added so that, for instance,
+// the range of the values can be checked without scanning the column.
+// Any changes to the column values, other than adding rows, cause the
+// summary to be rebuilt when next used. It returns an error if there is no
+// such column, in which case no summaries are started.
+func (df *DF) TrackColSummaries(names ...string) error {
+	idxs := make([]int, 0, len(names))
+	for _, name := range names {
+		i, ok := df.mci.colIdx(name)
+		if !ok {
+			return errUnknownColName(name)
+		}
+		idxs = append(idxs, i)
+	}
+	if len(names) == 0 {
+		for i := range df.mci.info {
+			idxs = append(idxs, i)
+		}
+	}
+
+	if df.summaries == nil {
+		df.summaries = map[int]*colSummary{}
+	}
+	for _, i := range idxs {
+		if _, ok := df.summaries[i]; ok {
+			continue
+		}
+		cs := newColSummary(df, i)
+		cs.update(df, i)
+		df.summaries[i] = cs
+	}
+
+	return nil
+}
+
+// ColSummary returns the summary of the values in the named column. If the
+// summary of the column is being kept (see TrackColSummaries) it is just
+// brought up to date with any rows added since it was last used;
+// otherwise the whole column is scanned. It returns an error if there is
+// no such column.
+func (df *DF) ColSummary(name string) (ColSummary, error) {
+	i, ok := df.mci.colIdx(name)
+	if !ok {
+		return ColSummary{}, errUnknownColName(name)
+	}
+
+	cs, ok := df.summaries[i]
+	if !ok {
+		cs = newColSummary(df, i)
+	}
+	cs.update(df, i)
+
+	return cs.sum, nil
+}
+
+// DFRColSummaries returns a function which will cause the DFReader to keep
+// a summary of each of the named columns, or of every column if no names
+// are given, as the values are read (see TrackColSummaries). It is an
+// error if the data read has no column with that name.
+func DFRColSummaries(names ...string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		for _, name := range names {
+			if name == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"the column name must not be empty")
+			}
+		}
+		dfr.summaryCols = append(dfr.summaryCols, names...)
+		dfr.summarise = true
+		return nil
+	}
+}
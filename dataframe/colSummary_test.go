@@ -0,0 +1,105 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestColSummary(t *testing.T) {
+	df := mkTestDF(t, "sym px qty\ndef 2.5 NA\nabc NA 20\nghi 1.5 10\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeString,
+			dataframe.ColTypeFloat, dataframe.ColTypeInt),
+		dataframe.DFRColSummaries("px", "qty"))
+	if err := df.AddRow(df.Row(0)); err != nil {
+		t.Fatal("BAD TEST - cannot add the row: ", err)
+	}
+	empty := mkTestDF(t, "n\n", dataframe.HasHeader,
+		dataframe.DFRColTypes(dataframe.ColTypeInt),
+		dataframe.DFRColSummaries())
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		df         *dataframe.DF
+		col        string
+		expCount   int
+		expNACount int
+		expMin     string
+		expMax     string
+	}{
+		{
+			ID:         testhelper.MkID("float column, tracked"),
+			df:         df,
+			col:        "px",
+			expCount:   4,
+			expNACount: 1,
+			expMin:     "1.5",
+			expMax:     "2.5",
+		},
+		{
+			ID:         testhelper.MkID("int column, tracked"),
+			df:         df,
+			col:        "qty",
+			expCount:   4,
+			expNACount: 2,
+			expMin:     "10",
+			expMax:     "20",
+		},
+		{
+			ID:       testhelper.MkID("string column, not tracked"),
+			df:       df,
+			col:      "sym",
+			expCount: 4,
+			expMin:   "abc",
+			expMax:   "ghi",
+		},
+		{
+			ID:     testhelper.MkID("no values"),
+			df:     empty,
+			col:    "n",
+			expMin: "NA",
+			expMax: "NA",
+		},
+		{
+			ID:     testhelper.MkID("bad: no such column"),
+			ExpErr: testhelper.MkExpErr(`"nonesuch"`),
+			df:     df,
+			col:    "nonesuch",
+		},
+	}
+
+	for _, tc := range testCases {
+		cs, err := tc.df.ColSummary(tc.col)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffInt(t, tc.IDStr(), "count", cs.Count, tc.expCount)
+			testhelper.DiffInt(t, tc.IDStr(), "NA count",
+				cs.NACount, tc.expNACount)
+			testhelper.DiffString(t, tc.IDStr(), "min",
+				fmt.Sprint(cs.Min), tc.expMin)
+			testhelper.DiffString(t, tc.IDStr(), "max",
+				fmt.Sprint(cs.Max), tc.expMax)
+		}
+	}
+
+	err := df.TrackColSummaries("nonesuch")
+	testhelper.CheckExpErrWithID(t, "track a missing column", err,
+		testhelper.MkExpErr(`"nonesuch"`))
+
+	tx := df.Begin()
+	if err := tx.SetVal("qty", 1, 5); err != nil {
+		t.Fatal("cannot change the value: ", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal("cannot commit the change: ", err)
+	}
+	cs, err := df.ColSummary("qty")
+	if err != nil {
+		t.Fatal("cannot get the summary after the change: ", err)
+	}
+	testhelper.DiffString(t, "after the change", "min",
+		fmt.Sprint(cs.Min), "5")
+}
@@ -11,15 +11,23 @@ type Column struct {
 	stringVals []StringVal
 }
 
-// SetInfo sets the column name and type. It will panic if the column type is
-// unknown or invalid
-func (c *Column) SetInfo(name string, colType ColType) {
+// SetInfo sets the column name and type. It will return an error if the
+// column type is unknown or invalid
+func (c *Column) SetInfo(name string, colType ColType) error {
 	if colType <= ColTypeUnknown || colType >= ColTypeMaxVal {
-		panic(dfErrorf("Unexpected column type: %q", colType))
+		return dfErrorf("Unexpected column type: %q", colType)
 	}
 
 	c.ci.name = name
 	c.ci.colType = colType
+	return nil
+}
+
+// MustSetInfo calls SetInfo and panics if a non-nil error is returned
+func (c *Column) MustSetInfo(name string, colType ColType) {
+	if err := c.SetInfo(name, colType); err != nil {
+		panic(err)
+	}
 }
 
 // Info returns the column info - the name and column type
@@ -27,44 +35,80 @@ func (c *Column) Info() (string, ColType) {
 	return c.ci.name, c.ci.colType
 }
 
-// AddBoolVal adds a bool value to the column. It will panic if the column
-// type is not bool
-func (c *Column) AddBoolVal(v BoolVal) {
+// AddBoolVal adds a bool value to the column. It will return an error if
+// the column type is not bool
+func (c *Column) AddBoolVal(v BoolVal) error {
 	if c.ci.colType != ColTypeBool {
-		panic(dfErrorf("Adding a BoolVal to a %q column", c.ci.colType))
+		return dfKindErrorf(ErrTypeMismatch,
+			"Adding a BoolVal to a %q column", c.ci.colType)
 	}
 
 	c.boolVals = append(c.boolVals, v)
+	return nil
+}
+
+// MustAddBoolVal calls AddBoolVal and panics if a non-nil error is returned
+func (c *Column) MustAddBoolVal(v BoolVal) {
+	if err := c.AddBoolVal(v); err != nil {
+		panic(err)
+	}
 }
 
-// AddIntVal adds a int value to the column. It will panic if the column
-// type is not int
-func (c *Column) AddIntVal(v IntVal) {
+// AddIntVal adds a int value to the column. It will return an error if
+// the column type is not int
+func (c *Column) AddIntVal(v IntVal) error {
 	if c.ci.colType != ColTypeInt {
-		panic(dfErrorf("Adding a IntVal to a %q column", c.ci.colType))
+		return dfKindErrorf(ErrTypeMismatch,
+			"Adding a IntVal to a %q column", c.ci.colType)
 	}
 
 	c.intVals = append(c.intVals, v)
+	return nil
 }
 
-// AddFloatVal adds a float value to the column. It will panic if the column
-// type is not float
-func (c *Column) AddFloatVal(v FloatVal) {
+// MustAddIntVal calls AddIntVal and panics if a non-nil error is returned
+func (c *Column) MustAddIntVal(v IntVal) {
+	if err := c.AddIntVal(v); err != nil {
+		panic(err)
+	}
+}
+
+// AddFloatVal adds a float value to the column. It will return an error if
+// the column type is not float
+func (c *Column) AddFloatVal(v FloatVal) error {
 	if c.ci.colType != ColTypeFloat {
-		panic(dfErrorf("Adding a FloatVal to a %q column", c.ci.colType))
+		return dfKindErrorf(ErrTypeMismatch,
+			"Adding a FloatVal to a %q column", c.ci.colType)
 	}
 
 	c.floatVals = append(c.floatVals, v)
+	return nil
 }
 
-// AddStringVal adds a string value to the column. It will panic if the column
-// type is not string
-func (c *Column) AddStringVal(v StringVal) {
+// MustAddFloatVal calls AddFloatVal and panics if a non-nil error is returned
+func (c *Column) MustAddFloatVal(v FloatVal) {
+	if err := c.AddFloatVal(v); err != nil {
+		panic(err)
+	}
+}
+
+// AddStringVal adds a string value to the column. It will return an error if
+// the column type is not string
+func (c *Column) AddStringVal(v StringVal) error {
 	if c.ci.colType != ColTypeString {
-		panic(dfErrorf("Adding a StringVal to a %q column", c.ci.colType))
+		return dfKindErrorf(ErrTypeMismatch,
+			"Adding a StringVal to a %q column", c.ci.colType)
 	}
 
 	c.stringVals = append(c.stringVals, v)
+	return nil
+}
+
+// MustAddStringVal calls AddStringVal and panics if a non-nil error is returned
+func (c *Column) MustAddStringVal(v StringVal) {
+	if err := c.AddStringVal(v); err != nil {
+		panic(err)
+	}
 }
 
 // RowCount returns the number of rows in the column
@@ -87,7 +131,8 @@ func (c Column) RowCount() int {
 // range
 func (c Column) checkRowIdx(i int) error {
 	if i < 0 || i >= c.RowCount() {
-		return dfErrorf("There is no row %d (valid range: 0-%d)",
+		return dfKindErrorf(ErrNoSuchRow,
+			"There is no row %d (valid range: 0-%d)",
 			i, c.RowCount()-1)
 	}
 	return nil
@@ -119,7 +164,8 @@ func (c Column) GetVal(i int) (any, error) {
 func (c Column) GetBoolVal(i int) (BoolVal, error) {
 	if c.ci.colType != ColTypeBool {
 		return BoolVal{IsNA: true},
-			dfErrorf("Getting a BoolVal from a %q column", c.ci.colType)
+			dfKindErrorf(ErrTypeMismatch,
+				"Getting a BoolVal from a %q column", c.ci.colType)
 	}
 	if err := c.checkRowIdx(i); err != nil {
 		return BoolVal{IsNA: true}, err
@@ -133,7 +179,8 @@ func (c Column) GetBoolVal(i int) (BoolVal, error) {
 func (c Column) GetIntVal(i int) (IntVal, error) {
 	if c.ci.colType != ColTypeInt {
 		return IntVal{IsNA: true},
-			dfErrorf("Getting a IntVal from a %q column", c.ci.colType)
+			dfKindErrorf(ErrTypeMismatch,
+				"Getting a IntVal from a %q column", c.ci.colType)
 	}
 	if err := c.checkRowIdx(i); err != nil {
 		return IntVal{IsNA: true}, err
@@ -147,7 +194,8 @@ func (c Column) GetIntVal(i int) (IntVal, error) {
 func (c Column) GetFloatVal(i int) (FloatVal, error) {
 	if c.ci.colType != ColTypeFloat {
 		return FloatVal{IsNA: true},
-			dfErrorf("Getting a FloatVal from a %q column", c.ci.colType)
+			dfKindErrorf(ErrTypeMismatch,
+				"Getting a FloatVal from a %q column", c.ci.colType)
 	}
 	if err := c.checkRowIdx(i); err != nil {
 		return FloatVal{IsNA: true}, err
@@ -161,7 +209,8 @@ func (c Column) GetFloatVal(i int) (FloatVal, error) {
 func (c Column) GetStringVal(i int) (StringVal, error) {
 	if c.ci.colType != ColTypeString {
 		return StringVal{IsNA: true},
-			dfErrorf("Getting a StringVal from a %q column", c.ci.colType)
+			dfKindErrorf(ErrTypeMismatch,
+				"Getting a StringVal from a %q column", c.ci.colType)
 	}
 	if err := c.checkRowIdx(i); err != nil {
 		return StringVal{IsNA: true}, err
@@ -169,3 +218,112 @@ func (c Column) GetStringVal(i int) (StringVal, error) {
 
 	return c.stringVals[i], nil
 }
+
+// addVal converts the value to the column type and appends it to the
+// column. The allowed types are as for a RowFunc. It returns an error if
+// the value cannot be converted.
+func (c *Column) addVal(v any) error {
+	switch c.ci.colType {
+	case ColTypeBool:
+		bv, err := boolValOf(v)
+		if err != nil {
+			return err
+		}
+		c.boolVals = append(c.boolVals, bv)
+	case ColTypeInt:
+		iv, err := intValOf(v)
+		if err != nil {
+			return err
+		}
+		c.intVals = append(c.intVals, iv)
+	case ColTypeFloat:
+		fv, err := floatValOf(v)
+		if err != nil {
+			return err
+		}
+		c.floatVals = append(c.floatVals, fv)
+	case ColTypeString:
+		sv, err := stringValOf(v)
+		if err != nil {
+			return err
+		}
+		c.stringVals = append(c.stringVals, sv)
+	default:
+		panic(dfErrorf("Unexpected column type: %q", c.ci.colType))
+	}
+	return nil
+}
+
+// Apply returns a new column with the same name and type as the column and
+// with each value replaced by the result of calling the function with
+// that value. The function is passed the column's value type (such as
+// IntVal) and the value it returns must be convertible to the column type;
+// the allowed types are as for a RowFunc and returning nil gives an NA
+// value. It returns an error if the function returns an error or a value
+// which cannot be converted. The typed variants (ApplyInt and so on) avoid
+// the conversions and are faster.
+func (c Column) Apply(f func(v any) (any, error)) (Column, error) {
+	rval := Column{ci: c.ci}
+	for i := 0; i < c.RowCount(); i++ {
+		v, _ := c.GetVal(i)
+		nv, err := f(v)
+		if err != nil {
+			return Column{}, dfWrapf(err, "row %d", i)
+		}
+		if err := rval.addVal(nv); err != nil {
+			return Column{}, dfWrapf(err, "row %d", i)
+		}
+	}
+	return rval, nil
+}
+
+// applyVals returns a new slice holding the result of calling the function
+// with each of the values
+func applyVals[T any](vals []T, f func(T) T) []T {
+	rval := make([]T, 0, len(vals))
+	for _, v := range vals {
+		rval = append(rval, f(v))
+	}
+	return rval
+}
+
+// ApplyBool returns a new column as for Apply but taking a function of
+// BoolVals. It will return an error if the column is not a bool column.
+func (c Column) ApplyBool(f func(BoolVal) BoolVal) (Column, error) {
+	if c.ci.colType != ColTypeBool {
+		return Column{}, dfKindErrorf(ErrTypeMismatch,
+			"Applying a BoolVal function to a %q column", c.ci.colType)
+	}
+	return Column{ci: c.ci, boolVals: applyVals(c.boolVals, f)}, nil
+}
+
+// ApplyInt returns a new column as for Apply but taking a function of
+// IntVals. It will return an error if the column is not an int column.
+func (c Column) ApplyInt(f func(IntVal) IntVal) (Column, error) {
+	if c.ci.colType != ColTypeInt {
+		return Column{}, dfKindErrorf(ErrTypeMismatch,
+			"Applying an IntVal function to a %q column", c.ci.colType)
+	}
+	return Column{ci: c.ci, intVals: applyVals(c.intVals, f)}, nil
+}
+
+// ApplyFloat returns a new column as for Apply but taking a function of
+// FloatVals. It will return an error if the column is not a float column.
+func (c Column) ApplyFloat(f func(FloatVal) FloatVal) (Column, error) {
+	if c.ci.colType != ColTypeFloat {
+		return Column{}, dfKindErrorf(ErrTypeMismatch,
+			"Applying a FloatVal function to a %q column", c.ci.colType)
+	}
+	return Column{ci: c.ci, floatVals: applyVals(c.floatVals, f)}, nil
+}
+
+// ApplyString returns a new column as for Apply but taking a function of
+// StringVals. It will return an error if the column is not a string
+// column.
+func (c Column) ApplyString(f func(StringVal) StringVal) (Column, error) {
+	if c.ci.colType != ColTypeString {
+		return Column{}, dfKindErrorf(ErrTypeMismatch,
+			"Applying a StringVal function to a %q column", c.ci.colType)
+	}
+	return Column{ci: c.ci, stringVals: applyVals(c.stringVals, f)}, nil
+}
@@ -0,0 +1,241 @@
+package dataframe
+
+import "fmt"
+
+// arithOp is an arithmetic operation which can be applied to numeric
+// columns
+type arithOp byte
+
+const (
+	opAdd arithOp = iota
+	opSub
+	opMul
+	opDiv
+)
+
+// symbol returns the symbol for the operation which is used in the name of
+// the resulting column
+func (op arithOp) symbol() string {
+	return [...]string{"+", "-", "*", "/"}[op]
+}
+
+// applyInt returns the result of the operation on the int values. It is
+// never called for division which always gives a float value.
+func (op arithOp) applyInt(x, y int64) int64 {
+	switch op {
+	case opAdd:
+		return x + y
+	case opSub:
+		return x - y
+	case opMul:
+		return x * y
+	}
+	panic(dfErrorf("Unexpected int operation: %q", op.symbol()))
+}
+
+// applyFloat returns the result of the operation on the float values
+func (op arithOp) applyFloat(x, y float64) float64 {
+	switch op {
+	case opAdd:
+		return x + y
+	case opSub:
+		return x - y
+	case opMul:
+		return x * y
+	}
+	return x / y
+}
+
+// arithOperand is one of the operands of an arithmetic operation: either a
+// column or a scalar value which is used for every row
+type arithOperand struct {
+	desc    string
+	colType ColType
+	rows    int // -1 for a scalar
+	intAt   func(i int) IntVal
+	floatAt func(i int) FloatVal
+}
+
+// colOperand returns the column as an operand. It returns an error if the
+// column is not numeric.
+func colOperand(c Column) (arithOperand, error) {
+	switch c.ci.colType {
+	case ColTypeInt:
+		return arithOperand{
+			desc:    c.ci.name,
+			colType: ColTypeInt,
+			rows:    len(c.intVals),
+			intAt:   func(i int) IntVal { return c.intVals[i] },
+			floatAt: func(i int) FloatVal {
+				v := c.intVals[i]
+				return FloatVal{Val: float64(v.Val), IsNA: v.IsNA}
+			},
+		}, nil
+	case ColTypeFloat:
+		return arithOperand{
+			desc:    c.ci.name,
+			colType: ColTypeFloat,
+			rows:    len(c.floatVals),
+			floatAt: func(i int) FloatVal { return c.floatVals[i] },
+		}, nil
+	}
+	return arithOperand{}, dfKindErrorf(ErrTypeMismatch,
+		"the column (%q) is of type %q, not a numeric type",
+		c.ci.name, c.ci.colType)
+}
+
+// scalarOperand returns the value as an operand. The value may be an int,
+// an int64 or an IntVal, giving an int operand, or a float64 or a FloatVal,
+// giving a float operand. Any other type gives an error.
+func scalarOperand(v any) (arithOperand, error) {
+	switch v.(type) {
+	case int, int64, IntVal:
+		iv, _ := intValOf(v)
+		return arithOperand{
+			desc:    iv.String(),
+			colType: ColTypeInt,
+			rows:    -1,
+			intAt:   func(int) IntVal { return iv },
+			floatAt: func(int) FloatVal {
+				return FloatVal{Val: float64(iv.Val), IsNA: iv.IsNA}
+			},
+		}, nil
+	case float64, FloatVal:
+		fv, _ := floatValOf(v)
+		return arithOperand{
+			desc:    fv.String(),
+			colType: ColTypeFloat,
+			rows:    -1,
+			floatAt: func(int) FloatVal { return fv },
+		}, nil
+	}
+	return arithOperand{}, dfKindErrorf(ErrTypeMismatch,
+		"the scalar value must be an int or a float, not a %T", v)
+}
+
+// arith returns a new column holding the result of applying the operation
+// to each row of the operands. The result is an int column if both
+// operands are ints and the operation is not a division, otherwise it is a
+// float column. If either value is NA the result is NA.
+func arith(a arithOperand, op arithOp, b arithOperand) (Column, error) {
+	rows := a.rows
+	if b.rows >= 0 && b.rows != rows {
+		return Column{}, dfKindErrorf(ErrDimensionMismatch,
+			"the columns have different lengths: %q has %d rows, %q has %d",
+			a.desc, a.rows, b.desc, b.rows)
+	}
+
+	c := Column{
+		ci: ColInfo{name: fmt.Sprintf("%s%s%s", a.desc, op.symbol(), b.desc)},
+	}
+	if a.colType == ColTypeInt && b.colType == ColTypeInt && op != opDiv {
+		c.ci.colType = ColTypeInt
+		c.intVals = make([]IntVal, 0, rows)
+		for i := 0; i < rows; i++ {
+			x, y := a.intAt(i), b.intAt(i)
+			if x.IsNA || y.IsNA {
+				c.intVals = append(c.intVals, IntVal{IsNA: true})
+				continue
+			}
+			c.intVals = append(c.intVals,
+				IntVal{Val: op.applyInt(x.Val, y.Val)})
+		}
+		return c, nil
+	}
+
+	c.ci.colType = ColTypeFloat
+	c.floatVals = make([]FloatVal, 0, rows)
+	for i := 0; i < rows; i++ {
+		x, y := a.floatAt(i), b.floatAt(i)
+		if x.IsNA || y.IsNA {
+			c.floatVals = append(c.floatVals, FloatVal{IsNA: true})
+			continue
+		}
+		c.floatVals = append(c.floatVals,
+			FloatVal{Val: op.applyFloat(x.Val, y.Val)})
+	}
+	return c, nil
+}
+
+// arithCol applies the operation to the columns
+func (c Column) arithCol(op arithOp, other Column) (Column, error) {
+	a, err := colOperand(c)
+	if err != nil {
+		return Column{}, err
+	}
+	b, err := colOperand(other)
+	if err != nil {
+		return Column{}, err
+	}
+	return arith(a, op, b)
+}
+
+// arithScalar applies the operation to the column and the scalar value
+func (c Column) arithScalar(op arithOp, v any) (Column, error) {
+	a, err := colOperand(c)
+	if err != nil {
+		return Column{}, err
+	}
+	b, err := scalarOperand(v)
+	if err != nil {
+		return Column{}, err
+	}
+	return arith(a, op, b)
+}
+
+// Add returns a new column holding the sum of the values in each row of
+// the two columns which must both be numeric and have the same number of
+// rows. The result is an int column if both columns are int columns,
+// otherwise it is a float column, and a row is NA if either value is NA.
+// The new column is named after the operation, for instance "a+b"; it can
+// be renamed with SetInfo and added to a dataframe with DF.AddCol.
+func (c Column) Add(other Column) (Column, error) {
+	return c.arithCol(opAdd, other)
+}
+
+// Sub returns a new column holding the value in each row of this column
+// less that in the other column, as for Add
+func (c Column) Sub(other Column) (Column, error) {
+	return c.arithCol(opSub, other)
+}
+
+// Mul returns a new column holding the product of the values in each row
+// of the two columns, as for Add
+func (c Column) Mul(other Column) (Column, error) {
+	return c.arithCol(opMul, other)
+}
+
+// Div returns a new column holding the value in each row of this column
+// divided by that in the other column, as for Add except that the result
+// is always a float column. Division by zero gives an infinite value (or
+// NaN if both values are zero) rather than NA.
+func (c Column) Div(other Column) (Column, error) {
+	return c.arithCol(opDiv, other)
+}
+
+// AddScalar returns a new column holding the sum of the value in each row
+// of the column and the scalar value, as for Add. The value may be an int,
+// an int64 or an IntVal, which is treated as an int column, or a float64
+// or a FloatVal, which is treated as a float column.
+func (c Column) AddScalar(v any) (Column, error) {
+	return c.arithScalar(opAdd, v)
+}
+
+// SubScalar returns a new column holding the value in each row of the
+// column less the scalar value, as for AddScalar
+func (c Column) SubScalar(v any) (Column, error) {
+	return c.arithScalar(opSub, v)
+}
+
+// MulScalar returns a new column holding the product of the value in each
+// row of the column and the scalar value, as for AddScalar
+func (c Column) MulScalar(v any) (Column, error) {
+	return c.arithScalar(opMul, v)
+}
+
+// DivScalar returns a new column holding the value in each row of the
+// column divided by the scalar value, as for AddScalar except that the
+// result is always a float column
+func (c Column) DivScalar(v any) (Column, error) {
+	return c.arithScalar(opDiv, v)
+}
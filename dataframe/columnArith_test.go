@@ -0,0 +1,120 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestColumnArith(t *testing.T) {
+	i := mkIntCol("i", 12, 7, 0)
+	i.MustAddIntVal(dataframe.IntVal{IsNA: true})
+	j := mkIntCol("j", 3, 2, 0, 5)
+	f := mkFloatCol("f", 0.5, 1.5, 2, 2.5)
+	s := mkStringCol("s", "a", "b", "c", "d")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		op      func() (dataframe.Column, error)
+		expName string
+		expType dataframe.ColType
+		expVals []string
+	}{
+		{
+			ID:      testhelper.MkID("int + int"),
+			op:      func() (dataframe.Column, error) { return i.Add(j) },
+			expName: "i+j",
+			expType: dataframe.ColTypeInt,
+			expVals: []string{"15", "9", "0", "NA"},
+		},
+		{
+			ID:      testhelper.MkID("int - float"),
+			op:      func() (dataframe.Column, error) { return i.Sub(f) },
+			expName: "i-f",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"11.5", "5.5", "-2", "NA"},
+		},
+		{
+			ID:      testhelper.MkID("float * int"),
+			op:      func() (dataframe.Column, error) { return f.Mul(j) },
+			expName: "f*j",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"1.5", "3", "0", "12.5"},
+		},
+		{
+			ID:      testhelper.MkID("int / int"),
+			op:      func() (dataframe.Column, error) { return i.Div(j) },
+			expName: "i/j",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"4", "3.5", "NaN", "NA"},
+		},
+		{
+			ID: testhelper.MkID("int + int scalar"),
+			op: func() (dataframe.Column, error) {
+				return j.AddScalar(10)
+			},
+			expName: "j+10",
+			expType: dataframe.ColTypeInt,
+			expVals: []string{"13", "12", "10", "15"},
+		},
+		{
+			ID: testhelper.MkID("int - float scalar"),
+			op: func() (dataframe.Column, error) {
+				return j.SubScalar(0.5)
+			},
+			expName: "j-0.5",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"2.5", "1.5", "-0.5", "4.5"},
+		},
+		{
+			ID: testhelper.MkID("float * NA scalar"),
+			op: func() (dataframe.Column, error) {
+				return f.MulScalar(dataframe.IntVal{IsNA: true})
+			},
+			expName: "f*NA",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"NA", "NA", "NA", "NA"},
+		},
+		{
+			ID:      testhelper.MkID("int / int scalar"),
+			op:      func() (dataframe.Column, error) { return j.DivScalar(2) },
+			expName: "j/2",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"1.5", "1", "0", "2.5"},
+		},
+		{
+			ID: testhelper.MkID("string column"),
+			ExpErr: testhelper.MkExpErr(
+				`the column ("s") is of type "String", not a numeric type`),
+			op: func() (dataframe.Column, error) { return i.Add(s) },
+		},
+		{
+			ID: testhelper.MkID("different lengths"),
+			ExpErr: testhelper.MkExpErr("the columns have different" +
+				` lengths: "j" has 4 rows, "k" has 1`),
+			op: func() (dataframe.Column, error) {
+				return j.Add(mkIntCol("k", 1))
+			},
+		},
+		{
+			ID: testhelper.MkID("bad scalar"),
+			ExpErr: testhelper.MkExpErr(
+				"the scalar value must be an int or a float, not a string"),
+			op: func() (dataframe.Column, error) { return j.AddScalar("1") },
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := tc.op()
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			name, ct := c.Info()
+			testhelper.DiffString(t, tc.IDStr(), "name", name, tc.expName)
+			testhelper.DiffString(t, tc.IDStr(), "type",
+				ct.String(), tc.expType.String())
+			testhelper.DiffStringSlice(t, tc.IDStr(), "values",
+				colVals(c), tc.expVals)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ConvPolicy determines what happens when a value cannot be converted to
+// the new type of a column
+type ConvPolicy uint
+
+// ConvStrict causes the conversion to fail with an error
+// ConvToNA causes the value to be replaced with an NA value
+const (
+	ConvStrict ConvPolicy = iota
+	ConvToNA
+)
+
+// Rename changes the name of the column. It returns an error if the name
+// is blank, in which case the column is unchanged.
+func (c *Column) Rename(name string) error {
+	if name == "" {
+		return dfErrorf("The column name is invalid: it must not be blank")
+	}
+	c.ci.name = name
+	return nil
+}
+
+// convertVal converts the value to the column type, returning nil for an
+// NA value. It returns an error if the value cannot be converted without
+// loss.
+func convertVal(v any, to ColType) (any, error) {
+	if _, isNA := keyOf(v); isNA {
+		return nil, nil
+	}
+
+	switch to {
+	case ColTypeString:
+		return fmt.Sprint(v), nil
+	case ColTypeBool:
+		switch v := v.(type) {
+		case BoolVal:
+			return v, nil
+		case IntVal:
+			if v.Val == 0 || v.Val == 1 {
+				return v.Val == 1, nil
+			}
+		case FloatVal:
+			if v.Val == 0 || v.Val == 1 {
+				return v.Val == 1, nil
+			}
+		case StringVal:
+			return strconv.ParseBool(v.Val)
+		}
+	case ColTypeInt:
+		switch v := v.(type) {
+		case BoolVal:
+			if v.Val {
+				return 1, nil
+			}
+			return 0, nil
+		case IntVal:
+			return v, nil
+		case FloatVal:
+			if v.Val == math.Trunc(v.Val) &&
+				v.Val >= math.MinInt64 && v.Val < math.MaxInt64 {
+				return int64(v.Val), nil
+			}
+		case StringVal:
+			return strconv.ParseInt(v.Val, 0, 64)
+		}
+	case ColTypeFloat:
+		switch v := v.(type) {
+		case BoolVal:
+			if v.Val {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		case IntVal, FloatVal:
+			return v, nil
+		case StringVal:
+			return strconv.ParseFloat(v.Val, 64)
+		}
+	}
+	return nil, dfErrorf("%v cannot be converted", v)
+}
+
+// Convert changes the type of the column, converting each value to the new
+// type. Bool values convert to 1 or 0 and any value converts to a string
+// as given by its String method. Strings are parsed as they are when a
+// dataframe is read. An int or a float converts to a bool only if it is 0
+// or 1 and a float converts to an int only if it is a whole number within
+// range. NA values stay NA. A value which cannot be converted gives an
+// error if the policy is ConvStrict, in which case the column is
+// unchanged, or an NA value if it is ConvToNA. It returns an error if the
+// new type is not valid.
+func (c *Column) Convert(to ColType, policy ConvPolicy) error {
+	if to <= ColTypeUnknown || to >= ColTypeMaxVal {
+		return dfErrorf("The column type is invalid: %s", to)
+	}
+
+	rval := Column{ci: ColInfo{name: c.ci.name, colType: to, meta: c.ci.meta}}
+	for i := 0; i < c.RowCount(); i++ {
+		v, _ := c.GetVal(i)
+		cv, err := convertVal(v, to)
+		if err != nil {
+			if policy != ConvToNA {
+				return dfKindErrorf(ErrTypeMismatch,
+					"row %d: cannot convert %q to %s", i, fmt.Sprint(v), to)
+			}
+			cv = nil
+		}
+		if err := rval.addVal(cv); err != nil {
+			return dfWrapf(err, "row %d", i)
+		}
+	}
+
+	*c = rval
+	return nil
+}
@@ -0,0 +1,115 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestColumnConvert(t *testing.T) {
+	withNA := mkStringCol("s", "12", "0x10", "x")
+	withNA.MustAddStringVal(dataframe.StringVal{IsNA: true})
+	var bools dataframe.Column
+	bools.MustSetInfo("b", dataframe.ColTypeBool)
+	bools.MustAddBoolVal(dataframe.BoolVal{Val: true})
+	bools.MustAddBoolVal(dataframe.BoolVal{Val: false})
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col     dataframe.Column
+		to      dataframe.ColType
+		policy  dataframe.ConvPolicy
+		expVals []string
+	}{
+		{
+			ID:      testhelper.MkID("string to int, to NA"),
+			col:     withNA,
+			to:      dataframe.ColTypeInt,
+			policy:  dataframe.ConvToNA,
+			expVals: []string{"12", "16", "NA", "NA"},
+		},
+		{
+			ID: testhelper.MkID("string to int, strict"),
+			ExpErr: testhelper.MkExpErr(
+				`row 2: cannot convert "x" to Int`),
+			col: withNA,
+			to:  dataframe.ColTypeInt,
+		},
+		{
+			ID:      testhelper.MkID("float to int"),
+			col:     mkFloatCol("f", 2, -3, 2.5),
+			to:      dataframe.ColTypeInt,
+			policy:  dataframe.ConvToNA,
+			expVals: []string{"2", "-3", "NA"},
+		},
+		{
+			ID:      testhelper.MkID("int to bool"),
+			col:     mkIntCol("i", 0, 1, 2),
+			to:      dataframe.ColTypeBool,
+			policy:  dataframe.ConvToNA,
+			expVals: []string{"false", "true", "NA"},
+		},
+		{
+			ID:      testhelper.MkID("bool to float"),
+			col:     bools,
+			to:      dataframe.ColTypeFloat,
+			expVals: []string{"1", "0"},
+		},
+		{
+			ID:      testhelper.MkID("int to float"),
+			col:     mkIntCol("i", 12, -3),
+			to:      dataframe.ColTypeFloat,
+			expVals: []string{"12", "-3"},
+		},
+		{
+			ID:      testhelper.MkID("float to string"),
+			col:     mkFloatCol("f", 2.5, 1e21),
+			to:      dataframe.ColTypeString,
+			expVals: []string{"2.5", "1e+21"},
+		},
+		{
+			ID: testhelper.MkID("bad type"),
+			ExpErr: testhelper.MkExpErr(
+				"The column type is invalid: Unknown"),
+			col: mkIntCol("i", 12),
+			to:  dataframe.ColTypeUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc.col
+		name, origType := c.Info()
+		err := c.Convert(tc.to, tc.policy)
+		if testhelper.CheckExpErr(t, err, tc) {
+			newName, ct := c.Info()
+			expType := tc.to
+			if err != nil {
+				expType = origType
+			}
+			testhelper.DiffString(t, tc.IDStr(), "name", newName, name)
+			testhelper.DiffString(t, tc.IDStr(), "type",
+				ct.String(), expType.String())
+			if err == nil {
+				testhelper.DiffStringSlice(t, tc.IDStr(), "values",
+					colVals(c), tc.expVals)
+			}
+		}
+	}
+}
+
+func TestColumnRename(t *testing.T) {
+	c := mkIntCol("i", 12)
+	if err := c.Rename("j"); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	name, _ := c.Info()
+	testhelper.DiffString(t, "Rename", "name", name, "j")
+
+	err := c.Rename("")
+	testhelper.CheckExpErrWithID(t, "Rename: blank name", err,
+		testhelper.MkExpErr("The column name is invalid"))
+	name, _ = c.Info()
+	testhelper.DiffString(t, "Rename: blank name", "name", name, "j")
+}
@@ -0,0 +1,99 @@
+package dataframe
+
+import "math"
+
+// checkNumeric returns an error if the column is not an int or a float
+// column
+func (c Column) checkNumeric(stat string) error {
+	if c.ci.colType != ColTypeInt && c.ci.colType != ColTypeFloat {
+		return dfKindErrorf(ErrTypeMismatch,
+			"the %s of a %q column cannot be calculated", stat, c.ci.colType)
+	}
+	return nil
+}
+
+// aggregate returns the value of the aggregation over the column
+func (c Column) aggregate(af AggFunc) (FloatVal, error) {
+	if err := c.checkNumeric(af.String()); err != nil {
+		return FloatVal{IsNA: true}, err
+	}
+
+	var acc aggAcc
+	for i := 0; i < c.RowCount(); i++ {
+		v, _ := c.GetVal(i)
+		acc.add(v)
+	}
+	return acc.val(af).(FloatVal), nil
+}
+
+// Sum returns the sum of the non-NA values in the column. The value is NA
+// if there are no non-NA values. It returns an error if the column is not
+// an int or a float column.
+func (c Column) Sum() (FloatVal, error) {
+	return c.aggregate(AggSum)
+}
+
+// Mean returns the mean of the non-NA values in the column, as for Sum
+func (c Column) Mean() (FloatVal, error) {
+	return c.aggregate(AggMean)
+}
+
+// Min returns the smallest of the non-NA values in the column, as for Sum
+func (c Column) Min() (FloatVal, error) {
+	return c.aggregate(AggMin)
+}
+
+// Max returns the largest of the non-NA values in the column, as for Sum
+func (c Column) Max() (FloatVal, error) {
+	return c.aggregate(AggMax)
+}
+
+// StdDev returns the sample standard deviation (with n-1 degrees of
+// freedom) of the non-NA values in the column. The value is NA if there
+// are fewer than two non-NA values. It returns an error if the column is
+// not an int or a float column.
+func (c Column) StdDev() (FloatVal, error) {
+	if err := c.checkNumeric("StdDev"); err != nil {
+		return FloatVal{IsNA: true}, err
+	}
+
+	vals := make([]float64, 0, c.RowCount())
+	for _, v := range c.intVals {
+		if !v.IsNA {
+			vals = append(vals, float64(v.Val))
+		}
+	}
+	for _, v := range c.floatVals {
+		if !v.IsNA {
+			vals = append(vals, v.Val)
+		}
+	}
+	if len(vals) < 2 {
+		return FloatVal{IsNA: true}, nil
+	}
+
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	mean := sum / float64(len(vals))
+
+	var sumSq float64
+	for _, v := range vals {
+		sumSq += (v - mean) * (v - mean)
+	}
+	return FloatVal{Val: math.Sqrt(sumSq / float64(len(vals)-1))}, nil
+}
+
+// NACount returns the number of NA values in the column. It may be used
+// with a column of any type.
+func (c Column) NACount() int {
+	count := 0
+	for i := 0; i < c.RowCount(); i++ {
+		v, _ := c.GetVal(i)
+		if _, isNA := keyOf(v); isNA {
+			count++
+		}
+	}
+	return count
+}
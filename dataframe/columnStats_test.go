@@ -0,0 +1,88 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestColumnStats(t *testing.T) {
+	withNA := mkFloatCol("f", 2, 4, 4, 4, 5, 5, 7, 9)
+	withNA.MustAddFloatVal(dataframe.FloatVal{IsNA: true})
+	allNA := mkIntCol("n")
+	allNA.MustAddIntVal(dataframe.IntVal{IsNA: true})
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col        dataframe.Column
+		expSum     string
+		expMean    string
+		expMin     string
+		expMax     string
+		expStdDev  string
+		expNACount int
+	}{
+		{
+			ID:         testhelper.MkID("float column with NA"),
+			col:        withNA,
+			expSum:     "40",
+			expMean:    "5",
+			expMin:     "2",
+			expMax:     "9",
+			expStdDev:  "2.138089935299395",
+			expNACount: 1,
+		},
+		{
+			ID:        testhelper.MkID("int column"),
+			col:       mkIntCol("i", 12, -3),
+			expSum:    "9",
+			expMean:   "4.5",
+			expMin:    "-3",
+			expMax:    "12",
+			expStdDev: "10.606601717798213",
+		},
+		{
+			ID:         testhelper.MkID("no values"),
+			col:        allNA,
+			expSum:     "NA",
+			expMean:    "NA",
+			expMin:     "NA",
+			expMax:     "NA",
+			expStdDev:  "NA",
+			expNACount: 1,
+		},
+		{
+			ID:  testhelper.MkID("string column"),
+			col: mkStringCol("s", "a"),
+			ExpErr: testhelper.MkExpErr(
+				`the Sum of a "String" column cannot be calculated`),
+		},
+	}
+
+	for _, tc := range testCases {
+		sum, err := tc.col.Sum()
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+		mean, _ := tc.col.Mean()
+		minVal, _ := tc.col.Min()
+		maxVal, _ := tc.col.Max()
+		sd, err := tc.col.StdDev()
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+		testhelper.DiffString(t, tc.IDStr(), "Sum", sum.String(), tc.expSum)
+		testhelper.DiffString(t, tc.IDStr(), "Mean",
+			mean.String(), tc.expMean)
+		testhelper.DiffString(t, tc.IDStr(), "Min",
+			minVal.String(), tc.expMin)
+		testhelper.DiffString(t, tc.IDStr(), "Max",
+			maxVal.String(), tc.expMax)
+		testhelper.DiffString(t, tc.IDStr(), "StdDev",
+			sd.String(), tc.expStdDev)
+		testhelper.DiffInt(t, tc.IDStr(), "NACount",
+			tc.col.NACount(), tc.expNACount)
+	}
+}
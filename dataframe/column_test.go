@@ -0,0 +1,377 @@
+package dataframe_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkIntCol returns an int column with the given name and values
+func mkIntCol(name string, vals ...int64) dataframe.Column {
+	var c dataframe.Column
+	c.MustSetInfo(name, dataframe.ColTypeInt)
+	for _, v := range vals {
+		c.MustAddIntVal(dataframe.IntVal{Val: v})
+	}
+	return c
+}
+
+// mkStringCol returns a string column with the given name and values
+func mkStringCol(name string, vals ...string) dataframe.Column {
+	var c dataframe.Column
+	c.MustSetInfo(name, dataframe.ColTypeString)
+	for _, v := range vals {
+		c.MustAddStringVal(dataframe.StringVal{Val: v})
+	}
+	return c
+}
+
+// mkFloatCol returns a float column with the given name and values
+func mkFloatCol(name string, vals ...float64) dataframe.Column {
+	var c dataframe.Column
+	c.MustSetInfo(name, dataframe.ColTypeFloat)
+	for _, v := range vals {
+		c.MustAddFloatVal(dataframe.FloatVal{Val: v})
+	}
+	return c
+}
+
+// colVals returns the values in the column as strings
+func colVals(c dataframe.Column) []string {
+	vals := make([]string, 0, c.RowCount())
+	for i := 0; i < c.RowCount(); i++ {
+		v, _ := c.GetVal(i)
+		vals = append(vals, fmt.Sprint(v))
+	}
+	return vals
+}
+
+func TestNewDFFromCols(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		cols    []dataframe.Column
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("good"),
+			cols: []dataframe.Column{
+				mkStringCol("s", "a", "b"),
+				mkIntCol("i", 1, 2),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"a", "1"}, {"b", "2"}},
+		},
+		{
+			ID:      testhelper.MkID("good - no columns"),
+			expVals: [][]string{},
+		},
+		{
+			ID: testhelper.MkID("bad - differing lengths"),
+			cols: []dataframe.Column{
+				mkStringCol("s", "a", "b"),
+				mkIntCol("i", 1),
+			},
+			ExpErr: testhelper.MkExpErr(
+				`column 1 ("i") has 1 rows, column 0 ("s") has 2`),
+		},
+		{
+			ID: testhelper.MkID("bad - duplicate names"),
+			cols: []dataframe.Column{
+				mkStringCol("s", "a"),
+				mkIntCol("s", 1),
+			},
+			ExpErr: testhelper.MkExpErr(
+				`duplicate column name: "s" is used for columns 0 and 1`),
+		},
+		{
+			ID: testhelper.MkID("bad - no name"),
+			cols: []dataframe.Column{
+				mkStringCol("", "a"),
+			},
+			ExpErr: testhelper.MkExpErr(
+				`column 0: The column name is invalid: it must not be blank`),
+		},
+		{
+			ID: testhelper.MkID("bad - no type"),
+			cols: []dataframe.Column{
+				{},
+			},
+			ExpErr: testhelper.MkExpErr(`column 0:`),
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.NewDFFromCols(tc.cols...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
+
+func TestDFAddCol(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		df      *dataframe.DF
+		col     dataframe.Column
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:  testhelper.MkID("good"),
+			df:  mkTestDF(t, "s\na\nb\n", dataframe.HasHeader),
+			col: mkIntCol("i", 1, 2),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"a", "1"}, {"b", "2"}},
+		},
+		{
+			ID:  testhelper.MkID("good - empty dataframe"),
+			df:  &dataframe.DF{},
+			col: mkIntCol("i", 1, 2, 3),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"1"}, {"2"}, {"3"}},
+		},
+		{
+			ID:  testhelper.MkID("bad - wrong length"),
+			df:  mkTestDF(t, "s\na\nb\n", dataframe.HasHeader),
+			col: mkIntCol("i", 1),
+			ExpErr: testhelper.MkExpErr(
+				`the column ("i") has 1 rows, the dataframe has 2`),
+		},
+		{
+			ID:  testhelper.MkID("bad - duplicate name"),
+			df:  mkTestDF(t, "s\na\nb\n", dataframe.HasHeader),
+			col: mkStringCol("s", "x", "y"),
+			ExpErr: testhelper.MkExpErr(
+				`Column name already used: Column 0 ("s": "String")`),
+		},
+		{
+			ID:     testhelper.MkID("bad - no type"),
+			df:     mkTestDF(t, "s\na\nb\n", dataframe.HasHeader),
+			ExpErr: testhelper.MkExpErr(`The column name is invalid`),
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.df.AddCol(tc.col)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), tc.df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), tc.df, tc.expVals)
+		}
+	}
+}
+
+func TestDFColByName(t *testing.T) {
+	df := mkTestDF(t, "s i\na 12\nb 13\n", dataframe.HasHeader)
+	if err := df.Compress("s"); err != nil {
+		t.Fatal("BAD TEST - cannot compress the column: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		name    string
+		expType dataframe.ColType
+		expVals []string
+	}{
+		{
+			ID:      testhelper.MkID("int column"),
+			name:    "i",
+			expType: dataframe.ColTypeInt,
+			expVals: []string{"12", "13"},
+		},
+		{
+			ID:      testhelper.MkID("compressed string column"),
+			name:    "s",
+			expType: dataframe.ColTypeString,
+			expVals: []string{"a", "b"},
+		},
+		{
+			ID:     testhelper.MkID("no such column"),
+			name:   "nonesuch",
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := df.ColByName(tc.name)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			name, ct := c.Info()
+			testhelper.DiffString(t, tc.IDStr(), "name", name, tc.name)
+			testhelper.DiffString(t, tc.IDStr(), "type",
+				ct.String(), tc.expType.String())
+			testhelper.DiffStringSlice(t, tc.IDStr(), "values",
+				colVals(c), tc.expVals)
+		}
+	}
+
+	_, err := df.ColByIdx(2)
+	testhelper.CheckExpErrWithID(t, "ColByIdx: bad index", err,
+		testhelper.MkExpErr("There is no column 2 (valid range: 0-1)"))
+
+	headerOnly := mkTestDF(t, "a b\n", dataframe.HasHeader)
+	_, err = headerOnly.ColByName("b")
+	testhelper.CheckExpErrWithID(t, "ColByName: header only", err,
+		testhelper.MkExpErr(`the column named "b" has no type`))
+}
+
+func TestDFColByNameRoundTrip(t *testing.T) {
+	df := mkTestDF(t, "s i\na 12\nb 13\n", dataframe.HasHeader)
+	c, err := df.ColByName("i")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	c.MustSetInfo("j", dataframe.ColTypeInt)
+	if err := df.AddCol(c); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	checkDFVals(t, "round trip", df,
+		[][]string{{"a", "12", "12"}, {"b", "13", "13"}})
+}
+
+func TestColumnAddValErrors(t *testing.T) {
+	var c dataframe.Column
+	err := c.SetInfo("x", dataframe.ColTypeUnknown)
+	testhelper.CheckExpErrWithID(t, "SetInfo: bad type", err,
+		testhelper.MkExpErr("Unexpected column type"))
+	c.MustSetInfo("i", dataframe.ColTypeInt)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		add func() error
+	}{
+		{
+			ID:  testhelper.MkID("int"),
+			add: func() error { return c.AddIntVal(dataframe.IntVal{Val: 1}) },
+		},
+		{
+			ID: testhelper.MkID("bool"),
+			add: func() error {
+				return c.AddBoolVal(dataframe.BoolVal{Val: true})
+			},
+			ExpErr: testhelper.MkExpErr(`Adding a BoolVal to a "Int" column`),
+		},
+		{
+			ID: testhelper.MkID("float"),
+			add: func() error {
+				return c.AddFloatVal(dataframe.FloatVal{Val: 1.5})
+			},
+			ExpErr: testhelper.MkExpErr(`Adding a FloatVal to a "Int" column`),
+		},
+		{
+			ID: testhelper.MkID("string"),
+			add: func() error {
+				return c.AddStringVal(dataframe.StringVal{Val: "a"})
+			},
+			ExpErr: testhelper.MkExpErr(`Adding a StringVal to a "Int" column`),
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.add()
+		if testhelper.CheckExpErr(t, err, tc) && err != nil &&
+			!errors.Is(err, dataframe.ErrTypeMismatch) {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the error should match ErrTypeMismatch")
+		}
+	}
+	testhelper.DiffInt(t, "add vals", "row count", c.RowCount(), 1)
+
+	_, err = dataframe.NewColInfo("x", dataframe.ColTypeMaxVal)
+	testhelper.CheckExpErrWithID(t, "NewColInfo: bad type", err,
+		testhelper.MkExpErr("Unexpected column type"))
+}
+
+func TestColumnApply(t *testing.T) {
+	i := mkIntCol("i", 12, 7, 3)
+	s := mkStringCol("s", "a", "bc", "")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		op      func() (dataframe.Column, error)
+		expVals []string
+	}{
+		{
+			ID: testhelper.MkID("Apply - int"),
+			op: func() (dataframe.Column, error) {
+				return i.Apply(func(v any) (any, error) {
+					iv := v.(dataframe.IntVal)
+					if iv.Val == 7 {
+						return nil, nil
+					}
+					return iv.Val * 2, nil
+				})
+			},
+			expVals: []string{"24", "NA", "6"},
+		},
+		{
+			ID: testhelper.MkID("Apply - error"),
+			ExpErr: testhelper.MkExpErr("row 1",
+				"cannot convert a value of type string into an IntVal"),
+			op: func() (dataframe.Column, error) {
+				return i.Apply(func(v any) (any, error) {
+					if v.(dataframe.IntVal).Val == 7 {
+						return "x", nil
+					}
+					return v, nil
+				})
+			},
+		},
+		{
+			ID: testhelper.MkID("ApplyInt"),
+			op: func() (dataframe.Column, error) {
+				return i.ApplyInt(func(v dataframe.IntVal) dataframe.IntVal {
+					v.Val = -v.Val
+					return v
+				})
+			},
+			expVals: []string{"-12", "-7", "-3"},
+		},
+		{
+			ID: testhelper.MkID("ApplyString"),
+			op: func() (dataframe.Column, error) {
+				return s.ApplyString(
+					func(v dataframe.StringVal) dataframe.StringVal {
+						v.IsNA = v.Val == ""
+						return v
+					})
+			},
+			expVals: []string{"a", "bc", "NA"},
+		},
+		{
+			ID: testhelper.MkID("ApplyFloat - wrong type"),
+			ExpErr: testhelper.MkExpErr(
+				`Applying a FloatVal function to a "String" column`),
+			op: func() (dataframe.Column, error) {
+				return s.ApplyFloat(
+					func(v dataframe.FloatVal) dataframe.FloatVal { return v })
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := tc.op()
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffStringSlice(t, tc.IDStr(), "values",
+				colVals(c), tc.expVals)
+		}
+	}
+	testhelper.DiffStringSlice(t, "after Apply", "original values",
+		colVals(i), []string{"12", "7", "3"})
+}
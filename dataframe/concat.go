@@ -0,0 +1,142 @@
+package dataframe
+
+// concatOpts holds the settings controlling how Concat reconciles the
+// column types
+type concatOpts struct {
+	noPromotion bool
+	numericOnly bool
+}
+
+// ConcatOpt is the type of an option function for Concat
+type ConcatOpt func(*concatOpts) error
+
+// ConcatStrictTypes is a function which will cause Concat to return an
+// error if a column has different types in different dataframes
+func ConcatStrictTypes(o *concatOpts) error {
+	o.noPromotion = true
+	return nil
+}
+
+// ConcatNumericOnly is a function which will cause Concat to promote int
+// columns to float columns where needed but to return an error for any
+// other difference in the column types rather than making a string column
+func ConcatNumericOnly(o *concatOpts) error {
+	o.numericOnly = true
+	return nil
+}
+
+// promote returns the type of a column holding values of both types or
+// false if the types cannot be reconciled
+func (o concatOpts) promote(ct, other ColType) (ColType, bool) {
+	if ct == other {
+		return ct, true
+	}
+	if o.noPromotion {
+		return ct, false
+	}
+	if ct, ok := mergeRecordType(ct, other); ok {
+		return ct, true
+	}
+	if o.numericOnly {
+		return ct, false
+	}
+	return ColTypeString, true
+}
+
+// concatColInfo returns the columns of the concatenated dataframes: every
+// column in any of the dataframes in the order in which they first appear
+// with the types reconciled
+func concatColInfo(dfs []*DF, o concatOpts) ([]ColInfo, error) {
+	var cis []ColInfo
+	idx := map[string]int{}
+
+	for i, df := range dfs {
+		if df == nil {
+			return nil, dfErrorf("dataframe %d is nil", i)
+		}
+		for _, ci := range df.mci.info {
+			j, ok := idx[ci.name]
+			if !ok {
+				idx[ci.name] = len(cis)
+				cis = append(cis, ci)
+				continue
+			}
+			ct, ok := o.promote(cis[j].colType, ci.colType)
+			if !ok {
+				return nil, dfKindErrorf(ErrTypeMismatch,
+					"column %q: the type in dataframe %d (%s)"+
+						" doesn't match the type (%s) in an earlier dataframe",
+					ci.name, i, ci.colType, cis[j].colType)
+			}
+			cis[j].colType = ct
+		}
+	}
+	return cis, nil
+}
+
+// Concat returns a new dataframe holding the rows of each of the
+// dataframes in turn. The new dataframe has every column found in any of
+// the dataframes, in the order in which they first appear; a dataframe
+// without one of the columns gives NA values for it. The column metadata
+// is taken from the first dataframe with the column. The dataframe
+// metadata is merged, taking the value of each key from the first
+// dataframe that has it.
+//
+// Where a column has different types in different dataframes the values
+// are converted: a mix of int and float columns gives a float column and
+// any other mix gives a string column, holding the values as given by
+// their String methods. The options can prevent this (see
+// ConcatStrictTypes and ConcatNumericOnly) in which case an error is
+// returned.
+func Concat(dfs []*DF, opts ...ConcatOpt) (*DF, error) {
+	var o concatOpts
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	cis, err := concatColInfo(dfs, o)
+	if err != nil {
+		return nil, err
+	}
+	rval, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, df := range dfs {
+		for k, v := range df.meta {
+			if _, ok := rval.meta[k]; !ok {
+				if err := rval.SetMeta(k, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		srcIdx := make([]int, len(cis))
+		for c, ci := range cis {
+			srcIdx[c] = -1
+			if i, ok := df.mci.nameToCol[ci.name]; ok {
+				srcIdx[c] = i
+			}
+		}
+
+		for r := 0; r < df.RowCount(); r++ {
+			for c, ci := range cis {
+				var v any
+				if srcIdx[c] >= 0 {
+					v, _ = df.valAt(srcIdx[c], r)
+					if v, err = convertVal(v, ci.colType); err != nil {
+						return nil, dfWrapf(err, "column %q", ci.name)
+					}
+				}
+				if err := rval.appendVal(c, v); err != nil {
+					return nil, dfWrapf(err, "column %q", ci.name)
+				}
+			}
+		}
+	}
+
+	return rval, nil
+}
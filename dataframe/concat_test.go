@@ -0,0 +1,109 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestConcat(t *testing.T) {
+	dfA := mkTestDF(t, "id x flag\n12 1.5 true\n13 2 false\n",
+		dataframe.HasHeader,
+		dataframe.DFRColMeta("x", dataframe.MetaUnit, "m"))
+	if err := dfA.SetMeta("source", "a"); err != nil {
+		t.Fatal("BAD TEST - cannot set the metadata: ", err)
+	}
+	dfB := mkTestDF(t, "x id note flag\n14 a hello 17\n",
+		dataframe.HasHeader)
+	if err := dfB.SetMeta("source", "b"); err != nil {
+		t.Fatal("BAD TEST - cannot set the metadata: ", err)
+	}
+	if err := dfB.SetMeta("extra", "b"); err != nil {
+		t.Fatal("BAD TEST - cannot set the metadata: ", err)
+	}
+	dfC := mkTestDF(t, "x\n2.5\n", dataframe.HasHeader)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		dfs     []*dataframe.DF
+		opts    []dataframe.ConcatOpt
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:  testhelper.MkID("promotion"),
+			dfs: []*dataframe.DF{dfA, dfB},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("id", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("x", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("flag", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("note", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"12", "1.5", "true", "NA"},
+				{"13", "2", "false", "NA"},
+				{"a", "14", "17", "hello"},
+			},
+		},
+		{
+			ID:   testhelper.MkID("numeric only"),
+			dfs:  []*dataframe.DF{dfC, dfA},
+			opts: []dataframe.ConcatOpt{dataframe.ConcatNumericOnly},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("x", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("id", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("flag", dataframe.ColTypeBool),
+			},
+			expVals: [][]string{
+				{"2.5", "NA", "NA"},
+				{"1.5", "12", "true"},
+				{"2", "13", "false"},
+			},
+		},
+		{
+			ID:   testhelper.MkID("numeric only - bad"),
+			dfs:  []*dataframe.DF{dfA, dfB},
+			opts: []dataframe.ConcatOpt{dataframe.ConcatNumericOnly},
+			ExpErr: testhelper.MkExpErr(`column "id": the type in` +
+				" dataframe 1 (String) doesn't match the type (Int)" +
+				" in an earlier dataframe"),
+		},
+		{
+			ID:   testhelper.MkID("strict"),
+			dfs:  []*dataframe.DF{dfA, dfB},
+			opts: []dataframe.ConcatOpt{dataframe.ConcatStrictTypes},
+			ExpErr: testhelper.MkExpErr(`column "x": the type in` +
+				" dataframe 1 (Int) doesn't match the type (Float)"),
+		},
+		{
+			ID:     testhelper.MkID("nil dataframe"),
+			dfs:    []*dataframe.DF{dfA, nil},
+			ExpErr: testhelper.MkExpErr("dataframe 1 is nil"),
+		},
+		{
+			ID:      testhelper.MkID("no dataframes"),
+			expVals: [][]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.Concat(tc.dfs, tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+
+	df, err := dataframe.Concat([]*dataframe.DF{dfA, dfB})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	unit, _, _ := df.ColMeta("x", dataframe.MetaUnit)
+	testhelper.DiffString(t, "metadata", "column unit", unit, "m")
+	src, _ := df.Meta("source")
+	testhelper.DiffString(t, "metadata", "source", src, "a")
+	extra, _ := df.Meta("extra")
+	testhelper.DiffString(t, "metadata", "extra", extra, "b")
+}
@@ -0,0 +1,70 @@
+package dataframe
+
+import "strings"
+
+// currencyFmt records the currency symbol and the digit grouping
+// characters to be removed from the values in a currency column
+type currencyFmt struct {
+	symbol    string
+	groupSeps string
+}
+
+// strip returns the value with any sign kept but the currency symbol,
+// which may come before or after the number, and any grouping characters
+// removed. A value without the symbol is allowed.
+func (cf currencyFmt) strip(s string) string {
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+
+	if strings.HasPrefix(s, cf.symbol) {
+		s = s[len(cf.symbol):]
+	} else if strings.HasSuffix(s, cf.symbol) {
+		s = s[:len(s)-len(cf.symbol)]
+	}
+	s = strings.TrimSpace(s)
+
+	if cf.groupSeps != "" {
+		s = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(cf.groupSeps, r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	return sign + s
+}
+
+// DFRCurrency returns a function which will cause the DFReader to read the
+// values in the named column as currency amounts: the symbol, which may
+// come before or after the number, and any of the digit grouping
+// characters in groupSeps are removed before the value is parsed. For
+// instance, with a symbol of "$" and a groupSeps of ",", the value
+// "$1,234.56" is read as 1234.56 and "-$5" as -5. The column is guessed to
+// be a float column and the symbol is recorded as its MetaCurrency
+// metadata. It is an error if the data read has no column with that name
+// or if the name or symbol is empty.
+func DFRCurrency(name, symbol, groupSeps string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if name == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the currency column name must not be empty")
+		}
+		if symbol == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the currency symbol for column %q"+
+					" must not be empty", name)
+		}
+
+		currency := make(map[string]currencyFmt,
+			len(dfr.parseOpts.currency)+1)
+		for k, v := range dfr.parseOpts.currency {
+			currency[k] = v
+		}
+		currency[name] = currencyFmt{symbol: symbol, groupSeps: groupSeps}
+		dfr.parseOpts.currency = currency
+
+		return DFRColMeta(name, MetaCurrency, symbol)(dfr)
+	}
+}
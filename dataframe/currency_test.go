@@ -0,0 +1,123 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRCurrency(t *testing.T) {
+	const content = `item;price;cost
+widget;$1,234.56;10 €
+gadget;-$5;1,234 €
+doohickey;$-0.5;7€
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("no currency"),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("item", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("cost", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"widget", "$1,234.56", "10 €"},
+				{"gadget", "-$5", "1,234 €"},
+				{"doohickey", "$-0.5", "7€"},
+			},
+		},
+		{
+			ID: testhelper.MkID("dollars"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCurrency("price", "$", ","),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("item", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeFloat).
+					WithMeta(dataframe.MetaCurrency, "$"),
+				dataframe.MustNewColInfo("cost", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"widget", "1234.56", "10 €"},
+				{"gadget", "-5", "1,234 €"},
+				{"doohickey", "-0.5", "7€"},
+			},
+		},
+		{
+			ID: testhelper.MkID("dollars and euros"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCurrency("price", "$", ","),
+				dataframe.DFRCurrency("cost", "€", ","),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("item", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeFloat).
+					WithMeta(dataframe.MetaCurrency, "$"),
+				dataframe.MustNewColInfo("cost", dataframe.ColTypeFloat).
+					WithMeta(dataframe.MetaCurrency, "€"),
+			},
+			expVals: [][]string{
+				{"widget", "1234.56", "10"},
+				{"gadget", "-5", "1234"},
+				{"doohickey", "-0.5", "7"},
+			},
+		},
+		{
+			ID: testhelper.MkID("empty symbol"),
+			ExpErr: testhelper.MkExpErr(
+				`the currency symbol for column "price" must not be empty`),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCurrency("price", "", ","),
+			},
+		},
+		{
+			ID: testhelper.MkID("empty name"),
+			ExpErr: testhelper.MkExpErr(
+				"the currency column name must not be empty"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCurrency("", "$", ","),
+			},
+		},
+		{
+			ID: testhelper.MkID("no such column"),
+			ExpErr: testhelper.MkExpErr(
+				"test data: cannot set the column metadata",
+				`Unknown column name: "nonesuch"`),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCurrency("nonesuch", "$", ","),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DFReaderOpt{
+			dataframe.HasHeader,
+			dataframe.SplitPattern(";"),
+		}, tc.opts...)
+		dfr, err := dataframe.NewDFReader(opts...)
+		if err == nil {
+			var df *dataframe.DF
+			df, err = dfr.Read(strings.NewReader(content), "test data")
+			if testhelper.CheckExpErr(t, err, tc) && err == nil {
+				checkColDetails(t, tc.IDStr(), df, tc.expCols)
+				checkDFVals(t, tc.IDStr(), df, tc.expVals)
+				for i, ci := range df.Columns() {
+					act, _ := ci.Meta(dataframe.MetaCurrency)
+					exp, _ := tc.expCols[i].Meta(dataframe.MetaCurrency)
+					testhelper.DiffString(t, tc.IDStr(),
+						ci.Name()+" currency", act, exp)
+				}
+			}
+			continue
+		}
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
@@ -0,0 +1,99 @@
+package dataframe
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// cutLabels returns the default labels for the bins given by the edges,
+// showing the range of each bin, such as "[10, 20)"
+func cutLabels(edges []float64) []string {
+	labels := make([]string, 0, len(edges)-1)
+	for i := 1; i < len(edges); i++ {
+		end := ")"
+		if i == len(edges)-1 {
+			end = "]"
+		}
+		labels = append(labels, "["+
+			strconv.FormatFloat(edges[i-1], 'g', -1, 64)+", "+
+			strconv.FormatFloat(edges[i], 'g', -1, 64)+end)
+	}
+	return labels
+}
+
+// Cut returns a string column holding, for each value in the named
+// column, the label of the bin into which the value falls. This is the
+// usual way to turn continuous values into buckets for reporting. The
+// edges give the bounds of the bins and must be in increasing order; each
+// bin includes its lower edge but not its upper edge, except for the last
+// which includes both. So edges of 0, 10 and 20 give two bins, 0 to just
+// below 10 and 10 to 20. Values outside the edges, NaN values and NA values
+// give NA.
+//
+// There must be one label for each bin. If no labels are given then each
+// bin is labelled with its range, "[0, 10)" and "[10, 20]" in the example
+// above. The column is named after the binned column with the suffix
+// "_Cut"; it can be renamed (see Column.Rename) before it is added to the
+// dataframe (see AddCol). As the column will typically hold a few distinct
+// values it may be worth compressing it once it has been added (see
+// Compress).
+//
+// It returns an error if there is no such column, if it is not an int or a
+// float column, if there are fewer than two edges or they are not in
+// increasing order or if the number of labels is wrong.
+func (df *DF) Cut(col string, edges []float64, labels []string) (
+	Column, error,
+) {
+	c, err := df.ColByName(col)
+	if err != nil {
+		return Column{}, err
+	}
+	if ct := c.ci.colType; ct != ColTypeInt && ct != ColTypeFloat {
+		return Column{}, dfKindErrorf(ErrTypeMismatch,
+			"the column named %q is of type %q, only %q and %q columns"+
+				" can be cut",
+			c.ci.name, ct, ColTypeInt, ColTypeFloat)
+	}
+	if len(edges) < 2 {
+		return Column{}, dfErrorf(
+			"there must be at least 2 edges, not %d", len(edges))
+	}
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i-1] < edges[i]) {
+			return Column{}, dfErrorf(
+				"the edges must be in increasing order: edge %d (%g)"+
+					" is not greater than edge %d (%g)",
+				i, edges[i], i-1, edges[i-1])
+		}
+	}
+	if labels == nil {
+		labels = cutLabels(edges)
+	} else if len(labels) != len(edges)-1 {
+		return Column{}, dfKindErrorf(ErrDimensionMismatch,
+			"there must be one label for each of the %d bins, not %d",
+			len(edges)-1, len(labels))
+	}
+
+	rval := Column{
+		ci: ColInfo{name: c.ci.name + "_Cut", colType: ColTypeString},
+	}
+	rval.stringVals = make([]StringVal, 0, c.RowCount())
+	last := len(edges) - 1
+	for i := 0; i < c.RowCount(); i++ {
+		v, _ := c.GetVal(i)
+		fv, _ := floatValOf(v)
+		if fv.IsNA || math.IsNaN(fv.Val) ||
+			fv.Val < edges[0] || fv.Val > edges[last] {
+			rval.stringVals = append(rval.stringVals, StringVal{IsNA: true})
+			continue
+		}
+		bin := sort.SearchFloat64s(edges, fv.Val)
+		if edges[bin] != fv.Val || bin == last {
+			bin--
+		}
+		rval.stringVals = append(rval.stringVals, StringVal{Val: labels[bin]})
+	}
+
+	return rval, nil
+}
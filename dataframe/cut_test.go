@@ -0,0 +1,99 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestCut(t *testing.T) {
+	df := mkTestDF(t, "sym qty px\nabc -5 0.5\ndef 0 9.99\nghi 10 10\n"+
+		"jkl 20 25.5\nmno 21 NA\npqr 22 NaN\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeString,
+			dataframe.ColTypeInt, dataframe.ColTypeFloat))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col     string
+		edges   []float64
+		labels  []string
+		expName string
+		expVals []string
+	}{
+		{
+			ID:      testhelper.MkID("int column, default labels"),
+			col:     "qty",
+			edges:   []float64{0, 10, 20},
+			expName: "qty_Cut",
+			expVals: []string{
+				"NA", "[0, 10)", "[10, 20]", "[10, 20]", "NA", "NA",
+			},
+		},
+		{
+			ID:      testhelper.MkID("float column, NA and NaN, labels given"),
+			col:     "px",
+			edges:   []float64{0, 1, 10, 100},
+			labels:  []string{"small", "medium", "large"},
+			expName: "px_Cut",
+			expVals: []string{
+				"small", "medium", "large", "large", "NA", "NA",
+			},
+		},
+		{
+			ID: testhelper.MkID("bad: string column"),
+			ExpErr: testhelper.MkExpErr(`the column named "sym"`,
+				"can be cut"),
+			col:   "sym",
+			edges: []float64{0, 1},
+		},
+		{
+			ID:     testhelper.MkID("bad: no such column"),
+			ExpErr: testhelper.MkExpErr(`"nonesuch"`),
+			col:    "nonesuch",
+			edges:  []float64{0, 1},
+		},
+		{
+			ID: testhelper.MkID("bad: too few edges"),
+			ExpErr: testhelper.MkExpErr(
+				"there must be at least 2 edges, not 1"),
+			col:   "qty",
+			edges: []float64{0},
+		},
+		{
+			ID: testhelper.MkID("bad: edges out of order"),
+			ExpErr: testhelper.MkExpErr(
+				"the edges must be in increasing order:" +
+					" edge 2 (10) is not greater than edge 1 (10)"),
+			col:   "qty",
+			edges: []float64{0, 10, 10},
+		},
+		{
+			ID: testhelper.MkID("bad: labels"),
+			ExpErr: testhelper.MkExpErr(
+				"there must be one label for each of the 2 bins, not 1"),
+			col:    "qty",
+			edges:  []float64{0, 10, 20},
+			labels: []string{"low"},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := df.Cut(tc.col, tc.edges, tc.labels)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			name, ct := c.Info()
+			testhelper.DiffString(t, tc.IDStr(), "name", name, tc.expName)
+			testhelper.DiffString(t, tc.IDStr(), "type",
+				ct.String(), dataframe.ColTypeString.String())
+			vals := make([]string, 0, c.RowCount())
+			for i := 0; i < c.RowCount(); i++ {
+				v, _ := c.GetStringVal(i)
+				vals = append(vals, v.String())
+			}
+			testhelper.DiffStringSlice(t, tc.IDStr(), "values",
+				vals, tc.expVals)
+		}
+	}
+}
@@ -12,11 +12,32 @@ type DF struct {
 	boolCols   [][]BoolVal
 	stringCols [][]StringVal
 
+	rleBoolCols   map[int]*rleVals[BoolVal]
+	rleStringCols map[int]*rleVals[StringVal]
+
+	indexes   map[int]*colIndex
+	summaries map[int]*colSummary
+
+	// keyCol is the index of the column set by SetIndex, if hasKeyCol is
+	// true
+	keyCol    int
+	hasKeyCol bool
+
+	meta map[string]string
+
 	// TODO: Consider whether the error details sit properly in the dataframe
 	// or whether they should be a return value from the ReadTable funcs
-	errors    []error
-	maxErrors int
-	errCount  int64
+	errors      []error
+	maxErrors   int
+	errCount    int64
+	colErrCount map[int]int64
+	errSummary  map[errSummaryKey]*errSummaryVal
+
+	// badRows holds the lines which gave errors when the dataframe was
+	// read (see DFRQuarantine) and errHook, if set, is called with each
+	// error as it is added
+	badRows *DF
+	errHook func(error)
 }
 
 // RowCount returns the number of rows in the dataframe
@@ -24,18 +45,24 @@ func (df *DF) RowCount() int {
 	if len(df.mci.valIdx) == 0 {
 		return 0
 	}
-	i := df.mci.valIdx[0]
-	colType := df.mci.info[0].colType
+	return df.colLen(0)
+}
+
+// colLen returns the number of values in the indexed column. This is
+// normally the number of rows but may differ while a row is being added.
+func (df *DF) colLen(colIdx int) int {
+	i := df.mci.valIdx[colIdx]
+	colType := df.mci.info[colIdx].colType
 
 	switch colType {
 	case ColTypeBool:
-		return len(df.boolCols[i])
+		return df.boolColLen(i)
 	case ColTypeInt:
 		return len(df.intCols[i])
 	case ColTypeFloat:
 		return len(df.floatCols[i])
 	case ColTypeString:
-		return len(df.stringCols[i])
+		return df.stringColLen(i)
 	}
 
 	panic(fmt.Sprintf("Unexpected column type: %d", colType))
@@ -55,7 +82,7 @@ func (df *DF) Row(i int) *Row {
 		switch cinfo.colType {
 		case ColTypeBool:
 			r.rd.boolVals = append(r.rd.boolVals,
-				df.boolCols[df.mci.valIdx[cidx]][i])
+				df.boolAt(df.mci.valIdx[cidx], i))
 		case ColTypeInt:
 			r.rd.intVals = append(r.rd.intVals,
 				df.intCols[df.mci.valIdx[cidx]][i])
@@ -64,7 +91,7 @@ func (df *DF) Row(i int) *Row {
 				df.floatCols[df.mci.valIdx[cidx]][i])
 		case ColTypeString:
 			r.rd.stringVals = append(r.rd.stringVals,
-				df.stringCols[df.mci.valIdx[cidx]][i])
+				df.stringAt(df.mci.valIdx[cidx], i))
 		}
 	}
 	return r
@@ -130,8 +157,9 @@ func (df *DF) RowNA() *Row {
 }
 
 // Clone creates an empty copy of the dataframe with the same column details
-// and column instances but with no data. The error values are all set to
-// their respective zero values.
+// and column instances but with no data. Any compressed columns will also be
+// compressed in the copy and the metadata (see SetMeta) is copied. The
+// error values are all set to their respective zero values.
 func (df *DF) Clone() *DF {
 	cloneVal := &DF{
 		mci:        df.mci.Clone(),
@@ -139,14 +167,30 @@ func (df *DF) Clone() *DF {
 		boolCols:   make([][]BoolVal, len(df.boolCols)),
 		intCols:    make([][]IntVal, len(df.intCols)),
 		stringCols: make([][]StringVal, len(df.stringCols)),
+		meta:       cloneMeta(df.meta),
 	}
+
+	for vi := range df.rleBoolCols {
+		if cloneVal.rleBoolCols == nil {
+			cloneVal.rleBoolCols = map[int]*rleVals[BoolVal]{}
+		}
+		cloneVal.rleBoolCols[vi] = &rleVals[BoolVal]{}
+	}
+	for vi := range df.rleStringCols {
+		if cloneVal.rleStringCols == nil {
+			cloneVal.rleStringCols = map[int]*rleVals[StringVal]{}
+		}
+		cloneVal.rleStringCols[vi] = &rleVals[StringVal]{}
+	}
+
 	return cloneVal
 }
 
 // assertTypeByName checks that actual == want and returns an error if not.
 func assertTypeByName(actual, want ColType, name string) error {
 	if actual != want {
-		return dfErrorf("The column named %q is of type %q not %q",
+		return dfKindErrorf(ErrTypeMismatch,
+			"The column named %q is of type %q not %q",
 			name, actual, want)
 	}
 	return nil
@@ -155,146 +199,375 @@ func assertTypeByName(actual, want ColType, name string) error {
 // assertTypeByIdx checks that actual == want and returns an error if not.
 func assertTypeByIdx(actual, want ColType, idx int) error {
 	if actual != want {
-		return dfErrorf("The column with index %d is of type %q not %q",
+		return dfKindErrorf(ErrTypeMismatch,
+			"The column with index %d is of type %q not %q",
 			idx, actual, want)
 	}
 	return nil
 }
 
-// FloatColByName returns the slice of FloatVals for the named column. The
-// error is non-nil if there is a problem (no such column or it's not a float
-// column)
-func (df DF) FloatColByName(name string) ([]FloatVal, error) {
-	i, ok := df.mci.nameToCol[name]
+// colValIdxByName returns the index into the type-specific slice of
+// columns for the named column. The error is non-nil if there is a problem
+// (no such column or it's not of the wanted type)
+func (df DF) colValIdxByName(name string, want ColType) (int, error) {
+	i, ok := df.mci.colIdx(name)
 	if !ok {
-		return nil, dfErrorf("Unknown column name: %q", name)
+		return 0, errUnknownColName(name)
 	}
 
 	ci := df.mci.info[i]
-	if err := assertTypeByName(ci.colType, ColTypeFloat, name); err != nil {
-		return nil, err
+	if err := assertTypeByName(ci.colType, want, name); err != nil {
+		return 0, err
 	}
 
-	return df.floatCols[df.mci.valIdx[i]], nil
+	return df.mci.valIdx[i], nil
 }
 
-// FloatColByIdx returns the slice of FloatVals for the indexed column. The
-// error is non-nil if there is a problem (no such column or it's not a float
-// column)
-func (df DF) FloatColByIdx(i int) ([]FloatVal, error) {
+// colValIdxByIdx returns the index into the type-specific slice of columns
+// for the indexed column. The error is non-nil if there is a problem (no
+// such column or it's not of the wanted type)
+func (df DF) colValIdxByIdx(i int, want ColType) (int, error) {
 	if i < 0 || i >= len(df.mci.info) {
-		return nil, dfErrorf("There is no column %d (valid range: 0-%d)",
-			i, len(df.mci.info)-1)
+		return 0, errUnknownColIdx(i, len(df.mci.info))
 	}
 
 	ci := df.mci.info[i]
-	if err := assertTypeByIdx(ci.colType, ColTypeFloat, i); err != nil {
+	if err := assertTypeByIdx(ci.colType, want, i); err != nil {
+		return 0, err
+	}
+
+	return df.mci.valIdx[i], nil
+}
+
+// cloneValSlice creates a new slice of values and copies the values from
+// the supplied slice into it
+func cloneValSlice[T any](vals []T) []T {
+	rval := make([]T, len(vals))
+	copy(rval, vals)
+	return rval
+}
+
+// FloatColByNameView returns the slice of FloatVals for the named column. The
+// error is non-nil if there is a problem (no such column or it's not a float
+// column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+func (df DF) FloatColByNameView(name string) ([]FloatVal, error) {
+	vi, err := df.colValIdxByName(name, ColTypeFloat)
+	if err != nil {
+		return nil, err
+	}
+
+	return df.floatCols[vi], nil
+}
+
+// FloatColByNameCopy returns a copy of the slice of FloatVals for the named
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a float column)
+func (df DF) FloatColByNameCopy(name string) ([]FloatVal, error) {
+	vals, err := df.FloatColByNameView(name)
+	if err != nil {
 		return nil, err
 	}
 
-	return df.floatCols[df.mci.valIdx[i]], nil
+	return cloneValSlice(vals), nil
 }
 
-// BoolColByName returns the slice of BoolVals for the named column. The
+// FloatColByName returns a copy of the slice of FloatVals for the named column.
+// The error is non-nil if there is a problem (no such column or it's not a
+// float column). It is the same as FloatColByNameCopy; use FloatColByNameView
+// if you want to avoid the cost of the copy.
+func (df DF) FloatColByName(name string) ([]FloatVal, error) {
+	return df.FloatColByNameCopy(name)
+}
+
+// FloatColByIdxView returns the slice of FloatVals for the indexed column. The
+// error is non-nil if there is a problem (no such column or it's not a float
+// column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+func (df DF) FloatColByIdxView(i int) ([]FloatVal, error) {
+	vi, err := df.colValIdxByIdx(i, ColTypeFloat)
+	if err != nil {
+		return nil, err
+	}
+
+	return df.floatCols[vi], nil
+}
+
+// FloatColByIdxCopy returns a copy of the slice of FloatVals for the indexed
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a float column)
+func (df DF) FloatColByIdxCopy(i int) ([]FloatVal, error) {
+	vals, err := df.FloatColByIdxView(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneValSlice(vals), nil
+}
+
+// FloatColByIdx returns a copy of the slice of FloatVals for the indexed
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a float column). It is the same as FloatColByIdxCopy; use
+// FloatColByIdxView if you want to avoid the cost of the copy.
+func (df DF) FloatColByIdx(i int) ([]FloatVal, error) {
+	return df.FloatColByIdxCopy(i)
+}
+
+// BoolColByNameView returns the slice of BoolVals for the named column. The
 // error is non-nil if there is a problem (no such column or it's not a bool
-// column)
-func (df DF) BoolColByName(name string) ([]BoolVal, error) {
-	i, ok := df.mci.nameToCol[name]
-	if !ok {
-		return nil, dfErrorf("Unknown column name: %q", name)
+// column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+//
+// If the column is compressed there is no uncompressed storage to share so
+// a copy of the values is returned and changes to it will not change the
+// dataframe; the column is not decompressed (see Decompress) so that the
+// dataframe is not changed by reading it.
+func (df DF) BoolColByNameView(name string) ([]BoolVal, error) {
+	vi, err := df.colValIdxByName(name, ColTypeBool)
+	if err != nil {
+		return nil, err
 	}
 
-	ci := df.mci.info[i]
-	if err := assertTypeByName(ci.colType, ColTypeBool, name); err != nil {
+	if r, ok := df.rleBoolCols[vi]; ok {
+		return r.expand(), nil
+	}
+
+	return df.boolCols[vi], nil
+}
+
+// BoolColByNameCopy returns a copy of the slice of BoolVals for the named
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a bool column)
+func (df DF) BoolColByNameCopy(name string) ([]BoolVal, error) {
+	vi, err := df.colValIdxByName(name, ColTypeBool)
+	if err != nil {
 		return nil, err
 	}
 
-	return df.boolCols[df.mci.valIdx[i]], nil
+	return df.boolValsCopy(vi), nil
 }
 
-// BoolColByIdx returns the slice of BoolVals for the indexed column. The
+// BoolColByName returns a copy of the slice of BoolVals for the named column.
+// The error is non-nil if there is a problem (no such column or it's not a bool
+// column). It is the same as BoolColByNameCopy; use BoolColByNameView if you
+// want to avoid the cost of the copy.
+func (df DF) BoolColByName(name string) ([]BoolVal, error) {
+	return df.BoolColByNameCopy(name)
+}
+
+// BoolColByIdxView returns the slice of BoolVals for the indexed column. The
 // error is non-nil if there is a problem (no such column or it's not a bool
-// column)
-func (df DF) BoolColByIdx(i int) ([]BoolVal, error) {
-	if i < 0 || i >= len(df.mci.info) {
-		return nil, dfErrorf("There is no column %d (valid range: 0-%d)",
-			i, len(df.mci.info)-1)
+// column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+//
+// If the column is compressed there is no uncompressed storage to share so
+// a copy of the values is returned and changes to it will not change the
+// dataframe; the column is not decompressed (see Decompress) so that the
+// dataframe is not changed by reading it.
+func (df DF) BoolColByIdxView(i int) ([]BoolVal, error) {
+	vi, err := df.colValIdxByIdx(i, ColTypeBool)
+	if err != nil {
+		return nil, err
 	}
 
-	ci := df.mci.info[i]
-	if err := assertTypeByIdx(ci.colType, ColTypeBool, i); err != nil {
+	if r, ok := df.rleBoolCols[vi]; ok {
+		return r.expand(), nil
+	}
+
+	return df.boolCols[vi], nil
+}
+
+// BoolColByIdxCopy returns a copy of the slice of BoolVals for the indexed
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a bool column)
+func (df DF) BoolColByIdxCopy(i int) ([]BoolVal, error) {
+	vi, err := df.colValIdxByIdx(i, ColTypeBool)
+	if err != nil {
+		return nil, err
+	}
+
+	return df.boolValsCopy(vi), nil
+}
+
+// BoolColByIdx returns a copy of the slice of BoolVals for the indexed column.
+// The error is non-nil if there is a problem (no such column or it's not a bool
+// column). It is the same as BoolColByIdxCopy; use BoolColByIdxView if you want
+// to avoid the cost of the copy.
+func (df DF) BoolColByIdx(i int) ([]BoolVal, error) {
+	return df.BoolColByIdxCopy(i)
+}
+
+// IntColByNameView returns the slice of IntVals for the named column. The error
+// is non-nil if there is a problem (no such column or it's not an int column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+func (df DF) IntColByNameView(name string) ([]IntVal, error) {
+	vi, err := df.colValIdxByName(name, ColTypeInt)
+	if err != nil {
 		return nil, err
 	}
 
-	return df.boolCols[df.mci.valIdx[i]], nil
+	return df.intCols[vi], nil
 }
 
-// IntColByName returns the slice of IntVals for the named column. The error
-// is non-nil if there is a problem (no such column or it's not an int
+// IntColByNameCopy returns a copy of the slice of IntVals for the named column.
+// The error is non-nil if there is a problem (no such column or it's not an int
 // column)
+func (df DF) IntColByNameCopy(name string) ([]IntVal, error) {
+	vals, err := df.IntColByNameView(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneValSlice(vals), nil
+}
+
+// IntColByName returns a copy of the slice of IntVals for the named column. The
+// error is non-nil if there is a problem (no such column or it's not an int
+// column). It is the same as IntColByNameCopy; use IntColByNameView if you want
+// to avoid the cost of the copy.
 func (df DF) IntColByName(name string) ([]IntVal, error) {
-	i, ok := df.mci.nameToCol[name]
-	if !ok {
-		return nil, dfErrorf("Unknown column name: %q", name)
+	return df.IntColByNameCopy(name)
+}
+
+// IntColByIdxView returns the slice of IntVals for the indexed column. The
+// error is non-nil if there is a problem (no such column or it's not an int
+// column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+func (df DF) IntColByIdxView(i int) ([]IntVal, error) {
+	vi, err := df.colValIdxByIdx(i, ColTypeInt)
+	if err != nil {
+		return nil, err
 	}
 
-	ci := df.mci.info[i]
-	if err := assertTypeByName(ci.colType, ColTypeInt, name); err != nil {
+	return df.intCols[vi], nil
+}
+
+// IntColByIdxCopy returns a copy of the slice of IntVals for the indexed
+// column. The error is non-nil if there is a problem (no such column or it's
+// not an int column)
+func (df DF) IntColByIdxCopy(i int) ([]IntVal, error) {
+	vals, err := df.IntColByIdxView(i)
+	if err != nil {
 		return nil, err
 	}
 
-	return df.intCols[df.mci.valIdx[i]], nil
+	return cloneValSlice(vals), nil
 }
 
-// IntColByIdx returns the slice of IntVals for the indexed column. The error
-// is non-nil if there is a problem (no such column or it's not an int
-// column)
+// IntColByIdx returns a copy of the slice of IntVals for the indexed column.
+// The error is non-nil if there is a problem (no such column or it's not an int
+// column). It is the same as IntColByIdxCopy; use IntColByIdxView if you want
+// to avoid the cost of the copy.
 func (df DF) IntColByIdx(i int) ([]IntVal, error) {
-	if i < 0 || i >= len(df.mci.info) {
-		return nil, dfErrorf("There is no column %d (valid range: 0-%d)",
-			i, len(df.mci.info)-1)
+	return df.IntColByIdxCopy(i)
+}
+
+// StringColByNameView returns the slice of StringVals for the named column. The
+// error is non-nil if there is a problem (no such column or it's not a string
+// column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+//
+// If the column is compressed there is no uncompressed storage to share so
+// a copy of the values is returned and changes to it will not change the
+// dataframe; the column is not decompressed (see Decompress) so that the
+// dataframe is not changed by reading it.
+func (df DF) StringColByNameView(name string) ([]StringVal, error) {
+	vi, err := df.colValIdxByName(name, ColTypeString)
+	if err != nil {
+		return nil, err
 	}
 
-	ci := df.mci.info[i]
-	if err := assertTypeByIdx(ci.colType, ColTypeInt, i); err != nil {
+	if r, ok := df.rleStringCols[vi]; ok {
+		return r.expand(), nil
+	}
+
+	return df.stringCols[vi], nil
+}
+
+// StringColByNameCopy returns a copy of the slice of StringVals for the named
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a string column)
+func (df DF) StringColByNameCopy(name string) ([]StringVal, error) {
+	vi, err := df.colValIdxByName(name, ColTypeString)
+	if err != nil {
 		return nil, err
 	}
 
-	return df.intCols[df.mci.valIdx[i]], nil
+	return df.stringValsCopy(vi), nil
 }
 
-// StringColByName returns the slice of StringVals for the named column. The
-// error is non-nil if there is a problem (no such column or it's not a
-// string column)
+// StringColByName returns a copy of the slice of StringVals for the named
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a string column). It is the same as StringColByNameCopy; use
+// StringColByNameView if you want to avoid the cost of the copy.
 func (df DF) StringColByName(name string) ([]StringVal, error) {
-	i, ok := df.mci.nameToCol[name]
-	if !ok {
-		return nil, dfErrorf("Unknown column name: %q", name)
-	}
+	return df.StringColByNameCopy(name)
+}
 
-	ci := df.mci.info[i]
-	if err := assertTypeByName(ci.colType, ColTypeString, name); err != nil {
+// StringColByIdxView returns the slice of StringVals for the indexed column.
+// The error is non-nil if there is a problem (no such column or it's not a
+// string column).
+//
+// Note that the returned slice is the dataframe's own storage, no copy is made.
+// Any changes made to the slice will change the dataframe and the slice may no
+// longer reflect the dataframe's contents if rows are subsequently added.
+//
+// If the column is compressed there is no uncompressed storage to share so
+// a copy of the values is returned and changes to it will not change the
+// dataframe; the column is not decompressed (see Decompress) so that the
+// dataframe is not changed by reading it.
+func (df DF) StringColByIdxView(i int) ([]StringVal, error) {
+	vi, err := df.colValIdxByIdx(i, ColTypeString)
+	if err != nil {
 		return nil, err
 	}
 
-	return df.stringCols[df.mci.valIdx[i]], nil
-}
-
-// StringColByIdx returns the slice of StringVals for the indexed column. The
-// error is non-nil if there is a problem (no such column or it's not a
-// string column)
-func (df DF) StringColByIdx(i int) ([]StringVal, error) {
-	if i < 0 || i >= len(df.mci.info) {
-		return nil, dfErrorf("There is no column %d (valid range: 0-%d)",
-			i, len(df.mci.info)-1)
+	if r, ok := df.rleStringCols[vi]; ok {
+		return r.expand(), nil
 	}
 
-	ci := df.mci.info[i]
-	if err := assertTypeByIdx(ci.colType, ColTypeString, i); err != nil {
+	return df.stringCols[vi], nil
+}
+
+// StringColByIdxCopy returns a copy of the slice of StringVals for the indexed
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a string column)
+func (df DF) StringColByIdxCopy(i int) ([]StringVal, error) {
+	vi, err := df.colValIdxByIdx(i, ColTypeString)
+	if err != nil {
 		return nil, err
 	}
 
-	return df.stringCols[df.mci.valIdx[i]], nil
+	return df.stringValsCopy(vi), nil
+}
+
+// StringColByIdx returns a copy of the slice of StringVals for the indexed
+// column. The error is non-nil if there is a problem (no such column or it's
+// not a string column). It is the same as StringColByIdxCopy; use
+// StringColByIdxView if you want to avoid the cost of the copy.
+func (df DF) StringColByIdx(i int) ([]StringVal, error) {
+	return df.StringColByIdxCopy(i)
 }
 
 // (df DF) String converts a DataFrame to a string
@@ -320,6 +593,83 @@ func NewDF(opts ...DFOpt) (*DF, error) {
 	return df, nil
 }
 
+// NewDFFromCols returns a new DataFrame made from the columns. The columns
+// must all have the same number of rows and each column must have a
+// unique, non-empty name. The values are copied from the columns so
+// subsequent changes to the columns will not affect the dataframe.
+func NewDFFromCols(cols ...Column) (*DF, error) {
+	cis := make([]ColInfo, 0, len(cols))
+	for i, c := range cols {
+		if err := c.ci.Check(); err != nil {
+			return nil, dfWrapf(err, "column %d", i)
+		}
+		if c.RowCount() != cols[0].RowCount() {
+			return nil, dfKindErrorf(ErrDimensionMismatch,
+				"column %d (%q) has %d rows, column 0 (%q) has %d",
+				i, c.ci.name, c.RowCount(),
+				cols[0].ci.name, cols[0].RowCount())
+		}
+		cis = append(cis, c.ci)
+	}
+
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, c := range cols {
+		vi := df.mci.valIdx[i]
+		switch c.ci.colType {
+		case ColTypeBool:
+			df.boolCols[vi] = cloneValSlice(c.boolVals)
+		case ColTypeInt:
+			df.intCols[vi] = cloneValSlice(c.intVals)
+		case ColTypeFloat:
+			df.floatCols[vi] = cloneValSlice(c.floatVals)
+		case ColTypeString:
+			df.stringCols[vi] = cloneValSlice(c.stringVals)
+		}
+	}
+
+	return df, nil
+}
+
+// AddCol adds the column to the end of the dataframe. The column must
+// have a unique, non-empty name and, unless the dataframe has no columns,
+// the same number of rows as the dataframe. The values are copied from the
+// column so subsequent changes to the column will not affect the
+// dataframe.
+func (df *DF) AddCol(c Column) error {
+	if err := c.ci.Check(); err != nil {
+		return err
+	}
+	if len(df.mci.info) > 0 && c.RowCount() != df.RowCount() {
+		return dfKindErrorf(ErrDimensionMismatch,
+			"the column (%q) has %d rows, the dataframe has %d",
+			c.ci.name, c.RowCount(), df.RowCount())
+	}
+
+	if df.mci.nameToCol == nil {
+		df.mci.nameToCol = make(map[string]int)
+	}
+	if err := (&df.mci).Add(c.ci); err != nil {
+		return err
+	}
+
+	switch c.ci.colType {
+	case ColTypeBool:
+		df.boolCols = append(df.boolCols, cloneValSlice(c.boolVals))
+	case ColTypeInt:
+		df.intCols = append(df.intCols, cloneValSlice(c.intVals))
+	case ColTypeFloat:
+		df.floatCols = append(df.floatCols, cloneValSlice(c.floatVals))
+	case ColTypeString:
+		df.stringCols = append(df.stringCols, cloneValSlice(c.stringVals))
+	}
+
+	return nil
+}
+
 func MaxErrors(n int) DFOpt {
 	return func(df *DF) error {
 		if n < 0 {
@@ -363,9 +713,9 @@ func (df DF) Columns() []ColInfo {
 // ColInfoByName returns the column detail of the named column or an error if
 // there is no column with that name
 func (df DF) ColInfoByName(name string) (ColInfo, error) {
-	i, ok := df.mci.nameToCol[name]
+	i, ok := df.mci.colIdx(name)
 	if !ok {
-		return ColInfo{}, dfErrorf("Unknown column name: %q", name)
+		return ColInfo{}, errUnknownColName(name)
 	}
 	return df.mci.info[i], nil
 }
@@ -374,12 +724,48 @@ func (df DF) ColInfoByName(name string) (ColInfo, error) {
 // there is no column with that idx
 func (df DF) ColInfoByIdx(i int) (ColInfo, error) {
 	if i < 0 || i >= len(df.mci.info) {
-		return ColInfo{}, dfErrorf("There is no column %d (valid range: 0-%d)",
-			i, len(df.mci.info)-1)
+		return ColInfo{}, errUnknownColIdx(i, len(df.mci.info))
 	}
 	return df.mci.info[i], nil
 }
 
+// ColByIdx returns the indexed column, holding both the column details and
+// a copy of its values, or an error if there is no column with that index
+// or the column has no type (as for a dataframe read from input with a
+// header but no data). Changes to the column will not affect the
+// dataframe; use AddCol to add a column to a dataframe.
+func (df DF) ColByIdx(i int) (Column, error) {
+	ci, err := df.ColInfoByIdx(i)
+	if err != nil {
+		return Column{}, err
+	}
+
+	c := Column{ci: ci}
+	switch ci.colType {
+	case ColTypeBool:
+		c.boolVals = df.boolValsCopy(df.mci.valIdx[i])
+	case ColTypeInt:
+		c.intVals = cloneValSlice(df.intCols[df.mci.valIdx[i]])
+	case ColTypeFloat:
+		c.floatVals = cloneValSlice(df.floatCols[df.mci.valIdx[i]])
+	case ColTypeString:
+		c.stringVals = df.stringValsCopy(df.mci.valIdx[i])
+	default:
+		return Column{}, errUntypedCol(ci.name)
+	}
+	return c, nil
+}
+
+// ColByName returns the named column as for ColByIdx or an error if there
+// is no column with that name
+func (df DF) ColByName(name string) (Column, error) {
+	i, ok := df.mci.colIdx(name)
+	if !ok {
+		return Column{}, errUnknownColName(name)
+	}
+	return df.ColByIdx(i)
+}
+
 // SetColNames sets the names of the columns of the DataFrame to the given names
 func (df *DF) SetColNames(names ...string) error {
 	if len(names) == 0 {
@@ -391,9 +777,9 @@ func (df *DF) SetColNames(names ...string) error {
 	if len(df.mci.info) == 0 {
 		df.mci.info = make([]ColInfo, len(names))
 	} else if len(df.mci.info) != len(names) {
-		err := dfError(fmt.Sprintf(
+		err := dfKindErrorf(ErrDimensionMismatch,
 			"the number of columns (%d) and number of names (%d) differ",
-			len(df.mci.info), len(names)))
+			len(df.mci.info), len(names))
 		df.addError(err)
 		return err
 	}
@@ -402,14 +788,23 @@ func (df *DF) SetColNames(names ...string) error {
 
 	for i, name := range names {
 		if dup, exists := colNameToIdx[name]; exists {
-			err := dfError(fmt.Sprintf(
+			err := dfCodeErrorf(ErrCodeDuplicateColName,
 				"duplicate column name: %q is used for columns %d and %d",
-				name, dup, i))
+				name, dup, i)
 			df.addError(err)
 			return err
 		}
 		colNameToIdx[name] = i
 	}
+	if df.mci.foldCase {
+		if i, j, clash := foldCaseClash(names); clash {
+			err := dfCodeErrorf(ErrCodeDuplicateColName,
+				"column names %q and %q (columns %d and %d)"+
+					" differ only in case", names[i], names[j], i, j)
+			df.addError(err)
+			return err
+		}
+	}
 
 	for i, name := range names {
 		df.mci.info[i].name = name
@@ -464,7 +859,7 @@ func (df *DF) SetColTypes(types ...ColType) error {
 	}
 
 	if len(df.mci.info) != len(types) {
-		err := dfErrorf(
+		err := dfKindErrorf(ErrDimensionMismatch,
 			"the number of columns (%d) and number of types (%d) differ",
 			len(df.mci.info), len(types))
 		df.addError(err)
@@ -483,9 +878,19 @@ func (df *DF) SetColTypes(types ...ColType) error {
 // check on maxErrors and increments the errCount
 func (df *DF) addError(err error) {
 	df.errCount++
+	if pe, ok := err.(ParseError); ok && pe.Col >= 0 {
+		if df.colErrCount == nil {
+			df.colErrCount = map[int]int64{}
+		}
+		df.colErrCount[pe.Col]++
+	}
+	df.summariseError(err)
 	if len(df.errors) < df.maxErrors {
 		df.errors = append(df.errors, err)
 	}
+	if df.errHook != nil {
+		df.errHook(err)
+	}
 }
 
 // AddRow will add a new row to the DataFrame
@@ -498,55 +903,138 @@ func (df *DF) AddRow(row *Row) error {
 		vi := df.mci.valIdx[i]
 		switch ci.colType {
 		case ColTypeBool:
-			df.boolCols[vi] = append(df.boolCols[vi], row.rd.boolVals[vi])
+			df.appendBoolVal(vi, row.rd.boolVals[vi])
 		case ColTypeFloat:
 			df.floatCols[vi] = append(df.floatCols[vi], row.rd.floatVals[vi])
 		case ColTypeInt:
 			df.intCols[vi] = append(df.intCols[vi], row.rd.intVals[vi])
 		case ColTypeString:
-			df.stringCols[vi] = append(df.stringCols[vi], row.rd.stringVals[vi])
+			df.appendStringVal(vi, row.rd.stringVals[vi])
 		}
 	}
+	df.updateSummaries()
 	return nil
 }
 
-// AddRowFromText will add a new row to the DataFrame
+// AddRowFromText will add a new row to the DataFrame. Any problems will be
+// recorded as ParseErrors in the dataframe's errors.
 func (df *DF) AddRowFromText(cols []string) {
+	_ = df.addRowFromText(parseOpts{}, cols, nil, "", 0, "")
+}
+
+// parseOpts holds the settings controlling how text is parsed into values
+type parseOpts struct {
+	numFmt  NumFormat
+	boolFmt BoolFormat
+
+	pctAll  bool
+	pctCols map[string]bool
+
+	currency map[string]currencyFmt
+
+	foldCase bool
+
+	emptyFields EmptyFieldPolicy
+	promotion   TypePromotion
+}
+
+// addRowFromText adds a new row to the DataFrame, parsing the text
+// according to the parseOpts. If isNA is not nil then any column for which
+// it is true is given an NA value without parsing the text. The source,
+// line and text of the line are recorded in any errors and the first error
+// seen (if any) is returned.
+func (df *DF) addRowFromText(po parseOpts, cols []string, isNA []bool,
+	source string, line int64, text string,
+) error {
 	if len(cols) != len(df.mci.info) {
-		df.addError(dfErrorf("dataframe has %d columns, %d are being added",
-			len(df.mci.info), len(cols)))
-		return
+		err := ParseError{
+			Source: source,
+			Line:   line,
+			Text:   text,
+			Col:    -1,
+			Msg: fmt.Sprintf("dataframe has %d columns, %d are being added",
+				len(df.mci.info), len(cols)),
+			Code: ErrCodeWrongFieldCount,
+			kind: ErrDimensionMismatch,
+		}
+		df.addError(err)
+		return err
 	}
 
-	for i, c := range df.mci.info {
-		valIdx := df.mci.valIdx[i]
+	var firstErr error
+	for i := range df.mci.info {
 		var err error
+		code := ErrCodeBadValue
 
-		switch c.colType {
-		case ColTypeBool:
-			var v BoolVal
-			err = v.SetVal(cols[i])
-			df.boolCols[valIdx] = append(df.boolCols[valIdx], v)
-		case ColTypeInt:
-			var v IntVal
-			err = v.SetVal(cols[i])
-			df.intCols[valIdx] = append(df.intCols[valIdx], v)
-		case ColTypeFloat:
-			var v FloatVal
-			err = v.SetVal(cols[i])
-			df.floatCols[valIdx] = append(df.floatCols[valIdx], v)
-		case ColTypeString:
-			v := StringVal{Val: cols[i]}
-			df.stringCols[valIdx] = append(df.stringCols[valIdx], v)
+		if isNA != nil && isNA[i] {
+			_ = df.appendVal(i, nil)
+			continue
+		}
+
+		switch {
+		case cols[i] == "" && po.emptyFields == EmptyAsNA:
+			_ = df.appendVal(i, nil)
+		case cols[i] == "" && po.emptyFields == EmptyIsError:
+			_ = df.appendVal(i, nil)
+			err = errEmptyField
+			code = ErrCodeEmptyField
 		default:
-			panic(dfErrorf("Unexpected column type: %q", c.colType))
+			err = df.appendText(po, i, cols[i])
+			if err != nil && po.promotion != PromoteNone {
+				err = df.promoteText(po, i, cols[i], err)
+			}
 		}
 
 		if err != nil {
-			df.addError(dfErrorf("data row: %d column: %d: %s",
-				df.RowCount(), i, err))
+			pErr := ParseError{
+				Source: source,
+				Line:   line,
+				Text:   text,
+				Col:    i,
+				Field:  cols[i],
+				Msg: fmt.Sprintf("data row: %d column: %d: %s",
+					df.RowCount(), i, err),
+				Code: code,
+				kind: ErrParse,
+			}
+			df.addError(pErr)
+			if firstErr == nil {
+				firstErr = pErr
+			}
 		}
 	}
+
+	return firstErr
+}
+
+// appendText parses the text according to the parseOpts and appends the
+// value to the indexed column. If the text cannot be parsed an NA value is
+// appended and the error is returned.
+func (df *DF) appendText(po parseOpts, i int, s string) error {
+	var err error
+
+	valIdx := df.mci.valIdx[i]
+	switch c := df.mci.info[i]; c.colType {
+	case ColTypeBool:
+		var v BoolVal
+		err = v.SetValWithFormat(s, po.boolFmt)
+		df.appendBoolVal(valIdx, v)
+	case ColTypeInt:
+		var v IntVal
+		err = v.SetValWithFormat(s, po.numFmt)
+		df.intCols[valIdx] = append(df.intCols[valIdx], v)
+	case ColTypeFloat:
+		var v FloatVal
+		v.Val, err = po.parseFloat(c.name, s)
+		v.IsNA = err != nil
+		df.floatCols[valIdx] = append(df.floatCols[valIdx], v)
+	case ColTypeString:
+		df.appendStringVal(valIdx, StringVal{Val: s})
+	default:
+		panic(dfErrorf("Unexpected column type: %q", c.colType))
+	}
+
+	return err
 }
 
 // AddRowsFromText will add a new row to the DataFrame for each of the rows
@@ -556,3 +1044,205 @@ func (df *DF) AddRowsFromText(rows [][]string) {
 		df.AddRowFromText(row)
 	}
 }
+
+// newDFFromColInfo creates a new, empty dataframe with columns having the
+// names, types and metadata given by the ColInfo values
+func newDFFromColInfo(cis ...ColInfo) (*DF, error) {
+	df, err := NewDF()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cis) == 0 {
+		return df, nil
+	}
+
+	names := make([]string, 0, len(cis))
+	types := make([]ColType, 0, len(cis))
+	for _, ci := range cis {
+		names = append(names, ci.name)
+		types = append(types, ci.colType)
+	}
+
+	if err := df.SetColTypes(types...); err != nil {
+		return nil, err
+	}
+	if err := df.SetColNames(names...); err != nil {
+		return nil, err
+	}
+	for i, ci := range cis {
+		df.mci.info[i].meta = ci.meta
+	}
+
+	return df, nil
+}
+
+// appendVal converts the value to the type of the indexed column and
+// appends it to that column. It returns an error if the value cannot be
+// converted. Note that no check is made that the columns are all kept to the
+// same length; the caller should append a value to every column.
+func (df *DF) appendVal(i int, v any) error {
+	vi := df.mci.valIdx[i]
+
+	switch ct := df.mci.info[i].colType; ct {
+	case ColTypeBool:
+		bv, err := boolValOf(v)
+		if err != nil {
+			return err
+		}
+		df.appendBoolVal(vi, bv)
+	case ColTypeInt:
+		iv, err := intValOf(v)
+		if err != nil {
+			return err
+		}
+		df.intCols[vi] = append(df.intCols[vi], iv)
+	case ColTypeFloat:
+		fv, err := floatValOf(v)
+		if err != nil {
+			return err
+		}
+		df.floatCols[vi] = append(df.floatCols[vi], fv)
+	case ColTypeString:
+		sv, err := stringValOf(v)
+		if err != nil {
+			return err
+		}
+		df.appendStringVal(vi, sv)
+	default:
+		panic(dfErrorf("Unexpected column type: %q", ct))
+	}
+
+	return nil
+}
+
+// appendRowVals appends the values from the row to the dataframe. The
+// values are taken in column order and converted to the dataframe's column
+// types. It returns an error if the row has a different number of columns
+// or a value cannot be converted; in that case the dataframe may be left
+// with columns of differing lengths.
+func (df *DF) appendRowVals(r *Row) error {
+	if len(r.mci.info) != len(df.mci.info) {
+		return dfKindErrorf(ErrDimensionMismatch,
+			"the row has %d columns, the dataframe has %d",
+			len(r.mci.info), len(df.mci.info))
+	}
+
+	for i := range df.mci.info {
+		v, _, err := r.ValByIdx(i)
+		if err != nil {
+			return err
+		}
+		if err := df.appendVal(i, v); err != nil {
+			return dfWrapf(err, "%s", df.mci.ColDesc(i))
+		}
+	}
+
+	return nil
+}
+
+// truncateRows removes rows from the end of the dataframe so that it has
+// just the first n rows. Any indexes and column summaries are rebuilt from
+// scratch when next used (see discardDerived).
+func (df *DF) truncateRows(n int) {
+	for i, ci := range df.mci.info {
+		vi := df.mci.valIdx[i]
+		switch ci.colType {
+		case ColTypeBool:
+			if r, ok := df.rleBoolCols[vi]; ok {
+				r.truncate(n)
+			} else {
+				df.boolCols[vi] = df.boolCols[vi][:n]
+			}
+		case ColTypeInt:
+			df.intCols[vi] = df.intCols[vi][:n]
+		case ColTypeFloat:
+			df.floatCols[vi] = df.floatCols[vi][:n]
+		case ColTypeString:
+			if r, ok := df.rleStringCols[vi]; ok {
+				r.truncate(n)
+			} else {
+				df.stringCols[vi] = df.stringCols[vi][:n]
+			}
+		}
+	}
+
+	df.discardDerived(n)
+}
+
+// discardDerived empties any indexes and column summaries which include
+// row n or later so that they are rebuilt from scratch when next used
+func (df *DF) discardDerived(n int) {
+	for _, idx := range df.indexes {
+		if idx.rowCount > n {
+			idx.rows = map[any][]int{}
+			idx.rowCount = 0
+		}
+	}
+	for i, cs := range df.summaries {
+		if cs.rowCount > n {
+			df.summaries[i] = newColSummary(df, i)
+		}
+	}
+}
+
+// moveLastRow replaces the i'th row of the dataframe with the last row,
+// which is then removed. The dataframe must not have any compressed
+// columns.
+func (df *DF) moveLastRow(i int) {
+	last := df.RowCount() - 1
+	for cidx, vi := range df.mci.valIdx {
+		switch df.mci.info[cidx].colType {
+		case ColTypeBool:
+			df.boolCols[vi][i] = df.boolCols[vi][last]
+		case ColTypeInt:
+			df.intCols[vi][i] = df.intCols[vi][last]
+		case ColTypeFloat:
+			df.floatCols[vi][i] = df.floatCols[vi][last]
+		case ColTypeString:
+			df.stringCols[vi][i] = df.stringCols[vi][last]
+		}
+	}
+	df.truncateRows(last)
+	df.discardDerived(i)
+}
+
+// copyRowFrom appends the i'th row of the src dataframe to df. The two
+// dataframes must have the same column structure, as given by Clone.
+func (df *DF) copyRowFrom(src *DF, i int) {
+	for cidx, vi := range df.mci.valIdx {
+		switch df.mci.info[cidx].colType {
+		case ColTypeBool:
+			df.appendBoolVal(vi, src.boolAt(vi, i))
+		case ColTypeInt:
+			df.intCols[vi] = append(df.intCols[vi], src.intCols[vi][i])
+		case ColTypeFloat:
+			df.floatCols[vi] = append(df.floatCols[vi], src.floatCols[vi][i])
+		case ColTypeString:
+			df.appendStringVal(vi, src.stringAt(vi, i))
+		}
+	}
+}
+
+// valAt returns the value (one of the Val types) in the indexed column and
+// row and whether or not it is NA.
+func (df DF) valAt(colIdx, rowIdx int) (any, bool) {
+	vi := df.mci.valIdx[colIdx]
+
+	switch ct := df.mci.info[colIdx].colType; ct {
+	case ColTypeBool:
+		v := df.boolAt(vi, rowIdx)
+		return v, v.IsNA
+	case ColTypeInt:
+		v := df.intCols[vi][rowIdx]
+		return v, v.IsNA
+	case ColTypeFloat:
+		v := df.floatCols[vi][rowIdx]
+		return v, v.IsNA
+	case ColTypeString:
+		v := df.stringAt(vi, rowIdx)
+		return v, v.IsNA
+	default:
+		panic(dfErrorf("Unexpected column type: %q", ct))
+	}
+}
@@ -2,6 +2,7 @@ package dataframe_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/nickwells/dataframe.mod/dataframe"
@@ -35,8 +36,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				{colNames: []string{"c1", "c2"}},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("c1", dataframe.ColTypeInt),
-				dataframe.NewColInfo("c2", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c1", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c2", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -50,8 +51,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("", dataframe.ColTypeInt),
-				dataframe.NewColInfo("", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -73,8 +74,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("", dataframe.ColTypeInt),
-				dataframe.NewColInfo("", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -96,8 +97,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("", dataframe.ColTypeInt),
-				dataframe.NewColInfo("", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -118,8 +119,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("", dataframe.ColTypeInt),
-				dataframe.NewColInfo("", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -134,8 +135,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("c1", dataframe.ColTypeInt),
-				dataframe.NewColInfo("c2", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c1", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c2", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -151,8 +152,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				{colNames: []string{"c3", "c4"}},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("c3", dataframe.ColTypeInt),
-				dataframe.NewColInfo("c4", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c3", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c4", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -174,8 +175,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("", dataframe.ColTypeInt),
-				dataframe.NewColInfo("", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -200,8 +201,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("c1", dataframe.ColTypeInt),
-				dataframe.NewColInfo("c2", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c1", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c2", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -223,8 +224,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("c1", dataframe.ColTypeInt),
-				dataframe.NewColInfo("c2", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c1", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c2", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -249,8 +250,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("c1", dataframe.ColTypeInt),
-				dataframe.NewColInfo("c2", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c1", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c2", dataframe.ColTypeFloat),
 			},
 		},
 		{
@@ -275,8 +276,8 @@ func TestDFSetColTypeAndName(t *testing.T) {
 				},
 			},
 			expColVals: []dataframe.ColInfo{
-				dataframe.NewColInfo("c1", dataframe.ColTypeInt),
-				dataframe.NewColInfo("c2", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c1", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c2", dataframe.ColTypeFloat),
 			},
 		},
 	}
@@ -364,7 +365,7 @@ func TestDFColInfoByName(t *testing.T) {
 		{
 			ID:     testhelper.MkID("all good"),
 			name:   "c1",
-			expVal: dataframe.NewColInfo("c1", dataframe.ColTypeBool),
+			expVal: dataframe.MustNewColInfo("c1", dataframe.ColTypeBool),
 		},
 		{
 			ID: testhelper.MkID("bad name"),
@@ -398,12 +399,12 @@ func TestDFColInfoByIdx(t *testing.T) {
 		{
 			ID:     testhelper.MkID("all good - first col"),
 			idx:    0,
-			expVal: dataframe.NewColInfo("c1", dataframe.ColTypeBool),
+			expVal: dataframe.MustNewColInfo("c1", dataframe.ColTypeBool),
 		},
 		{
 			ID:     testhelper.MkID("all good - last col"),
 			idx:    1,
-			expVal: dataframe.NewColInfo("c2", dataframe.ColTypeFloat),
+			expVal: dataframe.MustNewColInfo("c2", dataframe.ColTypeFloat),
 		},
 		{
 			ID: testhelper.MkID("bad index - < 0"),
@@ -441,3 +442,110 @@ func TestDFColInfoByIdx(t *testing.T) {
 		}
 	}
 }
+
+// mkTestDF reads the content into a new dataframe using the supplied
+// options. It reports a fatal error if the dataframe cannot be made
+func mkTestDF(t *testing.T, content string,
+	opts ...dataframe.DFReaderOpt,
+) *dataframe.DF {
+	t.Helper()
+
+	dfr, err := dataframe.NewDFReader(opts...)
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+	}
+
+	df, err := dfr.Read(strings.NewReader(content), "test data")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot read the test data: ", err)
+	}
+
+	return df
+}
+
+func TestDFColViewAndCopy(t *testing.T) {
+	df := mkTestDF(t, "i f\n1 1.5\n2 2.5\n", dataframe.HasHeader)
+
+	view, err := df.IntColByNameView("i")
+	if err != nil {
+		t.Fatal("unexpected error getting the view: ", err)
+	}
+	cp, err := df.IntColByName("i")
+	if err != nil {
+		t.Fatal("unexpected error getting the copy: ", err)
+	}
+
+	cp[0].Val = 99
+	after, _ := df.IntColByIdx(0)
+	testhelper.DiffInt(t, "change the copy", "dataframe value",
+		after[0].Val, 1)
+
+	view[0].Val = 42
+	after, _ = df.IntColByIdxCopy(0)
+	testhelper.DiffInt(t, "change the view", "dataframe value",
+		after[0].Val, 42)
+
+	_, err = df.FloatColByIdxView(0)
+	testhelper.CheckExpErrWithID(t, "view of the wrong type", err,
+		testhelper.MkExpErr(
+			`The column with index 0 is of type "Int" not "Float"`))
+	_, err = df.StringColByNameCopy("nonesuch")
+	testhelper.CheckExpErrWithID(t, "copy of a missing column", err,
+		testhelper.MkExpErr(`Unknown column name: "nonesuch"`))
+}
+
+// dfVals returns the values in the dataframe as a slice of rows, each value
+// being formatted as a string. NA values are shown as "NA"
+func dfVals(t *testing.T, df *dataframe.DF) [][]string {
+	t.Helper()
+
+	rval := [][]string{}
+	for i := 0; i < df.RowCount(); i++ {
+		r := df.Row(i)
+		row := []string{}
+		for j := 0; j < df.ColCount(); j++ {
+			v, _, err := r.ValByIdx(j)
+			if err != nil {
+				t.Fatal("unexpected error getting a row value: ", err)
+			}
+			var s string
+			switch v := v.(type) {
+			case dataframe.BoolVal:
+				s = fmt.Sprint(v.Val)
+				if v.IsNA {
+					s = "NA"
+				}
+			case dataframe.IntVal:
+				s = fmt.Sprint(v.Val)
+				if v.IsNA {
+					s = "NA"
+				}
+			case dataframe.FloatVal:
+				s = fmt.Sprint(v.Val)
+				if v.IsNA {
+					s = "NA"
+				}
+			case dataframe.StringVal:
+				s = v.Val
+				if v.IsNA {
+					s = "NA"
+				}
+			}
+			row = append(row, s)
+		}
+		rval = append(rval, row)
+	}
+	return rval
+}
+
+// checkDFVals checks that the dataframe values match the expected values
+func checkDFVals(t *testing.T, id string, df *dataframe.DF, exp [][]string) {
+	t.Helper()
+
+	if err := testhelper.DiffVals(dfVals(t, df), exp); err != nil {
+		t.Log(id)
+		t.Logf("\t: expected: %v\n", exp)
+		t.Logf("\t:   actual: %v\n", dfVals(t, df))
+		t.Errorf("\t: unexpected dataframe values: %s\n", err)
+	}
+}
@@ -0,0 +1,55 @@
+package dataframe
+
+import "sort"
+
+// SetMeta sets the dataframe metadata key to the value, replacing any
+// existing value. The metadata can be used to record the provenance of the
+// data, such as the source file, the load time or notes about it. It is
+// copied by Clone and by the operations which make a new dataframe from an
+// existing one, such as Select or Filter, and is written and read back in
+// round-trip mode (see DFWRoundTrip). It returns an error if the key is
+// empty.
+func (df *DF) SetMeta(key, val string) error {
+	if key == "" {
+		return dfErrorf("the metadata key must not be empty")
+	}
+	if df.meta == nil {
+		df.meta = make(map[string]string)
+	}
+	df.meta[key] = val
+	return nil
+}
+
+// Meta returns the value of the dataframe metadata with the given key and
+// true if it has been set, or the empty string and false otherwise
+func (df DF) Meta(key string) (string, bool) {
+	v, ok := df.meta[key]
+	return v, ok
+}
+
+// DeleteMeta removes the dataframe metadata with the given key, if any
+func (df *DF) DeleteMeta(key string) {
+	delete(df.meta, key)
+}
+
+// MetaKeys returns the keys of the dataframe metadata in sorted order
+func (df DF) MetaKeys() []string {
+	keys := make([]string, 0, len(df.meta))
+	for k := range df.meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cloneMeta returns a copy of the metadata map or nil if it is empty
+func cloneMeta(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	rval := make(map[string]string, len(m))
+	for k, v := range m {
+		rval[k] = v
+	}
+	return rval
+}
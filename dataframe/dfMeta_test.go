@@ -0,0 +1,79 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFMeta(t *testing.T) {
+	df, err := dataframe.NewDFFromCols(mkIntCol("i", 1, 2))
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+	}
+	for _, kv := range [][2]string{
+		{"source", "data.txt"},
+		{"note", "a \"quoted\"\nnote"},
+		{"tmp", "x"},
+	} {
+		if err := df.SetMeta(kv[0], kv[1]); err != nil {
+			t.Fatal("unexpected error setting the metadata: ", err)
+		}
+	}
+	df.DeleteMeta("tmp")
+
+	err = df.SetMeta("", "x")
+	testhelper.CheckExpErrWithID(t, "empty key", err,
+		testhelper.MkExpErr("the metadata key must not be empty"))
+
+	sel, err := df.Select("i")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot select the column: ", err)
+	}
+
+	dfw, err := dataframe.NewDFWriter(dataframe.DFWRoundTrip)
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the DFWriter: ", err)
+	}
+	var sb strings.Builder
+	if err := dfw.Write(&sb, df); err != nil {
+		t.Fatal("unexpected error writing the dataframe: ", err)
+	}
+	testhelper.DiffString(t, "round-trip format", "output", sb.String(),
+		`#meta "note" "a \"quoted\"\nnote"`+"\n"+
+			`#meta "source" "data.txt"`+"\n"+
+			`"i"`+"\n"+
+			"Int\n"+
+			"1\n"+
+			"2\n")
+	rtDF := mkTestDF(t, sb.String(), dataframe.DFRRoundTrip)
+
+	for name, d := range map[string]*dataframe.DF{
+		"original":   df,
+		"Clone":      df.Clone(),
+		"Select":     sel,
+		"round trip": rtDF,
+	} {
+		testhelper.DiffStringSlice(t, name, "meta keys", d.MetaKeys(),
+			[]string{"note", "source"})
+		v, ok := d.Meta("source")
+		testhelper.DiffBool(t, name, "source set", ok, true)
+		testhelper.DiffString(t, name, "source", v, "data.txt")
+		_, ok = d.Meta("tmp")
+		testhelper.DiffBool(t, name, "tmp set", ok, false)
+	}
+}
+
+func TestDFMetaBadLine(t *testing.T) {
+	dfr, err := dataframe.NewDFReader(dataframe.DFRRoundTrip)
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+	}
+	_, err = dfr.Read(strings.NewReader(
+		`#meta "key"`+"\n"+`"i"`+"\n"+"Int\n"+"1\n"), "test data")
+	testhelper.CheckExpErrWithID(t, "missing value", err,
+		testhelper.MkExpErr("test data:1",
+			"bad metadata line: expected a key and a value, found 1 fields"))
+}
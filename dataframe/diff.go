@@ -0,0 +1,250 @@
+package dataframe
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Names of the columns in the dataframe returned by Diff
+const (
+	DiffColKey    = "key"
+	DiffColChange = "change"
+	DiffColColumn = "column"
+	DiffColOld    = "old"
+	DiffColNew    = "new"
+)
+
+// The values of the change column in the dataframe returned by Diff
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// diffOpts holds the settings controlling the comparison made by Diff
+type diffOpts struct {
+	keyCol string
+	eq     dfEqualOpts
+}
+
+// DiffOpt is the type of an option function for Diff
+type DiffOpt func(*diffOpts) error
+
+// DFDKeyCol returns a function which will cause Diff to match the rows of
+// the two dataframes by the values in the named column rather than by their
+// position. The values in the column must be unique in each dataframe.
+func DFDKeyCol(name string) DiffOpt {
+	return func(o *diffOpts) error {
+		if name == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the key column name must not be empty")
+		}
+		o.keyCol = name
+		return nil
+	}
+}
+
+// DFDEqualOpts returns a function which will cause Diff to apply the
+// given Equal options when comparing values, for instance to allow float
+// values to differ by some tolerance. DFEIgnoreColOrder has no effect as
+// Diff always matches the columns by name.
+func DFDEqualOpts(opts ...DFEqualOpt) DiffOpt {
+	return func(o *diffOpts) error {
+		for _, opt := range opts {
+			if err := opt(&o.eq); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// diffReport accumulates the differences found by Diff
+type diffReport struct {
+	df *DF
+}
+
+// add appends a row to the report. A nil value gives an NA
+func (dr diffReport) add(key any, change string, col, oldV, newV any) error {
+	for i, v := range []any{key, change, col, oldV, newV} {
+		if err := dr.df.appendVal(i, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffVal returns the value at the indexed column and row as a string or
+// nil if it is NA
+func diffVal(df *DF, colIdx, rowIdx int) any {
+	v, isNA := df.valAt(colIdx, rowIdx)
+	if isNA {
+		return nil
+	}
+	if sv, ok := v.(StringVal); ok {
+		return sv.Val
+	}
+	return fmt.Sprint(v)
+}
+
+// diffRows adds a row to the report for each cell that differs between
+// the given rows. The key column, if any, is skipped.
+func (dr diffReport) diffRows(a, b *DF, bIdx []int, keyIdx int,
+	key any, aRow, bRow int, eq dfEqualOpts,
+) error {
+	for c, bc := range bIdx {
+		if c == keyIdx {
+			continue
+		}
+		av, aIsNA := a.valAt(c, aRow)
+		bv, bIsNA := b.valAt(bc, bRow)
+		if eq.valsEqual(av, aIsNA, bv, bIsNA) {
+			continue
+		}
+		if err := dr.add(key, DiffChanged, a.mci.info[c].name,
+			diffVal(a, c, aRow), diffVal(b, bc, bRow)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyRows returns a map of the values in the indexed column to the row
+// having that value. It returns an error if any value is repeated.
+func keyRows(df *DF, colIdx int) (map[any]int, error) {
+	rows := make(map[any]int, df.RowCount())
+	for r := 0; r < df.RowCount(); r++ {
+		k := df.keyAt(colIdx, r)
+		if prev, ok := rows[k]; ok {
+			return nil, dfErrorf(
+				"the key %s is repeated: rows %d and %d have the value %s",
+				df.mci.ColDesc(colIdx), prev, r, diffKeyText(df, colIdx, r))
+		}
+		rows[k] = r
+	}
+	return rows, nil
+}
+
+// diffKeyText returns the key value for the report or "NA"
+func diffKeyText(df *DF, colIdx, rowIdx int) string {
+	if k := diffVal(df, colIdx, rowIdx); k != nil {
+		return k.(string)
+	}
+	return "NA"
+}
+
+// Diff compares the two dataframes and returns a report, itself a
+// dataframe, listing each row that has been removed from a, each row that
+// has been added in b and each cell that has changed. The two dataframes
+// must have the same columns, with the same names and types, though they
+// may be in a different order. By default rows are matched by their
+// position; DFDKeyCol can be given to match them by a key column instead.
+//
+// The report has the following columns, all of them strings:
+//
+//	key    the row index or, if a key column is given, the key value
+//	change one of DiffAdded, DiffRemoved or DiffChanged
+//	column the name of the changed column (NA for added or removed rows)
+//	old    the value in a (NA if the value is NA or the row was added)
+//	new    the value in b (NA if the value is NA or the row was removed)
+//
+// The removed rows and changed cells are given in the order of the rows in
+// a, followed by the added rows in the order of the rows in b.
+func Diff(a, b *DF, opts ...DiffOpt) (*DF, error) {
+	var o diffOpts
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	bIdx, err := a.matchCols(b, dfEqualOpts{ignoreColOrder: true})
+	if err != nil {
+		return nil, err
+	}
+
+	rdf, err := newDFFromColInfo(
+		ColInfo{name: DiffColKey, colType: ColTypeString},
+		ColInfo{name: DiffColChange, colType: ColTypeString},
+		ColInfo{name: DiffColColumn, colType: ColTypeString},
+		ColInfo{name: DiffColOld, colType: ColTypeString},
+		ColInfo{name: DiffColNew, colType: ColTypeString},
+	)
+	if err != nil {
+		return nil, err
+	}
+	dr := diffReport{df: rdf}
+
+	if o.keyCol == "" {
+		err = dr.diffByIdx(a, b, bIdx, o.eq)
+	} else {
+		err = dr.diffByKey(a, b, bIdx, o)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rdf, nil
+}
+
+// diffByIdx compares the rows of the two dataframes by position
+func (dr diffReport) diffByIdx(a, b *DF, bIdx []int, eq dfEqualOpts) error {
+	for r := 0; r < a.RowCount(); r++ {
+		key := strconv.Itoa(r)
+		var err error
+		if r < b.RowCount() {
+			err = dr.diffRows(a, b, bIdx, -1, key, r, r, eq)
+		} else {
+			err = dr.add(key, DiffRemoved, nil, nil, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for r := a.RowCount(); r < b.RowCount(); r++ {
+		err := dr.add(strconv.Itoa(r), DiffAdded, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffByKey compares the rows of the two dataframes matching them by the
+// values in the key column
+func (dr diffReport) diffByKey(a, b *DF, bIdx []int, o diffOpts) error {
+	keyIdx, ok := a.mci.colIdx(o.keyCol)
+	if !ok {
+		return errUnknownColName(o.keyCol)
+	}
+	aRows, err := keyRows(a, keyIdx)
+	if err != nil {
+		return dfWrapf(err, "the first dataframe")
+	}
+	bRows, err := keyRows(b, bIdx[keyIdx])
+	if err != nil {
+		return dfWrapf(err, "the second dataframe")
+	}
+
+	for r := 0; r < a.RowCount(); r++ {
+		key := diffKeyText(a, keyIdx, r)
+		bRow, ok := bRows[a.keyAt(keyIdx, r)]
+		if ok {
+			err = dr.diffRows(a, b, bIdx, keyIdx, key, r, bRow, o.eq)
+		} else {
+			err = dr.add(key, DiffRemoved, nil, nil, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for r := 0; r < b.RowCount(); r++ {
+		if _, ok := aRows[b.keyAt(bIdx[keyIdx], r)]; ok {
+			continue
+		}
+		key := diffKeyText(b, bIdx[keyIdx], r)
+		if err := dr.add(key, DiffAdded, nil, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDiff(t *testing.T) {
+	mk := func(content string) *dataframe.DF {
+		return mkTestDF(t, content,
+			dataframe.HasHeader, dataframe.AllowErrors,
+			dataframe.DFRColTypes(dataframe.ColTypeString,
+				dataframe.ColTypeFloat, dataframe.ColTypeString))
+	}
+	a := mk("id f s\nk1 1 a\nk2 2 b\nk3 3 c\n")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		b      *dataframe.DF
+		opts   []dataframe.DiffOpt
+		expCSV string
+	}{
+		{
+			ID:     testhelper.MkID("no differences"),
+			b:      mk("id f s\nk1 1 a\nk2 2 b\nk3 3 c\n"),
+			expCSV: "key,change,column,old,new\n",
+		},
+		{
+			ID: testhelper.MkID("by index"),
+			b:  mk("id f s\nk1 1 a\nk3 NA b\n"),
+			expCSV: "key,change,column,old,new\n" +
+				"1,changed,id,k2,k3\n" +
+				"1,changed,f,2,\n" +
+				"2,removed,,,\n",
+		},
+		{
+			ID:   testhelper.MkID("by key"),
+			b:    mk("s f id\nb 2.5 k2\nc 3 k3\nd 4 k4\n"),
+			opts: []dataframe.DiffOpt{dataframe.DFDKeyCol("id")},
+			expCSV: "key,change,column,old,new\n" +
+				"k1,removed,,,\n" +
+				"k2,changed,f,2,2.5\n" +
+				"k4,added,,,\n",
+		},
+		{
+			ID: testhelper.MkID("by key, within tolerance"),
+			b:  mk("s f id\nb 2.5 k2\nc 3 k3\nd 4 k4\n"),
+			opts: []dataframe.DiffOpt{
+				dataframe.DFDKeyCol("id"),
+				dataframe.DFDEqualOpts(dataframe.DFEAbsTol(1)),
+			},
+			expCSV: "key,change,column,old,new\n" +
+				"k1,removed,,,\n" +
+				"k4,added,,,\n",
+		},
+		{
+			ID: testhelper.MkID("repeated key"),
+			ExpErr: testhelper.MkExpErr("the second dataframe",
+				`the key Column 0 ("id": "String") is repeated:`,
+				"rows 0 and 1 have the value k2"),
+			b:    mk("id f s\nk2 1 a\nk2 2 b\n"),
+			opts: []dataframe.DiffOpt{dataframe.DFDKeyCol("id")},
+		},
+		{
+			ID:     testhelper.MkID("unknown key"),
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "x"`),
+			b:      mk("id f s\nk1 1 a\n"),
+			opts:   []dataframe.DiffOpt{dataframe.DFDKeyCol("x")},
+		},
+		{
+			ID: testhelper.MkID("different columns"),
+			ExpErr: testhelper.MkExpErr(
+				`the columns differ: only in this dataframe: ["s"];` +
+					` only in the other dataframe: ["t"]`),
+			b: mk("id f t\nk1 1 a\n"),
+		},
+	}
+
+	for _, tc := range testCases {
+		rdf, err := dataframe.Diff(a, tc.b, tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			var sb strings.Builder
+			if err := rdf.WriteCSV(&sb); err != nil {
+				t.Fatal("unexpected error writing the report: ", err)
+			}
+			testhelper.DiffString(t, tc.IDStr(), "report",
+				sb.String(), tc.expCSV)
+		}
+	}
+}
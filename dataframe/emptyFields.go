@@ -0,0 +1,62 @@
+package dataframe
+
+import (
+	"errors"
+	"strconv"
+)
+
+// errEmptyField is the error reported for an empty field if the
+// EmptyFieldPolicy is EmptyIsError
+var errEmptyField = errors.New("the field is empty")
+
+// EmptyFieldPolicy determines how the DFReader handles an empty field, such
+// as is produced by two consecutive separators ("1,,3")
+type EmptyFieldPolicy uint
+
+// EmptyAsString reads an empty field like any other value so it gives an
+// empty string in a string column but an error in any other column and a
+// column with empty fields will be guessed to be a string column. This is
+// the default.
+//
+// EmptyAsNA reads an empty field as an NA value in any column and ignores
+// it when guessing the column types.
+//
+// EmptyIsError reports an empty field as an error, giving an NA value, and
+// ignores it when guessing the column types.
+//
+// EmptyFieldPolicyMaxVal is a guard value used to ensure validity
+const (
+	EmptyAsString EmptyFieldPolicy = iota
+	EmptyAsNA
+	EmptyIsError
+	EmptyFieldPolicyMaxVal
+)
+
+// String returns the name of the policy
+func (p EmptyFieldPolicy) String() string {
+	switch p {
+	case EmptyAsString:
+		return "EmptyAsString"
+	case EmptyAsNA:
+		return "EmptyAsNA"
+	case EmptyIsError:
+		return "EmptyIsError"
+	}
+	return "EmptyFieldPolicy(" + strconv.FormatUint(uint64(p), 10) + ")"
+}
+
+// DFREmptyFields returns a function which will cause the DFReader to
+// handle empty fields according to the policy. Note that, with the default
+// field separator, consecutive spaces are a single separator and so empty
+// fields are only seen with quoted fields (see DFRQuotedFields) or with a
+// different separator (see SplitPattern).
+func DFREmptyFields(p EmptyFieldPolicy) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if p >= EmptyFieldPolicyMaxVal {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"bad empty field policy: %s", p)
+		}
+		dfr.parseOpts.emptyFields = p
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFREmptyFields(t *testing.T) {
+	const content = `a,b,c,d
+1,x,1.5,
+,y,,
+3,,2.5,
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		policy    dataframe.EmptyFieldPolicy
+		expCols   []dataframe.ColInfo
+		expVals   [][]string
+		expErrCnt int64
+	}{
+		{
+			ID:     testhelper.MkID("as string"),
+			policy: dataframe.EmptyAsString,
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("d", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"1", "x", "1.5", ""},
+				{"", "y", "", ""},
+				{"3", "", "2.5", ""},
+			},
+		},
+		{
+			ID:     testhelper.MkID("as NA"),
+			policy: dataframe.EmptyAsNA,
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("d", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"1", "x", "1.5", "NA"},
+				{"NA", "y", "NA", "NA"},
+				{"3", "NA", "2.5", "NA"},
+			},
+		},
+		{
+			ID: testhelper.MkID("is error"),
+			ExpErr: testhelper.MkExpErr(
+				"errors parsing 3 of the initial lines,",
+				"the first is: test data:2: data row: 1 column: 3:",
+				"the field is empty"),
+			policy: dataframe.EmptyIsError,
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(
+			dataframe.HasHeader,
+			dataframe.SplitPattern(","),
+			dataframe.DFREmptyFields(tc.policy))
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(content), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+
+	id := "is error, errors allowed"
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.SplitPattern(","),
+		dataframe.DFREmptyFields(dataframe.EmptyIsError))
+	testhelper.DiffInt(t, id, "error count", df.ErrCount(), 6)
+	checkDFVals(t, id, df, [][]string{
+		{"1", "x", "1.5", "NA"},
+		{"NA", "y", "NA", "NA"},
+		{"3", "NA", "2.5", "NA"},
+	})
+
+	_, err := dataframe.NewDFReader(
+		dataframe.DFREmptyFields(dataframe.EmptyFieldPolicyMaxVal))
+	testhelper.CheckExpErrWithID(t, "bad policy", err,
+		testhelper.MkExpErr("bad empty field policy: EmptyFieldPolicy(3)"))
+}
@@ -0,0 +1,212 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// dfEqualOpts holds the settings controlling the comparison made by Equal
+type dfEqualOpts struct {
+	absTol         float64
+	relTol         float64
+	naUnequal      bool
+	ignoreColOrder bool
+}
+
+// DFEqualOpt is the type of an option function for Equal
+type DFEqualOpt func(*dfEqualOpts) error
+
+// checkTol returns an error if the tolerance is invalid
+func checkTol(name string, tol float64) error {
+	if tol < 0 || math.IsNaN(tol) || math.IsInf(tol, 1) {
+		return dfErrorf("the %s tolerance must be a number >= 0: %g",
+			name, tol)
+	}
+	return nil
+}
+
+// DFEAbsTol returns a function which will cause Equal to treat two float
+// values as equal if they differ by no more than the tolerance
+func DFEAbsTol(tol float64) DFEqualOpt {
+	return func(o *dfEqualOpts) error {
+		if err := checkTol("absolute", tol); err != nil {
+			return err
+		}
+		o.absTol = tol
+		return nil
+	}
+}
+
+// DFERelTol returns a function which will cause Equal to treat two float
+// values as equal if they differ by no more than the tolerance times the
+// larger of their magnitudes
+func DFERelTol(tol float64) DFEqualOpt {
+	return func(o *dfEqualOpts) error {
+		if err := checkTol("relative", tol); err != nil {
+			return err
+		}
+		o.relTol = tol
+		return nil
+	}
+}
+
+// DFENAUnequal is a function which will cause Equal to treat an NA value
+// as being different from every value, including another NA, as in SQL. By
+// default two NA values are equal.
+func DFENAUnequal(o *dfEqualOpts) error {
+	o.naUnequal = true
+	return nil
+}
+
+// DFEIgnoreColOrder is a function which will cause Equal to match the
+// columns by name rather than by position so that two dataframes with the
+// same columns in a different order can be equal. If the column names
+// differ the error lists every column present in only one of the
+// dataframes.
+func DFEIgnoreColOrder(o *dfEqualOpts) error {
+	o.ignoreColOrder = true
+	return nil
+}
+
+// floatsEqual returns true if the two floats are equal within the
+// tolerances. Two NaN values are equal.
+func (o dfEqualOpts) floatsEqual(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	diff := math.Abs(a - b)
+	if diff <= o.absTol {
+		return true
+	}
+	return diff <= o.relTol*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// valsEqual returns true if the two values, which must be of the same Val
+// type, are equal
+func (o dfEqualOpts) valsEqual(a any, aIsNA bool, b any, bIsNA bool) bool {
+	if aIsNA || bIsNA {
+		return aIsNA && bIsNA && !o.naUnequal
+	}
+	switch av := a.(type) {
+	case BoolVal:
+		return av.Val == b.(BoolVal).Val
+	case IntVal:
+		return av.Val == b.(IntVal).Val
+	case FloatVal:
+		return o.floatsEqual(av.Val, b.(FloatVal).Val)
+	case StringVal:
+		return av.Val == b.(StringVal).Val
+	}
+	panic(dfErrorf("Unexpected value type: %T", a))
+}
+
+// colNameDiffs returns an error listing the columns present in only one
+// of the dataframes or nil if they have the same column names
+func (df *DF) colNameDiffs(other *DF) error {
+	var onlyDF, onlyOther []string
+	for _, ci := range df.mci.info {
+		if _, ok := other.mci.nameToCol[ci.name]; !ok {
+			onlyDF = append(onlyDF, ci.name)
+		}
+	}
+	for _, ci := range other.mci.info {
+		if _, ok := df.mci.nameToCol[ci.name]; !ok {
+			onlyOther = append(onlyOther, ci.name)
+		}
+	}
+
+	var diffs []string
+	if len(onlyDF) > 0 {
+		diffs = append(diffs,
+			fmt.Sprintf("only in this dataframe: %q", onlyDF))
+	}
+	if len(onlyOther) > 0 {
+		diffs = append(diffs,
+			fmt.Sprintf("only in the other dataframe: %q", onlyOther))
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	return dfKindErrorf(ErrUnknownColumn,
+		"the columns differ: %s", strings.Join(diffs, "; "))
+}
+
+// matchCols returns, for each column in df, the index of the matching
+// column in other. It returns an error describing the first difference if
+// the columns don't match.
+func (df *DF) matchCols(other *DF, o dfEqualOpts) ([]int, error) {
+	if o.ignoreColOrder {
+		if err := df.colNameDiffs(other); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(df.mci.info) != len(other.mci.info) {
+		return nil, dfKindErrorf(ErrDimensionMismatch,
+			"differing numbers of columns: %d != %d",
+			len(df.mci.info), len(other.mci.info))
+	}
+
+	otherIdx := make([]int, len(df.mci.info))
+	for i, ci := range df.mci.info {
+		j := i
+		if o.ignoreColOrder {
+			j = other.mci.nameToCol[ci.name]
+		}
+
+		oci := other.mci.info[j]
+		if ci.name != oci.name {
+			return nil, dfErrorf("%s has a different name: %q != %q",
+				df.mci.ColDesc(i), ci.name, oci.name)
+		}
+		if ci.colType != oci.colType {
+			return nil, dfKindErrorf(ErrTypeMismatch,
+				"%s has a different type: %s != %s",
+				df.mci.ColDesc(i), ci.colType, oci.colType)
+		}
+		otherIdx[i] = j
+	}
+	return otherIdx, nil
+}
+
+// Equal returns nil if the two dataframes hold the same columns and values
+// or an error describing the first difference found otherwise. By default
+// the columns must be in the same order, two NA values are equal and float
+// values must be identical (two NaN values are equal); the options can
+// relax or change these rules. Only the columns and values are compared;
+// the errors, indexes, compression and column metadata are ignored.
+func (df *DF) Equal(other *DF, opts ...DFEqualOpt) error {
+	var o dfEqualOpts
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return err
+		}
+	}
+
+	otherIdx, err := df.matchCols(other, o)
+	if err != nil {
+		return err
+	}
+
+	if df.RowCount() != other.RowCount() {
+		return dfKindErrorf(ErrDimensionMismatch,
+			"differing numbers of rows: %d != %d",
+			df.RowCount(), other.RowCount())
+	}
+
+	for r := 0; r < df.RowCount(); r++ {
+		for c, oc := range otherIdx {
+			a, aIsNA := df.valAt(c, r)
+			b, bIsNA := other.valAt(oc, r)
+			if !o.valsEqual(a, aIsNA, b, bIsNA) {
+				return dfErrorf("row %d, %s: the values differ: %v != %v",
+					r, df.mci.ColDesc(c), a, b)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestEqual(t *testing.T) {
+	mk := func(content string, types ...dataframe.ColType) *dataframe.DF {
+		return mkTestDF(t, content,
+			dataframe.HasHeader, dataframe.AllowErrors,
+			dataframe.DFRColTypes(types...))
+	}
+	ifs := []dataframe.ColType{
+		dataframe.ColTypeInt, dataframe.ColTypeFloat, dataframe.ColTypeString,
+	}
+	df := mk("i f s\n1 100 a\n2 NA b\n", ifs...)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		other *dataframe.DF
+		opts  []dataframe.DFEqualOpt
+	}{
+		{
+			ID:    testhelper.MkID("identical"),
+			other: mk("i f s\n1 100 a\n2 NA b\n", ifs...),
+		},
+		{
+			ID: testhelper.MkID("NA unequal"),
+			ExpErr: testhelper.MkExpErr(
+				"row 1, Column 1", "the values differ: NA != NA"),
+			other: mk("i f s\n1 100 a\n2 NA b\n", ifs...),
+			opts:  []dataframe.DFEqualOpt{dataframe.DFENAUnequal},
+		},
+		{
+			ID: testhelper.MkID("float differs"),
+			ExpErr: testhelper.MkExpErr(
+				"row 0, Column 1", "the values differ: 100 != 100.5"),
+			other: mk("i f s\n1 100.5 a\n2 NA b\n", ifs...),
+		},
+		{
+			ID:    testhelper.MkID("within absolute tolerance"),
+			other: mk("i f s\n1 100.5 a\n2 NA b\n", ifs...),
+			opts:  []dataframe.DFEqualOpt{dataframe.DFEAbsTol(0.5)},
+		},
+		{
+			ID:    testhelper.MkID("within relative tolerance"),
+			other: mk("i f s\n1 100.5 a\n2 NA b\n", ifs...),
+			opts:  []dataframe.DFEqualOpt{dataframe.DFERelTol(0.01)},
+		},
+		{
+			ID: testhelper.MkID("outside relative tolerance"),
+			ExpErr: testhelper.MkExpErr(
+				"the values differ: 100 != 100.5"),
+			other: mk("i f s\n1 100.5 a\n2 NA b\n", ifs...),
+			opts:  []dataframe.DFEqualOpt{dataframe.DFERelTol(0.001)},
+		},
+		{
+			ID: testhelper.MkID("columns in a different order"),
+			ExpErr: testhelper.MkExpErr(
+				`has a different name: "i" != "s"`),
+			other: mk("s i f\na 1 100\nb 2 NA\n",
+				dataframe.ColTypeString, dataframe.ColTypeInt,
+				dataframe.ColTypeFloat),
+		},
+		{
+			ID: testhelper.MkID("column order ignored"),
+			other: mk("s i f\na 1 100\nb 2 NA\n",
+				dataframe.ColTypeString, dataframe.ColTypeInt,
+				dataframe.ColTypeFloat),
+			opts: []dataframe.DFEqualOpt{dataframe.DFEIgnoreColOrder},
+		},
+		{
+			ID: testhelper.MkID("column missing"),
+			ExpErr: testhelper.MkExpErr(
+				`the columns differ: only in this dataframe: ["s"];` +
+					` only in the other dataframe: ["t"]`),
+			other: mk("t i f\na 1 100\nb 2 NA\n",
+				dataframe.ColTypeString, dataframe.ColTypeInt,
+				dataframe.ColTypeFloat),
+			opts: []dataframe.DFEqualOpt{dataframe.DFEIgnoreColOrder},
+		},
+		{
+			ID: testhelper.MkID("extra column, order ignored"),
+			ExpErr: testhelper.MkExpErr(
+				`the columns differ: only in the other dataframe: ["g"]`),
+			other: mk("s g i f\na x 1 100\nb y 2 NA\n",
+				dataframe.ColTypeString, dataframe.ColTypeString,
+				dataframe.ColTypeInt, dataframe.ColTypeFloat),
+			opts: []dataframe.DFEqualOpt{dataframe.DFEIgnoreColOrder},
+		},
+		{
+			ID: testhelper.MkID("type differs"),
+			ExpErr: testhelper.MkExpErr(
+				"has a different type: Int != Float"),
+			other: mk("i f s\n1 100 a\n2 NA b\n",
+				dataframe.ColTypeFloat, dataframe.ColTypeFloat,
+				dataframe.ColTypeString),
+		},
+		{
+			ID:     testhelper.MkID("rows differ"),
+			ExpErr: testhelper.MkExpErr("differing numbers of rows: 2 != 1"),
+			other:  mk("i f s\n1 100 a\n", ifs...),
+		},
+		{
+			ID: testhelper.MkID("bad tolerance"),
+			ExpErr: testhelper.MkExpErr(
+				"the absolute tolerance must be a number >= 0: -1"),
+			other: mk("i f s\n1 100 a\n2 NA b\n", ifs...),
+			opts:  []dataframe.DFEqualOpt{dataframe.DFEAbsTol(-1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := df.Equal(tc.other, tc.opts...)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
@@ -2,16 +2,31 @@ package dataframe
 
 import "fmt"
 
+// Error is the interface satisfied by all the errors generated by this
+// package
 type Error interface {
 	error
 	DataframeError()
 }
 
+// errPrefix is the prefix added to the text of all dataframe errors
+const errPrefix = "dataframe error: "
+
 type dfError string
 
 // Error returns a string representation of the error
 func (e dfError) Error() string {
-	return "dataframe error: " + string(e)
+	return formatError(e.details())
+}
+
+// details returns the parts of the error
+func (e dfError) details() ErrorDetails {
+	return ErrorDetails{
+		Kind: errKind(e),
+		Code: e.errCode(),
+		Msg:  string(e),
+		Col:  -1,
+	}
 }
 
 // DataframeError exists purely to classify the error as a dataframe.Error
@@ -35,7 +50,255 @@ var (
 		" give some lines to work it out")
 )
 
+// These are the categories of error. Any error in one of these categories
+// will match the corresponding value when tested using errors.Is, for
+// instance:
+//
+//	if errors.Is(err, dataframe.ErrUnknownColumn) {
+//	    ...
+//	}
+//
+// ErrParse is the category for errors found when parsing a value or line
+// ErrUnknownColumn is the category for references to a column that doesn't
+// exist, either by name or by index
+// ErrNoSuchRow is the category for references to a row that doesn't exist
+// ErrTypeMismatch is the category for errors where the type of a column or
+// value is not the type needed
+// ErrDimensionMismatch is the category for errors where the numbers of
+// columns or rows do not match
+// ErrSchemaMismatch is the category for errors where a dataframe does not
+// match the expected Schema
+// ErrCheckFailed is the category for values which fail a check
+// ErrTooManyErrors is the category for reads abandoned because too many
+// errors have been seen
+// ErrInternal is the category for reads abandoned because of a panic (see
+// DFRRecoverPanics)
+// ErrInvalidArgument is the category for bad values passed to a function,
+// such as a repeated column name or a bad option value
+// ErrInvalidState is the category for operations which cannot be performed
+// in the current state, such as a lookup on a column with no index or the
+// use of a finished transaction
+var (
+	ErrParse             = dfError("parse error")
+	ErrUnknownColumn     = dfError("unknown column")
+	ErrNoSuchRow         = dfError("no such row")
+	ErrTypeMismatch      = dfError("type mismatch")
+	ErrDimensionMismatch = dfError("dimension mismatch")
+	ErrSchemaMismatch    = dfError("schema mismatch")
+	ErrCheckFailed       = dfError("check failed")
+	ErrTooManyErrors     = dfError("too many errors")
+	ErrInternal          = dfError("internal error")
+	ErrInvalidArgument   = dfError("invalid argument")
+	ErrInvalidState      = dfError("invalid state")
+)
+
+// kindError is a dataframe error belonging to one of the error categories
+type kindError struct {
+	kind dfError
+	msg  string
+}
+
+// Error returns a string representation of the error
+func (e kindError) Error() string {
+	return formatError(e.details())
+}
+
+// details returns the parts of the error
+func (e kindError) details() ErrorDetails {
+	return ErrorDetails{
+		Kind: string(e.kind),
+		Code: e.errCode(),
+		Msg:  e.msg,
+		Col:  -1,
+	}
+}
+
+// DataframeError exists purely to classify the error as a dataframe.Error
+func (e kindError) DataframeError() {}
+
+// Unwrap returns the error category so that the error can be matched using
+// errors.Is
+func (e kindError) Unwrap() error {
+	return e.kind
+}
+
+// wrappedError is a dataframe error giving the context of another error
+type wrappedError struct {
+	msg string
+	err error
+}
+
+// Error returns a string representation of the error
+func (e wrappedError) Error() string {
+	return formatError(e.details())
+}
+
+// details returns the parts of the error
+func (e wrappedError) details() ErrorDetails {
+	return ErrorDetails{
+		Kind: errKind(e),
+		Code: e.errCode(),
+		Msg:  e.msg + ": " + errText(e.err),
+		Col:  -1,
+	}
+}
+
+// DataframeError exists purely to classify the error as a dataframe.Error
+func (e wrappedError) DataframeError() {}
+
+// Unwrap returns the wrapped error so that the error can be matched using
+// errors.Is or errors.As
+func (e wrappedError) Unwrap() error {
+	return e.err
+}
+
+// ParseError records the details of a problem found while adding a row of
+// text values to a dataframe, typically while reading from a data source.
+// The fields allow the location of bad data to be reported and aggregated
+// programmatically.
+//
+// Depending on the problem it will match either ErrParse (for a value that
+// cannot be parsed or an unexpected line) or ErrDimensionMismatch (for a
+// line with the wrong number of columns) when tested using errors.Is.
+type ParseError struct {
+	// Source is the name of the data source. It will be empty if the row
+	// did not come from a DFReader
+	Source string
+	// Line is the line number in the source, counting from 1. It will be
+	// zero if the row did not come from a DFReader
+	Line int64
+	// Col is the index of the column in the dataframe, counting from 0. It
+	// will be -1 if the problem is not with any particular column
+	Col int
+	// Field is the text of the value that could not be parsed. It will be
+	// empty if the problem is not with any particular column
+	Field string
+	// Text is the text of the line on which the problem was found. It
+	// will be empty unless the DFReader was given DFRErrorText
+	Text string
+	// Msg describes the problem
+	Msg string
+	// Code identifies the problem (see ErrorCode). If it is not set the
+	// code of the error category is used, see ErrCode
+	Code ErrorCode
+
+	kind dfError
+}
+
+// Error returns a string representation of the error
+func (e ParseError) Error() string {
+	return formatError(e.details())
+}
+
+// details returns the parts of the error
+func (e ParseError) details() ErrorDetails {
+	return ErrorDetails{
+		Kind:   string(e.kind),
+		Code:   e.errCode(),
+		Msg:    e.Msg,
+		Source: e.Source,
+		Line:   e.Line,
+		Col:    e.Col,
+		Field:  e.Field,
+		Text:   e.Text,
+	}
+}
+
+// DataframeError exists purely to classify the error as a dataframe.Error
+func (e ParseError) DataframeError() {}
+
+// Unwrap returns the error category so that the error can be matched using
+// errors.Is
+func (e ParseError) Unwrap() error {
+	return e.kind
+}
+
 // dfErrorf formats the arguments into a dfError
 func dfErrorf(format string, args ...any) dfError {
 	return dfError(fmt.Sprintf(format, args...))
 }
+
+// codedError is a dataframe error with a specific ErrorCode, belonging to
+// the error category for that code
+type codedError struct {
+	kind dfError
+	code ErrorCode
+	msg  string
+}
+
+// Error returns a string representation of the error
+func (e codedError) Error() string {
+	return formatError(e.details())
+}
+
+// details returns the parts of the error
+func (e codedError) details() ErrorDetails {
+	return ErrorDetails{
+		Kind: string(e.kind),
+		Code: e.code,
+		Msg:  e.msg,
+		Col:  -1,
+	}
+}
+
+// DataframeError exists purely to classify the error as a dataframe.Error
+func (e codedError) DataframeError() {}
+
+// Unwrap returns the error category so that the error can be matched using
+// errors.Is
+func (e codedError) Unwrap() error {
+	return e.kind
+}
+
+// dfCodeErrorf formats the arguments into an error with the given code, in
+// the category for that code (see codeKinds)
+func dfCodeErrorf(code ErrorCode, format string, args ...any) codedError {
+	return codedError{
+		kind: codeKinds[code],
+		code: code,
+		msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+// dfKindErrorf formats the arguments into an error of the given category
+func dfKindErrorf(kind dfError, format string, args ...any) kindError {
+	return kindError{kind: kind, msg: fmt.Sprintf(format, args...)}
+}
+
+// dfWrapf formats the arguments into a description of the context of the
+// error and returns an error wrapping the error
+func dfWrapf(err error, format string, args ...any) wrappedError {
+	return wrappedError{msg: fmt.Sprintf(format, args...), err: err}
+}
+
+// errUnknownColName returns an error reporting that there is no column with
+// the given name
+func errUnknownColName(name string) kindError {
+	return dfKindErrorf(ErrUnknownColumn, "Unknown column name: %q", name)
+}
+
+// errUnknownColIdx returns an error reporting that there is no column with
+// the given index
+func errUnknownColIdx(i, colCount int) kindError {
+	return dfKindErrorf(ErrUnknownColumn,
+		"There is no column %d (valid range: 0-%d)", i, colCount-1)
+}
+
+// errUntypedCol returns an error reporting that the named column has no
+// type, as for the columns of a dataframe read from input having a header
+// but no data
+func errUntypedCol(name string) kindError {
+	return dfKindErrorf(ErrTypeMismatch,
+		"the column named %q has no type (there is no data)", name)
+}
+
+// errText returns the text of the error. If the error is a dataframe error
+// the text is returned without the standard prefix, regardless of the
+// ErrorFormatter, so that it can be included in the message of another
+// dataframe error.
+func errText(err error) string {
+	if e, ok := err.(interface{ details() ErrorDetails }); ok {
+		return e.details().text()
+	}
+	return err.Error()
+}
@@ -0,0 +1,274 @@
+package dataframe
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrorCode identifies the condition giving rise to a dataframe error so
+// that callers can act on particular errors without having to match the
+// text of the message. The code of an error is given by ErrCode. Not every
+// error has a specific code yet; those without one have ErrCodeOther.
+type ErrorCode uint
+
+// ErrCodeNone is the code given to an error which is not a dataframe error
+//
+// ErrCodeOther is the code given to a dataframe error with no more specific
+// code
+//
+// The following codes are given to the errors returned by the DFReader
+// options, they correspond to the error values with the same names:
+// ErrCodeHasNamesAndHeader, ErrCodeNoSkipColsGiven,
+// ErrCodeSkipIndexesAlreadySet, ErrCodeNoNamesGiven,
+// ErrCodeNamesAlreadySet, ErrCodeNoTypesGiven, ErrCodeTypesAlreadySet and
+// ErrCodeNoTypeInfo
+//
+// The following codes are given to errors in the corresponding error
+// category (see ErrParse etc) for which there is no more specific code:
+// ErrCodeParse, ErrCodeUnknownColumn, ErrCodeNoSuchRow,
+// ErrCodeTypeMismatch, ErrCodeDimensionMismatch, ErrCodeSchemaMismatch,
+// ErrCodeCheckFailed, ErrCodeTooManyErrors, ErrCodeInternal,
+// ErrCodeInvalidArgument and ErrCodeInvalidState
+//
+// ErrCodeBadValue is given to a ParseError for a value that cannot be read
+// as the type of its column
+//
+// ErrCodeEmptyField is given to a ParseError for an empty field when empty
+// fields are errors (see DFREmptyFields)
+//
+// ErrCodeWrongFieldCount is given to a ParseError for a line with the wrong
+// number of fields
+//
+// ErrCodeBlankLine is given to a ParseError for a blank line when blank
+// lines are not being skipped (see SkipBlankLines)
+//
+// ErrCodeBadQuoting is given to a ParseError for a line whose quoted fields
+// cannot be split
+//
+// ErrCodeBadMetaLine is given to a ParseError for a metadata line that
+// cannot be read in round-trip mode
+//
+// ErrCodeBadTypesLine is given to a ParseError for a column types line that
+// cannot be read in round-trip mode
+//
+// ErrCodeNAValue is given to a ParseError for an NA value in a column which
+// the Schema requires to have values
+//
+// The following codes are given to errors in the ErrInvalidArgument
+// category:
+//
+// ErrCodeDuplicateColName is given to an error for a column name which is
+// already in use, or which differs only in case from one in use when the
+// names are case-insensitive
+//
+// ErrCodeDuplicateKeyCol is given to an error for a key column given more
+// than once
+//
+// ErrCodeNoKeyCols is given to an error for a grouping for which no key
+// columns have been given
+//
+// ErrCodeBadOption is given to an error for a bad value given to an option
+// function, such as those for a DFReader or DFWriter
+//
+// The following codes are given to errors in the ErrInvalidState category:
+//
+// ErrCodeNoIndex is given to an error for a lookup on a column with no
+// index (see BuildIndex)
+//
+// ErrCodeNoKey is given to an error for an operation needing a key column
+// when none has been set or given (see SetIndex)
+//
+// ErrCodeTxnFinished is given to an error for the use of a transaction
+// which has already been committed or rolled back
+//
+// ErrCodeKeyNotUnique is given to an error, in the ErrCheckFailed category,
+// for key values which are not unique
+//
+// ErrorCodeMaxVal is a guard value used to ensure validity
+const (
+	ErrCodeNone ErrorCode = iota
+	ErrCodeOther
+
+	ErrCodeHasNamesAndHeader
+	ErrCodeNoSkipColsGiven
+	ErrCodeSkipIndexesAlreadySet
+	ErrCodeNoNamesGiven
+	ErrCodeNamesAlreadySet
+	ErrCodeNoTypesGiven
+	ErrCodeTypesAlreadySet
+	ErrCodeNoTypeInfo
+
+	ErrCodeParse
+	ErrCodeUnknownColumn
+	ErrCodeNoSuchRow
+	ErrCodeTypeMismatch
+	ErrCodeDimensionMismatch
+	ErrCodeSchemaMismatch
+	ErrCodeCheckFailed
+	ErrCodeTooManyErrors
+	ErrCodeInternal
+
+	ErrCodeBadValue
+	ErrCodeEmptyField
+	ErrCodeWrongFieldCount
+	ErrCodeBlankLine
+	ErrCodeBadQuoting
+	ErrCodeBadMetaLine
+	ErrCodeBadTypesLine
+	ErrCodeNAValue
+
+	ErrCodeDuplicateColName
+	ErrCodeDuplicateKeyCol
+	ErrCodeNoKeyCols
+	ErrCodeNoIndex
+	ErrCodeNoKey
+	ErrCodeKeyNotUnique
+	ErrCodeTxnFinished
+	ErrCodeBadOption
+
+	ErrCodeInvalidArgument
+	ErrCodeInvalidState
+
+	ErrorCodeMaxVal
+)
+
+// errCodeNames holds the names of the error codes, indexed by code
+var errCodeNames = [...]string{
+	ErrCodeNone:                  "None",
+	ErrCodeOther:                 "Other",
+	ErrCodeHasNamesAndHeader:     "HasNamesAndHeader",
+	ErrCodeNoSkipColsGiven:       "NoSkipColsGiven",
+	ErrCodeSkipIndexesAlreadySet: "SkipIndexesAlreadySet",
+	ErrCodeNoNamesGiven:          "NoNamesGiven",
+	ErrCodeNamesAlreadySet:       "NamesAlreadySet",
+	ErrCodeNoTypesGiven:          "NoTypesGiven",
+	ErrCodeTypesAlreadySet:       "TypesAlreadySet",
+	ErrCodeNoTypeInfo:            "NoTypeInfo",
+	ErrCodeParse:                 "Parse",
+	ErrCodeUnknownColumn:         "UnknownColumn",
+	ErrCodeNoSuchRow:             "NoSuchRow",
+	ErrCodeTypeMismatch:          "TypeMismatch",
+	ErrCodeDimensionMismatch:     "DimensionMismatch",
+	ErrCodeSchemaMismatch:        "SchemaMismatch",
+	ErrCodeCheckFailed:           "CheckFailed",
+	ErrCodeTooManyErrors:         "TooManyErrors",
+	ErrCodeInternal:              "Internal",
+	ErrCodeBadValue:              "BadValue",
+	ErrCodeEmptyField:            "EmptyField",
+	ErrCodeWrongFieldCount:       "WrongFieldCount",
+	ErrCodeBlankLine:             "BlankLine",
+	ErrCodeBadQuoting:            "BadQuoting",
+	ErrCodeBadMetaLine:           "BadMetaLine",
+	ErrCodeBadTypesLine:          "BadTypesLine",
+	ErrCodeNAValue:               "NAValue",
+	ErrCodeDuplicateColName:      "DuplicateColName",
+	ErrCodeDuplicateKeyCol:       "DuplicateKeyCol",
+	ErrCodeNoKeyCols:             "NoKeyCols",
+	ErrCodeNoIndex:               "NoIndex",
+	ErrCodeNoKey:                 "NoKey",
+	ErrCodeKeyNotUnique:          "KeyNotUnique",
+	ErrCodeTxnFinished:           "TxnFinished",
+	ErrCodeBadOption:             "BadOption",
+	ErrCodeInvalidArgument:       "InvalidArgument",
+	ErrCodeInvalidState:          "InvalidState",
+}
+
+// String returns the name of the error code
+func (c ErrorCode) String() string {
+	if c < ErrorCodeMaxVal {
+		return errCodeNames[c]
+	}
+	return "ErrorCode(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// dfErrorCodes maps the dataframe error values to their codes
+var dfErrorCodes = map[dfError]ErrorCode{
+	ErrHasNamesAndHeader:     ErrCodeHasNamesAndHeader,
+	ErrNoSkipColsGiven:       ErrCodeNoSkipColsGiven,
+	ErrSkipIndexesAlreadySet: ErrCodeSkipIndexesAlreadySet,
+	ErrNoNamesGiven:          ErrCodeNoNamesGiven,
+	ErrNamesAlreadySet:       ErrCodeNamesAlreadySet,
+	ErrNoTypesGiven:          ErrCodeNoTypesGiven,
+	ErrTypesAlreadySet:       ErrCodeTypesAlreadySet,
+	ErrNoTypeInfo:            ErrCodeNoTypeInfo,
+
+	ErrParse:             ErrCodeParse,
+	ErrUnknownColumn:     ErrCodeUnknownColumn,
+	ErrNoSuchRow:         ErrCodeNoSuchRow,
+	ErrTypeMismatch:      ErrCodeTypeMismatch,
+	ErrDimensionMismatch: ErrCodeDimensionMismatch,
+	ErrSchemaMismatch:    ErrCodeSchemaMismatch,
+	ErrCheckFailed:       ErrCodeCheckFailed,
+	ErrTooManyErrors:     ErrCodeTooManyErrors,
+	ErrInternal:          ErrCodeInternal,
+	ErrInvalidArgument:   ErrCodeInvalidArgument,
+	ErrInvalidState:      ErrCodeInvalidState,
+}
+
+// codeKinds maps the specific error codes given by dfCodeErrorf to their
+// error categories
+var codeKinds = map[ErrorCode]dfError{
+	ErrCodeDuplicateColName: ErrInvalidArgument,
+	ErrCodeDuplicateKeyCol:  ErrInvalidArgument,
+	ErrCodeNoKeyCols:        ErrInvalidArgument,
+	ErrCodeBadOption:        ErrInvalidArgument,
+	ErrCodeNoIndex:          ErrInvalidState,
+	ErrCodeNoKey:            ErrInvalidState,
+	ErrCodeTxnFinished:      ErrInvalidState,
+	ErrCodeKeyNotUnique:     ErrCheckFailed,
+}
+
+// errCoder is satisfied by the errors which carry an ErrorCode
+type errCoder interface {
+	errCode() ErrorCode
+}
+
+// ErrCode returns the code of the first dataframe error in the chain of
+// errors (see errors.As) or ErrCodeNone if there is none. For instance:
+//
+//	if dataframe.ErrCode(err) == dataframe.ErrCodeWrongFieldCount {
+//		...
+//	}
+func ErrCode(err error) ErrorCode {
+	var ec errCoder
+	if errors.As(err, &ec) {
+		return ec.errCode()
+	}
+	return ErrCodeNone
+}
+
+// errCode returns the code of the error
+func (e dfError) errCode() ErrorCode {
+	if c, ok := dfErrorCodes[e]; ok {
+		return c
+	}
+	return ErrCodeOther
+}
+
+// errCode returns the code recorded in the error
+func (e codedError) errCode() ErrorCode {
+	return e.code
+}
+
+// errCode returns the code of the error category
+func (e kindError) errCode() ErrorCode {
+	return e.kind.errCode()
+}
+
+// errCode returns the code of the wrapped error if it is a dataframe error
+// and ErrCodeOther otherwise
+func (e wrappedError) errCode() ErrorCode {
+	if c := ErrCode(e.err); c != ErrCodeNone {
+		return c
+	}
+	return ErrCodeOther
+}
+
+// errCode returns the code recorded in the error or, if none is recorded,
+// the code of the error category
+func (e ParseError) errCode() ErrorCode {
+	if e.Code != ErrCodeNone {
+		return e.Code
+	}
+	return e.kind.errCode()
+}
@@ -0,0 +1,158 @@
+package dataframe_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestErrCode(t *testing.T) {
+	df := mkTestDF(t, "i s\n1 a\n2 b\n", dataframe.HasHeader)
+	_, unknownColErr := df.ColInfoByName("nonesuch")
+	_, noRowErr := mkIntCol("i", 1).GetVal(99)
+	dupColNameErr := df.SetColNames("x", "x")
+	_, noIndexErr := df.LookupRows("i", int64(1))
+	_, noKeyErr := df.RowByKey(int64(1))
+	_, noKeyColsErr := df.GroupByCols()
+	_, dupKeyColErr := df.GroupByCols("s", "s")
+	_, badOptionErr := dataframe.NewDFReader(dataframe.SkipLines(-1))
+
+	nonUniqueDF := mkTestDF(t, "i\n1\n1\n", dataframe.HasHeader)
+	keyNotUniqueErr := nonUniqueDF.SetIndex("i")
+
+	txn := df.Begin()
+	txn.Rollback()
+	txnFinishedErr := txn.Commit()
+
+	testCases := []struct {
+		testhelper.ID
+		err     error
+		expCode dataframe.ErrorCode
+	}{
+		{
+			ID:      testhelper.MkID("nil"),
+			expCode: dataframe.ErrCodeNone,
+		},
+		{
+			ID:      testhelper.MkID("not a dataframe error"),
+			err:     errors.New("whatever"),
+			expCode: dataframe.ErrCodeNone,
+		},
+		{
+			ID:      testhelper.MkID("error value"),
+			err:     dataframe.ErrNoTypeInfo,
+			expCode: dataframe.ErrCodeNoTypeInfo,
+		},
+		{
+			ID:      testhelper.MkID("error value, wrapped"),
+			err:     fmt.Errorf("context: %w", dataframe.ErrNamesAlreadySet),
+			expCode: dataframe.ErrCodeNamesAlreadySet,
+		},
+		{
+			ID:      testhelper.MkID("error category"),
+			err:     unknownColErr,
+			expCode: dataframe.ErrCodeUnknownColumn,
+		},
+		{
+			ID:      testhelper.MkID("no such row"),
+			err:     noRowErr,
+			expCode: dataframe.ErrCodeNoSuchRow,
+		},
+		{
+			ID:      testhelper.MkID("duplicate column name"),
+			err:     dupColNameErr,
+			expCode: dataframe.ErrCodeDuplicateColName,
+		},
+		{
+			ID:      testhelper.MkID("duplicate key column"),
+			err:     dupKeyColErr,
+			expCode: dataframe.ErrCodeDuplicateKeyCol,
+		},
+		{
+			ID:      testhelper.MkID("no key columns"),
+			err:     noKeyColsErr,
+			expCode: dataframe.ErrCodeNoKeyCols,
+		},
+		{
+			ID:      testhelper.MkID("no index"),
+			err:     noIndexErr,
+			expCode: dataframe.ErrCodeNoIndex,
+		},
+		{
+			ID:      testhelper.MkID("no key"),
+			err:     noKeyErr,
+			expCode: dataframe.ErrCodeNoKey,
+		},
+		{
+			ID:      testhelper.MkID("key not unique"),
+			err:     keyNotUniqueErr,
+			expCode: dataframe.ErrCodeKeyNotUnique,
+		},
+		{
+			ID:      testhelper.MkID("transaction finished"),
+			err:     txnFinishedErr,
+			expCode: dataframe.ErrCodeTxnFinished,
+		},
+		{
+			ID:      testhelper.MkID("bad option"),
+			err:     badOptionErr,
+			expCode: dataframe.ErrCodeBadOption,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "code",
+			dataframe.ErrCode(tc.err).String(), tc.expCode.String())
+	}
+}
+
+func TestParseErrorCode(t *testing.T) {
+	const content = "a,b\n1,x\ntwo,y\n,z\n\n3,w,extra\n"
+
+	dfr, err := dataframe.NewDFReader(
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.SplitPattern(","),
+		dataframe.DFREmptyFields(dataframe.EmptyIsError),
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeString))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	df, err := dfr.Read(strings.NewReader(content), "test data")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expCodes := []dataframe.ErrorCode{
+		dataframe.ErrCodeBadValue,
+		dataframe.ErrCodeEmptyField,
+		dataframe.ErrCodeBlankLine,
+		dataframe.ErrCodeWrongFieldCount,
+	}
+	errs := df.Errors()
+	if testhelper.DiffInt(t, "parse errors", "error count",
+		len(errs), len(expCodes)) {
+		return
+	}
+	for i, e := range errs {
+		id := fmt.Sprintf("parse error %d", i)
+		var pe dataframe.ParseError
+		if !errors.As(e, &pe) {
+			t.Log(id)
+			t.Errorf("\t: not a ParseError: %s", e)
+			continue
+		}
+		testhelper.DiffString(t, id, "code",
+			pe.Code.String(), expCodes[i].String())
+		testhelper.DiffString(t, id, "ErrCode",
+			dataframe.ErrCode(e).String(), expCodes[i].String())
+	}
+
+	testhelper.DiffString(t, "bad code", "name",
+		dataframe.ErrorCodeMaxVal.String(),
+		fmt.Sprintf("ErrorCode(%d)", dataframe.ErrorCodeMaxVal))
+}
@@ -0,0 +1,144 @@
+package dataframe
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// ErrorDetails holds the parts of a dataframe error from which an
+// ErrorFormatter makes the text of the error
+type ErrorDetails struct {
+	// Kind is the category of the error (such as ErrParse) as given by the
+	// text of the category. It is empty if the error is in no category
+	Kind string
+	// Code identifies the problem, see ErrorCode
+	Code ErrorCode
+	// Msg describes the problem, including any context
+	Msg string
+	// Source is the name of the data source for a ParseError. It will be
+	// empty for any other error
+	Source string
+	// Line is the line number in the source for a ParseError
+	Line int64
+	// Col is the index of the column for a ParseError. It will be -1 if
+	// the problem is not with any particular column or for any other error
+	Col int
+	// Field is the text of the value that could not be parsed for a
+	// ParseError
+	Field string
+	// Text is the text of the line for a ParseError (see DFRErrorText)
+	Text string
+}
+
+// text returns the description of the error, preceded by the location if
+// it is known
+func (d ErrorDetails) text() string {
+	if d.Source == "" {
+		return d.Msg
+	}
+	return fmt.Sprintf("%s:%d: %s", d.Source, d.Line, d.Msg)
+}
+
+// ErrorFormatter is the type of a function giving the text returned by the
+// Error method of a dataframe error. See SetErrorFormatter.
+type ErrorFormatter func(d ErrorDetails) string
+
+// PlainErrors gives the location of the error, if known, and the
+// description of the problem after a standard prefix ("dataframe error: ").
+// This is the default ErrorFormatter.
+func PlainErrors(d ErrorDetails) string {
+	return errPrefix + d.text()
+}
+
+// UnprefixedErrors gives the error as for PlainErrors but without the
+// standard prefix
+func UnprefixedErrors(d ErrorDetails) string {
+	return d.text()
+}
+
+// jsonError is the form in which an error is given by JSONErrors
+type jsonError struct {
+	Kind   string `json:"kind,omitempty"`
+	Code   string `json:"code"`
+	Msg    string `json:"message"`
+	Source string `json:"source,omitempty"`
+	Line   int64  `json:"line,omitempty"`
+	Col    *int   `json:"column,omitempty"`
+	Field  string `json:"field,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+// JSONErrors gives the error as a JSON object, suitable for structured
+// logging, with the parts of the ErrorDetails as the fields "kind",
+// "code", "message", "source", "line", "column", "field" and "text". The
+// code is given by name. Parts which are not known are left out.
+func JSONErrors(d ErrorDetails) string {
+	je := jsonError{
+		Kind:   d.Kind,
+		Code:   d.Code.String(),
+		Msg:    d.Msg,
+		Source: d.Source,
+		Line:   d.Line,
+		Field:  d.Field,
+		Text:   d.Text,
+	}
+	if d.Col >= 0 {
+		je.Col = &d.Col
+	}
+
+	b, err := json.Marshal(je)
+	if err != nil {
+		return PlainErrors(d)
+	}
+	return string(b)
+}
+
+// errFormatter holds the ErrorFormatter in use
+type errFormatter struct {
+	f ErrorFormatter
+}
+
+var currentErrFormatter atomic.Value
+
+// SetErrorFormatter sets the function used to give the text of all the
+// errors generated by this package, as returned by their Error methods,
+// and returns the previous one. A nil value restores the default
+// (PlainErrors). Note that this applies to every dataframe error, not just
+// those of a particular dataframe, and so should normally be set once at
+// the start of the program. The messages of errors found within other
+// dataframe errors are always given as for UnprefixedErrors.
+func SetErrorFormatter(f ErrorFormatter) ErrorFormatter {
+	if f == nil {
+		f = PlainErrors
+	}
+	prev := currentErrFormatter.Swap(errFormatter{f: f})
+	if prev == nil {
+		return PlainErrors
+	}
+	return prev.(errFormatter).f
+}
+
+// formatError returns the text of the error as given by the current
+// ErrorFormatter
+func formatError(d ErrorDetails) string {
+	if ef, ok := currentErrFormatter.Load().(errFormatter); ok {
+		return ef.f(d)
+	}
+	return PlainErrors(d)
+}
+
+// WriteErrors writes the errors recorded while constructing the dataframe
+// to the writer, one per line, formatted by the current ErrorFormatter.
+// Note that only the first maxErrors errors are recorded (see ErrCount).
+func (df DF) WriteErrors(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, err := range df.errors {
+		if _, werr := fmt.Fprintln(bw, err.Error()); werr != nil {
+			return werr
+		}
+	}
+	return bw.Flush()
+}
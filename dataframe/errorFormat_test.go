@@ -0,0 +1,94 @@
+package dataframe_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSetErrorFormatter(t *testing.T) {
+	const content = "a b\n1 x\ntwo y\n"
+
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.DFRErrorText,
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeString))
+	if len(df.Errors()) != 1 {
+		t.Fatal("expected exactly one error, got: ", len(df.Errors()))
+	}
+	parseErr := df.Errors()[0]
+	_, otherErr := df.ColInfoByName("nonesuch")
+
+	testCases := []struct {
+		testhelper.ID
+		f        dataframe.ErrorFormatter
+		expParse string
+		expOther string
+	}{
+		{
+			ID: testhelper.MkID("default"),
+			expParse: `dataframe error: test data:3: data row: 2 column: 0:` +
+				` strconv.ParseInt: parsing "two": invalid syntax`,
+			expOther: `dataframe error: Unknown column name: "nonesuch"`,
+		},
+		{
+			ID: testhelper.MkID("plain"),
+			f:  dataframe.PlainErrors,
+			expParse: `dataframe error: test data:3: data row: 2 column: 0:` +
+				` strconv.ParseInt: parsing "two": invalid syntax`,
+			expOther: `dataframe error: Unknown column name: "nonesuch"`,
+		},
+		{
+			ID: testhelper.MkID("unprefixed"),
+			f:  dataframe.UnprefixedErrors,
+			expParse: `test data:3: data row: 2 column: 0:` +
+				` strconv.ParseInt: parsing "two": invalid syntax`,
+			expOther: `Unknown column name: "nonesuch"`,
+		},
+		{
+			ID: testhelper.MkID("JSON"),
+			f:  dataframe.JSONErrors,
+			expParse: `{"kind":"parse error","code":"BadValue",` +
+				`"message":"data row: 2 column: 0:` +
+				` strconv.ParseInt: parsing \"two\": invalid syntax",` +
+				`"source":"test data","line":3,"column":0,` +
+				`"field":"two","text":"two y"}`,
+			expOther: `{"kind":"unknown column","code":"UnknownColumn",` +
+				`"message":"Unknown column name: \"nonesuch\""}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		prev := dataframe.SetErrorFormatter(tc.f)
+		testhelper.DiffString(t, tc.IDStr(), "parse error",
+			parseErr.Error(), tc.expParse)
+		testhelper.DiffString(t, tc.IDStr(), "other error",
+			otherErr.Error(), tc.expOther)
+		dataframe.SetErrorFormatter(prev)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	const content = "a b\n1 x\ntwo y\nthree z\n"
+
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeString))
+
+	prev := dataframe.SetErrorFormatter(dataframe.UnprefixedErrors)
+	defer dataframe.SetErrorFormatter(prev)
+
+	var buf bytes.Buffer
+	if err := df.WriteErrors(&buf); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	testhelper.DiffString(t, "write errors", "output", buf.String(),
+		`test data:3: data row: 2 column: 0:`+
+			` strconv.ParseInt: parsing "two": invalid syntax`+"\n"+
+			`test data:4: data row: 3 column: 0:`+
+			` strconv.ParseInt: parsing "three": invalid syntax`+"\n")
+}
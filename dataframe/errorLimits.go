@@ -0,0 +1,49 @@
+package dataframe
+
+// DFRMaxErrors returns a function which will set the maximum number of
+// errors recorded in the dataframe made by the DFReader, as for the
+// MaxErrors option of NewDF. Errors beyond this are counted but not kept
+// (see ErrCount and Errors).
+func DFRMaxErrors(n int) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if n < 0 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the maximum number of errors must be >= 0: %d", n)
+		}
+		dfr.maxErrors = n
+		return nil
+	}
+}
+
+// FailAfterErrors returns a function which will cause the DFReader to
+// abandon the read once n errors have been seen, even if errors are
+// allowed (see AllowErrors). This stops a read of data that is so badly
+// malformed that the dataframe would be of no use from continuing through
+// the rest of the input. The error returned gives the first error recorded
+// and will match ErrTooManyErrors when tested using errors.Is.
+func FailAfterErrors(n int64) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if n < 1 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the number of errors to fail after must be > 0: %d", n)
+		}
+		dfr.failAfter = n
+		return nil
+	}
+}
+
+// checkErrCount returns an error if the number of errors seen so far has
+// reached the limit set by FailAfterErrors
+func (dfr *DFReader) checkErrCount(state *dfReadState, df *DF) error {
+	if dfr.failAfter == 0 || df.errCount < dfr.failAfter {
+		return nil
+	}
+
+	if len(df.errors) == 0 {
+		return dfKindErrorf(ErrTooManyErrors,
+			"%s: abandoned after %d errors", state.loc.Source(), df.errCount)
+	}
+	return dfKindErrorf(ErrTooManyErrors,
+		"%s: abandoned after %d errors, the first is: %s",
+		state.loc.Source(), df.errCount, errText(df.errors[0]))
+}
@@ -0,0 +1,101 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFailAfterErrors(t *testing.T) {
+	const content = `a b
+1 x
+2 y
+three z
+four w
+5 v
+six u
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts      []dataframe.DFReaderOpt
+		expErrCnt int64
+		expErrs   int
+	}{
+		{
+			ID:        testhelper.MkID("no limit"),
+			expErrCnt: 3,
+			expErrs:   3,
+		},
+		{
+			ID: testhelper.MkID("limit not reached"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.FailAfterErrors(4),
+			},
+			expErrCnt: 3,
+			expErrs:   3,
+		},
+		{
+			ID: testhelper.MkID("limit reached"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.FailAfterErrors(2),
+			},
+			ExpErr: testhelper.MkExpErr(
+				"test data: abandoned after 2 errors, the first is:",
+				"test data:4: data row: 3 column: 0:",
+				`"three"`),
+		},
+		{
+			ID: testhelper.MkID("limit reached, no errors kept"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRMaxErrors(0),
+				dataframe.FailAfterErrors(3),
+			},
+			ExpErr: testhelper.MkExpErr("test data: abandoned after 3 errors"),
+		},
+		{
+			ID: testhelper.MkID("max errors"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRMaxErrors(1),
+			},
+			expErrCnt: 3,
+			expErrs:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(
+			append([]dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.AllowErrors,
+				dataframe.InitialLines(1),
+			}, tc.opts...)...)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(content), "test data")
+		if testhelper.CheckExpErr(t, err, tc) {
+			if err != nil {
+				if !errors.Is(err, dataframe.ErrTooManyErrors) {
+					t.Log(tc.IDStr())
+					t.Errorf("\t: the error should match ErrTooManyErrors")
+				}
+				continue
+			}
+			testhelper.DiffInt(t, tc.IDStr(), "error count",
+				df.ErrCount(), tc.expErrCnt)
+			testhelper.DiffInt(t, tc.IDStr(), "errors kept",
+				len(df.Errors()), tc.expErrs)
+		}
+	}
+
+	_, err := dataframe.NewDFReader(dataframe.FailAfterErrors(0))
+	testhelper.CheckExpErrWithID(t, "bad fail after", err,
+		testhelper.MkExpErr("the number of errors to fail after must be > 0"))
+	_, err = dataframe.NewDFReader(dataframe.DFRMaxErrors(-1))
+	testhelper.CheckExpErrWithID(t, "bad max errors", err,
+		testhelper.MkExpErr("the maximum number of errors must be >= 0"))
+}
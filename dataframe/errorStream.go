@@ -0,0 +1,42 @@
+package dataframe
+
+// DFROnError returns a function which will cause the DFReader to call f
+// with each non-fatal error as it is found during a read rather than only
+// recording it in the dataframe returned at the end. This allows a
+// long-running load to report problems with the data immediately, by
+// logging them or sending them on a channel, for instance. It applies to
+// every form of read (Read, ReadFile, ReadRecords and ReadCSVRecords).
+//
+// The errors are only non-fatal if errors are allowed (see AllowErrors),
+// otherwise the first error ends the read and is returned in the usual
+// way. The function is called for every error, including those beyond the
+// number recorded in the dataframe (see DFRMaxErrors). If the DFReader is
+// used by several goroutines at once the function must be safe to call
+// concurrently.
+func DFROnError(f func(error)) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if f == nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the error function must not be nil")
+		}
+		dfr.onError = f
+		return nil
+	}
+}
+
+// startErrStream arranges for the errors added to the dataframe to be
+// passed to the DFReader's error function, if any, after any other
+// handling of the error
+func (dfr *DFReader) startErrStream(df *DF) {
+	if dfr.onError == nil {
+		return
+	}
+
+	hook := df.errHook
+	df.errHook = func(err error) {
+		if hook != nil {
+			hook(err)
+		}
+		dfr.onError(err)
+	}
+}
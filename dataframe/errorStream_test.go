@@ -0,0 +1,61 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFROnError(t *testing.T) {
+	const content = "a b\n1 x\ntwo y\n3 z\nfour w\n"
+	expErrs := []string{
+		`test data:3: data row: 2 column: 0:` +
+			` strconv.ParseInt: parsing "two": invalid syntax`,
+		`test data:5: data row: 4 column: 0:` +
+			` strconv.ParseInt: parsing "four": invalid syntax`,
+	}
+
+	var errs []string
+	dfr, err := dataframe.NewDFReader(
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.DFRMaxErrors(1),
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeString),
+		dataframe.DFROnError(func(err error) {
+			errs = append(errs, err.Error())
+		}))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	prev := dataframe.SetErrorFormatter(dataframe.UnprefixedErrors)
+	defer dataframe.SetErrorFormatter(prev)
+
+	id := "Read"
+	df, err := dfr.Read(strings.NewReader(content), "test data")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	testhelper.DiffInt(t, id, "errors kept", len(df.Errors()), 1)
+	testhelper.DiffStringSlice(t, id, "errors given", errs, expErrs)
+
+	id = "ReadRecords"
+	errs = nil
+	_, err = dfr.ReadRecords([][]string{
+		{"a", "b"},
+		{"1", "x"},
+		{"two", "y"},
+		{"3", "z"},
+		{"four", "w"},
+	}, "test data")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	testhelper.DiffStringSlice(t, id, "errors given", errs, expErrs)
+
+	_, err = dataframe.NewDFReader(dataframe.DFROnError(nil))
+	testhelper.CheckExpErrWithID(t, "nil function", err,
+		testhelper.MkExpErr("the error function must not be nil"))
+}
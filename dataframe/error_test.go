@@ -0,0 +1,436 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestErrorKinds(t *testing.T) {
+	df := mkTestDF(t, "i s\n1 a\n2 b\n", dataframe.HasHeader)
+	kinds := []error{
+		dataframe.ErrParse,
+		dataframe.ErrUnknownColumn,
+		dataframe.ErrNoSuchRow,
+		dataframe.ErrTypeMismatch,
+		dataframe.ErrDimensionMismatch,
+		dataframe.ErrSchemaMismatch,
+		dataframe.ErrCheckFailed,
+		dataframe.ErrInvalidArgument,
+		dataframe.ErrInvalidState,
+	}
+
+	mkErr := func(f func() error) error { return f() }
+
+	testCases := []struct {
+		testhelper.ID
+		err     error
+		expKind error
+	}{
+		{
+			ID: testhelper.MkID("unknown column name"),
+			err: mkErr(func() error {
+				_, err := df.IntColByName("nonesuch")
+				return err
+			}),
+			expKind: dataframe.ErrUnknownColumn,
+		},
+		{
+			ID: testhelper.MkID("unknown column index"),
+			err: mkErr(func() error {
+				_, err := df.ColInfoByIdx(9)
+				return err
+			}),
+			expKind: dataframe.ErrUnknownColumn,
+		},
+		{
+			ID: testhelper.MkID("wrong column type"),
+			err: mkErr(func() error {
+				_, err := df.FloatColByName("i")
+				return err
+			}),
+			expKind: dataframe.ErrTypeMismatch,
+		},
+		{
+			ID: testhelper.MkID("wrong number of names"),
+			err: mkErr(func() error {
+				return df.SetColNames("a", "b", "c")
+			}),
+			expKind: dataframe.ErrDimensionMismatch,
+		},
+		{
+			ID: testhelper.MkID("no such row"),
+			err: mkErr(func() error {
+				cols, _ := df.Row(0).ColsByName("i")
+				_, err := cols[0].GetIntVal(1)
+				return err
+			}),
+			expKind: dataframe.ErrNoSuchRow,
+		},
+		{
+			ID: testhelper.MkID("parse error"),
+			err: mkErr(func() error {
+				dfr, _ := dataframe.NewDFReader(dataframe.HasHeader,
+					dataframe.DFRColTypes(
+						dataframe.ColTypeInt, dataframe.ColTypeString))
+				_, err := dfr.Read(
+					strings.NewReader("i s\nx a\n"), "test data")
+				return err
+			}),
+			expKind: dataframe.ErrParse,
+		},
+		{
+			ID: testhelper.MkID("wrapped, unknown column"),
+			err: mkErr(func() error {
+				_, err := df.Pipe().Select("nonesuch").Result()
+				return err
+			}),
+			expKind: dataframe.ErrUnknownColumn,
+		},
+		{
+			ID: testhelper.MkID("duplicate column name"),
+			err: mkErr(func() error {
+				return df.Clone().SetColNames("x", "x")
+			}),
+			expKind: dataframe.ErrInvalidArgument,
+		},
+		{
+			ID: testhelper.MkID("bad option"),
+			err: mkErr(func() error {
+				_, err := dataframe.NewDFReader(dataframe.SkipLines(-1))
+				return err
+			}),
+			expKind: dataframe.ErrInvalidArgument,
+		},
+		{
+			ID: testhelper.MkID("no index"),
+			err: mkErr(func() error {
+				_, err := df.LookupRows("i", int64(1))
+				return err
+			}),
+			expKind: dataframe.ErrInvalidState,
+		},
+		{
+			ID: testhelper.MkID("key not unique"),
+			err: mkErr(func() error {
+				return mkTestDF(t, "i\n1\n1\n", dataframe.HasHeader).
+					SetIndex("i")
+			}),
+			expKind: dataframe.ErrCheckFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.err == nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: an error was expected but none was returned")
+			continue
+		}
+
+		var dfErr dataframe.Error
+		if !errors.As(tc.err, &dfErr) {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the error is not a dataframe.Error: %T", tc.err)
+		}
+
+		for _, k := range kinds {
+			testhelper.DiffBool(t, tc.IDStr(), "errors.Is "+k.Error(),
+				errors.Is(tc.err, k), k == tc.expKind)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	const data = "i f s\n1 1.5 a\nx 2.5 b\n3 y c\n4 4.5\n"
+
+	testCases := []struct {
+		testhelper.ID
+		allowErrs bool
+		expErrs   []dataframe.ParseError
+		expKinds  []error
+	}{
+		{
+			ID:        testhelper.MkID("errors allowed"),
+			allowErrs: true,
+			expErrs: []dataframe.ParseError{
+				{Source: "test data", Line: 3, Col: 0, Field: "x"},
+				{Source: "test data", Line: 4, Col: 1, Field: "y"},
+				{Source: "test data", Line: 5, Col: -1},
+			},
+			expKinds: []error{
+				dataframe.ErrParse,
+				dataframe.ErrParse,
+				dataframe.ErrDimensionMismatch,
+			},
+		},
+		{
+			ID: testhelper.MkID("errors not allowed"),
+			expErrs: []dataframe.ParseError{
+				{Source: "test data", Line: 3, Col: 0, Field: "x"},
+			},
+			expKinds: []error{dataframe.ErrParse},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := []dataframe.DFReaderOpt{
+			dataframe.HasHeader,
+			dataframe.DFRColTypes(dataframe.ColTypeInt,
+				dataframe.ColTypeFloat, dataframe.ColTypeString),
+		}
+		if tc.allowErrs {
+			opts = append(opts, dataframe.AllowErrors)
+		}
+		dfr, err := dataframe.NewDFReader(opts...)
+		if err != nil {
+			t.Fatal("Cannot create the DFReader: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(data), "test data")
+
+		var errs []error
+		if tc.allowErrs {
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: unexpected error: %s", err)
+				continue
+			}
+			errs = df.Errors()
+		} else {
+			errs = []error{err}
+		}
+
+		if testhelper.DiffInt(t, tc.IDStr(), "error count",
+			len(errs), len(tc.expErrs)) {
+			continue
+		}
+		for i, e := range errs {
+			var pe dataframe.ParseError
+			if !errors.As(e, &pe) {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: error %d is not a ParseError: %s", i, e)
+				continue
+			}
+			exp := tc.expErrs[i]
+			testhelper.DiffString(t, tc.IDStr(), "Source",
+				pe.Source, exp.Source)
+			testhelper.DiffInt(t, tc.IDStr(), "Line", pe.Line, exp.Line)
+			testhelper.DiffInt(t, tc.IDStr(), "Col", pe.Col, exp.Col)
+			testhelper.DiffString(t, tc.IDStr(), "Field", pe.Field, exp.Field)
+			testhelper.DiffBool(t, tc.IDStr(), "kind",
+				errors.Is(e, tc.expKinds[i]), true)
+		}
+	}
+}
+
+func TestErrorsAsDF(t *testing.T) {
+	const data = "i f s\n1 1.5 a\nx 2.5 b\n3 y c\n4 4.5\n"
+
+	readDF := mkTestDF(t, data, dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+
+	otherDF, err := dataframe.NewDF()
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+	}
+	_ = otherDF.SetColNames()
+
+	testCases := []struct {
+		testhelper.ID
+		df  *dataframe.DF
+		exp [][]string
+	}{
+		{
+			ID: testhelper.MkID("read errors"),
+			df: readDF,
+			exp: [][]string{
+				{
+					"test data", "3", "i",
+					"data row: 2 column: 0:" +
+						` strconv.ParseInt: parsing "x": invalid syntax`,
+				},
+				{
+					"test data", "4", "f",
+					"data row: 3 column: 1:" +
+						` strconv.ParseFloat: parsing "y": invalid syntax`,
+				},
+				{
+					"test data", "5", "NA",
+					"the dataframe has 3 columns but this line has 2: " +
+						` col 0: "4" col 1: "4.5"`,
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("no location"),
+			df: otherDF,
+			exp: [][]string{
+				{"NA", "NA", "NA", "no column names have been given"},
+			},
+		},
+		{
+			ID:  testhelper.MkID("no errors"),
+			df:  mkTestDF(t, "i\n1\n", dataframe.HasHeader),
+			exp: [][]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		edf, err := tc.df.ErrorsAsDF()
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error: %s", err)
+			continue
+		}
+		checkDFVals(t, tc.IDStr(), edf, tc.exp)
+	}
+}
+
+func TestErrCountByCol(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		data string
+		exp  map[string]int64
+	}{
+		{
+			ID:   testhelper.MkID("no errors"),
+			data: "i f s\n1 1.5 a\n",
+			exp:  map[string]int64{},
+		},
+		{
+			ID:   testhelper.MkID("errors in several columns"),
+			data: "i f s\n1 1.5 a\nx 2.5 b\ny z c\n4 4.5\n5 w d\n",
+			exp:  map[string]int64{"i": 2, "f": 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		df := mkTestDF(t, tc.data, dataframe.HasHeader, dataframe.AllowErrors,
+			dataframe.DFRColTypes(dataframe.ColTypeInt,
+				dataframe.ColTypeFloat, dataframe.ColTypeString))
+		if err := testhelper.DiffVals(df.ErrCountByCol(), tc.exp); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: %s", err)
+		}
+	}
+}
+
+func TestErrorSummary(t *testing.T) {
+	const data = "i f s\n1 1.5 a\nx 2.5 b\ny z c\n4 4.5\n5 w d\n6 v e\n"
+
+	readDF := mkTestDF(t, data, dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRMaxErrors(1),
+		dataframe.DFRColTypes(dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+
+	otherDF, err := dataframe.NewDF()
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+	}
+	_ = otherDF.SetColNames()
+
+	testCases := []struct {
+		testhelper.ID
+		df  *dataframe.DF
+		exp [][]string
+	}{
+		{
+			ID: testhelper.MkID("read errors"),
+			df: readDF,
+			exp: [][]string{
+				{
+					"parse error", "f", "3", "4",
+					"data row: 3 column: 1:" +
+						` strconv.ParseFloat: parsing "z": invalid syntax`,
+				},
+				{
+					"parse error", "i", "2", "3",
+					"data row: 2 column: 0:" +
+						` strconv.ParseInt: parsing "x": invalid syntax`,
+				},
+				{
+					"dimension mismatch", "NA", "1", "5",
+					"the dataframe has 3 columns but this line has 2: " +
+						` col 0: "4" col 1: "4.5"`,
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("no category"),
+			df: otherDF,
+			exp: [][]string{
+				{"other", "NA", "1", "NA", "no column names have been given"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		sdf, err := tc.df.ErrorSummary()
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error: %s", err)
+			continue
+		}
+		checkDFVals(t, tc.IDStr(), sdf, tc.exp)
+	}
+}
+
+func TestParseErrorText(t *testing.T) {
+	const data = "i s\n1 a\nx   b\n\n"
+
+	testCases := []struct {
+		testhelper.ID
+		opts    []dataframe.DFReaderOpt
+		expErrs []dataframe.ParseError
+	}{
+		{
+			ID: testhelper.MkID("no text"),
+			expErrs: []dataframe.ParseError{
+				{Line: 3, Col: 0, Field: "x"},
+				{Line: 4, Col: -1},
+			},
+		},
+		{
+			ID:   testhelper.MkID("with text"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRErrorText},
+			expErrs: []dataframe.ParseError{
+				{Line: 3, Col: 0, Field: "x", Text: "x   b"},
+				{Line: 4, Col: -1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		df := mkTestDF(t, data,
+			append([]dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.AllowErrors,
+				dataframe.DFRColTypes(
+					dataframe.ColTypeInt, dataframe.ColTypeString),
+			}, tc.opts...)...)
+
+		errs := df.Errors()
+		if len(errs) != len(tc.expErrs) {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: expected %d errors, got %d: %v",
+				len(tc.expErrs), len(errs), errs)
+			continue
+		}
+		for i, err := range errs {
+			var pe dataframe.ParseError
+			if !errors.As(err, &pe) {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: error %d is not a ParseError: %v", i, err)
+				continue
+			}
+			exp := tc.expErrs[i]
+			testhelper.DiffInt(t, tc.IDStr(), "line", pe.Line, exp.Line)
+			testhelper.DiffInt(t, tc.IDStr(), "column", pe.Col, exp.Col)
+			testhelper.DiffString(t, tc.IDStr(), "field", pe.Field, exp.Field)
+			testhelper.DiffString(t, tc.IDStr(), "text", pe.Text, exp.Text)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package dataframe
+
+import (
+	"errors"
+	"sort"
+)
+
+// Names of the columns in the dataframe returned by ErrorsAsDF
+const (
+	ErrColSource  = "source"
+	ErrColLine    = "line"
+	ErrColColumn  = "column"
+	ErrColMessage = "message"
+	ErrColKind    = "kind"
+	ErrColCount   = "count"
+)
+
+// ErrorsAsDF returns a new dataframe holding the errors recorded while
+// constructing the dataframe, one row per error. This allows data-quality
+// problems to be filtered, grouped and written out in the same way as any
+// other data.
+//
+// The dataframe has the following columns:
+//
+//	source  (string) the name of the data source
+//	line    (int)    the line number in the source
+//	column  (string) the name of the column with the bad value
+//	message (string) a description of the problem
+//
+// Any part of the location that is not known, such as the column of a line
+// with the wrong number of fields or anything about an error that is not a
+// ParseError, is NA. Note that only the first maxErrors errors are recorded
+// (see ErrCount).
+func (df DF) ErrorsAsDF() (*DF, error) {
+	edf, err := newDFFromColInfo(
+		ColInfo{name: ErrColSource, colType: ColTypeString},
+		ColInfo{name: ErrColLine, colType: ColTypeInt},
+		ColInfo{name: ErrColColumn, colType: ColTypeString},
+		ColInfo{name: ErrColMessage, colType: ColTypeString},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range df.errors {
+		vals := []any{nil, nil, nil, errText(e)}
+
+		var pe ParseError
+		if errors.As(e, &pe) {
+			if pe.Source != "" {
+				vals[0] = pe.Source
+			}
+			if pe.Line > 0 {
+				vals[1] = pe.Line
+			}
+			if pe.Col >= 0 && pe.Col < len(df.mci.info) {
+				vals[2] = df.mci.info[pe.Col].name
+			}
+			vals[3] = pe.Msg
+		}
+
+		for i, v := range vals {
+			if err := edf.appendVal(i, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return edf, nil
+}
+
+// ErrCountByCol returns the number of parse errors found in each column,
+// keyed by column name. Only columns with errors appear in the map. Unlike
+// the errors returned by Errors, every error is counted, not just the first
+// maxErrors, so after reading with AllowErrors this shows which columns
+// have values that don't match the expected type.
+func (df DF) ErrCountByCol() map[string]int64 {
+	counts := make(map[string]int64, len(df.colErrCount))
+	for i, n := range df.colErrCount {
+		if i < len(df.mci.info) {
+			counts[df.mci.info[i].name] = n
+		}
+	}
+	return counts
+}
+
+// errKinds are the error categories in the order in which they are tested
+// when finding the category of an error
+var errKinds = []dfError{
+	ErrParse,
+	ErrUnknownColumn,
+	ErrNoSuchRow,
+	ErrTypeMismatch,
+	ErrDimensionMismatch,
+	ErrSchemaMismatch,
+	ErrCheckFailed,
+	ErrTooManyErrors,
+	ErrInternal,
+	ErrInvalidArgument,
+	ErrInvalidState,
+}
+
+// errKindOther is the category given in the error summary to errors not
+// in any of the error categories
+const errKindOther = "other"
+
+// errKind returns the name of the category of the error or the empty
+// string if it is in none of them
+func errKind(err error) string {
+	for _, k := range errKinds {
+		if errors.Is(err, k) {
+			return string(k)
+		}
+	}
+	return ""
+}
+
+// errSummaryKey identifies a group of errors in the error summary: those
+// of the same category in the same column. The column is -1 for errors not
+// in any particular column.
+type errSummaryKey struct {
+	kind string
+	col  int
+}
+
+// errSummaryVal records the number of errors in a group of the error
+// summary together with the details of the first of them
+type errSummaryVal struct {
+	seq   int
+	count int64
+	line  int64
+	msg   string
+}
+
+// summariseError adds the error to the error summary
+func (df *DF) summariseError(err error) {
+	key := errSummaryKey{kind: errKind(err), col: -1}
+	if key.kind == "" {
+		key.kind = errKindOther
+	}
+	var pe ParseError
+	isPE := errors.As(err, &pe)
+	if isPE && pe.Col >= 0 {
+		key.col = pe.Col
+	}
+
+	if df.errSummary == nil {
+		df.errSummary = map[errSummaryKey]*errSummaryVal{}
+	}
+	v, ok := df.errSummary[key]
+	if !ok {
+		v = &errSummaryVal{seq: len(df.errSummary), msg: errText(err)}
+		if isPE {
+			v.line = pe.Line
+			v.msg = pe.Msg
+		}
+		df.errSummary[key] = v
+	}
+	v.count++
+}
+
+// ErrorSummary returns a new dataframe summarising the errors recorded
+// while constructing the dataframe, with one row for each category of
+// error in each column. Unlike ErrorsAsDF, every error is counted, not
+// just the first maxErrors, so this shows at a glance which columns have
+// problems and of what sort. The rows are in descending order of the
+// number of errors, groups with the same number being in the order in
+// which their first error was seen.
+//
+// The dataframe has the following columns:
+//
+//	kind    (string) the category of the errors, as given by the Error
+//	                 method of the category (such as ErrParse); errors not
+//	                 in any category are given as "other"
+//	column  (string) the name of the column with the bad values
+//	count   (int)    the number of errors
+//	line    (int)    the line number in the source of the first error
+//	message (string) a description of the first error
+//
+// The column and line are NA if they are not known, as for ErrorsAsDF.
+func (df DF) ErrorSummary() (*DF, error) {
+	sdf, err := newDFFromColInfo(
+		ColInfo{name: ErrColKind, colType: ColTypeString},
+		ColInfo{name: ErrColColumn, colType: ColTypeString},
+		ColInfo{name: ErrColCount, colType: ColTypeInt},
+		ColInfo{name: ErrColLine, colType: ColTypeInt},
+		ColInfo{name: ErrColMessage, colType: ColTypeString},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]errSummaryKey, 0, len(df.errSummary))
+	for k := range df.errSummary {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		vi, vj := df.errSummary[keys[i]], df.errSummary[keys[j]]
+		if vi.count != vj.count {
+			return vi.count > vj.count
+		}
+		return vi.seq < vj.seq
+	})
+
+	for _, k := range keys {
+		v := df.errSummary[k]
+		vals := []any{k.kind, nil, v.count, nil, v.msg}
+		if k.col >= 0 && k.col < len(df.mci.info) {
+			vals[1] = df.mci.info[k.col].name
+		}
+		if v.line > 0 {
+			vals[3] = v.line
+		}
+
+		for i, v := range vals {
+			if err := sdf.appendVal(i, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return sdf, nil
+}
@@ -0,0 +1,480 @@
+package dataframe
+
+import "strconv"
+
+// exprNode is a node in a compiled expression
+type exprNode interface {
+	// eval returns the values of the expression for the dataframe. It
+	// returns an error if the expression cannot be applied to the
+	// dataframe, for instance if a column does not exist or has the wrong
+	// type.
+	eval(df *DF) (exprVec, error)
+}
+
+// exprCol is a reference to a column
+type exprCol struct {
+	name string
+}
+
+// eval returns the values of the column
+func (e exprCol) eval(df *DF) (exprVec, error) {
+	ci, ok := df.mci.colIdx(e.name)
+	if !ok {
+		return exprVec{}, errUnknownColName(e.name)
+	}
+	return df.colVec(ci)
+}
+
+// exprLit is a literal value
+type exprLit struct {
+	v exprVec
+}
+
+// eval returns the value
+func (e exprLit) eval(_ *DF) (exprVec, error) {
+	return e.v, nil
+}
+
+// exprArith is an arithmetic operation
+type exprArith struct {
+	op   arithOp
+	a, b exprNode
+}
+
+// eval returns the result of the operation
+func (e exprArith) eval(df *DF) (exprVec, error) {
+	a, b, err := evalPair(df, e.a, e.b)
+	if err != nil {
+		return exprVec{}, err
+	}
+	if !a.isNumeric() || !b.isNumeric() {
+		return exprVec{}, exprTypeErr(e.op.symbol(), "numeric", a, b)
+	}
+	return arithVec(e.op, a, b, df.RowCount()), nil
+}
+
+// exprCmp is a comparison
+type exprCmp struct {
+	op   cmpOp
+	sym  string
+	a, b exprNode
+}
+
+// eval returns the result of the comparison
+func (e exprCmp) eval(df *DF) (exprVec, error) {
+	a, b, err := evalPair(df, e.a, e.b)
+	if err != nil {
+		return exprVec{}, err
+	}
+	if a.ct != b.ct && (!a.isNumeric() || !b.isNumeric()) {
+		return exprVec{}, exprTypeErr(e.sym, "of comparable types", a, b)
+	}
+	return cmpVec(e.op, a, b, df.RowCount()), nil
+}
+
+// exprLogic is a logical AND or OR
+type exprLogic struct {
+	and  bool
+	a, b exprNode
+}
+
+// eval returns the result of the operation
+func (e exprLogic) eval(df *DF) (exprVec, error) {
+	a, b, err := evalPair(df, e.a, e.b)
+	if err != nil {
+		return exprVec{}, err
+	}
+	sym := "OR"
+	if e.and {
+		sym = "AND"
+	}
+	if a.ct != ColTypeBool || b.ct != ColTypeBool {
+		return exprVec{}, exprTypeErr(sym, "bool", a, b)
+	}
+	if e.and {
+		return andVec(a, b, df.RowCount()), nil
+	}
+	return orVec(a, b, df.RowCount()), nil
+}
+
+// exprNot is a logical NOT
+type exprNot struct {
+	a exprNode
+}
+
+// eval returns the negation of the operand
+func (e exprNot) eval(df *DF) (exprVec, error) {
+	a, err := e.a.eval(df)
+	if err != nil {
+		return exprVec{}, err
+	}
+	if a.ct != ColTypeBool {
+		return exprVec{}, dfKindErrorf(ErrTypeMismatch,
+			"expression: the operand of NOT must be bool, not %s", a.ct)
+	}
+	return notVec(a), nil
+}
+
+// exprNeg is a unary minus
+type exprNeg struct {
+	a exprNode
+}
+
+// eval returns the operand with its sign changed
+func (e exprNeg) eval(df *DF) (exprVec, error) {
+	a, err := e.a.eval(df)
+	if err != nil {
+		return exprVec{}, err
+	}
+	if !a.isNumeric() {
+		return exprVec{}, dfKindErrorf(ErrTypeMismatch,
+			"expression: the operand of unary - must be numeric, not %s",
+			a.ct)
+	}
+	return negVec(a), nil
+}
+
+// exprIsNull is a test for NA values
+type exprIsNull struct {
+	not bool
+	a   exprNode
+}
+
+// eval returns the result of the test
+func (e exprIsNull) eval(df *DF) (exprVec, error) {
+	a, err := e.a.eval(df)
+	if err != nil {
+		return exprVec{}, err
+	}
+	return isNullVec(a, e.not, df.RowCount()), nil
+}
+
+// evalPair evaluates the operands of a binary operation
+func evalPair(df *DF, x, y exprNode) (exprVec, exprVec, error) {
+	a, err := x.eval(df)
+	if err != nil {
+		return exprVec{}, exprVec{}, err
+	}
+	b, err := y.eval(df)
+	if err != nil {
+		return exprVec{}, exprVec{}, err
+	}
+	return a, b, nil
+}
+
+// exprTypeErr returns the error for a binary operation whose operands are
+// of the wrong types
+func exprTypeErr(sym, want string, a, b exprVec) error {
+	return dfKindErrorf(ErrTypeMismatch,
+		"expression: the operands of %s must be %s, not %s and %s",
+		sym, want, a.ct, b.ct)
+}
+
+// parseExpr parses an expression. In order of increasing precedence the
+// operators are: OR; AND; NOT; the comparisons and IS [NOT] NULL; + and -;
+// * and /; unary minus.
+func (p *qParser) parseExpr() (exprNode, error) {
+	e, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		e2, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		e = exprLogic{a: e, b: e2}
+	}
+	return e, nil
+}
+
+// parseAnd parses expressions joined by AND
+func (p *qParser) parseAnd() (exprNode, error) {
+	e, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		e2, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		e = exprLogic{and: true, a: e, b: e2}
+	}
+	return e, nil
+}
+
+// parseNot parses an expression optionally preceded by NOT
+func (p *qParser) parseNot() (exprNode, error) {
+	if p.acceptKeyword("NOT") {
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return exprNot{a: e}, nil
+	}
+	return p.parseCmp()
+}
+
+// parseCmp parses an arithmetic expression optionally followed by a
+// comparison with another or an IS NULL test
+func (p *qParser) parseCmp() (exprNode, error) {
+	e, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return exprIsNull{not: not, a: e}, nil
+	}
+
+	t := p.peek()
+	op, ok := cmpOps[t.text]
+	if t.kind != qTokSym || !ok {
+		return e, nil
+	}
+	p.next()
+
+	e2, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	return exprCmp{op: op, sym: t.text, a: e, b: e2}, nil
+}
+
+// parseSum parses terms joined by + or -
+func (p *qParser) parseSum() (exprNode, error) {
+	e, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := opAdd
+		switch {
+		case p.acceptSym("+"):
+		case p.acceptSym("-"):
+			op = opSub
+		default:
+			return e, nil
+		}
+		e2, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		e = exprArith{op: op, a: e, b: e2}
+	}
+}
+
+// parseTerm parses factors joined by * or /
+func (p *qParser) parseTerm() (exprNode, error) {
+	e, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := opMul
+		switch {
+		case p.acceptSym("*"):
+		case p.acceptSym("/"):
+			op = opDiv
+		default:
+			return e, nil
+		}
+		e2, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		e = exprArith{op: op, a: e, b: e2}
+	}
+}
+
+// parseFactor parses a value optionally preceded by a unary minus
+func (p *qParser) parseFactor() (exprNode, error) {
+	if p.acceptSym("-") {
+		e, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return exprNeg{a: e}, nil
+	}
+	return p.parseValue()
+}
+
+// parseValue parses an expression in parentheses, a literal value (a
+// number, a string in single quotes, TRUE or FALSE) or a column name
+func (p *qParser) parseValue() (exprNode, error) {
+	if p.acceptSym("(") {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSym(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+
+	t := p.next()
+	switch {
+	case t.kind == qTokNum:
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return exprLit{exprVec{ct: ColTypeInt, scalar: true,
+				i: []int64{i}}}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t, "bad number: %q", t.text)
+		}
+		return exprLit{exprVec{ct: ColTypeFloat, scalar: true,
+			f: []float64{f}}}, nil
+	case t.kind == qTokStr:
+		return exprLit{exprVec{ct: ColTypeString, scalar: true,
+			s: []string{t.text}}}, nil
+	case t.isKeyword("TRUE"), t.isKeyword("FALSE"):
+		return exprLit{exprVec{ct: ColTypeBool, scalar: true,
+			b: []bool{t.isKeyword("TRUE")}}}, nil
+	case t.kind == qTokIdent:
+		return exprCol{name: t.text}, nil
+	}
+
+	return nil, p.unexpected(t, "a value")
+}
+
+// Expr is an expression which has been compiled so that it can be
+// evaluated efficiently over the rows of a dataframe. It is made by
+// CompileExpr and can be used with any number of dataframes, for instance
+// with FilterExpr and MutateExpr. An Expr is not changed by being
+// evaluated and so may be used by several goroutines at once.
+type Expr struct {
+	src  string
+	root exprNode
+}
+
+// CompileExpr parses the expression and returns it in a form that can be
+// evaluated against a dataframe. The expression can refer to columns by
+// name and can have literal values: numbers, strings in single quotes and
+// TRUE and FALSE. The operators, in order of increasing precedence, are:
+//
+//	OR
+//	AND
+//	NOT
+//	=, != (or <>), <, <=, >, >=, IS NULL, IS NOT NULL
+//	+, -
+//	*, /
+//	- (unary minus)
+//
+// and parentheses can be used for grouping. Column names may be given in
+// double quotes if they are not simple identifiers or are keywords, and a
+// quote in a string or name is given by doubling it. The keywords may be
+// given in upper or lower case.
+//
+// The arithmetic operators apply to int and float values, giving an int if
+// both values are ints and the operator is not a division, otherwise a
+// float. Numbers may be compared with each other as may values of the same
+// type. The logical operators apply to bool values. If any value is NA the
+// result is NA, except that AND is false if either value is false and OR
+// is true if either value is true, following the SQL treatment of NULL.
+//
+// The columns are not resolved until the expression is evaluated, when
+// any unknown columns or type mismatches are reported.
+func CompileExpr(s string) (*Expr, error) {
+	toks, err := tokeniseQuery(s, "expression")
+	if err != nil {
+		return nil, err
+	}
+	p := &qParser{toks: toks, what: "expression"}
+
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.next(); t.kind != qTokEOF {
+		return nil, p.unexpected(t, "the end of the expression")
+	}
+
+	return &Expr{src: s, root: root}, nil
+}
+
+// String returns the text of the expression
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval returns a column holding the value of the expression for each row
+// of the dataframe. The column is named after the text of the expression.
+// The values are calculated a column at a time rather than row by row. It
+// returns an error if the expression refers to a column that does not
+// exist or if the values are of the wrong types for the operators.
+func (e *Expr) Eval(df *DF) (Column, error) {
+	v, err := e.root.eval(df)
+	if err != nil {
+		return Column{}, err
+	}
+	return v.column(e.src, df.RowCount()), nil
+}
+
+// exprMask returns the rows for which the expression is true, rows where
+// it is false or NA are not included
+func (df *DF) exprMask(e exprNode) ([]bool, error) {
+	v, err := e.eval(df)
+	if err != nil {
+		return nil, err
+	}
+	if v.ct != ColTypeBool {
+		return nil, dfKindErrorf(ErrTypeMismatch,
+			"the expression must give a bool value, not %s", v.ct)
+	}
+
+	mask := make([]bool, df.RowCount())
+	step := v.step()
+	for k, j := 0, 0; k < len(mask); k, j = k+1, j+step {
+		mask[k] = v.b[j] && !v.isNA(j)
+	}
+	return mask, nil
+}
+
+// FilterExpr returns a new dataframe with the same columns as df holding
+// just those rows for which the expression, which must give a bool value,
+// is true; rows where it is false or NA are dropped. The values are copied
+// as for Filter, but the expression is evaluated a column at a time which
+// is much faster than calling a RowFilter for each row.
+func (df *DF) FilterExpr(e *Expr) (*DF, error) {
+	mask, err := df.exprMask(e.root)
+	if err != nil {
+		return nil, dfWrapf(err, "filter %q", e.src)
+	}
+	return df.WhereMask(mask)
+}
+
+// MutateExpr returns a new dataframe having all the columns of df plus a
+// new column with the given name holding the value of the expression for
+// each row. The type of the column is given by the expression. The values
+// are copied as for Mutate, but the expression is evaluated a column at a
+// time which is much faster than calling a RowFunc for each row. It
+// returns an error if the name is already in use or if the expression
+// cannot be evaluated.
+func (df *DF) MutateExpr(name string, e *Expr) (*DF, error) {
+	c, err := e.Eval(df)
+	if err != nil {
+		return nil, dfWrapf(err, "mutate %q", e.src)
+	}
+	c.ci.name = name
+	if _, err := mutateColInfo(df.mci.info, name, c.ci.colType); err != nil {
+		return nil, err
+	}
+
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+	for i := 0; i < df.RowCount(); i++ {
+		rval.copyRowFrom(df, i)
+	}
+	if err := rval.AddCol(c); err != nil {
+		return nil, err
+	}
+
+	return rval, nil
+}
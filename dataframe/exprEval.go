@@ -0,0 +1,379 @@
+package dataframe
+
+// exprVec holds the values of an expression, either one value per row or a
+// single (scalar) value which applies to every row. Only the slice for the
+// type of the values is populated. The values are held in plain slices
+// rather than as Val types so that the operations can be applied a column
+// at a time in tight loops.
+type exprVec struct {
+	ct     ColType
+	scalar bool
+
+	b []bool
+	i []int64
+	f []float64
+	s []string
+	// na records which values are NA, it is nil if none of them are. For
+	// a bool value it records where the value is neither true nor false
+	na []bool
+}
+
+// step returns the amount by which the index into the values advances for
+// each row: zero for a scalar and one otherwise
+func (v exprVec) step() int {
+	if v.scalar {
+		return 0
+	}
+	return 1
+}
+
+// isNA returns true if the k'th value is NA
+func (v exprVec) isNA(k int) bool {
+	return v.na != nil && v.na[k]
+}
+
+// setNA records the k'th of n values as NA
+func (v *exprVec) setNA(k, n int) {
+	if v.na == nil {
+		v.na = make([]bool, n)
+	}
+	v.na[k] = true
+}
+
+// isNumeric returns true if the values are ints or floats
+func (v exprVec) isNumeric() bool {
+	return v.ct == ColTypeInt || v.ct == ColTypeFloat
+}
+
+// floats returns the values as floats, converting int values
+func (v exprVec) floats() []float64 {
+	if v.ct == ColTypeFloat {
+		return v.f
+	}
+	f := make([]float64, len(v.i))
+	for k, x := range v.i {
+		f[k] = float64(x)
+	}
+	return f
+}
+
+// rowsOf returns the number of values to calculate for the operands: one
+// if they are all scalars and otherwise the number of rows
+func rowsOf(n int, vs ...exprVec) (int, bool) {
+	for _, v := range vs {
+		if !v.scalar {
+			return n, false
+		}
+	}
+	return 1, true
+}
+
+// colVec returns the values of the column as an exprVec. It returns an
+// error if the column has no type.
+func (df *DF) colVec(ci int) (exprVec, error) {
+	v := exprVec{ct: df.mci.info[ci].colType}
+	if v.ct == ColTypeUnknown {
+		return exprVec{}, errUntypedCol(df.mci.info[ci].name)
+	}
+	vi := df.mci.valIdx[ci]
+	n := df.RowCount()
+
+	switch v.ct {
+	case ColTypeBool:
+		vals := df.boolCols[vi]
+		if r, ok := df.rleBoolCols[vi]; ok {
+			vals = r.expand()
+		}
+		v.b = make([]bool, n)
+		for k, x := range vals {
+			v.b[k] = x.Val
+			if x.IsNA {
+				v.setNA(k, n)
+			}
+		}
+	case ColTypeInt:
+		v.i = make([]int64, n)
+		for k, x := range df.intCols[vi] {
+			v.i[k] = x.Val
+			if x.IsNA {
+				v.setNA(k, n)
+			}
+		}
+	case ColTypeFloat:
+		v.f = make([]float64, n)
+		for k, x := range df.floatCols[vi] {
+			v.f[k] = x.Val
+			if x.IsNA {
+				v.setNA(k, n)
+			}
+		}
+	case ColTypeString:
+		vals := df.stringCols[vi]
+		if r, ok := df.rleStringCols[vi]; ok {
+			vals = r.expand()
+		}
+		v.s = make([]string, n)
+		for k, x := range vals {
+			v.s[k] = x.Val
+			if x.IsNA {
+				v.setNA(k, n)
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// column returns the values as a column with the given name and n rows, a
+// scalar value being repeated for every row
+func (v exprVec) column(name string, n int) Column {
+	c := Column{ci: ColInfo{name: name, colType: v.ct}}
+	step := v.step()
+
+	switch v.ct {
+	case ColTypeBool:
+		c.boolVals = make([]BoolVal, n)
+		for k, j := 0, 0; k < n; k, j = k+1, j+step {
+			c.boolVals[k] = BoolVal{Val: v.b[j], IsNA: v.isNA(j)}
+		}
+	case ColTypeInt:
+		c.intVals = make([]IntVal, n)
+		for k, j := 0, 0; k < n; k, j = k+1, j+step {
+			c.intVals[k] = IntVal{Val: v.i[j], IsNA: v.isNA(j)}
+		}
+	case ColTypeFloat:
+		c.floatVals = make([]FloatVal, n)
+		for k, j := 0, 0; k < n; k, j = k+1, j+step {
+			c.floatVals[k] = FloatVal{Val: v.f[j], IsNA: v.isNA(j)}
+		}
+	case ColTypeString:
+		c.stringVals = make([]StringVal, n)
+		for k, j := 0, 0; k < n; k, j = k+1, j+step {
+			c.stringVals[k] = StringVal{Val: v.s[j], IsNA: v.isNA(j)}
+		}
+	}
+
+	return c
+}
+
+// mergeNA returns the NA values for the result of an operation on a and b:
+// a value is NA if either operand is NA
+func mergeNA(a, b exprVec, n int) []bool {
+	if a.na == nil && b.na == nil {
+		return nil
+	}
+
+	na := make([]bool, n)
+	as, bs := a.step(), b.step()
+	for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+		na[k] = a.isNA(ai) || b.isNA(bi)
+	}
+	return na
+}
+
+// arithVec applies the arithmetic operation to the numeric operands. The
+// result is an int if both operands are ints and the operation is not a
+// division, otherwise it is a float.
+func arithVec(op arithOp, a, b exprVec, rows int) exprVec {
+	n, scalar := rowsOf(rows, a, b)
+	rval := exprVec{scalar: scalar, na: mergeNA(a, b, n)}
+	as, bs := a.step(), b.step()
+
+	if a.ct == ColTypeInt && b.ct == ColTypeInt && op != opDiv {
+		rval.ct = ColTypeInt
+		rval.i = make([]int64, n)
+		for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+			rval.i[k] = op.applyInt(a.i[ai], b.i[bi])
+		}
+		return rval
+	}
+
+	af, bf := a.floats(), b.floats()
+	rval.ct = ColTypeFloat
+	rval.f = make([]float64, n)
+	for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+		rval.f[k] = op.applyFloat(af[ai], bf[bi])
+	}
+	return rval
+}
+
+// cmpOp is a comparison operation
+type cmpOp byte
+
+const (
+	cmpEQ cmpOp = iota
+	cmpNE
+	cmpLT
+	cmpLE
+	cmpGT
+	cmpGE
+)
+
+// cmpOps maps the comparison symbols to their operations
+var cmpOps = map[string]cmpOp{
+	"=":  cmpEQ,
+	"!=": cmpNE,
+	"<>": cmpNE,
+	"<":  cmpLT,
+	"<=": cmpLE,
+	">":  cmpGT,
+	">=": cmpGE,
+}
+
+// ordered is the constraint satisfied by the types of value that can be
+// compared in an expression
+type ordered interface {
+	~int64 | ~float64 | ~string
+}
+
+// cmpSlices compares the values of a and b, starting from index 0 and
+// advancing by the steps, and records the results in rval
+func cmpSlices[T ordered](op cmpOp, a, b []T, as, bs int, rval []bool) {
+	n := len(rval)
+	switch op {
+	case cmpEQ:
+		for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+			rval[k] = a[ai] == b[bi]
+		}
+	case cmpNE:
+		for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+			rval[k] = a[ai] != b[bi]
+		}
+	case cmpLT:
+		for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+			rval[k] = a[ai] < b[bi]
+		}
+	case cmpLE:
+		for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+			rval[k] = a[ai] <= b[bi]
+		}
+	case cmpGT:
+		for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+			rval[k] = a[ai] > b[bi]
+		}
+	case cmpGE:
+		for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+			rval[k] = a[ai] >= b[bi]
+		}
+	}
+}
+
+// boolsAsInts returns the bool values as ints so that they can be ordered,
+// false being less than true
+func boolsAsInts(bs []bool) []int64 {
+	rval := make([]int64, len(bs))
+	for k, b := range bs {
+		if b {
+			rval[k] = 1
+		}
+	}
+	return rval
+}
+
+// cmpVec compares the operands, which must be of comparable types. The
+// result is NA if either operand is NA.
+func cmpVec(op cmpOp, a, b exprVec, rows int) exprVec {
+	n, scalar := rowsOf(rows, a, b)
+	rval := exprVec{
+		ct:     ColTypeBool,
+		scalar: scalar,
+		b:      make([]bool, n),
+		na:     mergeNA(a, b, n),
+	}
+	as, bs := a.step(), b.step()
+
+	switch {
+	case a.ct == ColTypeInt && b.ct == ColTypeInt:
+		cmpSlices(op, a.i, b.i, as, bs, rval.b)
+	case a.isNumeric():
+		cmpSlices(op, a.floats(), b.floats(), as, bs, rval.b)
+	case a.ct == ColTypeString:
+		cmpSlices(op, a.s, b.s, as, bs, rval.b)
+	case a.ct == ColTypeBool:
+		cmpSlices(op, boolsAsInts(a.b), boolsAsInts(b.b), as, bs, rval.b)
+	}
+	return rval
+}
+
+// andVec returns the logical AND of the bool operands: false if either is
+// false, otherwise NA (neither true nor false) if either is NA
+func andVec(a, b exprVec, rows int) exprVec {
+	n, scalar := rowsOf(rows, a, b)
+	rval := exprVec{ct: ColTypeBool, scalar: scalar, b: make([]bool, n)}
+	as, bs := a.step(), b.step()
+
+	for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+		aNA, bNA := a.isNA(ai), b.isNA(bi)
+		switch {
+		case !aNA && !a.b[ai], !bNA && !b.b[bi]:
+		case aNA || bNA:
+			rval.setNA(k, n)
+		default:
+			rval.b[k] = true
+		}
+	}
+	return rval
+}
+
+// orVec returns the logical OR of the bool operands: true if either is
+// true, otherwise NA (neither true nor false) if either is NA
+func orVec(a, b exprVec, rows int) exprVec {
+	n, scalar := rowsOf(rows, a, b)
+	rval := exprVec{ct: ColTypeBool, scalar: scalar, b: make([]bool, n)}
+	as, bs := a.step(), b.step()
+
+	for k, ai, bi := 0, 0, 0; k < n; k, ai, bi = k+1, ai+as, bi+bs {
+		aNA, bNA := a.isNA(ai), b.isNA(bi)
+		switch {
+		case !aNA && a.b[ai], !bNA && b.b[bi]:
+			rval.b[k] = true
+		case aNA || bNA:
+			rval.setNA(k, n)
+		}
+	}
+	return rval
+}
+
+// notVec returns the logical negation of the bool operand, an NA value
+// remains NA
+func notVec(a exprVec) exprVec {
+	rval := exprVec{
+		ct:     ColTypeBool,
+		scalar: a.scalar,
+		b:      make([]bool, len(a.b)),
+		na:     a.na,
+	}
+	for k, b := range a.b {
+		rval.b[k] = !b
+	}
+	return rval
+}
+
+// negVec returns the numeric operand with its sign changed
+func negVec(a exprVec) exprVec {
+	rval := exprVec{ct: a.ct, scalar: a.scalar, na: a.na}
+	if a.ct == ColTypeInt {
+		rval.i = make([]int64, len(a.i))
+		for k, x := range a.i {
+			rval.i[k] = -x
+		}
+		return rval
+	}
+
+	rval.f = make([]float64, len(a.f))
+	for k, x := range a.f {
+		rval.f[k] = -x
+	}
+	return rval
+}
+
+// isNullVec returns true for each value of the operand that is NA (or that
+// is not NA if not is set). The result is never NA.
+func isNullVec(a exprVec, not bool, rows int) exprVec {
+	n, scalar := rowsOf(rows, a)
+	rval := exprVec{ct: ColTypeBool, scalar: scalar, b: make([]bool, n)}
+	for k := range rval.b {
+		rval.b[k] = a.isNA(k) != not
+	}
+	return rval
+}
@@ -0,0 +1,269 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkExprTestDF returns the dataframe used to test expressions
+func mkExprTestDF(t *testing.T) *dataframe.DF {
+	t.Helper()
+
+	const content = `s,i,f,b
+x,1,0.5,true
+y,2,,false
+x,,4.5,
+z,4,2,true
+`
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.SplitPattern(","),
+		dataframe.DFREmptyFields(dataframe.EmptyAsNA),
+		dataframe.DFRColTypes(dataframe.ColTypeString, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeBool))
+	if err := df.Compress("s", "b"); err != nil {
+		t.Fatal("cannot compress the columns: ", err)
+	}
+	return df
+}
+
+func TestExprEval(t *testing.T) {
+	df := mkExprTestDF(t)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		expr    string
+		expType dataframe.ColType
+		expVals []string
+	}{
+		{
+			ID:      testhelper.MkID("int arithmetic"),
+			expr:    "i * 2 + 1",
+			expType: dataframe.ColTypeInt,
+			expVals: []string{"3", "5", "NA", "9"},
+		},
+		{
+			ID:      testhelper.MkID("mixed arithmetic, precedence"),
+			expr:    "-(i - f) / 2",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"-0.25", "NA", "NA", "-1"},
+		},
+		{
+			ID:      testhelper.MkID("int division"),
+			expr:    "i / 2",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"0.5", "1", "NA", "2"},
+		},
+		{
+			ID:      testhelper.MkID("scalar"),
+			expr:    "1 + 2.5",
+			expType: dataframe.ColTypeFloat,
+			expVals: []string{"3.5", "3.5", "3.5", "3.5"},
+		},
+		{
+			ID:      testhelper.MkID("string comparison"),
+			expr:    "s >= 'y'",
+			expType: dataframe.ColTypeBool,
+			expVals: []string{"false", "true", "false", "true"},
+		},
+		{
+			ID:      testhelper.MkID("numeric comparison"),
+			expr:    "i <> f",
+			expType: dataframe.ColTypeBool,
+			expVals: []string{"true", "NA", "NA", "true"},
+		},
+		{
+			ID:      testhelper.MkID("AND with NA"),
+			expr:    "b AND i > 1",
+			expType: dataframe.ColTypeBool,
+			expVals: []string{"false", "false", "NA", "true"},
+		},
+		{
+			ID:      testhelper.MkID("OR with NA"),
+			expr:    "b or i > 1",
+			expType: dataframe.ColTypeBool,
+			expVals: []string{"true", "true", "NA", "true"},
+		},
+		{
+			ID:      testhelper.MkID("NOT, IS NULL"),
+			expr:    "NOT b OR f IS NULL",
+			expType: dataframe.ColTypeBool,
+			expVals: []string{"false", "true", "NA", "false"},
+		},
+		{
+			ID:      testhelper.MkID("IS NOT NULL"),
+			expr:    `"i" IS NOT NULL`,
+			expType: dataframe.ColTypeBool,
+			expVals: []string{"true", "true", "false", "true"},
+		},
+		{
+			ID:     testhelper.MkID("bad: unknown column"),
+			expr:   "nonesuch + 1",
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+		},
+		{
+			ID:   testhelper.MkID("bad: arithmetic on strings"),
+			expr: "s + 1",
+			ExpErr: testhelper.MkExpErr(
+				"the operands of + must be numeric, not String and Int"),
+		},
+		{
+			ID:   testhelper.MkID("bad: comparison"),
+			expr: "b = 1",
+			ExpErr: testhelper.MkExpErr("the operands of =" +
+				" must be of comparable types, not Bool and Int"),
+		},
+		{
+			ID:   testhelper.MkID("bad: logic"),
+			expr: "b AND i",
+			ExpErr: testhelper.MkExpErr(
+				"the operands of AND must be bool, not Bool and Int"),
+		},
+		{
+			ID:   testhelper.MkID("bad: NOT"),
+			expr: "NOT i",
+			ExpErr: testhelper.MkExpErr(
+				"the operand of NOT must be bool, not Int"),
+		},
+	}
+
+	for _, tc := range testCases {
+		e, err := dataframe.CompileExpr(tc.expr)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": cannot compile the expression: ", err)
+		}
+		c, err := e.Eval(df)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			name, ct := c.Info()
+			testhelper.DiffString(t, tc.IDStr(), "name", name, tc.expr)
+			testhelper.DiffString(t, tc.IDStr(), "type",
+				ct.String(), tc.expType.String())
+			vals := make([]string, 0, c.RowCount())
+			for i := 0; i < c.RowCount(); i++ {
+				v, _ := c.GetVal(i)
+				vals = append(vals, v.(interface{ String() string }).String())
+			}
+			testhelper.DiffStringSlice(t, tc.IDStr(), "values",
+				vals, tc.expVals)
+		}
+	}
+
+	e, err := dataframe.CompileExpr("b = 'x'")
+	if err != nil {
+		t.Fatal("cannot compile the expression: ", err)
+	}
+	_, err = e.Eval(mkTestDF(t, "a b\n", dataframe.HasHeader))
+	testhelper.CheckExpErrWithID(t, "header only", err,
+		testhelper.MkExpErr(`the column named "b" has no type`))
+}
+
+func TestCompileExpr(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		expr string
+	}{
+		{
+			ID:   testhelper.MkID("good"),
+			expr: "(a + b) * -c >= 1e3 AND d = 'it''s'",
+		},
+		{
+			ID:     testhelper.MkID("bad: trailing text"),
+			expr:   "a + b c",
+			ExpErr: testhelper.MkExpErr(`expected the end of the expression`),
+		},
+		{
+			ID:     testhelper.MkID("bad: missing operand"),
+			expr:   "a +",
+			ExpErr: testhelper.MkExpErr(`expected a value`),
+		},
+		{
+			ID:   testhelper.MkID("bad: unclosed parentheses"),
+			expr: "((((",
+			ExpErr: testhelper.MkExpErr(`expression: position 4:` +
+				` expected a value, found the end of the expression`),
+		},
+		{
+			ID:   testhelper.MkID("bad: no closing quote"),
+			expr: "a = 'x",
+			ExpErr: testhelper.MkExpErr(`expression: position 4:` +
+				` no closing quote (')`),
+		},
+		{
+			ID:   testhelper.MkID("bad: unexpected character"),
+			expr: "a ? b",
+			ExpErr: testhelper.MkExpErr(`expression: position 2:` +
+				` unexpected '?'`),
+		},
+		{
+			ID:     testhelper.MkID("bad: unclosed parenthesis"),
+			expr:   "(a + b",
+			ExpErr: testhelper.MkExpErr(`expected ")"`),
+		},
+		{
+			ID:     testhelper.MkID("bad: IS"),
+			expr:   "a IS 1",
+			ExpErr: testhelper.MkExpErr(`expected NULL, found "1"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		e, err := dataframe.CompileExpr(tc.expr)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "text", e.String(), tc.expr)
+		}
+	}
+}
+
+func TestFilterMutateExpr(t *testing.T) {
+	df := mkExprTestDF(t)
+
+	e, err := dataframe.CompileExpr("i > 1 OR f < 1")
+	if err != nil {
+		t.Fatal("cannot compile the expression: ", err)
+	}
+	id := "FilterExpr"
+	filtered, err := df.FilterExpr(e)
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	checkDFVals(t, id, filtered, [][]string{
+		{"x", "1", "0.5", "true"},
+		{"y", "2", "NA", "false"},
+		{"z", "4", "2", "true"},
+	})
+
+	e, err = dataframe.CompileExpr("i * f")
+	if err != nil {
+		t.Fatal("cannot compile the expression: ", err)
+	}
+	id = "MutateExpr"
+	mutated, err := df.MutateExpr("prod", e)
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	checkColDetails(t, id, mutated, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+		dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+		dataframe.MustNewColInfo("f", dataframe.ColTypeFloat),
+		dataframe.MustNewColInfo("b", dataframe.ColTypeBool),
+		dataframe.MustNewColInfo("prod", dataframe.ColTypeFloat),
+	})
+	checkDFVals(t, id, mutated, [][]string{
+		{"x", "1", "0.5", "true", "0.5"},
+		{"y", "2", "NA", "false", "NA"},
+		{"x", "NA", "4.5", "NA", "NA"},
+		{"z", "4", "2", "true", "8"},
+	})
+
+	_, err = df.MutateExpr("i", e)
+	testhelper.CheckExpErrWithID(t, "MutateExpr: name in use", err,
+		testhelper.MkExpErr("Column name already used"))
+	_, err = df.FilterExpr(e)
+	testhelper.CheckExpErrWithID(t, "FilterExpr: not bool", err,
+		testhelper.MkExpErr(`filter "i * f":`,
+			"the expression must give a bool value, not Float"))
+}
@@ -0,0 +1,103 @@
+package dataframe
+
+// RowFilter is a function type used to choose rows from a dataframe. It
+// should return true if the row is to be kept
+type RowFilter func(r *Row) bool
+
+// Filter returns a new dataframe with the same columns as df holding just
+// those rows for which the keep function returns true. The values are copied
+// so subsequent changes to df will not affect the new dataframe.
+func (df *DF) Filter(keep RowFilter) *DF {
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+
+	for i := 0; i < df.RowCount(); i++ {
+		if keep(df.Row(i)) {
+			rval.copyRowFrom(df, i)
+		}
+	}
+
+	return rval
+}
+
+// Where returns a new dataframe with the same columns as df holding just
+// those rows for which the value in the named bool column is true; rows
+// where it is false or NA are dropped. The mask column itself is kept. The
+// values are copied as for Filter. It returns an error if there is no such
+// column or it is not a bool column.
+func (df *DF) Where(maskCol string) (*DF, error) {
+	vi, err := df.colValIdxByName(maskCol, ColTypeBool)
+	if err != nil {
+		return nil, err
+	}
+
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+
+	for i := 0; i < df.RowCount(); i++ {
+		if v := df.boolAt(vi, i); v.Val && !v.IsNA {
+			rval.copyRowFrom(df, i)
+		}
+	}
+
+	return rval, nil
+}
+
+// WhereMask returns a new dataframe with the same columns as df holding
+// just those rows for which the corresponding mask value is true. The
+// values are copied as for Filter. It returns an error if the mask does not
+// have one value for each row.
+func (df *DF) WhereMask(mask []bool) (*DF, error) {
+	if len(mask) != df.RowCount() {
+		return nil, dfKindErrorf(ErrDimensionMismatch,
+			"the mask has %d values, the dataframe has %d rows",
+			len(mask), df.RowCount())
+	}
+
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+
+	for i, keep := range mask {
+		if keep {
+			rval.copyRowFrom(df, i)
+		}
+	}
+
+	return rval, nil
+}
+
+// IsIn returns a mask holding, for each row, whether the value in the named
+// column is one of the values given. The result can be passed directly to
+// WhereMask to choose the matching rows. The values may be given either as
+// one of the Val types (BoolVal, IntVal etc) or as the corresponding Go
+// type, as for LookupRows; a nil value or a Val with IsNA set matches NA
+// values. The values are held in a set so each row is checked in constant
+// time however many values are given.
+//
+// It returns an error if there is no such column or if any value cannot be
+// converted to the column type.
+func (df *DF) IsIn(col string, values ...any) ([]bool, error) {
+	i, ok := df.mci.colIdx(col)
+	if !ok {
+		return nil, errUnknownColName(col)
+	}
+
+	set := make(map[any]struct{}, len(values))
+	for vIdx, v := range values {
+		k, err := df.keyFor(i, v)
+		if err != nil {
+			return nil, dfWrapf(err, "column %q: value %d", col, vIdx)
+		}
+		set[k] = struct{}{}
+	}
+
+	mask := make([]bool, df.RowCount())
+	if len(set) == 0 {
+		return mask, nil
+	}
+	for r := range mask {
+		_, mask[r] = set[df.keyAt(i, r)]
+	}
+
+	return mask, nil
+}
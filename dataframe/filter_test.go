@@ -0,0 +1,153 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// whereTestData is in round-trip format so that it can hold NA values
+const whereTestData = `sym qty big
+String Int Bool
+"abc" 10 false
+"xyz" 20 true
+"abc" 30 NA
+"def" 40 true
+`
+
+func TestWhere(t *testing.T) {
+	df := mkTestDF(t, whereTestData, dataframe.DFRRoundTrip)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col     string
+		expVals [][]string
+	}{
+		{
+			ID:  testhelper.MkID("bool column"),
+			col: "big",
+			expVals: [][]string{
+				{"xyz", "20", "true"},
+				{"def", "40", "true"},
+			},
+		},
+		{
+			ID:     testhelper.MkID("no such column"),
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+			col:    "nonesuch",
+		},
+		{
+			ID: testhelper.MkID("not a bool column"),
+			ExpErr: testhelper.MkExpErr(
+				`The column named "qty" is of type "Int" not "Bool"`),
+			col: "qty",
+		},
+	}
+
+	for _, tc := range testCases {
+		wdf, err := df.Where(tc.col)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), wdf, df.Columns())
+			checkDFVals(t, tc.IDStr(), wdf, tc.expVals)
+		}
+	}
+}
+
+func TestWhereMask(t *testing.T) {
+	df := mkTestDF(t, whereTestData, dataframe.DFRRoundTrip)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		mask    []bool
+		expVals [][]string
+	}{
+		{
+			ID:   testhelper.MkID("some rows"),
+			mask: []bool{true, false, true, false},
+			expVals: [][]string{
+				{"abc", "10", "false"},
+				{"abc", "30", "NA"},
+			},
+		},
+		{
+			ID:   testhelper.MkID("no rows"),
+			mask: []bool{false, false, false, false},
+		},
+		{
+			ID: testhelper.MkID("short mask"),
+			ExpErr: testhelper.MkExpErr(
+				"the mask has 2 values, the dataframe has 4 rows"),
+			mask: []bool{true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		wdf, err := df.WhereMask(tc.mask)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkDFVals(t, tc.IDStr(), wdf, tc.expVals)
+		}
+	}
+}
+
+func TestIsIn(t *testing.T) {
+	df := mkTestDF(t, whereTestData, dataframe.DFRRoundTrip)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col     string
+		values  []any
+		expMask []bool
+	}{
+		{
+			ID:      testhelper.MkID("strings"),
+			col:     "sym",
+			values:  []any{"abc", dataframe.StringVal{Val: "pqr"}},
+			expMask: []bool{true, false, true, false},
+		},
+		{
+			ID:      testhelper.MkID("ints, repeated"),
+			col:     "qty",
+			values:  []any{int64(20), int64(30), int64(20)},
+			expMask: []bool{false, true, true, false},
+		},
+		{
+			ID:      testhelper.MkID("NA"),
+			col:     "big",
+			values:  []any{nil, true},
+			expMask: []bool{false, true, true, true},
+		},
+		{
+			ID:      testhelper.MkID("no values"),
+			col:     "sym",
+			expMask: []bool{false, false, false, false},
+		},
+		{
+			ID:     testhelper.MkID("bad: no such column"),
+			ExpErr: testhelper.MkExpErr(`"nonesuch"`),
+			col:    "nonesuch",
+		},
+		{
+			ID:     testhelper.MkID("bad: value type"),
+			ExpErr: testhelper.MkExpErr(`column "qty": value 1`),
+			col:    "qty",
+			values: []any{int64(20), "abc"},
+		},
+	}
+
+	for _, tc := range testCases {
+		mask, err := df.IsIn(tc.col, tc.values...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffSlice(t, tc.IDStr(), "mask", mask, tc.expMask)
+		}
+	}
+
+	headerOnly := mkTestDF(t, "a b\n", dataframe.HasHeader)
+	_, err := headerOnly.IsIn("b", "x")
+	testhelper.CheckExpErrWithID(t, "header only", err,
+		testhelper.MkExpErr(`column "b": value 0:`,
+			`the column named "b" has no type`))
+}
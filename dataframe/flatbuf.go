@@ -0,0 +1,281 @@
+package dataframe
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// This file holds a minimal implementation of the FlatBuffers encoding,
+// just enough to write and read the metadata of the Arrow IPC format.
+
+// fbScalar is a scalar value in a flatbuffer table. The size is in bytes
+type fbScalar struct {
+	size int
+	v    uint64
+}
+
+// fbTable is a flatbuffer table. Each entry is indexed by its field id and
+// is one of: nil (the field is absent), fbScalar, string, fbTable,
+// []fbTable or fbStructs
+type fbTable []any
+
+// fbStructs is a vector of structs. Each struct must be a multiple of 8
+// bytes long and 8-byte aligned
+type fbStructs struct {
+	n    int
+	data []byte
+}
+
+// fbBuilder builds a flatbuffer. Unlike the standard FlatBuffers builders
+// it works from the front so each object is written before the objects it
+// refers to, which keeps all the offsets positive as required.
+type fbBuilder struct {
+	buf []byte
+}
+
+// alignUp returns n rounded up to a multiple of align
+func alignUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
+
+// align pads the buffer to a multiple of n bytes
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// put writes the low size bytes of v at pos
+func (b *fbBuilder) put(pos, size int, v uint64) {
+	for i := 0; i < size; i++ {
+		b.buf[pos+i] = byte(v >> (8 * i))
+	}
+}
+
+// appendN appends the low size bytes of v
+func (b *fbBuilder) appendN(size int, v uint64) {
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	b.put(pos, size, v)
+}
+
+// finish writes the root table and returns the completed flatbuffer
+func (b *fbBuilder) finish(root fbTable) []byte {
+	b.buf = append(b.buf[:0], 0, 0, 0, 0)
+	pos := b.table(root)
+	b.put(0, 4, uint64(pos))
+	return b.buf
+}
+
+// table writes the vtable and the table and then the objects that the
+// table refers to. It returns the position of the table.
+func (b *fbBuilder) table(t fbTable) int {
+	offs := make([]int, len(t))
+	size := 4
+	for i, f := range t {
+		sz := 4
+		switch f := f.(type) {
+		case nil:
+			continue
+		case fbScalar:
+			sz = f.size
+		}
+		size = alignUp(size, sz)
+		offs[i] = size
+		size += sz
+	}
+
+	b.align(2)
+	vt := len(b.buf)
+	b.appendN(2, uint64(4+2*len(t)))
+	b.appendN(2, uint64(size))
+	for _, o := range offs {
+		b.appendN(2, uint64(o))
+	}
+
+	b.align(8)
+	tp := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	b.put(tp, 4, uint64(tp-vt))
+
+	for i, f := range t {
+		if s, ok := f.(fbScalar); ok {
+			b.put(tp+offs[i], s.size, s.v)
+		}
+	}
+	for i, f := range t {
+		switch f.(type) {
+		case nil, fbScalar:
+			continue
+		}
+		fieldPos := tp + offs[i]
+		b.put(fieldPos, 4, uint64(b.obj(f)-fieldPos))
+	}
+	return tp
+}
+
+// obj writes the referenced object and returns its position
+func (b *fbBuilder) obj(o any) int {
+	switch o := o.(type) {
+	case string:
+		b.align(4)
+		pos := len(b.buf)
+		b.appendN(4, uint64(len(o)))
+		b.buf = append(b.buf, o...)
+		b.buf = append(b.buf, 0)
+		return pos
+	case fbTable:
+		return b.table(o)
+	case []fbTable:
+		b.align(4)
+		pos := len(b.buf)
+		b.appendN(4, uint64(len(o)))
+		b.buf = append(b.buf, make([]byte, 4*len(o))...)
+		for i, t := range o {
+			slot := pos + 4 + 4*i
+			b.put(slot, 4, uint64(b.table(t)-slot))
+		}
+		return pos
+	case fbStructs:
+		for len(b.buf)%8 != 4 {
+			b.buf = append(b.buf, 0)
+		}
+		pos := len(b.buf)
+		b.appendN(4, uint64(o.n))
+		b.buf = append(b.buf, o.data...)
+		return pos
+	}
+	panic(dfErrorf("Unexpected flatbuffer object type: %T", o))
+}
+
+// fbError is the type of the value passed to panic when a flatbuffer being
+// read is found to be corrupt. It is recovered and returned as an error.
+type fbError struct {
+	msg string
+}
+
+// fbReader reads values from a flatbuffer, checking that they lie within
+// the buffer
+type fbReader struct {
+	buf []byte
+}
+
+// check panics with an fbError if the n bytes at pos are not all in the
+// buffer
+func (r fbReader) check(pos, n int) {
+	if pos < 0 || n < 0 || pos > len(r.buf) || n > len(r.buf)-pos {
+		panic(fbError{
+			msg: fmt.Sprintf("offset %d (length %d) is outside the buffer"+
+				" (length %d)", pos, n, len(r.buf)),
+		})
+	}
+}
+
+// uint reads the size byte unsigned integer at pos
+func (r fbReader) uint(pos, size int) uint64 {
+	r.check(pos, size)
+	switch size {
+	case 1:
+		return uint64(r.buf[pos])
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(r.buf[pos:]))
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(r.buf[pos:]))
+	}
+	return binary.LittleEndian.Uint64(r.buf[pos:])
+}
+
+// root returns the root table of the flatbuffer
+func (r fbReader) root() fbTab {
+	return r.tab(int(r.uint(0, 4)))
+}
+
+// fbTab is a table in a flatbuffer being read
+type fbTab struct {
+	r     fbReader
+	pos   int
+	vt    int
+	vtLen int
+}
+
+// tab returns the table at pos
+func (r fbReader) tab(pos int) fbTab {
+	vt := pos - int(int32(r.uint(pos, 4)))
+	return fbTab{r: r, pos: pos, vt: vt, vtLen: int(r.uint(vt, 2))}
+}
+
+// off returns the offset in the table of the field with the given id or 0
+// if the field is absent
+func (t fbTab) off(id int) int {
+	o := 4 + 2*id
+	if o+2 > t.vtLen {
+		return 0
+	}
+	return int(t.r.uint(t.vt+o, 2))
+}
+
+// scalar returns the value of the scalar field with the given id or the
+// default value if it is absent
+func (t fbTab) scalar(id, size int, dflt uint64) uint64 {
+	o := t.off(id)
+	if o == 0 {
+		return dflt
+	}
+	return t.r.uint(t.pos+o, size)
+}
+
+// ref returns the position of the object referred to by the field with
+// the given id and true, or false if the field is absent
+func (t fbTab) ref(id int) (int, bool) {
+	o := t.off(id)
+	if o == 0 {
+		return 0, false
+	}
+	p := t.pos + o
+	return p + int(t.r.uint(p, 4)), true
+}
+
+// table returns the table referred to by the field with the given id and
+// true, or false if the field is absent
+func (t fbTab) table(id int) (fbTab, bool) {
+	p, ok := t.ref(id)
+	if !ok {
+		return fbTab{}, false
+	}
+	return t.r.tab(p), true
+}
+
+// str returns the string referred to by the field with the given id or the
+// empty string if the field is absent
+func (t fbTab) str(id int) string {
+	p, ok := t.ref(id)
+	if !ok {
+		return ""
+	}
+	n := int(t.r.uint(p, 4))
+	t.r.check(p+4, n)
+	return string(t.r.buf[p+4 : p+4+n])
+}
+
+// vec returns the position of the first element and the length of the
+// vector referred to by the field with the given id. Each element is
+// elemSize bytes long. The length is zero if the field is absent.
+func (t fbTab) vec(id, elemSize int) (int, int) {
+	p, ok := t.ref(id)
+	if !ok {
+		return 0, 0
+	}
+	n := int(t.r.uint(p, 4))
+	if n > (len(t.r.buf)-p-4)/elemSize {
+		panic(fbError{
+			msg: fmt.Sprintf("the vector length (%d) is too long", n),
+		})
+	}
+	return p + 4, n
+}
+
+// vecTable returns the i'th table in a vector of tables starting at pos
+func (r fbReader) vecTable(pos, i int) fbTab {
+	slot := pos + 4*i
+	return r.tab(slot + int(r.uint(slot, 4)))
+}
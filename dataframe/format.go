@@ -0,0 +1,315 @@
+package dataframe
+
+import (
+	"encoding/csv"
+	"io"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FormatReadFunc reads a dataframe in a particular format from the
+// io.Reader. The source is used in error messages. The options are those
+// given to ReadFile; a format need not use them.
+type FormatReadFunc func(r io.Reader, source string, opts ...DFReaderOpt,
+) (*DF, error)
+
+// FormatWriteFunc writes the dataframe in a particular format to the
+// io.Writer. The options are those given to WriteFile; a format need not
+// use them.
+type FormatWriteFunc func(w io.Writer, df *DF, opts ...DFWriterOpt) error
+
+// Format describes a file format so that it can be registered with
+// RegisterFormat and used by ReadFile and WriteFile
+type Format struct {
+	// Name is the unique name of the format, such as "csv"
+	Name string
+	// Exts are the file extensions, including the leading dot, such as
+	// ".csv", which identify files in this format. They are matched
+	// ignoring case.
+	Exts []string
+	// MIMETypes are the media types, such as "text/csv", of data in this
+	// format
+	MIMETypes []string
+	// Read reads data in this format; it is nil if the format cannot be
+	// read
+	Read FormatReadFunc
+	// Write writes data in this format; it is nil if the format cannot be
+	// written
+	Write FormatWriteFunc
+}
+
+// formatRegistry holds the registered formats
+type formatRegistry struct {
+	mu     sync.RWMutex
+	byName map[string]Format
+	byExt  map[string]string
+	byMIME map[string]string
+}
+
+var formats = formatRegistry{
+	byName: map[string]Format{},
+	byExt:  map[string]string{},
+	byMIME: map[string]string{},
+}
+
+// mediaType returns the media type without any parameters and in lower
+// case
+func mediaType(mt string) string {
+	if t, _, err := mime.ParseMediaType(mt); err == nil {
+		return t
+	}
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// RegisterFormat adds the format to the registry so that files with any
+// of its extensions are read and written by ReadFile and WriteFile using
+// the format's Read and Write functions; either may be nil if the format
+// cannot be read or written. It returns an error if the format has no
+// name or has neither a Read nor a Write function, if an extension doesn't
+// start with a dot or if the name, any of the extensions or any of the
+// MIME types have already been registered.
+func RegisterFormat(f Format) error {
+	if f.Name == "" {
+		return dfErrorf("the format name must not be empty")
+	}
+	if f.Read == nil && f.Write == nil {
+		return dfErrorf("format %q: neither a Read nor a Write function"+
+			" has been given", f.Name)
+	}
+
+	exts := make([]string, 0, len(f.Exts))
+	for _, ext := range f.Exts {
+		if len(ext) < 2 || ext[0] != '.' {
+			return dfErrorf("format %q: bad file extension: %q"+
+				" (it must start with a dot)", f.Name, ext)
+		}
+		exts = append(exts, strings.ToLower(ext))
+	}
+	mts := make([]string, 0, len(f.MIMETypes))
+	for _, mt := range f.MIMETypes {
+		mts = append(mts, mediaType(mt))
+	}
+
+	formats.mu.Lock()
+	defer formats.mu.Unlock()
+
+	if _, ok := formats.byName[f.Name]; ok {
+		return dfErrorf("format %q has already been registered", f.Name)
+	}
+	for _, ext := range exts {
+		if other, ok := formats.byExt[ext]; ok {
+			return dfErrorf("format %q: the file extension %q is already"+
+				" used by format %q", f.Name, ext, other)
+		}
+	}
+	for _, mt := range mts {
+		if other, ok := formats.byMIME[mt]; ok {
+			return dfErrorf("format %q: the MIME type %q is already"+
+				" used by format %q", f.Name, mt, other)
+		}
+	}
+
+	f.Exts = append([]string(nil), f.Exts...)
+	f.MIMETypes = append([]string(nil), f.MIMETypes...)
+	formats.byName[f.Name] = f
+	for _, ext := range exts {
+		formats.byExt[ext] = f.Name
+	}
+	for _, mt := range mts {
+		formats.byMIME[mt] = f.Name
+	}
+	return nil
+}
+
+// MustRegisterFormat calls RegisterFormat and panics if it returns an error
+func MustRegisterFormat(f Format) {
+	if err := RegisterFormat(f); err != nil {
+		panic(err)
+	}
+}
+
+// FormatByName returns the registered format with the given name. The
+// bool is false if there is no such format.
+func FormatByName(name string) (Format, bool) {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+
+	f, ok := formats.byName[name]
+	return f, ok
+}
+
+// FormatForFile returns the registered format for the extension of the
+// filename. The bool is false if there is no such format.
+func FormatForFile(filename string) (Format, bool) {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+
+	name, ok := formats.byExt[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		return Format{}, false
+	}
+	return formats.byName[name], true
+}
+
+// FormatByMIME returns the registered format for the MIME type, any
+// parameters (such as "; charset=utf-8") are ignored. The bool is false if
+// there is no such format.
+func FormatByMIME(mt string) (Format, bool) {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+
+	name, ok := formats.byMIME[mediaType(mt)]
+	if !ok {
+		return Format{}, false
+	}
+	return formats.byName[name], true
+}
+
+// Formats returns the registered formats in order of their names
+func Formats() []Format {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+
+	rval := make([]Format, 0, len(formats.byName))
+	for _, f := range formats.byName {
+		rval = append(rval, f)
+	}
+	sort.Slice(rval, func(i, j int) bool { return rval[i].Name < rval[j].Name })
+	return rval
+}
+
+// readTable reads the data using a DFReader with the options
+func readTable(r io.Reader, source string, opts ...DFReaderOpt) (*DF, error) {
+	dfr, err := NewDFReader(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return dfr.Read(r, source)
+}
+
+// writeTable writes the data using a DFWriter with the options
+func writeTable(w io.Writer, df *DF, opts ...DFWriterOpt) error {
+	dfw, err := NewDFWriter(opts...)
+	if err != nil {
+		return err
+	}
+	return dfw.Write(w, df)
+}
+
+// csvFormatReader returns a FormatReadFunc reading CSV records with the
+// given field separator. If any options are given which apply only to
+// lines of text, such as SplitPattern or CommentPattern, the data is
+// instead read as a table of values (see DFReader.Read) so that the
+// options are honoured.
+func csvFormatReader(sep rune) FormatReadFunc {
+	return func(r io.Reader, source string, opts ...DFReaderOpt) (*DF, error) {
+		dfr, err := NewDFReader(opts...)
+		if err != nil {
+			return nil, err
+		}
+		if dfr.usesLineOpts() {
+			return dfr.Read(r, source)
+		}
+		cr := csv.NewReader(r)
+		cr.Comma = sep
+		return dfr.ReadCSVRecords(cr, source)
+	}
+}
+
+// jsonFormatWriter returns a FormatWriteFunc writing JSON with the given
+// orientation
+func jsonFormatWriter(orient Orientation) FormatWriteFunc {
+	return func(w io.Writer, df *DF, _ ...DFWriterOpt) error {
+		return df.WriteJSON(w, orient)
+	}
+}
+
+func init() {
+	MustRegisterFormat(Format{
+		Name:      "table",
+		Exts:      []string{".txt"},
+		MIMETypes: []string{"text/plain"},
+		Read:      readTable,
+		Write:     writeTable,
+	})
+	MustRegisterFormat(Format{
+		Name:      "csv",
+		Exts:      []string{".csv"},
+		MIMETypes: []string{"text/csv"},
+		Read:      csvFormatReader(','),
+		Write: func(w io.Writer, df *DF, opts ...DFWriterOpt) error {
+			return df.WriteCSV(w, opts...)
+		},
+	})
+	MustRegisterFormat(Format{
+		Name:      "tsv",
+		Exts:      []string{".tsv"},
+		MIMETypes: []string{"text/tab-separated-values"},
+		Read:      csvFormatReader('\t'),
+		Write: func(w io.Writer, df *DF, opts ...DFWriterOpt) error {
+			opts = append([]DFWriterOpt{DFWSeparator("\t")}, opts...)
+			return df.WriteCSV(w, opts...)
+		},
+	})
+	MustRegisterFormat(Format{
+		Name:      "json",
+		Exts:      []string{".json"},
+		MIMETypes: []string{"application/json"},
+		Read: func(r io.Reader, _ string, _ ...DFReaderOpt) (*DF, error) {
+			return ReadJSON(r)
+		},
+		Write: jsonFormatWriter(OrientRecords),
+	})
+	MustRegisterFormat(Format{
+		Name:      "jsonl",
+		Exts:      []string{".jsonl", ".ndjson"},
+		MIMETypes: []string{"application/jsonl", "application/x-ndjson"},
+		Read: func(r io.Reader, _ string, _ ...DFReaderOpt) (*DF, error) {
+			return ReadJSON(r)
+		},
+		Write: jsonFormatWriter(OrientLines),
+	})
+	MustRegisterFormat(Format{
+		Name:      "yaml",
+		Exts:      []string{".yaml", ".yml"},
+		MIMETypes: []string{"application/yaml"},
+		Read: func(r io.Reader, _ string, _ ...DFReaderOpt) (*DF, error) {
+			return ReadYAML(r)
+		},
+	})
+	MustRegisterFormat(Format{
+		Name:      "toml",
+		Exts:      []string{".toml"},
+		MIMETypes: []string{"application/toml"},
+		Read: func(r io.Reader, _ string, _ ...DFReaderOpt) (*DF, error) {
+			return ReadTOML(r, "")
+		},
+	})
+	MustRegisterFormat(Format{
+		Name:      "msgpack",
+		Exts:      []string{".msgpack", ".mpk"},
+		MIMETypes: []string{"application/msgpack"},
+		Read: func(r io.Reader, _ string, _ ...DFReaderOpt) (*DF, error) {
+			return ReadMsgPack(r)
+		},
+		Write: func(w io.Writer, df *DF, _ ...DFWriterOpt) error {
+			return df.WriteMsgPack(w)
+		},
+	})
+	MustRegisterFormat(Format{
+		Name: "arrow",
+		Exts: []string{".arrow", ".feather"},
+		MIMETypes: []string{
+			"application/vnd.apache.arrow.file",
+		},
+		Read: func(r io.Reader, _ string, _ ...DFReaderOpt) (*DF, error) {
+			return ReadIPC(r)
+		},
+		Write: func(w io.Writer, df *DF, _ ...DFWriterOpt) error {
+			return df.WriteIPC(w)
+		},
+	})
+}
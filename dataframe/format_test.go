@@ -0,0 +1,240 @@
+package dataframe_test
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFormatFiles(t *testing.T) {
+	df := mkTestDF(t,
+		"b i f s\n"+
+			"true 1 1.5 x\n"+
+			"false 2 -3 y,z\n",
+		dataframe.HasHeader)
+	dir := t.TempDir()
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		filename string
+	}{
+		{ID: testhelper.MkID("table"), filename: "data.txt"},
+		{ID: testhelper.MkID("no extension"), filename: "data"},
+		{ID: testhelper.MkID("csv"), filename: "data.csv"},
+		{ID: testhelper.MkID("tsv"), filename: "data.TSV"},
+		{ID: testhelper.MkID("json"), filename: "data.json"},
+		{ID: testhelper.MkID("jsonl"), filename: "data.jsonl"},
+		{ID: testhelper.MkID("msgpack"), filename: "data.msgpack"},
+		{ID: testhelper.MkID("arrow"), filename: "data.arrow"},
+		{
+			ID:       testhelper.MkID("yaml"),
+			filename: "data.yaml",
+			ExpErr: testhelper.MkExpErr(
+				`the "yaml" format cannot be written`),
+		},
+	}
+
+	for _, tc := range testCases {
+		filename := filepath.Join(dir, tc.filename)
+		err := dataframe.WriteFile(filename, df)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		var opts []dataframe.DFReaderOpt
+		if f, ok := dataframe.FormatForFile(filename); !ok ||
+			f.Name == "table" || f.Name == "csv" || f.Name == "tsv" {
+			opts = append(opts, dataframe.HasHeader)
+		}
+		rdf, err := dataframe.ReadFile(filename, opts...)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot read the file back: %s", err)
+			continue
+		}
+		if err := rdf.Equal(df); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the dataframe read back differs: %s", err)
+		}
+	}
+}
+
+func TestReadFileFormats(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		testhelper.ID
+		filename string
+		content  string
+		opts     []dataframe.DFReaderOpt
+		expCols  []dataframe.ColInfo
+		expVals  [][]string
+	}{
+		{
+			ID:       testhelper.MkID("csv"),
+			filename: "data.csv",
+			content:  "a,b\n\"x, y\",2\n",
+			opts:     []dataframe.DFReaderOpt{dataframe.HasHeader},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"x, y", "2"}},
+		},
+		{
+			ID:       testhelper.MkID("csv, line options"),
+			filename: "lines.csv",
+			content:  "# comment\na|b\ntrue|2\n",
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.SplitPattern(`\|`),
+				dataframe.CommentPattern("#.*"),
+				dataframe.SkipBlankLines,
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"true", "2"}},
+		},
+		{
+			ID:       testhelper.MkID("toml"),
+			filename: "data.toml",
+			content:  "[[fruit]]\nname = \"apple\"\nqty = 3\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"apple", "3"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		filename := filepath.Join(dir, tc.filename)
+		err := os.WriteFile(filename, []byte(tc.content), 0o600)
+		if err != nil {
+			t.Fatal("cannot write the test file: ", err)
+		}
+		df, err := dataframe.ReadFile(filename, tc.opts...)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot read the file: %s", err)
+			continue
+		}
+		checkColDetails(t, tc.IDStr(), df, tc.expCols)
+		checkDFVals(t, tc.IDStr(), df, tc.expVals)
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	// upperRead reads a table of values converting them to upper case
+	upperRead := func(r io.Reader, source string,
+		opts ...dataframe.DFReaderOpt,
+	) (*dataframe.DF, error) {
+		var sb strings.Builder
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			sb.WriteString(strings.ToUpper(scanner.Text()) + "\n")
+		}
+		dfr, err := dataframe.NewDFReader(opts...)
+		if err != nil {
+			return nil, err
+		}
+		return dfr.Read(strings.NewReader(sb.String()), source)
+	}
+
+	err := dataframe.RegisterFormat(dataframe.Format{
+		Name:      "upper-test",
+		Exts:      []string{".UPR"},
+		MIMETypes: []string{"text/x-upper"},
+		Read:      upperRead,
+	})
+	if err != nil {
+		t.Fatal("cannot register the format: ", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "data.upr")
+	if err := os.WriteFile(filename, []byte("a b\nx y\n"), 0o600); err != nil {
+		t.Fatal("cannot write the test file: ", err)
+	}
+	df, err := dataframe.ReadFile(filename, dataframe.HasHeader)
+	if err != nil {
+		t.Fatal("cannot read the file: ", err)
+	}
+	checkColDetails(t, "custom format", df, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("A", dataframe.ColTypeString),
+		dataframe.MustNewColInfo("B", dataframe.ColTypeString),
+	})
+	checkDFVals(t, "custom format", df, [][]string{{"X", "Y"}})
+
+	f, ok := dataframe.FormatByMIME("Text/X-Upper; charset=utf-8")
+	testhelper.DiffBool(t, "FormatByMIME", "found", ok, true)
+	testhelper.DiffString(t, "FormatByMIME", "name", f.Name, "upper-test")
+	_, ok = dataframe.FormatByName("nonesuch")
+	testhelper.DiffBool(t, "FormatByName: nonesuch", "found", ok, false)
+
+	err = dataframe.WriteFile(filename, df)
+	testhelper.CheckExpErrWithID(t, "write a read-only format", err,
+		testhelper.MkExpErr(`the "upper-test" format cannot be written`))
+
+	badCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		f dataframe.Format
+	}{
+		{
+			ID:     testhelper.MkID("no name"),
+			f:      dataframe.Format{Read: upperRead},
+			ExpErr: testhelper.MkExpErr("the format name must not be empty"),
+		},
+		{
+			ID: testhelper.MkID("no functions"),
+			f:  dataframe.Format{Name: "x"},
+			ExpErr: testhelper.MkExpErr(
+				"neither a Read nor a Write function has been given"),
+		},
+		{
+			ID: testhelper.MkID("bad extension"),
+			f: dataframe.Format{
+				Name: "x", Exts: []string{"x"}, Read: upperRead,
+			},
+			ExpErr: testhelper.MkExpErr(`bad file extension: "x"`),
+		},
+		{
+			ID: testhelper.MkID("name in use"),
+			f:  dataframe.Format{Name: "csv", Read: upperRead},
+			ExpErr: testhelper.MkExpErr(
+				`format "csv" has already been registered`),
+		},
+		{
+			ID: testhelper.MkID("extension in use"),
+			f: dataframe.Format{
+				Name: "x", Exts: []string{".CSV"}, Read: upperRead,
+			},
+			ExpErr: testhelper.MkExpErr(
+				`the file extension ".csv" is already used by format "csv"`),
+		},
+		{
+			ID: testhelper.MkID("MIME type in use"),
+			f: dataframe.Format{
+				Name: "x", MIMETypes: []string{"text/csv"}, Read: upperRead,
+			},
+			ExpErr: testhelper.MkExpErr(
+				`the MIME type "text/csv" is already used by format "csv"`),
+		},
+	}
+
+	for _, tc := range badCases {
+		err := dataframe.RegisterFormat(tc.f)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+	_, ok = dataframe.FormatByName("x")
+	testhelper.DiffBool(t, "failed registrations", "found", ok, false)
+}
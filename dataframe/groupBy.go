@@ -0,0 +1,419 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AggFunc identifies the aggregation to be applied to the values of a column
+// in each group
+type AggFunc uint
+
+// AggCount counts the non-NA values (or the rows if no column is given)
+// AggSum sums the non-NA values
+// AggMean gives the mean of the non-NA values
+// AggMin gives the smallest of the non-NA values
+// AggMax gives the largest of the non-NA values
+// AggMaxVal is a guard value used to ensure validity
+const (
+	AggCount AggFunc = iota
+	AggSum
+	AggMean
+	AggMin
+	AggMax
+	AggMaxVal
+)
+
+// String returns the name of the aggregation function
+func (af AggFunc) String() string {
+	switch af {
+	case AggCount:
+		return "Count"
+	case AggSum:
+		return "Sum"
+	case AggMean:
+		return "Mean"
+	case AggMin:
+		return "Min"
+	case AggMax:
+		return "Max"
+	}
+	return "AggFunc(" + strconv.FormatUint(uint64(af), 10) + ")"
+}
+
+// Agg describes an aggregation to be calculated for each group: the column
+// whose values are aggregated, the aggregation function and the name of the
+// resulting column. If the Name is empty a name is made from the column name
+// and the function name (for instance "price_Mean"). The column may only be
+// empty for AggCount in which case the rows in the group are counted.
+//
+// The AggCount function gives an int column, the other functions are only
+// valid for int or float columns and give a float column. If a group has no
+// non-NA values the result of the other functions will be NA.
+type Agg struct {
+	Col  string
+	Func AggFunc
+	Name string
+}
+
+// colName returns the name of the column holding the aggregated values
+func (a Agg) colName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	if a.Col == "" {
+		return a.Func.String()
+	}
+	return a.Col + "_" + a.Func.String()
+}
+
+// colInfo checks that the aggregation is valid for the columns and returns
+// the ColInfo for the resulting column
+func (a Agg) colInfo(cis []ColInfo) (ColInfo, error) {
+	if a.Func >= AggMaxVal {
+		return ColInfo{}, dfErrorf("bad aggregation function: %s", a.Func)
+	}
+
+	if a.Col == "" {
+		if a.Func != AggCount {
+			return ColInfo{},
+				dfErrorf("no column given for the %s aggregation", a.Func)
+		}
+		return ColInfo{name: a.colName(), colType: ColTypeInt}, nil
+	}
+
+	ci, err := colInfoByName(cis, a.Col)
+	if err != nil {
+		return ColInfo{}, err
+	}
+
+	if a.Func == AggCount {
+		return ColInfo{name: a.colName(), colType: ColTypeInt}, nil
+	}
+
+	if ci.colType != ColTypeInt && ci.colType != ColTypeFloat {
+		return ColInfo{}, dfKindErrorf(ErrTypeMismatch,
+			"cannot calculate the %s of column %q: it is of type %q",
+			a.Func, a.Col, ci.colType)
+	}
+
+	return ColInfo{name: a.colName(), colType: ColTypeFloat}, nil
+}
+
+// colInfoByName returns the ColInfo with the given name or an error if
+// there is no such column
+func colInfoByName(cis []ColInfo, name string) (ColInfo, error) {
+	for _, ci := range cis {
+		if ci.name == name {
+			return ci, nil
+		}
+	}
+	return ColInfo{}, errUnknownColName(name)
+}
+
+// aggAcc accumulates the values needed to calculate an aggregation
+type aggAcc struct {
+	count    int64
+	sum      float64
+	min, max float64
+}
+
+// add adds the value to the accumulated values. A nil value (no column) is
+// counted, NA values are ignored.
+func (acc *aggAcc) add(v any) {
+	var f float64
+
+	switch v := v.(type) {
+	case nil:
+		acc.count++
+		return
+	case BoolVal:
+		if !v.IsNA {
+			acc.count++
+		}
+		return
+	case StringVal:
+		if !v.IsNA {
+			acc.count++
+		}
+		return
+	case IntVal:
+		if v.IsNA {
+			return
+		}
+		f = float64(v.Val)
+	case FloatVal:
+		if v.IsNA {
+			return
+		}
+		f = v.Val
+	}
+
+	if acc.count == 0 {
+		acc.min, acc.max = f, f
+	} else {
+		acc.min = math.Min(acc.min, f)
+		acc.max = math.Max(acc.max, f)
+	}
+	acc.count++
+	acc.sum += f
+}
+
+// val returns the value of the aggregation
+func (acc aggAcc) val(af AggFunc) any {
+	if af == AggCount {
+		return IntVal{Val: acc.count}
+	}
+
+	if acc.count == 0 {
+		return FloatVal{IsNA: true}
+	}
+
+	switch af {
+	case AggSum:
+		return FloatVal{Val: acc.sum}
+	case AggMean:
+		return FloatVal{Val: acc.sum / float64(acc.count)}
+	case AggMin:
+		return FloatVal{Val: acc.min}
+	case AggMax:
+		return FloatVal{Val: acc.max}
+	}
+
+	return FloatVal{IsNA: true}
+}
+
+// keyOf returns the underlying value of the column value and whether or not
+// it is NA. The value can be used as a map key.
+func keyOf(v any) (any, bool) {
+	switch v := v.(type) {
+	case BoolVal:
+		return v.Val, v.IsNA
+	case IntVal:
+		return v.Val, v.IsNA
+	case FloatVal:
+		return v.Val, v.IsNA
+	case StringVal:
+		return v.Val, v.IsNA
+	}
+	return v, v == nil
+}
+
+// groupKey returns a string which uniquely identifies the tuple of key
+// values so that it can be used as a map key. The values of each type are
+// formatted differently so that, for instance, the int 1 and the string
+// "1" give different keys, as do each of the NA values.
+func groupKey(vals []any) string {
+	var b strings.Builder
+	for i, v := range vals {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		fmt.Fprintf(&b, "%#v", v)
+	}
+	return b.String()
+}
+
+// grouper splits rows into groups according to the values of the key
+// columns and accumulates the aggregations for each group
+type grouper struct {
+	keys    []string
+	naGroup bool
+	aggs    []Agg
+	outCIs  []ColInfo
+
+	groupIdx  map[string]int
+	groupKeys [][]any
+	accs      [][]aggAcc
+}
+
+// newGrouper checks that the keys and aggregations are valid for the
+// columns and returns a grouper ready to have rows added. If naGroup is
+// true then rows having NA key values form groups of their own, otherwise
+// they are ignored.
+func newGrouper(cis []ColInfo, keys []string, naGroup bool, aggs []Agg) (
+	*grouper, error,
+) {
+	if len(keys) == 0 {
+		return nil, dfCodeErrorf(ErrCodeNoKeyCols,
+			"no key columns have been given")
+	}
+
+	g := &grouper{
+		keys:     keys,
+		naGroup:  naGroup,
+		aggs:     aggs,
+		groupIdx: map[string]int{},
+	}
+
+	names := map[string]bool{}
+	for _, key := range keys {
+		keyCI, err := colInfoByName(cis, key)
+		if err != nil {
+			return nil, err
+		}
+		if names[key] {
+			return nil, dfCodeErrorf(ErrCodeDuplicateKeyCol,
+				"duplicate key column: %q", key)
+		}
+		names[key] = true
+		g.outCIs = append(g.outCIs, keyCI)
+	}
+
+	for _, a := range aggs {
+		ci, err := a.colInfo(cis)
+		if err != nil {
+			return nil, err
+		}
+		if names[ci.name] {
+			return nil, dfCodeErrorf(ErrCodeDuplicateColName,
+				"duplicate column name: %q", ci.name)
+		}
+		names[ci.name] = true
+		g.outCIs = append(g.outCIs, ci)
+	}
+
+	return g, nil
+}
+
+// addRow adds the row to the group given by its key values. Rows with an
+// NA key value are ignored unless the grouper has NA groups.
+func (g *grouper) addRow(r *Row) error {
+	kvs := make([]any, 0, len(g.keys))
+	for _, key := range g.keys {
+		kv, _, err := r.ValByName(key)
+		if err != nil {
+			return err
+		}
+		if _, isNA := keyOf(kv); isNA && !g.naGroup {
+			return nil
+		}
+		kvs = append(kvs, kv)
+	}
+
+	k := groupKey(kvs)
+	idx, ok := g.groupIdx[k]
+	if !ok {
+		idx = len(g.groupKeys)
+		g.groupIdx[k] = idx
+		g.groupKeys = append(g.groupKeys, kvs)
+		g.accs = append(g.accs, make([]aggAcc, len(g.aggs)))
+	}
+
+	for i, a := range g.aggs {
+		var v any
+		if a.Col != "" {
+			var err error
+			v, _, err = r.ValByName(a.Col)
+			if err != nil {
+				return err
+			}
+		}
+		g.accs[idx][i].add(v)
+	}
+
+	return nil
+}
+
+// result creates a dataframe having one row per group, in the order in
+// which the groups were first seen. The first columns have the key values
+// and the subsequent columns have the aggregated values.
+func (g *grouper) result() (*DF, error) {
+	df, err := newDFFromColInfo(g.outCIs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for gi, kvs := range g.groupKeys {
+		for i, kv := range kvs {
+			if err := df.appendVal(i, kv); err != nil {
+				return nil, err
+			}
+		}
+		for i, a := range g.aggs {
+			err := df.appendVal(len(kvs)+i, g.accs[gi][i].val(a.Func))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return df, nil
+}
+
+// GroupedDF records a dataframe and the columns used to split it into
+// groups
+type GroupedDF struct {
+	df      *DF
+	keys    []string
+	naGroup bool
+}
+
+// GroupBy returns a GroupedDF which can be used to calculate aggregations
+// for each distinct value of the key column. It returns an error if there
+// is no such column. Rows with an NA key value are ignored; see GroupByCols
+// for grouping which keeps them.
+func (df *DF) GroupBy(key string) (*GroupedDF, error) {
+	i, ok := df.mci.colIdx(key)
+	if !ok {
+		return nil, errUnknownColName(key)
+	}
+
+	return &GroupedDF{df: df, keys: []string{df.mci.info[i].name}}, nil
+}
+
+// GroupByCols returns a GroupedDF which can be used to calculate
+// aggregations for each distinct tuple of values of the key columns. The
+// result of Agg has one column for each key, in the order given, followed
+// by the aggregated values. Unlike GroupBy, NA is treated as a key value
+// like any other so rows with NA key values are grouped together rather
+// than being ignored. It returns an error if no keys are given, if there
+// is no such column or if a column is given more than once.
+func (df *DF) GroupByCols(keys ...string) (*GroupedDF, error) {
+	if len(keys) == 0 {
+		return nil, dfCodeErrorf(ErrCodeNoKeyCols,
+			"no key columns have been given")
+	}
+
+	names := make([]string, 0, len(keys))
+	seen := map[string]bool{}
+	for _, key := range keys {
+		i, ok := df.mci.colIdx(key)
+		if !ok {
+			return nil, errUnknownColName(key)
+		}
+		name := df.mci.info[i].name
+		if seen[name] {
+			return nil, dfCodeErrorf(ErrCodeDuplicateKeyCol,
+				"duplicate key column: %q", key)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return &GroupedDF{
+		df:      df,
+		keys:    names,
+		naGroup: true,
+	}, nil
+}
+
+// Agg calculates the aggregations for each group and returns a new
+// dataframe with one row per group. The first columns hold the key values,
+// the remaining columns hold the aggregated values in the order given.
+func (gdf *GroupedDF) Agg(aggs ...Agg) (*DF, error) {
+	g, err := newGrouper(gdf.df.mci.info, gdf.keys, gdf.naGroup, aggs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < gdf.df.RowCount(); i++ {
+		if err := g.addRow(gdf.df.Row(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.result()
+}
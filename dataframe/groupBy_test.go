@@ -0,0 +1,127 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// groupTestData is in round-trip format so that it can hold NA values
+const groupTestData = `region sym qty
+String String Int
+"north" "abc" 10
+"south" "abc" 20
+"north" "xyz" 30
+"north" "abc" 40
+NA      "abc" 50
+"south" NA    60
+NA      "abc" 70
+"NA"    "abc" 80
+`
+
+func TestGroupByCols(t *testing.T) {
+	df := mkTestDF(t, groupTestData, dataframe.DFRRoundTrip)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		keys    []string
+		aggs    []dataframe.Agg
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:   testhelper.MkID("two keys"),
+			keys: []string{"region", "sym"},
+			aggs: []dataframe.Agg{
+				{Func: dataframe.AggCount},
+				{Col: "qty", Func: dataframe.AggSum},
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("sym", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("Count", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("qty_Sum", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"north", "abc", "2", "50"},
+				{"south", "abc", "1", "20"},
+				{"north", "xyz", "1", "30"},
+				{"NA", "abc", "2", "120"},
+				{"south", "NA", "1", "60"},
+				{"NA", "abc", "1", "80"},
+			},
+		},
+		{
+			ID:   testhelper.MkID("one key, NA is a group"),
+			keys: []string{"region"},
+			aggs: []dataframe.Agg{{Col: "qty", Func: dataframe.AggMax}},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty_Max", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"north", "40"},
+				{"south", "60"},
+				{"NA", "70"},
+				{"NA", "80"},
+			},
+		},
+		{
+			ID:   testhelper.MkID("keys in a different order"),
+			keys: []string{"sym", "region"},
+			aggs: []dataframe.Agg{{Func: dataframe.AggCount}},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("sym", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("Count", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"abc", "north", "2"},
+				{"abc", "south", "1"},
+				{"xyz", "north", "1"},
+				{"abc", "NA", "2"},
+				{"NA", "south", "1"},
+				{"abc", "NA", "1"},
+			},
+		},
+		{
+			ID:     testhelper.MkID("no keys"),
+			ExpErr: testhelper.MkExpErr("no key columns have been given"),
+		},
+		{
+			ID:     testhelper.MkID("bad key"),
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+			keys:   []string{"region", "nonesuch"},
+		},
+		{
+			ID:     testhelper.MkID("duplicate key"),
+			ExpErr: testhelper.MkExpErr(`duplicate key column: "sym"`),
+			keys:   []string{"sym", "region", "sym"},
+		},
+		{
+			ID: testhelper.MkID("aggregation clashes with a key"),
+			ExpErr: testhelper.MkExpErr(
+				`duplicate column name: "sym"`),
+			keys: []string{"region", "sym"},
+			aggs: []dataframe.Agg{
+				{Col: "qty", Func: dataframe.AggSum, Name: "sym"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		gdf, err := df.GroupByCols(tc.keys...)
+		if err == nil {
+			var adf *dataframe.DF
+			adf, err = gdf.Agg(tc.aggs...)
+			if testhelper.CheckExpErr(t, err, tc) && err == nil {
+				checkColDetails(t, tc.IDStr(), adf, tc.expCols)
+				checkDFVals(t, tc.IDStr(), adf, tc.expVals)
+			}
+			continue
+		}
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
@@ -0,0 +1,119 @@
+package dataframe
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+)
+
+// hashWriter writes values to a hash in an unambiguous form
+type hashWriter struct {
+	h   hash.Hash64
+	buf [8]byte
+}
+
+// newHashWriter returns a hashWriter using a new 64-bit FNV-1a hash
+func newHashWriter() *hashWriter {
+	return &hashWriter{h: fnv.New64a()}
+}
+
+// uint64 writes the value to the hash
+func (hw *hashWriter) uint64(v uint64) {
+	binary.LittleEndian.PutUint64(hw.buf[:], v)
+	_, _ = hw.h.Write(hw.buf[:]) // writing to a hash never fails
+}
+
+// string writes the length of the string and then the string to the hash
+func (hw *hashWriter) string(s string) {
+	hw.uint64(uint64(len(s)))
+	_, _ = hw.h.Write([]byte(s)) // writing to a hash never fails
+}
+
+// schema writes the column names and types to the hash
+func (hw *hashWriter) schema(mci MultiColInfo) {
+	hw.uint64(uint64(len(mci.info)))
+	for _, ci := range mci.info {
+		hw.string(ci.name)
+		hw.uint64(uint64(ci.colType))
+	}
+}
+
+// val writes the value at the indexed column and row to the hash. All NA
+// values hash the same, as do all NaN values, and negative zero hashes the
+// same as zero.
+func (hw *hashWriter) val(df *DF, colIdx, rowIdx int) {
+	v, isNA := df.valAt(colIdx, rowIdx)
+	if isNA {
+		hw.uint64(0)
+		return
+	}
+	hw.uint64(1)
+
+	switch v := v.(type) {
+	case BoolVal:
+		if v.Val {
+			hw.uint64(1)
+		} else {
+			hw.uint64(0)
+		}
+	case IntVal:
+		hw.uint64(uint64(v.Val))
+	case FloatVal:
+		switch {
+		case math.IsNaN(v.Val):
+			hw.uint64(math.Float64bits(math.NaN()))
+		case v.Val == 0:
+			hw.uint64(0)
+		default:
+			hw.uint64(math.Float64bits(v.Val))
+		}
+	case StringVal:
+		hw.string(v.Val)
+	}
+}
+
+// rowHash returns the hash of the values in the indexed row
+func (df *DF) rowHash(rowIdx int) uint64 {
+	hw := newHashWriter()
+	for c := range df.mci.info {
+		hw.val(df, c, rowIdx)
+	}
+	return hw.h.Sum64()
+}
+
+// Hash returns a fingerprint of the dataframe covering the column names
+// and types and all the values, in order. Two dataframes with the same
+// columns holding the same values in the same order will have the same
+// hash. The hash is stable between runs so it can be used as a cache key or
+// saved to detect later changes, but note that, as with any hash,
+// different dataframes may have the same hash. The errors, indexes,
+// compression and metadata of the dataframe are not included.
+func (df *DF) Hash() uint64 {
+	hw := newHashWriter()
+	hw.schema(df.mci)
+	hw.uint64(uint64(df.RowCount()))
+	for r := 0; r < df.RowCount(); r++ {
+		for c := range df.mci.info {
+			hw.val(df, c, r)
+		}
+	}
+	return hw.h.Sum64()
+}
+
+// HashUnordered returns a fingerprint of the dataframe as for Hash except
+// that the order of the rows is ignored, so a dataframe will have the same
+// hash after it has been sorted. The order of the columns is still
+// significant, as is the number of times each row appears.
+func (df *DF) HashUnordered() uint64 {
+	var rowSum uint64
+	for r := 0; r < df.RowCount(); r++ {
+		rowSum += df.rowHash(r)
+	}
+
+	hw := newHashWriter()
+	hw.schema(df.mci)
+	hw.uint64(uint64(df.RowCount()))
+	hw.uint64(rowSum)
+	return hw.h.Sum64()
+}
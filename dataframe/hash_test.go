@@ -0,0 +1,69 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestHash(t *testing.T) {
+	mk := func(content string) *dataframe.DF {
+		return mkTestDF(t, content,
+			dataframe.HasHeader, dataframe.AllowErrors,
+			dataframe.DFRColTypes(dataframe.ColTypeInt,
+				dataframe.ColTypeFloat, dataframe.ColTypeString))
+	}
+	df := mk("i f s\n1 1.5 a\n2 NA b\n3 0 c\n")
+
+	testCases := []struct {
+		testhelper.ID
+		other        *dataframe.DF
+		expSame      bool
+		expSameUnord bool
+	}{
+		{
+			ID:           testhelper.MkID("same"),
+			other:        mk("i f s\n1 1.5 a\n2 NA b\n3 0 c\n"),
+			expSame:      true,
+			expSameUnord: true,
+		},
+		{
+			ID:           testhelper.MkID("same, negative zero, other NA"),
+			other:        mk("i f s\n1 1.5 a\n2 x b\n3 -0 c\n"),
+			expSame:      true,
+			expSameUnord: true,
+		},
+		{
+			ID:           testhelper.MkID("rows reordered"),
+			other:        mk("i f s\n3 0 c\n1 1.5 a\n2 NA b\n"),
+			expSameUnord: true,
+		},
+		{
+			ID:    testhelper.MkID("value changed"),
+			other: mk("i f s\n1 1.5 a\n2 NA b\n3 0 d\n"),
+		},
+		{
+			ID:    testhelper.MkID("row repeated"),
+			other: mk("i f s\n1 1.5 a\n2 NA b\n3 0 c\n3 0 c\n"),
+		},
+		{
+			ID:    testhelper.MkID("column renamed"),
+			other: mk("i f t\n1 1.5 a\n2 NA b\n3 0 c\n"),
+		},
+		{
+			ID: testhelper.MkID("column type changed"),
+			other: mkTestDF(t, "i f s\n1 1.5 a\n2 NA b\n3 0 c\n",
+				dataframe.HasHeader, dataframe.AllowErrors,
+				dataframe.DFRColTypes(dataframe.ColTypeFloat,
+					dataframe.ColTypeFloat, dataframe.ColTypeString)),
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffBool(t, tc.IDStr(), "same hash",
+			df.Hash() == tc.other.Hash(), tc.expSame)
+		testhelper.DiffBool(t, tc.IDStr(), "same unordered hash",
+			df.HashUnordered() == tc.other.HashUnordered(), tc.expSameUnord)
+	}
+}
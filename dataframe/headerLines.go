@@ -0,0 +1,114 @@
+package dataframe
+
+import "fmt"
+
+// DFRHeaderLines returns a function which will cause the DFReader to treat
+// the first n lines (after any skipped lines) as a header spanning several
+// lines, for instance with the column names on the first line and their
+// units on the second. The header lines must all have the same number of
+// fields. The metaKeys give, for each header line after the first, the
+// column metadata key under which the fields of that line are stored (see
+// ColMeta); an empty or missing key means that the fields are instead
+// added to the column names, separated by a space. An empty field is
+// ignored, so a column with no unit gets no unit metadata. For example,
+// given:
+//
+//	name  price  weight
+//	""    GBP    kg
+//
+// DFRHeaderLines(2, MetaUnit) (with DFRQuotedFields so the empty field
+// can be given) gives columns named name, price and weight with units of
+// GBP and kg on the last two, while DFRHeaderLines(2) gives columns named
+// name, "price GBP" and "weight kg".
+//
+// This implies HasHeader and so cannot be given with DFRColNames.
+func DFRHeaderLines(n int, metaKeys ...string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if n < 1 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the number of header lines (%d) must be > 0", n)
+		}
+		if len(metaKeys) > n-1 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"too many metadata keys (%d)"+
+					" for %d header lines, the maximum is %d",
+				len(metaKeys), n, n-1)
+		}
+		if err := HasHeader(dfr); err != nil {
+			return err
+		}
+		dfr.headerLines = n
+		dfr.headerMetaKeys = append([]string(nil), metaKeys...)
+		return nil
+	}
+}
+
+// headerMetaKey returns the metadata key for the header line following
+// the first, with index i, or the empty string if there is none
+func (dfr *DFReader) headerMetaKey(i int) string {
+	if i < len(dfr.headerMetaKeys) {
+		return dfr.headerMetaKeys[i]
+	}
+	return ""
+}
+
+// joinHeaderField returns the name with the field from a later header line
+// added, separated by a space. An empty field is ignored.
+func joinHeaderField(name, field string) string {
+	switch {
+	case field == "":
+		return name
+	case name == "":
+		return field
+	}
+	return name + " " + field
+}
+
+// handleHeaderLines collects the header lines when the header spans more
+// than one line (see DFRHeaderLines). It skips each header line until the
+// last when it replaces the columns with the combined column names and
+// records any header metadata to be set on the columns.
+func handleHeaderLines(dfr *DFReader, state *dfReadState, df *DF) (
+	bool, error,
+) {
+	if dfr.headerLines <= 1 || state.dataLineNum != 0 {
+		return false, nil
+	}
+
+	if len(state.headerRows) > 0 &&
+		len(state.cols) != len(state.headerRows[0]) {
+		err := state.parseError(ErrDimensionMismatch, ErrCodeWrongFieldCount,
+			fmt.Sprintf("header line %d has %d fields but the first has %d",
+				len(state.headerRows)+1, len(state.cols),
+				len(state.headerRows[0])))
+		df.addError(err)
+		return true, err
+	}
+
+	state.headerRows = append(state.headerRows, state.cols)
+	if len(state.headerRows) < dfr.headerLines {
+		return true, nil
+	}
+
+	names := append([]string(nil), state.headerRows[0]...)
+	for r, row := range state.headerRows[1:] {
+		if key := dfr.headerMetaKey(r); key == "" {
+			for i, val := range row {
+				names[i] = joinHeaderField(names[i], val)
+			}
+		}
+	}
+	for r, row := range state.headerRows[1:] {
+		if key := dfr.headerMetaKey(r); key != "" {
+			for i, val := range row {
+				if val != "" {
+					state.headerMeta = append(state.headerMeta,
+						colMetaSetting{col: names[i], key: key, val: val})
+				}
+			}
+		}
+	}
+	state.cols = names
+
+	return false, nil
+}
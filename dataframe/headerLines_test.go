@@ -0,0 +1,143 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRHeaderLines(t *testing.T) {
+	type expMeta struct {
+		col, key, val string
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+		expMeta []expMeta
+	}{
+		{
+			ID: testhelper.MkID("units as metadata"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRQuotedFields,
+				dataframe.DFRHeaderLines(2, dataframe.MetaUnit),
+			},
+			data: "name price weight\n" +
+				"\"\" GBP kg\n" +
+				"apple 1.5 20\n" +
+				"pear 2 30\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("weight", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"apple", "1.5", "20"}, {"pear", "2", "30"}},
+			expMeta: []expMeta{
+				{col: "price", key: dataframe.MetaUnit, val: "GBP"},
+				{col: "weight", key: dataframe.MetaUnit, val: "kg"},
+			},
+		},
+		{
+			ID: testhelper.MkID("lines joined into the names"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.SkipLines(1),
+				dataframe.DFRHeaderLines(3, "", dataframe.MetaLabel),
+				dataframe.DFRColMeta("x GBP", dataframe.MetaLabel, "X"),
+			},
+			data: "# skipped\n" +
+				"x y\n" +
+				"GBP kg\n" +
+				"cost mass\n" +
+				"12 13\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("x GBP", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("y kg", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"12", "13"}},
+			expMeta: []expMeta{
+				{col: "x GBP", key: dataframe.MetaLabel, val: "X"},
+				{col: "y kg", key: dataframe.MetaLabel, val: "mass"},
+			},
+		},
+		{
+			ID: testhelper.MkID("header only"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRHeaderLines(2),
+				dataframe.DFRColTypes(dataframe.ColTypeInt),
+			},
+			data: "x\nm\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("x m", dataframe.ColTypeInt),
+			},
+		},
+		{
+			ID: testhelper.MkID("header lines differ in length"),
+			ExpErr: testhelper.MkExpErr(
+				"header line 2 has 1 fields but the first has 2"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRHeaderLines(2)},
+			data: "x y\nm\n12 13\n",
+		},
+		{
+			ID: testhelper.MkID("bad line count"),
+			ExpErr: testhelper.MkExpErr(
+				"the number of header lines (0) must be > 0"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRHeaderLines(0)},
+		},
+		{
+			ID: testhelper.MkID("too many keys"),
+			ExpErr: testhelper.MkExpErr(
+				"too many metadata keys (2) for 2 header lines," +
+					" the maximum is 1"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRHeaderLines(2, "a", "b"),
+			},
+		},
+		{
+			ID: testhelper.MkID("with column names"),
+			ExpErr: testhelper.MkExpErr("you cannot give column names" +
+				" and take names from a header"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColNames("a"),
+				dataframe.DFRHeaderLines(2),
+			},
+		},
+		{
+			ID: testhelper.MkID("round-trip"),
+			ExpErr: testhelper.MkExpErr("a header spanning several lines" +
+				" cannot be given in round-trip mode"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRRoundTrip,
+				dataframe.DFRHeaderLines(2),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if err == nil {
+			var df *dataframe.DF
+			df, err = dfr.Read(strings.NewReader(tc.data), "test data")
+			if err == nil {
+				checkColDetails(t, tc.IDStr(), df, tc.expCols)
+				checkDFVals(t, tc.IDStr(), df, tc.expVals)
+				for _, em := range tc.expMeta {
+					val, _, err := df.ColMeta(em.col, em.key)
+					if err != nil {
+						t.Log(tc.IDStr())
+						t.Error("\t: unexpected error: ", err)
+						continue
+					}
+					testhelper.DiffString(t, tc.IDStr(),
+						em.col+" metadata: "+em.key, val, em.val)
+				}
+			}
+		}
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
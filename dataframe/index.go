@@ -0,0 +1,212 @@
+package dataframe
+
+// naKey is used as the index key for NA values
+type naKey struct{}
+
+// colIndex maps the values in a column to the indexes of the rows having
+// that value. The rowCount records the number of rows that have been
+// indexed.
+type colIndex struct {
+	rows     map[any][]int
+	rowCount int
+}
+
+// keyAt returns the value of the indexed column and row in a form suitable
+// for use as a map key. NA values are all given the same key.
+func (df *DF) keyAt(colIdx, rowIdx int) any {
+	v, isNA := df.valAt(colIdx, rowIdx)
+	if isNA {
+		return naKey{}
+	}
+
+	k, _ := keyOf(v)
+	return k
+}
+
+// update adds any rows not yet indexed to the index. The index is not
+// changed if it is up to date so that it may be safely called by many
+// goroutines at once (see SyncDF).
+func (idx *colIndex) update(df *DF, colIdx int) {
+	if idx.rowCount == df.RowCount() {
+		return
+	}
+	for i := idx.rowCount; i < df.RowCount(); i++ {
+		k := df.keyAt(colIdx, i)
+		idx.rows[k] = append(idx.rows[k], i)
+	}
+	idx.rowCount = df.RowCount()
+}
+
+// BuildIndex creates an index on the named column mapping each value to the
+// rows having that value. This allows rows to be found by LookupRows
+// without scanning the whole dataframe. Any existing index on the column is
+// replaced. It returns an error if there is no such column or if it has no
+// type (as for a dataframe read from input with a header but no data).
+func (df *DF) BuildIndex(col string) error {
+	i, ok := df.mci.colIdx(col)
+	if !ok {
+		return errUnknownColName(col)
+	}
+	if df.mci.info[i].colType == ColTypeUnknown {
+		return errUntypedCol(df.mci.info[i].name)
+	}
+
+	if df.indexes == nil {
+		df.indexes = map[int]*colIndex{}
+	}
+
+	idx := &colIndex{rows: map[any][]int{}}
+	idx.update(df, i)
+	df.indexes[i] = idx
+
+	return nil
+}
+
+// updateIndexes adds any rows not yet indexed to all the indexes, and to
+// the column summaries
+func (df *DF) updateIndexes() {
+	for i, idx := range df.indexes {
+		idx.update(df, i)
+	}
+	df.updateSummaries()
+}
+
+// keyFor converts the value to the type of the indexed column and returns
+// it in a form suitable for use as an index key. It returns an error if the
+// value cannot be converted or the column has no type.
+func (df *DF) keyFor(colIdx int, value any) (any, error) {
+	var k any
+	var isNA bool
+	switch ct := df.mci.info[colIdx].colType; ct {
+	case ColTypeBool:
+		v, err := boolValOf(value)
+		if err != nil {
+			return nil, err
+		}
+		k, isNA = keyOf(v)
+	case ColTypeInt:
+		v, err := intValOf(value)
+		if err != nil {
+			return nil, err
+		}
+		k, isNA = keyOf(v)
+	case ColTypeFloat:
+		v, err := floatValOf(value)
+		if err != nil {
+			return nil, err
+		}
+		k, isNA = keyOf(v)
+	case ColTypeString:
+		v, err := stringValOf(value)
+		if err != nil {
+			return nil, err
+		}
+		k, isNA = keyOf(v)
+	default:
+		return nil, errUntypedCol(df.mci.info[colIdx].name)
+	}
+
+	if isNA {
+		return naKey{}, nil
+	}
+	return k, nil
+}
+
+// LookupRows returns the indexes of the rows where the named column has the
+// given value, in increasing order. The value may be given either as one of
+// the Val types (BoolVal, IntVal etc) or as the corresponding Go type
+// (bool, int64 etc). A nil value or a Val with IsNA set will find the rows
+// where the value is NA.
+//
+// An index must have been created on the column by BuildIndex. Rows that
+// have been added since the index was built are added to the index before
+// the lookup but note that any changes made to the values through the
+// slices returned by the ...View methods will not be reflected in the
+// index.
+//
+// It returns an error if there is no such column, if it has no index or if
+// the value cannot be converted to the column type.
+func (df *DF) LookupRows(col string, value any) ([]int, error) {
+	i, ok := df.mci.colIdx(col)
+	if !ok {
+		return nil, errUnknownColName(col)
+	}
+
+	idx, ok := df.indexes[i]
+	if !ok {
+		return nil, dfCodeErrorf(ErrCodeNoIndex,
+			"There is no index on column %q", col)
+	}
+
+	k, err := df.keyFor(i, value)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.update(df, i)
+
+	return cloneIntSlice(idx.rows[k]), nil
+}
+
+// SetIndex makes the named column the key of the dataframe: an index is
+// built on the column (as for BuildIndex) and the values in the column
+// must be unique so that RowByKey can find the row with a given value. An
+// NA value is treated like any other value so at most one row may have an
+// NA key. It returns an error if there is no such column or the values
+// are not unique, in which case the key is unchanged.
+func (df *DF) SetIndex(col string) error {
+	i, ok := df.mci.colIdx(col)
+	if !ok {
+		return errUnknownColName(col)
+	}
+
+	idx := &colIndex{rows: map[any][]int{}}
+	idx.update(df, i)
+	for r := 0; r < df.RowCount(); r++ {
+		if rows := idx.rows[df.keyAt(i, r)]; len(rows) > 1 {
+			v, _ := df.valAt(i, r)
+			return dfCodeErrorf(ErrCodeKeyNotUnique,
+				"the values in column %q are not unique:"+
+					" %v is in rows %d and %d", col, v, rows[0], rows[1])
+		}
+	}
+
+	if df.indexes == nil {
+		df.indexes = map[int]*colIndex{}
+	}
+	df.indexes[i] = idx
+	df.keyCol = i
+	df.hasKeyCol = true
+
+	return nil
+}
+
+// RowByKey returns the row whose value in the key column set by SetIndex
+// matches the value. The value may be given as for LookupRows. It returns
+// an error if no key has been set, if the value cannot be converted to the
+// column type or if there is no such row. Rows added since the key was
+// set are also searched and an error is returned if they have made the
+// value non-unique.
+func (df *DF) RowByKey(value any) (*Row, error) {
+	if !df.hasKeyCol {
+		return nil, dfCodeErrorf(ErrCodeNoKey,
+			"no key column has been set (see SetIndex)")
+	}
+
+	col := df.mci.info[df.keyCol].name
+	rows, err := df.LookupRows(col, value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(rows) {
+	case 0:
+		return nil, dfKindErrorf(ErrNoSuchRow,
+			"There is no row with %q = %v", col, value)
+	case 1:
+		return df.Row(rows[0]), nil
+	}
+	return nil, dfCodeErrorf(ErrCodeKeyNotUnique,
+		"the key is not unique: %q = %v is in rows %d and %d",
+		col, value, rows[0], rows[1])
+}
@@ -0,0 +1,169 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestLookupRows(t *testing.T) {
+	df := mkTestDF(t, `sym qty price
+abc 10 1.5
+xyz 20 2.5
+abc 30 NA
+def 10 1.5
+`,
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(
+			dataframe.ColTypeString,
+			dataframe.ColTypeInt,
+			dataframe.ColTypeFloat),
+		dataframe.AllowErrors)
+
+	for _, col := range []string{"sym", "qty", "price"} {
+		if err := df.BuildIndex(col); err != nil {
+			t.Fatal("unexpected error building the index: ", err)
+		}
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col     string
+		val     any
+		expRows []int
+	}{
+		{
+			ID:      testhelper.MkID("string - several rows"),
+			col:     "sym",
+			val:     "abc",
+			expRows: []int{0, 2},
+		},
+		{
+			ID:      testhelper.MkID("string - no rows"),
+			col:     "sym",
+			val:     dataframe.StringVal{Val: "nonesuch"},
+			expRows: []int{},
+		},
+		{
+			ID:      testhelper.MkID("int - given as int"),
+			col:     "qty",
+			val:     10,
+			expRows: []int{0, 3},
+		},
+		{
+			ID:      testhelper.MkID("float - given as float64"),
+			col:     "price",
+			val:     1.5,
+			expRows: []int{0, 3},
+		},
+		{
+			ID:      testhelper.MkID("float - NA"),
+			col:     "price",
+			val:     nil,
+			expRows: []int{2},
+		},
+		{
+			ID:  testhelper.MkID("bad value type"),
+			col: "qty",
+			val: "ten",
+			ExpErr: testhelper.MkExpErr(
+				"cannot convert a value of type string into an IntVal"),
+		},
+		{
+			ID:     testhelper.MkID("bad column"),
+			col:    "nonesuch",
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		rows, err := df.LookupRows(tc.col, tc.val)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffSlice(t, tc.IDStr(), "rows", rows, tc.expRows)
+		}
+	}
+
+	df.AddRowFromText([]string{"abc", "40", "4.5"})
+	rows, err := df.LookupRows("sym", "abc")
+	if err == nil {
+		testhelper.DiffSlice(t, "after AddRowFromText", "rows",
+			rows, []int{0, 2, 4})
+	} else {
+		t.Error("unexpected error after AddRowFromText: ", err)
+	}
+
+	df2 := mkTestDF(t, "1 2\n")
+	_, err = df2.LookupRows("V0", 1)
+	testhelper.CheckExpErrWithID(t, "no index", err,
+		testhelper.MkExpErr(`There is no index on column "V0"`))
+
+	headerOnly := mkTestDF(t, "a b\n", dataframe.HasHeader)
+	err = headerOnly.BuildIndex("b")
+	testhelper.CheckExpErrWithID(t, "header only", err,
+		testhelper.MkExpErr(`the column named "b" has no type`))
+}
+
+func TestRowByKey(t *testing.T) {
+	df := mkTestDF(t, "sym qty\nabc 10\nxyz 20\nabc 30\n",
+		dataframe.HasHeader)
+
+	_, err := df.RowByKey("abc")
+	testhelper.CheckExpErrWithID(t, "no key", err,
+		testhelper.MkExpErr("no key column has been set"))
+
+	err = df.SetIndex("sym")
+	testhelper.CheckExpErrWithID(t, "not unique", err,
+		testhelper.MkExpErr(`the values in column "sym" are not unique:`+
+			" abc is in rows 0 and 2"))
+	err = df.SetIndex("nonesuch")
+	testhelper.CheckExpErrWithID(t, "no such column", err,
+		testhelper.MkExpErr(`Unknown column name: "nonesuch"`))
+
+	if err := df.SetIndex("qty"); err != nil {
+		t.Fatal("unexpected error setting the index: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		key    any
+		expRow string
+	}{
+		{
+			ID:     testhelper.MkID("int key"),
+			key:    20,
+			expRow: `sym="xyz" qty=20`,
+		},
+		{
+			ID:     testhelper.MkID("IntVal key"),
+			key:    dataframe.IntVal{Val: 30},
+			expRow: `sym="abc" qty=30`,
+		},
+		{
+			ID:     testhelper.MkID("missing key"),
+			key:    40,
+			ExpErr: testhelper.MkExpErr(`There is no row with "qty" = 40`),
+		},
+		{
+			ID:  testhelper.MkID("wrong type"),
+			key: "abc",
+			ExpErr: testhelper.MkExpErr(
+				"cannot convert a value of type string into an IntVal"),
+		},
+	}
+
+	for _, tc := range testCases {
+		r, err := df.RowByKey(tc.key)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "row", r.String(), tc.expRow)
+		}
+	}
+
+	df.AddRowFromText([]string{"def", "20"})
+	_, err = df.RowByKey(20)
+	testhelper.CheckExpErrWithID(t, "duplicate added", err,
+		testhelper.MkExpErr(`the key is not unique: "qty" = 20`+
+			" is in rows 1 and 3"))
+}
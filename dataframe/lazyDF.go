@@ -0,0 +1,240 @@
+package dataframe
+
+// lazyStep is an operation in a LazyDF pipeline. It is either a
+// lazyRowStep or a groupByStep.
+type lazyStep interface {
+	// lazyStep marks the type as a LazyDF step
+	lazyStep()
+}
+
+// lazyRowStep is an operation in a LazyDF pipeline which acts on one row at
+// a time
+type lazyRowStep interface {
+	lazyStep
+	// colInfo checks that the step is valid for the input columns and
+	// returns the columns that the step will produce
+	colInfo(cis []ColInfo) ([]ColInfo, error)
+	// apply performs the step on the row, it returns the new row and
+	// whether or not the row should be kept
+	apply(r *Row) (*Row, bool, error)
+}
+
+// filterStep keeps just those rows for which the filter returns true
+type filterStep struct {
+	keep RowFilter
+}
+
+// lazyStep marks filterStep as a LazyDF step
+func (s filterStep) lazyStep() {}
+
+// colInfo returns the columns unchanged
+func (s filterStep) colInfo(cis []ColInfo) ([]ColInfo, error) {
+	return cis, nil
+}
+
+// apply returns the row and the result of the filter
+func (s filterStep) apply(r *Row) (*Row, bool, error) {
+	return r, s.keep(r), nil
+}
+
+// selectStep keeps just the named columns
+type selectStep struct {
+	names []string
+}
+
+// lazyStep marks selectStep as a LazyDF step
+func (s selectStep) lazyStep() {}
+
+// colInfo returns the selected columns
+func (s selectStep) colInfo(cis []ColInfo) ([]ColInfo, error) {
+	mci, err := NewMultiColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	cis, err = selectColInfo(*mci, s.names)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = NewMultiColInfo(cis...); err != nil {
+		return nil, err
+	}
+	return cis, nil
+}
+
+// apply returns a new row with just the selected columns
+func (s selectStep) apply(r *Row) (*Row, bool, error) {
+	r, err := r.project(s.names...)
+	return r, true, err
+}
+
+// mutateStep adds a new column calculated from the other values in the row
+type mutateStep struct {
+	name    string
+	colType ColType
+	f       RowFunc
+}
+
+// lazyStep marks mutateStep as a LazyDF step
+func (s mutateStep) lazyStep() {}
+
+// colInfo returns the columns with the new column added
+func (s mutateStep) colInfo(cis []ColInfo) ([]ColInfo, error) {
+	return mutateColInfo(cis, s.name, s.colType)
+}
+
+// apply adds the calculated value to the row
+func (s mutateStep) apply(r *Row) (*Row, bool, error) {
+	v, err := s.f(r)
+	if err != nil {
+		return nil, false, err
+	}
+	return r, true, r.addVal(s.name, s.colType, v)
+}
+
+// groupByStep groups the rows by the value of the key column and
+// calculates the aggregations for each group. Unlike the other steps it
+// needs to see every row before it can produce any results.
+type groupByStep struct {
+	key  string
+	aggs []Agg
+}
+
+// lazyStep marks groupByStep as a LazyDF step
+func (s groupByStep) lazyStep() {}
+
+// LazyDF records a sequence of operations to be performed on a
+// dataframe. No work is done until the Collect method is called at which
+// point the operations are performed together, row by row, in a single pass
+// over the data. This avoids the creation of the intermediate dataframes
+// that would be made by calling the corresponding DF methods in turn.
+//
+// A GroupBy operation needs to see all the rows before it can produce its
+// results so any subsequent operations are performed in a further pass over
+// the grouped results.
+//
+// The methods which add operations do not change the LazyDF but return a
+// new one so a partial pipeline can be shared and extended in different
+// ways.
+type LazyDF struct {
+	src   *DF
+	steps []lazyStep
+}
+
+// Lazy returns a LazyDF which will perform its operations on the dataframe
+func (df *DF) Lazy() *LazyDF {
+	return &LazyDF{src: df}
+}
+
+// addStep returns a new LazyDF with the step added
+func (ldf *LazyDF) addStep(step lazyStep) *LazyDF {
+	steps := make([]lazyStep, 0, len(ldf.steps)+1)
+	steps = append(steps, ldf.steps...)
+	return &LazyDF{
+		src:   ldf.src,
+		steps: append(steps, step),
+	}
+}
+
+// Filter adds a step which keeps just those rows for which the keep
+// function returns true. See DF.Filter
+func (ldf *LazyDF) Filter(keep RowFilter) *LazyDF {
+	return ldf.addStep(filterStep{keep: keep})
+}
+
+// Select adds a step which keeps just the named columns in the order
+// given. See DF.Select
+func (ldf *LazyDF) Select(names ...string) *LazyDF {
+	return ldf.addStep(selectStep{names: names})
+}
+
+// Mutate adds a step which adds a new column calculated from the other
+// values in the row. See DF.Mutate
+func (ldf *LazyDF) Mutate(name string, colType ColType, f RowFunc) *LazyDF {
+	return ldf.addStep(mutateStep{name: name, colType: colType, f: f})
+}
+
+// GroupBy adds a step which groups the rows by the value of the key column
+// and calculates the aggregations for each group. See DF.GroupBy and
+// GroupedDF.Agg
+func (ldf *LazyDF) GroupBy(key string, aggs ...Agg) *LazyDF {
+	return ldf.addStep(groupByStep{key: key, aggs: aggs})
+}
+
+// Collect performs the recorded operations and returns the resulting
+// dataframe. The source dataframe is not changed.
+func (ldf *LazyDF) Collect() (*DF, error) {
+	df := ldf.src
+	stage := []lazyRowStep{}
+
+	for _, step := range ldf.steps {
+		switch s := step.(type) {
+		case lazyRowStep:
+			stage = append(stage, s)
+		case groupByStep:
+			var err error
+			df, err = runLazyStage(df, stage, &s)
+			if err != nil {
+				return nil, err
+			}
+			stage = []lazyRowStep{}
+		default:
+			panic(dfErrorf("Unexpected LazyDF step type: %T", step))
+		}
+	}
+
+	return runLazyStage(df, stage, nil)
+}
+
+// runLazyStage performs the row steps on each row of the source dataframe
+// in turn. If there is a groupBy step the resulting rows are grouped and
+// aggregated otherwise they are added to the resulting dataframe.
+func runLazyStage(src *DF, steps []lazyRowStep, gbs *groupByStep) (*DF, error) {
+	cis := src.mci.info
+	for _, s := range steps {
+		var err error
+		if cis, err = s.colInfo(cis); err != nil {
+			return nil, err
+		}
+	}
+
+	var g *grouper
+	var rval *DF
+	var err error
+	if gbs != nil {
+		g, err = newGrouper(cis, []string{gbs.key}, false, gbs.aggs)
+	} else {
+		rval, err = newDFFromColInfo(cis...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+Loop:
+	for i := 0; i < src.RowCount(); i++ {
+		r := src.Row(i)
+		for _, s := range steps {
+			var keep bool
+			r, keep, err = s.apply(r)
+			if err != nil {
+				return nil, dfWrapf(err, "data row: %d", i)
+			}
+			if !keep {
+				continue Loop
+			}
+		}
+
+		if g != nil {
+			err = g.addRow(r)
+		} else {
+			err = rval.appendRowVals(r)
+		}
+		if err != nil {
+			return nil, dfWrapf(err, "data row: %d", i)
+		}
+	}
+
+	if g != nil {
+		return g.result()
+	}
+	return rval, nil
+}
@@ -0,0 +1,227 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+const lazyTestData = `sym qty price
+abc 10 1.5
+xyz 20 2.5
+abc 30 3.5
+def NA 4.5
+xyz 40 5.5
+`
+
+// bigQty is a RowFilter keeping rows with a quantity of more than 15
+func bigQty(r *dataframe.Row) bool {
+	v, _, _ := r.ValByName("qty")
+	iv := v.(dataframe.IntVal)
+	return !iv.IsNA && iv.Val > 15
+}
+
+// cost is a RowFunc calculating the quantity times the price
+func cost(r *dataframe.Row) (any, error) {
+	q, _, _ := r.ValByName("qty")
+	p, _, _ := r.ValByName("price")
+	qv, pv := q.(dataframe.IntVal), p.(dataframe.FloatVal)
+	if qv.IsNA || pv.IsNA {
+		return nil, nil
+	}
+	return float64(qv.Val) * pv.Val, nil
+}
+
+func TestLazyDF(t *testing.T) {
+	df := mkTestDF(t, lazyTestData,
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(
+			dataframe.ColTypeString,
+			dataframe.ColTypeInt,
+			dataframe.ColTypeFloat),
+		dataframe.AllowErrors)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		ldf     *dataframe.LazyDF
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:  testhelper.MkID("no steps"),
+			ldf: df.Lazy(),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("sym", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"abc", "10", "1.5"},
+				{"xyz", "20", "2.5"},
+				{"abc", "30", "3.5"},
+				{"def", "NA", "4.5"},
+				{"xyz", "40", "5.5"},
+			},
+		},
+		{
+			ID: testhelper.MkID("filter, mutate, select"),
+			ldf: df.Lazy().
+				Filter(bigQty).
+				Mutate("cost", dataframe.ColTypeFloat, cost).
+				Select("cost", "sym"),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("cost", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("sym", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"50", "xyz"},
+				{"105", "abc"},
+				{"220", "xyz"},
+			},
+		},
+		{
+			ID: testhelper.MkID("mutate, group by, filter"),
+			ldf: df.Lazy().
+				Mutate("cost", dataframe.ColTypeFloat, cost).
+				GroupBy("sym",
+					dataframe.Agg{Func: dataframe.AggCount},
+					dataframe.Agg{Col: "qty", Func: dataframe.AggSum},
+					dataframe.Agg{
+						Col:  "cost",
+						Func: dataframe.AggMax,
+						Name: "maxCost",
+					}).
+				Filter(func(r *dataframe.Row) bool {
+					v, _, _ := r.ValByName("Count")
+					return v.(dataframe.IntVal).Val > 1
+				}),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("sym", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("Count", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("qty_Sum", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("maxCost", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"abc", "2", "40", "105"},
+				{"xyz", "2", "60", "220"},
+			},
+		},
+		{
+			ID:     testhelper.MkID("bad select"),
+			ldf:    df.Lazy().Select("nonesuch"),
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+		},
+		{
+			ID: testhelper.MkID("bad mutate - name in use"),
+			ldf: df.Lazy().
+				Mutate("qty", dataframe.ColTypeFloat, cost),
+			ExpErr: testhelper.MkExpErr(`Column name already used`),
+		},
+		{
+			ID: testhelper.MkID("bad mutate - wrong value type"),
+			ldf: df.Lazy().
+				Mutate("cost", dataframe.ColTypeInt, cost),
+			ExpErr: testhelper.MkExpErr(
+				"data row: 0: cannot convert a value of type float64" +
+					" into an IntVal"),
+		},
+		{
+			ID: testhelper.MkID("bad group by - sum of strings"),
+			ldf: df.Lazy().
+				GroupBy("qty", dataframe.Agg{
+					Col:  "sym",
+					Func: dataframe.AggSum,
+				}),
+			ExpErr: testhelper.MkExpErr(
+				`cannot calculate the Sum of column "sym"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		res, err := tc.ldf.Collect()
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), res, tc.expCols)
+			checkDFVals(t, tc.IDStr(), res, tc.expVals)
+		}
+	}
+
+	if df.RowCount() != 5 || df.ColCount() != 3 {
+		t.Errorf("the source dataframe has been changed: %s", df)
+	}
+}
+
+func TestEagerOps(t *testing.T) {
+	df := mkTestDF(t, lazyTestData,
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(
+			dataframe.ColTypeString,
+			dataframe.ColTypeInt,
+			dataframe.ColTypeFloat),
+		dataframe.AllowErrors)
+
+	id := "Filter"
+	fdf := df.Filter(bigQty)
+	checkDFVals(t, id, fdf, [][]string{
+		{"xyz", "20", "2.5"},
+		{"abc", "30", "3.5"},
+		{"xyz", "40", "5.5"},
+	})
+
+	id = "Select"
+	sdf, err := fdf.Select("price", "sym")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	checkDFVals(t, id, sdf, [][]string{
+		{"2.5", "xyz"},
+		{"3.5", "abc"},
+		{"5.5", "xyz"},
+	})
+
+	id = "Select - header only"
+	hdf, err := mkTestDF(t, "a b\n", dataframe.HasHeader).Select("b")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	testhelper.DiffString(t, id, "columns",
+		fmt.Sprint(hdf.Columns()), "[b(Unknown)]")
+	testhelper.DiffInt(t, id, "rows", hdf.RowCount(), 0)
+
+	id = "Mutate"
+	mdf, err := df.Mutate("cost", dataframe.ColTypeFloat, cost)
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	checkDFVals(t, id, mdf, [][]string{
+		{"abc", "10", "1.5", "15"},
+		{"xyz", "20", "2.5", "50"},
+		{"abc", "30", "3.5", "105"},
+		{"def", "NA", "4.5", "NA"},
+		{"xyz", "40", "5.5", "220"},
+	})
+
+	id = "GroupBy"
+	gdf, err := df.GroupBy("sym")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	adf, err := gdf.Agg(
+		dataframe.Agg{Col: "qty", Func: dataframe.AggCount},
+		dataframe.Agg{Col: "qty", Func: dataframe.AggMean},
+		dataframe.Agg{Col: "price", Func: dataframe.AggMin})
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	checkDFVals(t, id, adf, [][]string{
+		{"abc", "2", "20", "1.5"},
+		{"xyz", "2", "30", "2.5"},
+		{"def", "0", "NA", "4.5"},
+	})
+
+	_, err = df.GroupBy("nonesuch")
+	testhelper.CheckExpErrWithID(t, "GroupBy - bad key", err,
+		testhelper.MkExpErr(`Unknown column name: "nonesuch"`))
+}
@@ -24,10 +24,10 @@ var makeDataFrameTests = []struct {
 9 2 3 4`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V3", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeInt),
 		},
 	},
 	{
@@ -37,10 +37,10 @@ var makeDataFrameTests = []struct {
 9 2 3 4`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V3", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeInt),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.SkipBlankLines,
@@ -53,10 +53,10 @@ var makeDataFrameTests = []struct {
 9 2 3 4`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("firstCol", dataframe.ColTypeInt),
-			dataframe.NewColInfo("2ndCol", dataframe.ColTypeInt),
-			dataframe.NewColInfo("3rd", dataframe.ColTypeInt),
-			dataframe.NewColInfo("lastCol", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("firstCol", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("2ndCol", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("3rd", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("lastCol", dataframe.ColTypeInt),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.HasHeader,
@@ -68,10 +68,10 @@ var makeDataFrameTests = []struct {
 9 2 3 4`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("col1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("col2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("col3", dataframe.ColTypeInt),
-			dataframe.NewColInfo("col4", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("col1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("col2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("col3", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("col4", dataframe.ColTypeInt),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.DFRColNames(
@@ -87,10 +87,10 @@ var makeDataFrameTests = []struct {
 world 2 3 4.0`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeString),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V3", dataframe.ColTypeFloat),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeString),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeFloat),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.DFRColTypes(
@@ -106,10 +106,10 @@ world 2 3 4.0`,
 9 2 3 4`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V3", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeInt),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.CommentPattern(`\s*#.*$`),
@@ -121,10 +121,10 @@ world 2 3 4.0`,
 9,2,3,4`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V3", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeInt),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.SplitPattern(`,`),
@@ -136,10 +136,10 @@ world 2 3 4.0`,
 9 2 3 4`,
 		expRowCount: 1,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V3", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeInt),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.SkipLines(1),
@@ -151,10 +151,10 @@ world 2 3 4.0`,
 9 2 3 4`,
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V3", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeInt),
 		},
 		optArgs: []dataframe.DFReaderOpt{
 			dataframe.InitialLines(2),
@@ -170,10 +170,10 @@ f 2 3 4
 1 2 3 4`,
 		expRowCount: 6,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeBool),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeFloat),
-			dataframe.NewColInfo("V3", dataframe.ColTypeString),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeBool),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeFloat),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeString),
 		},
 	},
 	{
@@ -376,9 +376,9 @@ bad 2 3 4`,
 		},
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeBool),
-			dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeBool),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
 		},
 	},
 	{
@@ -390,9 +390,9 @@ bad 2 3 4`,
 		},
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeBool),
-			dataframe.NewColInfo("V2", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeBool),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
 		},
 	},
 	{
@@ -404,10 +404,10 @@ bad 2 3 4`,
 		},
 		expRowCount: 2,
 		expCols: []dataframe.ColInfo{
-			dataframe.NewColInfo("V0", dataframe.ColTypeInt),
-			dataframe.NewColInfo("V1", dataframe.ColTypeBool),
-			dataframe.NewColInfo("V2", dataframe.ColTypeString),
-			dataframe.NewColInfo("V3", dataframe.ColTypeFloat),
+			dataframe.MustNewColInfo("V0", dataframe.ColTypeInt),
+			dataframe.MustNewColInfo("V1", dataframe.ColTypeBool),
+			dataframe.MustNewColInfo("V2", dataframe.ColTypeString),
+			dataframe.MustNewColInfo("V3", dataframe.ColTypeFloat),
 		},
 	},
 }
@@ -0,0 +1,460 @@
+package dataframe
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"unsafe"
+)
+
+// mapFileMagic is written at the start of a map file to identify it
+const mapFileMagic = "DFMAP\x00\x01\x00"
+
+// mapFileAlign is the alignment of the column data in a map file
+const mapFileAlign = 8
+
+// mapFileHdr is the header of a map file, it is written as JSON after the
+// magic string and the header length
+type mapFileHdr struct {
+	ByteOrder string            `json:"byteOrder"`
+	ValSizes  []uintptr         `json:"valSizes"`
+	Rows      int               `json:"rows"`
+	Meta      map[string]string `json:"meta,omitempty"`
+	Cols      []mapFileCol      `json:"cols"`
+}
+
+// mapFileCol describes a column in a map file. The values are at offset
+// Off; for a string column Off is the offset of the n+1 string offsets,
+// NAOff the offset of the n NA flags and DataOff the offset of the string
+// data.
+type mapFileCol struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	Meta    map[string]string `json:"meta,omitempty"`
+	Off     int64             `json:"off"`
+	NAOff   int64             `json:"naOff,omitempty"`
+	DataOff int64             `json:"dataOff,omitempty"`
+}
+
+// nativeByteOrder returns the name of the byte order of this machine
+func nativeByteOrder() string {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return "little"
+	}
+	return "big"
+}
+
+// mapValSizes returns the sizes of the Val types, they must be the same
+// when the map file is opened as when it was written
+func mapValSizes() []uintptr {
+	return []uintptr{
+		unsafe.Sizeof(BoolVal{}),
+		unsafe.Sizeof(IntVal{}),
+		unsafe.Sizeof(FloatVal{}),
+	}
+}
+
+// mapAlign returns the offset rounded up to the map file alignment
+func mapAlign(off int64) int64 {
+	return (off + mapFileAlign - 1) / mapFileAlign * mapFileAlign
+}
+
+// sliceBytes returns the memory holding the slice as a slice of bytes
+func sliceBytes[T any](s []T) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	var v T
+	return unsafe.Slice((*byte)(unsafe.Pointer(&s[0])),
+		len(s)*int(unsafe.Sizeof(v)))
+}
+
+// mapFileLayout returns the header for the dataframe, with the offset of
+// each column, and its JSON encoding
+func (df *DF) mapFileLayout(hdrStart int64) (mapFileHdr, []byte, error) {
+	n := df.RowCount()
+	sizes := mapValSizes()
+	hdr := mapFileHdr{
+		ByteOrder: nativeByteOrder(),
+		ValSizes:  sizes,
+		Rows:      n,
+		Meta:      df.meta,
+	}
+
+	var colLens []int64
+	for c, ci := range df.mci.info {
+		col := mapFileCol{
+			Name: ci.name,
+			Type: ci.colType.String(),
+			Meta: ci.colMetaMap(),
+		}
+		switch ci.colType {
+		case ColTypeBool:
+			colLens = append(colLens, int64(n)*int64(sizes[0]))
+		case ColTypeInt:
+			colLens = append(colLens, int64(n)*int64(sizes[1]))
+		case ColTypeFloat:
+			colLens = append(colLens, int64(n)*int64(sizes[2]))
+		case ColTypeString:
+			dataLen := int64(0)
+			vi := df.mci.valIdx[c]
+			for r := 0; r < n; r++ {
+				dataLen += int64(len(df.stringAt(vi, r).Val))
+			}
+			colLens = append(colLens, int64(n+1)*8, int64(n), dataLen)
+		}
+		hdr.Cols = append(hdr.Cols, col)
+	}
+
+	// the offsets depend on the header length which depends on the
+	// offsets so the header is encoded until its length is stable
+	hdrLen := 0
+	for {
+		off := mapAlign(hdrStart + int64(hdrLen))
+		li := 0
+		for i := range hdr.Cols {
+			col := &hdr.Cols[i]
+			col.Off = off
+			off = mapAlign(off + colLens[li])
+			li++
+			if col.Type == ColTypeString.String() {
+				col.NAOff = off
+				off = mapAlign(off + colLens[li])
+				col.DataOff = off
+				off = mapAlign(off + colLens[li+1])
+				li += 2
+			}
+		}
+
+		hdrJSON, err := json.Marshal(hdr)
+		if err != nil {
+			return hdr, nil, err
+		}
+		if len(hdrJSON) == hdrLen {
+			return hdr, hdrJSON, nil
+		}
+		hdrLen = len(hdrJSON)
+	}
+}
+
+// mapFileWriter writes the sections of a map file, padding them to the
+// map file alignment
+type mapFileWriter struct {
+	bw  *bufio.Writer
+	off int64
+	err error
+}
+
+// write writes the bytes at the current offset
+func (mw *mapFileWriter) write(b []byte) {
+	if mw.err != nil {
+		return
+	}
+	var n int
+	n, mw.err = mw.bw.Write(b)
+	mw.off += int64(n)
+}
+
+// padTo writes zero bytes up to the offset
+func (mw *mapFileWriter) padTo(off int64) {
+	if off > mw.off {
+		mw.write(make([]byte, off-mw.off))
+	}
+}
+
+// WriteMapFile writes the dataframe to the named file in a form which can
+// be opened by OpenMapFile without reading the values into memory. The
+// values are written in the in-memory layout of this machine so the file
+// can only be opened on a machine of the same architecture. Compressed
+// columns are written uncompressed.
+func (df *DF) WriteMapFile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = df.writeMapFile(file)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return dfWrapf(err, "cannot write the map file: %s", filename)
+	}
+	return nil
+}
+
+// writeMapFile writes the map file to the io.Writer
+func (df *DF) writeMapFile(w io.Writer) error {
+	hdrStart := int64(len(mapFileMagic) + 8)
+	hdr, hdrJSON, err := df.mapFileLayout(hdrStart)
+	if err != nil {
+		return err
+	}
+
+	mw := &mapFileWriter{bw: bufio.NewWriter(w)}
+	mw.write([]byte(mapFileMagic))
+	var lenBuf [8]byte
+	binary.LittleEndian.PutUint64(lenBuf[:], uint64(len(hdrJSON)))
+	mw.write(lenBuf[:])
+	mw.write(hdrJSON)
+
+	n := df.RowCount()
+	for c, col := range hdr.Cols {
+		mw.padTo(col.Off)
+		vi := df.mci.valIdx[c]
+		switch df.mci.info[c].colType {
+		case ColTypeBool:
+			for r := 0; r < n; r++ {
+				bv := df.boolAt(vi, r)
+				mw.write(unsafe.Slice((*byte)(unsafe.Pointer(&bv)),
+					unsafe.Sizeof(bv)))
+			}
+		case ColTypeInt:
+			mw.write(sliceBytes(df.intCols[vi]))
+		case ColTypeFloat:
+			mw.write(sliceBytes(df.floatCols[vi]))
+		case ColTypeString:
+			offs := make([]uint64, 0, n+1)
+			nas := make([]byte, 0, n)
+			end := uint64(0)
+			for r := 0; r < n; r++ {
+				sv := df.stringAt(vi, r)
+				offs = append(offs, end)
+				end += uint64(len(sv.Val))
+				na := byte(0)
+				if sv.IsNA {
+					na = 1
+				}
+				nas = append(nas, na)
+			}
+			offs = append(offs, end)
+			mw.write(sliceBytes(offs))
+			mw.padTo(col.NAOff)
+			mw.write(nas)
+			mw.padTo(col.DataOff)
+			for r := 0; r < n; r++ {
+				mw.write([]byte(df.stringAt(vi, r).Val))
+			}
+		}
+	}
+	if mw.err != nil {
+		return mw.err
+	}
+	return mw.bw.Flush()
+}
+
+// MappedDF is a dataframe whose values are held in a memory-mapped file
+// rather than being read into memory, see OpenMapFile
+type MappedDF struct {
+	df     *DF
+	data   []byte
+	unmap  func([]byte) error
+	closed bool
+}
+
+// DF returns the dataframe. Its values are in the mapped file and so it
+// must not be used after the MappedDF has been closed. The same is true of
+// any dataframe made from it, by Sort for instance, as the string values
+// are not copied.
+func (m *MappedDF) DF() *DF {
+	return m.df
+}
+
+// Close unmaps the file
+func (m *MappedDF) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	m.df = nil
+	return m.unmap(m.data)
+}
+
+// mappedSlice returns the n values of type T at the offset in the data
+func mappedSlice[T any](data []byte, off int64, n int) ([]T, error) {
+	var v T
+	if off < 0 || off%mapFileAlign != 0 || off > int64(len(data)) {
+		return nil, dfErrorf("bad column offset: %d", off)
+	}
+	if n < 0 || int64(n) > (int64(len(data))-off)/int64(unsafe.Sizeof(v)) {
+		return nil, dfErrorf("bad number of values: %d", n)
+	}
+	if n == 0 {
+		return []T{}, nil
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&data[off])), n)[:n:n], nil
+}
+
+// mappedStrings returns the string values for the column, the string
+// headers are in memory but the string data stays in the mapped file
+func mappedStrings(data []byte, col mapFileCol, n int) ([]StringVal, error) {
+	if n < 0 || n >= len(data) {
+		return nil, dfErrorf("bad number of values: %d", n)
+	}
+	offs, err := mappedSlice[uint64](data, col.Off, n+1)
+	if err != nil {
+		return nil, err
+	}
+	nas, err := mappedSlice[byte](data, col.NAOff, n)
+	if err != nil {
+		return nil, err
+	}
+	if col.DataOff < 0 || col.DataOff > int64(len(data)) ||
+		offs[n] > uint64(int64(len(data))-col.DataOff) {
+		return nil, dfErrorf("bad string data offset: %d", col.DataOff)
+	}
+	strData := data[col.DataOff : col.DataOff+int64(offs[n])]
+
+	vals := make([]StringVal, n)
+	for r := range vals {
+		if offs[r] > offs[r+1] || offs[r+1] > uint64(len(strData)) {
+			return nil, dfErrorf("bad string offset for row %d", r)
+		}
+		b := strData[offs[r]:offs[r+1]]
+		vals[r] = StringVal{
+			Val:  *(*string)(unsafe.Pointer(&b)),
+			IsNA: nas[r] != 0,
+		}
+	}
+	return vals, nil
+}
+
+// mappedDF makes the dataframe from the mapped data
+func mappedDF(data []byte) (*DF, error) {
+	hdrStart := len(mapFileMagic) + 8
+	if len(data) < hdrStart ||
+		string(data[:len(mapFileMagic)]) != mapFileMagic {
+		return nil, dfErrorf("the file is not a map file")
+	}
+	hdrLen := binary.LittleEndian.Uint64(data[len(mapFileMagic):hdrStart])
+	if hdrLen > uint64(len(data)-hdrStart) {
+		return nil, dfErrorf("the header is truncated")
+	}
+
+	var hdr mapFileHdr
+	err := json.Unmarshal(data[hdrStart:hdrStart+int(hdrLen)], &hdr)
+	if err != nil {
+		return nil, dfWrapf(err, "bad header")
+	}
+	if hdr.ByteOrder != nativeByteOrder() ||
+		len(hdr.ValSizes) != len(mapValSizes()) {
+		return nil,
+			dfErrorf("the file was written on a different architecture")
+	}
+	for i, size := range mapValSizes() {
+		if hdr.ValSizes[i] != size {
+			return nil,
+				dfErrorf("the file was written on a different architecture")
+		}
+	}
+
+	if hdr.Rows < 0 {
+		return nil, dfErrorf("bad number of rows: %d", hdr.Rows)
+	}
+
+	cis := make([]ColInfo, 0, len(hdr.Cols))
+	for _, col := range hdr.Cols {
+		ct, err := colTypeByName(col.Type)
+		if err != nil {
+			return nil, dfWrapf(err, "column %q", col.Name)
+		}
+		ci := ColInfo{name: col.Name, colType: ct}
+		for k, v := range col.Meta {
+			ci = ci.WithMeta(k, v)
+		}
+		cis = append(cis, ci)
+	}
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range hdr.Meta {
+		if err := df.SetMeta(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	for c, col := range hdr.Cols {
+		vi := df.mci.valIdx[c]
+		switch df.mci.info[c].colType {
+		case ColTypeBool:
+			df.boolCols[vi], err = mappedSlice[BoolVal](data, col.Off, hdr.Rows)
+		case ColTypeInt:
+			df.intCols[vi], err = mappedSlice[IntVal](data, col.Off, hdr.Rows)
+		case ColTypeFloat:
+			df.floatCols[vi], err =
+				mappedSlice[FloatVal](data, col.Off, hdr.Rows)
+		case ColTypeString:
+			df.stringCols[vi], err = mappedStrings(data, col, hdr.Rows)
+		}
+		if err != nil {
+			return nil, dfWrapf(err, "column %q", col.Name)
+		}
+	}
+	return df, nil
+}
+
+// OpenMapFile opens the map file, written by WriteMapFile, and returns a
+// dataframe whose values are held in the file rather than being read into
+// memory so that a dataframe bigger than the available memory can be
+// opened and scanned; the operating system reads the pages of the file as
+// they are used. Only the string headers, not the string data, are held in
+// memory. The file is mapped copy-on-write so changes to the values are
+// not written to the file. The MappedDF should be closed when the
+// dataframe is no longer needed.
+//
+// On systems which don't support memory-mapped files the whole file is
+// read into memory.
+func OpenMapFile(filename string) (*MappedDF, error) {
+	data, unmap, err := mapFile(filename)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot open the map file: %s", filename)
+	}
+
+	df, err := mappedDF(data)
+	if err != nil {
+		_ = unmap(data)
+		return nil, dfWrapf(err, "cannot open the map file: %s", filename)
+	}
+	return &MappedDF{df: df, data: data, unmap: unmap}, nil
+}
+
+// MapToTempFile writes the dataframe to a temporary map file in the
+// directory (or the default directory for temporary files if dir is
+// empty) and opens it as for OpenMapFile so that its values are held in
+// the file rather than in memory. The file is removed once it has been
+// opened on systems which allow it, otherwise when the MappedDF is closed.
+func (df *DF) MapToTempFile(dir string) (*MappedDF, error) {
+	file, err := os.CreateTemp(dir, "dataframe-*.dfmap")
+	if err != nil {
+		return nil, err
+	}
+	filename := file.Name()
+	err = df.writeMapFile(file)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(filename)
+		return nil, dfWrapf(err, "cannot write the map file: %s", filename)
+	}
+
+	m, err := OpenMapFile(filename)
+	if err != nil {
+		os.Remove(filename)
+		return nil, err
+	}
+	if os.Remove(filename) != nil {
+		unmap := m.unmap
+		m.unmap = func(data []byte) error {
+			err := unmap(data)
+			if rerr := os.Remove(filename); err == nil {
+				err = rerr
+			}
+			return err
+		}
+	}
+	return m, nil
+}
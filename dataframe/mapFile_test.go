@@ -0,0 +1,197 @@
+package dataframe_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestMapFile(t *testing.T) {
+	bTrue, i, f := true, int64(-42), 1.0/3
+	strs := []string{"", "NA", "a b", "ünïcödé", "line1\nline2"}
+
+	recs := []roundTripRec{
+		{B: &bTrue, I: &i, F: &f, S: &strs[0], S2: strs[1]},
+		{S: &strs[1], S2: strs[0]},
+	}
+	for _, s := range strs[2:] {
+		s := s
+		recs = append(recs, roundTripRec{B: &bTrue, S: &s, S2: s})
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		df func() (*dataframe.DF, error)
+	}{
+		{
+			ID: testhelper.MkID("all types, NA values"),
+			df: func() (*dataframe.DF, error) {
+				return dataframe.FromStructs(recs)
+			},
+		},
+		{
+			ID: testhelper.MkID("compressed columns, metadata"),
+			df: func() (*dataframe.DF, error) {
+				df, err := dataframe.FromStructs(recs)
+				if err != nil {
+					return nil, err
+				}
+				if err := df.Compress("a bool", "str"); err != nil {
+					return nil, err
+				}
+				err = df.SetColMeta("int", dataframe.MetaUnit, "kg")
+				if err != nil {
+					return nil, err
+				}
+				return df, df.SetMeta("source", "test")
+			},
+		},
+		{
+			ID: testhelper.MkID("no rows"),
+			df: func() (*dataframe.DF, error) {
+				return dataframe.FromStructs([]roundTripRec{})
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tc := range testCases {
+		df, err := tc.df()
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+		}
+
+		filename := filepath.Join(dir, "df.dfmap")
+		if err := df.WriteMapFile(filename); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot write the map file: %s", err)
+			continue
+		}
+		m, err := dataframe.OpenMapFile(filename)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot open the map file: %s", err)
+			continue
+		}
+
+		if err := df.Equal(m.DF()); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the mapped dataframe differs: %s", err)
+		}
+		testhelper.DiffStringSlice(t, tc.IDStr(), "meta keys",
+			m.DF().MetaKeys(), df.MetaKeys())
+		unit, _, _ := m.DF().ColMeta("int", dataframe.MetaUnit)
+		expUnit, _, _ := df.ColMeta("int", dataframe.MetaUnit)
+		testhelper.DiffString(t, tc.IDStr(), "column meta", unit, expUnit)
+
+		if err := m.Close(); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot close the map file: %s", err)
+		}
+	}
+}
+
+func TestMapToTempFile(t *testing.T) {
+	df := mkTestDF(t, "i f s\n1 1.5 a\n2 NA b\n", dataframe.HasHeader)
+	dir := t.TempDir()
+
+	m, err := df.MapToTempFile(dir)
+	if err != nil {
+		t.Fatal("cannot map the dataframe: ", err)
+	}
+	mdf := m.DF()
+	mdf.AddRowFromText([]string{"3", "3.5", "c"})
+	checkDFVals(t, "mapped", mdf, [][]string{
+		{"1", "1.5", "a"},
+		{"2", "NA", "b"},
+		{"3", "3.5", "c"},
+	})
+	sorted, err := mdf.Sort(dataframe.SortKey{Col: "i", Desc: true})
+	if err != nil {
+		t.Fatal("cannot sort the mapped dataframe: ", err)
+	}
+	checkDFVals(t, "sorted", sorted, [][]string{
+		{"3", "3.5", "c"},
+		{"2", "NA", "b"},
+		{"1", "1.5", "a"},
+	})
+	if err := m.Close(); err != nil {
+		t.Fatal("cannot close the mapped dataframe: ", err)
+	}
+
+	checkDFVals(t, "original", df, [][]string{
+		{"1", "1.5", "a"},
+		{"2", "NA", "b"},
+	})
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal("cannot read the temporary directory: ", err)
+	}
+	testhelper.DiffInt(t, "temporary directory", "files", len(files), 0)
+}
+
+func TestOpenMapFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	notMap := filepath.Join(dir, "notMap")
+	if err := os.WriteFile(notMap, []byte("a b\n1 2\n"), 0o600); err != nil {
+		t.Fatal("cannot write the test file: ", err)
+	}
+	_, err := dataframe.OpenMapFile(notMap)
+	testhelper.CheckExpErrWithID(t, "not a map file", err,
+		testhelper.MkExpErr("the file is not a map file"))
+
+	_, err = dataframe.OpenMapFile(filepath.Join(dir, "missing"))
+	testhelper.CheckExpErrWithID(t, "missing file", err,
+		testhelper.MkExpErr("cannot open the map file"))
+
+	// the header is rewritten in place, keeping its length the same by
+	// changing the length of the long column name
+	const longName = "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz"
+	good := filepath.Join(dir, "good.dfmap")
+	df := mkTestDF(t, longName+" s\n1 a\n2 b\n3 c\n", dataframe.HasHeader)
+	if err := df.WriteMapFile(good); err != nil {
+		t.Fatal("cannot write the test map file: ", err)
+	}
+	content, err := os.ReadFile(good)
+	if err != nil {
+		t.Fatal("cannot read the test map file: ", err)
+	}
+
+	for _, tc := range []struct {
+		rows   string
+		expErr testhelper.ExpErr
+	}{
+		{rows: "-1", expErr: testhelper.MkExpErr("bad number of rows: -1")},
+		{rows: "-2", expErr: testhelper.MkExpErr("bad number of rows: -2")},
+		{
+			rows: "9223372036854775807",
+			expErr: testhelper.MkExpErr(
+				"bad number of values: 9223372036854775807"),
+		},
+		{
+			rows:   "4",
+			expErr: testhelper.MkExpErr(`column "s": bad string offset`),
+		},
+	} {
+		name := longName[:len(longName)-len(tc.rows)+1]
+		bad := bytes.Replace(content, []byte(`"rows":3`),
+			[]byte(`"rows":`+tc.rows), 1)
+		bad = bytes.Replace(bad, []byte(`"`+longName+`"`),
+			[]byte(`"`+name+`"`), 1)
+		if len(bad) != len(content) {
+			t.Fatal("BAD TEST - the header has changed length")
+		}
+		corrupt := filepath.Join(dir, "corrupt.dfmap")
+		if err := os.WriteFile(corrupt, bad, 0o600); err != nil {
+			t.Fatal("cannot write the test file: ", err)
+		}
+		_, err := dataframe.OpenMapFile(corrupt)
+		testhelper.CheckExpErrWithID(t, "corrupt header: rows: "+tc.rows,
+			err, tc.expErr)
+	}
+}
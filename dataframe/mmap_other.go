@@ -0,0 +1,38 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package dataframe
+
+import (
+	"io"
+	"os"
+	"unsafe"
+)
+
+// mapFile reads the whole of the named file into memory, as memory-mapped
+// files are not supported, and returns the data and a function to release
+// it. The data is held in a slice of uint64 values so that it is aligned
+// as it would be if it were mapped.
+func mapFile(filename string) ([]byte, func([]byte) error, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := info.Size()
+	if size == 0 || int64(int(size)) != size {
+		return nil, nil, dfErrorf("the file cannot be read, its size is %d",
+			size)
+	}
+
+	words := make([]uint64, (size+7)/8)
+	data := unsafe.Slice((*byte)(unsafe.Pointer(&words[0])), int(size))
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, nil, err
+	}
+	return data, func([]byte) error { return nil }, nil
+}
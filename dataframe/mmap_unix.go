@@ -0,0 +1,36 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package dataframe
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the named file into memory copy-on-write, so that the
+// mapped data can be changed without changing the file, and returns the
+// data and the function to unmap it
+func mapFile(filename string) ([]byte, func([]byte) error, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := info.Size()
+	if size == 0 || int64(int(size)) != size {
+		return nil, nil, dfErrorf("the file cannot be mapped, its size is %d",
+			size)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size),
+		syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, syscall.Munmap, nil
+}
@@ -0,0 +1,487 @@
+package dataframe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// The keys used in the MessagePack encoding of a dataframe
+const (
+	mpKeyMeta    = "meta"
+	mpKeyColumns = "columns"
+	mpKeyName    = "name"
+	mpKeyType    = "type"
+	mpKeyData    = "data"
+)
+
+// appendMPNil appends the MessagePack encoding of nil
+func appendMPNil(b []byte) []byte { return append(b, 0xc0) }
+
+// appendMPBool appends the MessagePack encoding of the bool
+func appendMPBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+	return append(b, 0xc2)
+}
+
+// appendMPInt appends the MessagePack encoding of the int using the
+// smallest encoding that can hold it
+func appendMPInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0 && v <= 0x7f:
+		return append(b, byte(v))
+	case v < 0 && v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return append(b, 0xd1, byte(v>>8), byte(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return appendBE(b, 0xd2, uint64(v), 4)
+	}
+	return appendBE(b, 0xd3, uint64(v), 8)
+}
+
+// appendMPFloat appends the MessagePack encoding of the float
+func appendMPFloat(b []byte, v float64) []byte {
+	return appendBE(b, 0xcb, math.Float64bits(v), 8)
+}
+
+// appendMPStr appends the MessagePack encoding of the string
+func appendMPStr(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = appendBE(b, 0xda, uint64(n), 2)
+	default:
+		b = appendBE(b, 0xdb, uint64(n), 4)
+	}
+	return append(b, s...)
+}
+
+// appendMPArrayLen appends the MessagePack header for an array of n
+// elements
+func appendMPArrayLen(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return appendBE(b, 0xdc, uint64(n), 2)
+	}
+	return appendBE(b, 0xdd, uint64(n), 4)
+}
+
+// appendMPMapLen appends the MessagePack header for a map of n entries
+func appendMPMapLen(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return appendBE(b, 0xde, uint64(n), 2)
+	}
+	return appendBE(b, 0xdf, uint64(n), 4)
+}
+
+// appendBE appends the tag byte followed by the low size bytes of v in
+// big-endian order
+func appendBE(b []byte, tag byte, v uint64, size int) []byte {
+	b = append(b, tag)
+	for i := size - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+// appendMPStrMap appends the MessagePack encoding of the map with its
+// keys in sorted order
+func appendMPStrMap(b []byte, m map[string]string) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b = appendMPMapLen(b, len(keys))
+	for _, k := range keys {
+		b = appendMPStr(b, k)
+		b = appendMPStr(b, m[k])
+	}
+	return b
+}
+
+// appendMPVal appends the MessagePack encoding of the value at the given
+// row of the given column. NA values are encoded as nil.
+func (df *DF) appendMPVal(b []byte, colIdx, rowIdx int) []byte {
+	v, isNA := df.valAt(colIdx, rowIdx)
+	if isNA {
+		return appendMPNil(b)
+	}
+	switch v := v.(type) {
+	case BoolVal:
+		return appendMPBool(b, v.Val)
+	case IntVal:
+		return appendMPInt(b, v.Val)
+	case FloatVal:
+		return appendMPFloat(b, v.Val)
+	case StringVal:
+		return appendMPStr(b, v.Val)
+	}
+	panic(dfErrorf("Unexpected value type: %T", v))
+}
+
+// colMetaMap returns the column metadata as a map
+func (ci ColInfo) colMetaMap() map[string]string {
+	m := map[string]string{}
+	for _, k := range ci.MetaKeys() {
+		m[k], _ = ci.Meta(k)
+	}
+	return m
+}
+
+// WriteMsgPack writes the dataframe to the io.Writer in a compact,
+// columnar MessagePack encoding which can be read back by ReadMsgPack to
+// give an identical dataframe. The encoding is a map with two entries:
+// "meta", a map holding the dataframe metadata, and "columns", an array
+// with one map per column. Each column map has the entries "name",
+// "type" (one of "Bool", "Int", "Float" or "String"), "meta" (the column
+// metadata) and "data", an array of the values in the column with NA
+// values encoded as nil.
+func (df *DF) WriteMsgPack(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 64)
+
+	buf = appendMPMapLen(buf, 2)
+	buf = appendMPStr(buf, mpKeyMeta)
+	buf = appendMPStrMap(buf, df.meta)
+	buf = appendMPStr(buf, mpKeyColumns)
+	buf = appendMPArrayLen(buf, len(df.mci.info))
+	for c, ci := range df.mci.info {
+		buf = appendMPMapLen(buf, 4)
+		buf = appendMPStr(buf, mpKeyName)
+		buf = appendMPStr(buf, ci.name)
+		buf = appendMPStr(buf, mpKeyType)
+		buf = appendMPStr(buf, ci.colType.String())
+		buf = appendMPStr(buf, mpKeyMeta)
+		buf = appendMPStrMap(buf, ci.colMetaMap())
+		buf = appendMPStr(buf, mpKeyData)
+		buf = appendMPArrayLen(buf, df.RowCount())
+		for r := 0; r < df.RowCount(); r++ {
+			buf = df.appendMPVal(buf, c, r)
+			if len(buf) >= bw.Size() {
+				if _, err := bw.Write(buf); err != nil {
+					return dfWrapf(err,
+						"cannot write the dataframe as MessagePack")
+				}
+				buf = buf[:0]
+			}
+		}
+	}
+
+	if _, err := bw.Write(buf); err != nil {
+		return dfWrapf(err, "cannot write the dataframe as MessagePack")
+	}
+	if err := bw.Flush(); err != nil {
+		return dfWrapf(err, "cannot write the dataframe as MessagePack")
+	}
+	return nil
+}
+
+// mpError is the type of the value passed to panic when the MessagePack
+// data being decoded is truncated or malformed. It is recovered by
+// ReadMsgPack.
+type mpError struct {
+	msg string
+}
+
+// mpReader decodes MessagePack values from a buffer
+type mpReader struct {
+	buf []byte
+	pos int
+}
+
+// fail panics with an mpError
+func (mr *mpReader) fail(format string, args ...any) {
+	panic(mpError{msg: fmt.Sprintf(format, args...)})
+}
+
+// take returns the next n bytes, panicking if there are not enough
+func (mr *mpReader) take(n int) []byte {
+	if n < 0 || n > len(mr.buf)-mr.pos {
+		mr.fail("unexpected end of data at offset %d", mr.pos)
+	}
+	b := mr.buf[mr.pos : mr.pos+n]
+	mr.pos += n
+	return b
+}
+
+// uint reads an unsigned big-endian integer of the given size
+func (mr *mpReader) uint(size int) uint64 {
+	var v uint64
+	for _, c := range mr.take(size) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// length reads a length of the given size, checking that it is not larger
+// than the remaining data could hold
+func (mr *mpReader) length(size int) int {
+	n := mr.uint(size)
+	if n > uint64(len(mr.buf)-mr.pos) {
+		mr.fail("bad length at offset %d: %d", mr.pos-size, n)
+	}
+	return int(n)
+}
+
+// value reads the next value which is returned as nil, a bool, an int64,
+// a uint64 (only if it is too large for an int64), a float64, a string (for
+// both str and bin values), a []any or a map[string]any. Extension types
+// and maps with non-string keys are not supported.
+func (mr *mpReader) value() any {
+	offset := mr.pos
+	tag := mr.take(1)[0]
+
+	switch {
+	case tag <= 0x7f:
+		return int64(tag)
+	case tag >= 0xe0:
+		return int64(int8(tag))
+	case tag&0xf0 == 0x80:
+		return mr.mapVal(int(tag & 0x0f))
+	case tag&0xf0 == 0x90:
+		return mr.arrayVal(int(tag & 0x0f))
+	case tag&0xe0 == 0xa0:
+		return string(mr.take(int(tag & 0x1f)))
+	}
+
+	switch tag {
+	case 0xc0:
+		return nil
+	case 0xc2:
+		return false
+	case 0xc3:
+		return true
+	case 0xc4, 0xd9:
+		return string(mr.take(mr.length(1)))
+	case 0xc5, 0xda:
+		return string(mr.take(mr.length(2)))
+	case 0xc6, 0xdb:
+		return string(mr.take(mr.length(4)))
+	case 0xca:
+		return float64(math.Float32frombits(uint32(mr.uint(4))))
+	case 0xcb:
+		return math.Float64frombits(mr.uint(8))
+	case 0xcc:
+		return int64(mr.uint(1))
+	case 0xcd:
+		return int64(mr.uint(2))
+	case 0xce:
+		return int64(mr.uint(4))
+	case 0xcf:
+		v := mr.uint(8)
+		if v > math.MaxInt64 {
+			return v
+		}
+		return int64(v)
+	case 0xd0:
+		return int64(int8(mr.uint(1)))
+	case 0xd1:
+		return int64(int16(mr.uint(2)))
+	case 0xd2:
+		return int64(int32(mr.uint(4)))
+	case 0xd3:
+		return int64(mr.uint(8))
+	case 0xdc:
+		return mr.arrayVal(mr.length(2))
+	case 0xdd:
+		return mr.arrayVal(mr.length(4))
+	case 0xde:
+		return mr.mapVal(mr.length(2))
+	case 0xdf:
+		return mr.mapVal(mr.length(4))
+	}
+	mr.fail("unsupported MessagePack type (0x%02x) at offset %d",
+		tag, offset)
+	return nil
+}
+
+// arrayVal reads the n elements of an array
+func (mr *mpReader) arrayVal(n int) []any {
+	a := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		a = append(a, mr.value())
+	}
+	return a
+}
+
+// mapVal reads the n entries of a map
+func (mr *mpReader) mapVal(n int) map[string]any {
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		offset := mr.pos
+		k, ok := mr.value().(string)
+		if !ok {
+			mr.fail("non-string map key at offset %d", offset)
+		}
+		m[k] = mr.value()
+	}
+	return m
+}
+
+// mpStrMap converts the decoded value to a map of strings
+func mpStrMap(v any, what string) (map[string]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, dfErrorf("the %s is not a map", what)
+	}
+	sm := make(map[string]string, len(m))
+	for k, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			return nil, dfErrorf("the %s value for %q is not a string",
+				what, k)
+		}
+		sm[k] = s
+	}
+	return sm, nil
+}
+
+// mpColInfo returns the ColInfo and the values for the decoded column
+func mpColInfo(v any) (ColInfo, []any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return ColInfo{}, nil, dfErrorf("the column is not a map")
+	}
+
+	name, ok := m[mpKeyName].(string)
+	if !ok {
+		return ColInfo{}, nil, dfErrorf("the column has no name")
+	}
+	typeName, ok := m[mpKeyType].(string)
+	if !ok {
+		return ColInfo{}, nil, dfErrorf("column %q has no type", name)
+	}
+	ct, err := colTypeByName(typeName)
+	if err != nil {
+		return ColInfo{}, nil, dfWrapf(err, "column %q", name)
+	}
+	ci := ColInfo{name: name, colType: ct}
+
+	meta, err := mpStrMap(m[mpKeyMeta], "column metadata")
+	if err != nil {
+		return ColInfo{}, nil, dfWrapf(err, "column %q", name)
+	}
+	for k, v := range meta {
+		ci = ci.WithMeta(k, v)
+	}
+
+	data, ok := m[mpKeyData].([]any)
+	if !ok && m[mpKeyData] != nil {
+		return ColInfo{}, nil,
+			dfErrorf("the data for column %q is not an array", name)
+	}
+	return ci, data, nil
+}
+
+// readMsgPack decodes the dataframe from the MessagePack data
+func readMsgPack(buf []byte) (*DF, error) {
+	mr := &mpReader{buf: buf}
+	top, ok := mr.value().(map[string]any)
+	if !ok {
+		return nil, dfErrorf("the data is not a map")
+	}
+	if mr.pos != len(buf) {
+		return nil, dfErrorf("unexpected data after the dataframe")
+	}
+
+	cols, ok := top[mpKeyColumns].([]any)
+	if !ok && top[mpKeyColumns] != nil {
+		return nil, dfErrorf("the columns are not an array")
+	}
+	cis := make([]ColInfo, 0, len(cols))
+	data := make([][]any, 0, len(cols))
+	for i, c := range cols {
+		ci, d, err := mpColInfo(c)
+		if err != nil {
+			return nil, dfWrapf(err, "column %d", i)
+		}
+		if i > 0 && len(d) != len(data[0]) {
+			return nil, dfKindErrorf(ErrDimensionMismatch,
+				"column %q has %d values, expected %d",
+				ci.name, len(d), len(data[0]))
+		}
+		cis = append(cis, ci)
+		data = append(data, d)
+	}
+
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := mpStrMap(top[mpKeyMeta], "metadata")
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range meta {
+		if err := df.SetMeta(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	for c, d := range data {
+		for r, v := range d {
+			if u, ok := v.(uint64); ok {
+				return nil, dfErrorf("row %d, %s: the value is too large: %d",
+					r, df.mci.ColDesc(c), u)
+			}
+			if err := df.appendVal(c, v); err != nil {
+				return nil, dfWrapf(err, "row %d, %s", r, df.mci.ColDesc(c))
+			}
+		}
+	}
+	return df, nil
+}
+
+// decodeMsgPack decodes the dataframe from the MessagePack data, reporting
+// truncated or malformed data as an error
+func decodeMsgPack(buf []byte) (df *DF, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			mpErr, ok := p.(mpError)
+			if !ok {
+				panic(p)
+			}
+			df, err = nil, dfErrorf("corrupt MessagePack data: %s", mpErr.msg)
+		}
+	}()
+
+	return readMsgPack(buf)
+}
+
+// ReadMsgPack reads a dataframe from MessagePack data in the format
+// written by WriteMsgPack. Integer values are allowed in float columns and
+// the MessagePack bin type is allowed in string columns. Any other entries
+// in the maps are ignored. The whole of the data is read into memory
+// before it is decoded.
+func ReadMsgPack(r io.Reader) (*DF, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the MessagePack data")
+	}
+
+	df, err := decodeMsgPack(buf)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the MessagePack data")
+	}
+	return df, nil
+}
@@ -0,0 +1,112 @@
+package dataframe_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestMsgPackRoundTrip(t *testing.T) {
+	df := mkTestDF(t,
+		"b i f s\n"+
+			"true 1 1.5 a\n"+
+			"x 300 -2 "+strings.Repeat("long", 10)+"\n"+
+			"false -70000 x NA\n"+
+			"true 5000000000 1e300 é\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeBool, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString),
+		dataframe.DFRColMeta("f", dataframe.MetaUnit, "m"))
+	if err := df.SetMeta("source", "test"); err != nil {
+		t.Fatal("BAD TEST - cannot set the metadata: ", err)
+	}
+
+	var buf bytes.Buffer
+	if err := df.WriteMsgPack(&buf); err != nil {
+		t.Fatal("unexpected error writing the dataframe: ", err)
+	}
+	got, err := dataframe.ReadMsgPack(&buf)
+	if err != nil {
+		t.Fatal("unexpected error reading the dataframe: ", err)
+	}
+
+	if err := df.Equal(got); err != nil {
+		t.Error("the dataframe read back is different: ", err)
+	}
+	unit, _, err := got.ColMeta("f", dataframe.MetaUnit)
+	if err != nil {
+		t.Fatal("unexpected error getting the column metadata: ", err)
+	}
+	testhelper.DiffString(t, "round trip", "column metadata", unit, "m")
+	src, _ := got.Meta("source")
+	testhelper.DiffString(t, "round trip", "metadata", src, "test")
+}
+
+func TestReadMsgPack(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data    []byte
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("ints in a float column, bin string"),
+			data: []byte("\x81\xa7columns\x92" +
+				"\x83\xa4name\xa1x\xa4type\xa5Float\xa4data\x92\x01\xcb" +
+				"\x3f\xf8\x00\x00\x00\x00\x00\x00" +
+				"\x83\xa4name\xa1s\xa4type\xa6String\xa4data\x92" +
+				"\xc4\x02ab\xc0"),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("x", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+			},
+			expVals: [][]string{{"1", "ab"}, {"1.5", "NA"}},
+		},
+		{
+			ID: testhelper.MkID("truncated"),
+			ExpErr: testhelper.MkExpErr("corrupt MessagePack data",
+				"unexpected end of data at offset 2"),
+			data: []byte("\x81\xa7col"),
+		},
+		{
+			ID: testhelper.MkID("not a map"),
+			ExpErr: testhelper.MkExpErr("cannot read the MessagePack data",
+				"the data is not a map"),
+			data: []byte("\x01"),
+		},
+		{
+			ID: testhelper.MkID("bad type"),
+			ExpErr: testhelper.MkExpErr(`column 0`, `column "x"`,
+				`unknown column type: "Date"`),
+			data: []byte("\x81\xa7columns\x91" +
+				"\x82\xa4name\xa1x\xa4type\xa4Date"),
+		},
+		{
+			ID: testhelper.MkID("wrong value type"),
+			ExpErr: testhelper.MkExpErr(`row 0, Column 0 ("x": "Int")`,
+				"cannot convert a value of type string into an IntVal"),
+			data: []byte("\x81\xa7columns\x91" +
+				"\x83\xa4name\xa1x\xa4type\xa3Int\xa4data\x91\xa1a"),
+		},
+		{
+			ID: testhelper.MkID("columns of different lengths"),
+			ExpErr: testhelper.MkExpErr(
+				`column "y" has 0 values, expected 1`),
+			data: []byte("\x81\xa7columns\x92" +
+				"\x83\xa4name\xa1x\xa4type\xa3Int\xa4data\x91\x01" +
+				"\x83\xa4name\xa1y\xa4type\xa3Int\xa4data\x90"),
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.ReadMsgPack(bytes.NewReader(tc.data))
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package dataframe
+
+// RowFunc is a function type used to calculate a new value from the values
+// in a row. The value returned must be convertible to the type of the
+// column being calculated. The allowed types are:
+//
+//   - for a bool column: BoolVal or bool
+//   - for an int column: IntVal, int64 or int
+//   - for a float column: FloatVal, float64, IntVal, int64 or int
+//   - for a string column: StringVal or string
+//
+// Returning nil will give an NA value
+type RowFunc func(r *Row) (any, error)
+
+// mutateColInfo checks that the new column can be added to the existing
+// columns and returns the new set of ColInfo values
+func mutateColInfo(cis []ColInfo, name string, colType ColType) (
+	[]ColInfo, error,
+) {
+	newCI := ColInfo{name: name, colType: colType}
+	if err := newCI.Check(); err != nil {
+		return nil, err
+	}
+
+	for i, ci := range cis {
+		if ci.name == name {
+			return nil, dfCodeErrorf(ErrCodeDuplicateColName,
+				"Column name already used: column %d is named %q", i, name)
+		}
+	}
+
+	return append(cloneColInfoSlice(cis), newCI), nil
+}
+
+// Mutate returns a new dataframe having all the columns of df plus a new
+// column with the given name and type. The values of the new column are
+// calculated by calling the function with each row in turn. It returns an
+// error if the name is already in use, if the function returns an error or
+// if the function's value cannot be converted to the column type.
+func (df *DF) Mutate(name string, colType ColType, f RowFunc) (*DF, error) {
+	cis, err := mutateColInfo(df.mci.info, name, colType)
+	if err != nil {
+		return nil, err
+	}
+
+	rval, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	rval.maxErrors = df.maxErrors
+	rval.meta = cloneMeta(df.meta)
+
+	for i := 0; i < df.RowCount(); i++ {
+		r := df.Row(i)
+		v, err := f(r)
+		if err != nil {
+			return nil, dfWrapf(err, "data row: %d", i)
+		}
+		if err := r.addVal(name, colType, v); err != nil {
+			return nil, dfWrapf(err, "data row: %d", i)
+		}
+		if err := rval.appendRowVals(r); err != nil {
+			return nil, dfWrapf(err, "data row: %d", i)
+		}
+	}
+
+	return rval, nil
+}
@@ -0,0 +1,123 @@
+package dataframe
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// These are the errors reported when a number is given in a form which the
+// NumFormat does not allow
+var (
+	errIntPrefix     = errors.New("a base prefix is not allowed")
+	errUnderscore    = errors.New("underscores are not allowed")
+	errHexFloat      = errors.New("hexadecimal floats are not allowed")
+	errUnderscorePos = errors.New("invalid use of underscores")
+)
+
+// NumFormat controls which forms of numeric literal are accepted when
+// parsing int and float values. The zero value accepts everything that Go
+// itself allows: ints with a base prefix (0x, 0o or 0b) or a leading 0
+// (octal), underscores between digits and hexadecimal floats.
+type NumFormat struct {
+	// NoIntPrefixes causes ints to be read as decimal numbers; an int
+	// with a base prefix (0x, 0o or 0b) is rejected and a leading zero
+	// does not make the number octal so "010" is ten.
+	NoIntPrefixes bool
+	// NoUnderscores causes numbers containing underscores, such as
+	// "1_000", to be rejected
+	NoUnderscores bool
+	// NoHexFloats causes floats given in hexadecimal, such as "0x1p-2",
+	// to be rejected
+	NoHexFloats bool
+}
+
+// unsigned returns the string with any leading sign removed
+func unsigned(s string) string {
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		return s[1:]
+	}
+	return s
+}
+
+// hasBasePrefix returns true if the number starts with a base prefix (0x,
+// 0o or 0b in either case), after any sign
+func hasBasePrefix(s string) bool {
+	s = unsigned(s)
+	if len(s) < 2 || s[0] != '0' {
+		return false
+	}
+	switch s[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	}
+	return false
+}
+
+// isDigit returns true if the byte is a decimal digit
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
+
+// decimalUnderscoresOK returns true if every underscore in the decimal
+// number is between two digits
+func decimalUnderscoresOK(s string) bool {
+	s = unsigned(s)
+	for i := 0; i < len(s); i++ {
+		if s[i] != '_' {
+			continue
+		}
+		if i == 0 || i == len(s)-1 || !isDigit(s[i-1]) || !isDigit(s[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseInt parses the string as an int64 according to the format
+func (nf NumFormat) ParseInt(s string) (int64, error) {
+	const fn = "ParseInt"
+
+	hasUnderscore := strings.ContainsRune(s, '_')
+	if nf.NoUnderscores && hasUnderscore {
+		return 0, &strconv.NumError{Func: fn, Num: s, Err: errUnderscore}
+	}
+	if !nf.NoIntPrefixes {
+		return strconv.ParseInt(s, 0, 64)
+	}
+
+	if hasBasePrefix(s) {
+		return 0, &strconv.NumError{Func: fn, Num: s, Err: errIntPrefix}
+	}
+	if hasUnderscore {
+		if !decimalUnderscoresOK(s) {
+			return 0, &strconv.NumError{Func: fn, Num: s, Err: errUnderscorePos}
+		}
+		s = strings.ReplaceAll(s, "_", "")
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// ParseFloat parses the string as a float64 according to the format
+func (nf NumFormat) ParseFloat(s string) (float64, error) {
+	const fn = "ParseFloat"
+
+	if nf.NoUnderscores && strings.ContainsRune(s, '_') {
+		return 0, &strconv.NumError{Func: fn, Num: s, Err: errUnderscore}
+	}
+	if nf.NoHexFloats && hasBasePrefix(s) {
+		return 0, &strconv.NumError{Func: fn, Num: s, Err: errHexFloat}
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// DFRNumFormat returns a function which will cause the DFReader to accept
+// just those forms of int and float values allowed by the NumFormat, both
+// when guessing the column types and when parsing the values. By default
+// every form is accepted so, for instance, "0x1F" is read as the int 31;
+// giving a NumFormat with NoIntPrefixes set will cause it to be rejected
+// and so the column would be guessed to be a string column.
+func DFRNumFormat(nf NumFormat) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		dfr.parseOpts.numFmt = nf
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestNumFormatParseInt(t *testing.T) {
+	strict := dataframe.NumFormat{NoIntPrefixes: true, NoUnderscores: true}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		nf     dataframe.NumFormat
+		s      string
+		expVal int64
+	}{
+		{ID: testhelper.MkID("default: decimal"), s: "42", expVal: 42},
+		{ID: testhelper.MkID("default: hex"), s: "0x1F", expVal: 31},
+		{ID: testhelper.MkID("default: octal"), s: "010", expVal: 8},
+		{ID: testhelper.MkID("default: binary"), s: "-0b101", expVal: -5},
+		{ID: testhelper.MkID("default: underscores"), s: "1_000", expVal: 1000},
+		{
+			ID:     testhelper.MkID("no prefixes: decimal"),
+			nf:     dataframe.NumFormat{NoIntPrefixes: true},
+			s:      "-42",
+			expVal: -42,
+		},
+		{
+			ID:     testhelper.MkID("no prefixes: leading zero"),
+			nf:     dataframe.NumFormat{NoIntPrefixes: true},
+			s:      "010",
+			expVal: 10,
+		},
+		{
+			ID:     testhelper.MkID("no prefixes: underscores"),
+			nf:     dataframe.NumFormat{NoIntPrefixes: true},
+			s:      "+1_000_000",
+			expVal: 1000000,
+		},
+		{
+			ID: testhelper.MkID("no prefixes: bad underscores"),
+			ExpErr: testhelper.MkExpErr(
+				`strconv.ParseInt: parsing "1__000":` +
+					` invalid use of underscores`),
+			nf: dataframe.NumFormat{NoIntPrefixes: true},
+			s:  "1__000",
+		},
+		{
+			ID: testhelper.MkID("no prefixes: hex"),
+			ExpErr: testhelper.MkExpErr(
+				`strconv.ParseInt: parsing "0x1F":` +
+					` a base prefix is not allowed`),
+			nf: dataframe.NumFormat{NoIntPrefixes: true},
+			s:  "0x1F",
+		},
+		{
+			ID: testhelper.MkID("no prefixes: negative binary"),
+			ExpErr: testhelper.MkExpErr(
+				`parsing "-0B11": a base prefix is not allowed`),
+			nf: dataframe.NumFormat{NoIntPrefixes: true},
+			s:  "-0B11",
+		},
+		{
+			ID: testhelper.MkID("no underscores"),
+			ExpErr: testhelper.MkExpErr(
+				`strconv.ParseInt: parsing "1_000":` +
+					` underscores are not allowed`),
+			nf: strict,
+			s:  "1_000",
+		},
+		{
+			ID:     testhelper.MkID("not an int"),
+			ExpErr: testhelper.MkExpErr(`parsing "1.5": invalid syntax`),
+			nf:     strict,
+			s:      "1.5",
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.nf.ParseInt(tc.s)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffInt(t, tc.IDStr(), "value", v, tc.expVal)
+		}
+	}
+}
+
+func TestNumFormatParseFloat(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		nf     dataframe.NumFormat
+		s      string
+		expVal float64
+	}{
+		{ID: testhelper.MkID("default: decimal"), s: "1.5", expVal: 1.5},
+		{ID: testhelper.MkID("default: hex"), s: "0x1p-2", expVal: 0.25},
+		{
+			ID:     testhelper.MkID("default: underscores"),
+			s:      "1_000.5",
+			expVal: 1000.5,
+		},
+		{
+			ID: testhelper.MkID("no hex floats"),
+			ExpErr: testhelper.MkExpErr(
+				`strconv.ParseFloat: parsing "-0x1p-2":` +
+					` hexadecimal floats are not allowed`),
+			nf: dataframe.NumFormat{NoHexFloats: true},
+			s:  "-0x1p-2",
+		},
+		{
+			ID:     testhelper.MkID("no hex floats: decimal"),
+			nf:     dataframe.NumFormat{NoHexFloats: true},
+			s:      "2.5e3",
+			expVal: 2500,
+		},
+		{
+			ID: testhelper.MkID("no underscores"),
+			ExpErr: testhelper.MkExpErr(
+				`strconv.ParseFloat: parsing "1_000.5":` +
+					` underscores are not allowed`),
+			nf: dataframe.NumFormat{NoUnderscores: true},
+			s:  "1_000.5",
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.nf.ParseFloat(tc.s)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffFloat(t, tc.IDStr(), "value", v, tc.expVal, 0)
+		}
+	}
+}
+
+func TestDFRNumFormat(t *testing.T) {
+	const content = `id  n    f
+0x1F 010 0x1p-2
+0x20 011 2.5
+`
+	testCases := []struct {
+		testhelper.ID
+		opts    []dataframe.DFReaderOpt
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("default"),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("id", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("n", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("f", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"31", "8", "0.25"},
+				{"32", "9", "2.5"},
+			},
+		},
+		{
+			ID: testhelper.MkID("strict"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRNumFormat(dataframe.NumFormat{
+					NoIntPrefixes: true,
+					NoHexFloats:   true,
+				}),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("id", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("n", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("f", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"0x1F", "10", "0x1p-2"},
+				{"0x20", "11", "2.5"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DFReaderOpt{dataframe.HasHeader},
+			tc.opts...)
+		df := mkTestDF(t, content, opts...)
+		checkColDetails(t, tc.IDStr(), df, tc.expCols)
+		checkDFVals(t, tc.IDStr(), df, tc.expVals)
+	}
+
+	id := "strict with the types given"
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt,
+			dataframe.ColTypeInt, dataframe.ColTypeFloat),
+		dataframe.DFRNumFormat(dataframe.NumFormat{NoIntPrefixes: true}))
+	testhelper.DiffInt(t, id, "error count", df.ErrCount(), 2)
+}
@@ -0,0 +1,97 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// OneHot returns a new dataframe in which the named column is replaced by
+// one indicator column for each distinct non-NA value in the column. This
+// is the usual form for giving categorical values to machine learning
+// libraries. Each indicator column is an int column holding 1 in the rows
+// having that value and 0 elsewhere; a row with an NA value, or a float
+// NaN value, has 0 in every indicator column. The indicator columns are
+// named after the column and the value, joined by an underscore, so a
+// column "side" holding "b" and "s" is replaced by columns "side_b" and
+// "side_s". They are in increasing order of the values and take the place
+// of the original column, the other columns being unchanged.
+//
+// The number of new columns is the number of distinct values so it should
+// only be used with columns having few distinct values. It returns an
+// error if there is no such column or if an indicator column name is
+// already in use.
+func (df *DF) OneHot(col string) (*DF, error) {
+	colIdx, ok := df.mci.colIdx(col)
+	if !ok {
+		return nil, errUnknownColName(col)
+	}
+	name := df.mci.info[colIdx].name
+
+	var vals []any
+	seen := map[any]bool{}
+	for r := 0; r < df.RowCount(); r++ {
+		v, isNA := df.valAt(colIdx, r)
+		if fv, ok := v.(FloatVal); isNA || ok && math.IsNaN(fv.Val) {
+			continue
+		}
+		if k := df.keyAt(colIdx, r); !seen[k] {
+			seen[k] = true
+			vals = append(vals, v)
+		}
+	}
+	sort.Slice(vals, func(i, j int) bool {
+		return cmpVals(vals[i], vals[j]) < 0
+	})
+
+	cis := make([]ColInfo, 0, len(df.mci.info)-1+len(vals))
+	cis = append(cis, df.mci.info[:colIdx]...)
+	for _, v := range vals {
+		cis = append(cis,
+			ColInfo{name: fmt.Sprintf("%s_%s", name, v), colType: ColTypeInt})
+	}
+	cis = append(cis, df.mci.info[colIdx+1:]...)
+	names := map[string]bool{}
+	for _, ci := range cis {
+		if names[ci.name] {
+			return nil, dfCodeErrorf(ErrCodeDuplicateColName,
+				"column %q: the indicator column name %q is already in use",
+				name, ci.name)
+		}
+		names[ci.name] = true
+	}
+
+	rval, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	rval.maxErrors = df.maxErrors
+	rval.meta = cloneMeta(df.meta)
+
+	for r := 0; r < df.RowCount(); r++ {
+		out := 0
+		for i := range df.mci.info {
+			if i != colIdx {
+				v, _ := df.valAt(i, r)
+				if err := rval.appendVal(out, v); err != nil {
+					return nil, err
+				}
+				out++
+				continue
+			}
+			k := df.keyAt(colIdx, r)
+			for _, v := range vals {
+				var ind int64
+				if kv, _ := keyOf(v); kv == k {
+					ind = 1
+				}
+				if err := rval.appendVal(out, ind); err != nil {
+					return nil, err
+				}
+				out++
+			}
+		}
+	}
+
+	return rval, nil
+}
@@ -0,0 +1,92 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestOneHot(t *testing.T) {
+	df := mkTestDF(t, "sym side qty\nabc s 1\ndef b 2\nghi NA NA\njkl s 1\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeString,
+			dataframe.ColTypeString, dataframe.ColTypeInt))
+	if err := df.SetMeta("source", "test"); err != nil {
+		t.Fatal("BAD TEST - cannot set the metadata: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col      string
+		expNames []string
+		expVals  [][]string
+	}{
+		{
+			ID:       testhelper.MkID("string column, NA as text"),
+			col:      "side",
+			expNames: []string{"sym", "side_NA", "side_b", "side_s", "qty"},
+			expVals: [][]string{
+				{"abc", "0", "0", "1", "1"},
+				{"def", "0", "1", "0", "2"},
+				{"ghi", "1", "0", "0", "NA"},
+				{"jkl", "0", "0", "1", "1"},
+			},
+		},
+		{
+			ID:       testhelper.MkID("int column at the end, with NA"),
+			col:      "qty",
+			expNames: []string{"sym", "side", "qty_1", "qty_2"},
+			expVals: [][]string{
+				{"abc", "s", "1", "0"},
+				{"def", "b", "0", "1"},
+				{"ghi", "NA", "0", "0"},
+				{"jkl", "s", "1", "0"},
+			},
+		},
+		{
+			ID:     testhelper.MkID("bad: no such column"),
+			ExpErr: testhelper.MkExpErr(`"nonesuch"`),
+			col:    "nonesuch",
+		},
+	}
+
+	for _, tc := range testCases {
+		ohdf, err := df.OneHot(tc.col)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			names := make([]string, 0, len(tc.expNames))
+			for _, ci := range ohdf.Columns() {
+				names = append(names, ci.Name())
+			}
+			testhelper.DiffStringSlice(t, tc.IDStr(), "names",
+				names, tc.expNames)
+			checkDFVals(t, tc.IDStr(), ohdf, tc.expVals)
+			src, _ := ohdf.Meta("source")
+			testhelper.DiffString(t, tc.IDStr(), "metadata", src, "test")
+		}
+	}
+
+	clash := mkTestDF(t, "x x_a\na 1\n", dataframe.HasHeader)
+	_, err := clash.OneHot("x")
+	testhelper.CheckExpErrWithID(t, "name in use", err,
+		testhelper.MkExpErr(
+			`column "x": the indicator column name "x_a" is already in use`))
+
+	nanDF := mkTestDF(t, "f\n1.5\nNaN\n2.5\nNaN\n", dataframe.HasHeader,
+		dataframe.DFRColTypes(dataframe.ColTypeFloat))
+	ohdf, err := nanDF.OneHot("f")
+	if err != nil {
+		t.Fatal("NaN values: unexpected error: ", err)
+	}
+	checkColDetails(t, "NaN values", ohdf, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("f_1.5", dataframe.ColTypeInt),
+		dataframe.MustNewColInfo("f_2.5", dataframe.ColTypeInt),
+	})
+	checkDFVals(t, "NaN values", ohdf, [][]string{
+		{"1", "0"},
+		{"0", "0"},
+		{"0", "1"},
+		{"0", "0"},
+	})
+}
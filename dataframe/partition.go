@@ -0,0 +1,42 @@
+package dataframe
+
+import "fmt"
+
+// PartitionBy splits the dataframe into sub-dataframes, one for each
+// distinct value in the named column, so that each can be processed
+// independently. The map key is the value as given by its String method,
+// so the rows with an NA value are in the sub-dataframe with the key "NA".
+// Each sub-dataframe has the same columns and metadata as df and holds
+// copies of the rows having that value, in their original order.
+//
+// It returns an error if there is no such column or if two different
+// values give the same key, as could happen, for instance, with a string
+// column holding both NA and the string "NA".
+func (df *DF) PartitionBy(col string) (map[string]*DF, error) {
+	colIdx, ok := df.mci.colIdx(col)
+	if !ok {
+		return nil, errUnknownColName(col)
+	}
+
+	parts := map[string]*DF{}
+	partVals := map[string]string{}
+	for i := 0; i < df.RowCount(); i++ {
+		v, _ := df.valAt(colIdx, i)
+		k := fmt.Sprint(v)
+		kv := fmt.Sprintf("%#v", df.keyAt(colIdx, i))
+
+		part, ok := parts[k]
+		if !ok {
+			part = df.Clone()
+			part.maxErrors = df.maxErrors
+			parts[k] = part
+			partVals[k] = kv
+		} else if partVals[k] != kv {
+			return nil, dfErrorf("column %q: row %d: the key %q has"+
+				" already been given to a different value", col, i, k)
+		}
+		part.copyRowFrom(df, i)
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,98 @@
+package dataframe_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// partitionTestData is in round-trip format so that it can hold NA values
+const partitionTestData = `sym qty price
+String Int Float
+"abc" 10 1.5
+"xyz" 20 2.5
+"abc" 30 3.5
+NA    40 4.5
+"xyz" 50 NA
+`
+
+func TestPartitionBy(t *testing.T) {
+	df := mkTestDF(t, partitionTestData, dataframe.DFRRoundTrip)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		col      string
+		expParts map[string][][]string
+	}{
+		{
+			ID:  testhelper.MkID("by string"),
+			col: "sym",
+			expParts: map[string][][]string{
+				"abc": {{"abc", "10", "1.5"}, {"abc", "30", "3.5"}},
+				"xyz": {{"xyz", "20", "2.5"}, {"xyz", "50", "NA"}},
+				"NA":  {{"NA", "40", "4.5"}},
+			},
+		},
+		{
+			ID:  testhelper.MkID("by float, with NA"),
+			col: "price",
+			expParts: map[string][][]string{
+				"1.5": {{"abc", "10", "1.5"}},
+				"2.5": {{"xyz", "20", "2.5"}},
+				"3.5": {{"abc", "30", "3.5"}},
+				"4.5": {{"NA", "40", "4.5"}},
+				"NA":  {{"xyz", "50", "NA"}},
+			},
+		},
+		{
+			ID:     testhelper.MkID("bad column"),
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+			col:    "nonesuch",
+		},
+	}
+
+	for _, tc := range testCases {
+		parts, err := df.PartitionBy(tc.col)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		keys := make([]string, 0, len(parts))
+		for k := range parts {
+			keys = append(keys, k)
+		}
+		expKeys := make([]string, 0, len(tc.expParts))
+		for k := range tc.expParts {
+			expKeys = append(expKeys, k)
+		}
+		sort.Strings(keys)
+		sort.Strings(expKeys)
+		if testhelper.DiffStringSlice(t, tc.IDStr(), "keys",
+			keys, expKeys) {
+			continue
+		}
+
+		for k, exp := range tc.expParts {
+			id := tc.IDStr() + ": " + k
+			checkColDetails(t, id, parts[k], df.Columns())
+			checkDFVals(t, id, parts[k], exp)
+		}
+	}
+}
+
+func TestPartitionByClash(t *testing.T) {
+	c := mkStringCol("s", "NA")
+	c.MustAddStringVal(dataframe.StringVal{IsNA: true})
+	df, err := dataframe.NewDFFromCols(c)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	_, err = df.PartitionBy("s")
+	testhelper.CheckExpErrWithID(t, "NA and \"NA\"", err,
+		testhelper.MkExpErr(`column "s": row 1:`,
+			`the key "NA" has already been given to a different value`))
+}
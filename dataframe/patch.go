@@ -0,0 +1,224 @@
+package dataframe
+
+// Patch holds the changes which turn one dataframe into another, with the
+// rows matched by the values in a key column: the rows to be inserted, the
+// keys of the rows to be deleted and the new values of the rows to be
+// updated. It is made by MakePatch and can be applied by Apply to any
+// dataframe with the same columns, such as a cached copy of the original
+// dataframe, so that only the changes need to be passed on.
+type Patch struct {
+	keyCol  string
+	inserts *DF
+	deletes *DF
+	updates *DF
+}
+
+// KeyCol returns the name of the key column used to match the rows
+func (p *Patch) KeyCol() string {
+	return p.keyCol
+}
+
+// Inserts returns the rows to be inserted. It shares its data with the
+// patch and so it should not be changed.
+func (p *Patch) Inserts() *DF {
+	return p.inserts
+}
+
+// Deletes returns the keys of the rows to be deleted as a dataframe with
+// just the key column. It shares its data with the patch and so it should
+// not be changed.
+func (p *Patch) Deletes() *DF {
+	return p.deletes
+}
+
+// Updates returns the rows to be updated with all their new values. It
+// shares its data with the patch and so it should not be changed.
+func (p *Patch) Updates() *DF {
+	return p.updates
+}
+
+// IsEmpty returns true if the patch makes no changes
+func (p *Patch) IsEmpty() bool {
+	return p.inserts.RowCount() == 0 &&
+		p.deletes.RowCount() == 0 &&
+		p.updates.RowCount() == 0
+}
+
+// appendMappedRow appends the row of src to the dataframe taking the value
+// for each column from the column of src given by srcIdx
+func (df *DF) appendMappedRow(src *DF, srcIdx []int, row int) error {
+	for c, sc := range srcIdx {
+		v, _ := src.valAt(sc, row)
+		if err := df.appendVal(c, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowsEqual returns true if the values in the rows are the same, the
+// columns of b being given by bIdx
+func rowsEqual(a, b *DF, bIdx []int, aRow, bRow int, eq dfEqualOpts) bool {
+	for c, bc := range bIdx {
+		av, aIsNA := a.valAt(c, aRow)
+		bv, bIsNA := b.valAt(bc, bRow)
+		if !eq.valsEqual(av, aIsNA, bv, bIsNA) {
+			return false
+		}
+	}
+	return true
+}
+
+// MakePatch compares the two dataframes and returns the Patch which turns
+// a into b. The dataframes must have the same columns, with the same names
+// and types, though they may be in a different order, and the key column
+// must be given with DFDKeyCol; its values must be unique in each
+// dataframe. A row is updated if any of its values differ; DFDEqualOpts
+// may be given to control how values are compared. The rows of the patch
+// have the columns in the order of a.
+func MakePatch(a, b *DF, opts ...DiffOpt) (*Patch, error) {
+	var o diffOpts
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	if o.keyCol == "" {
+		return nil, dfCodeErrorf(ErrCodeNoKey,
+			"no key column has been given (see DFDKeyCol)")
+	}
+
+	bIdx, err := a.matchCols(b, dfEqualOpts{ignoreColOrder: true})
+	if err != nil {
+		return nil, err
+	}
+	keyIdx, ok := a.mci.colIdx(o.keyCol)
+	if !ok {
+		return nil, errUnknownColName(o.keyCol)
+	}
+	aRows, err := keyRows(a, keyIdx)
+	if err != nil {
+		return nil, dfWrapf(err, "the first dataframe")
+	}
+	bRows, err := keyRows(b, bIdx[keyIdx])
+	if err != nil {
+		return nil, dfWrapf(err, "the second dataframe")
+	}
+
+	p := &Patch{keyCol: a.mci.info[keyIdx].name}
+	if p.inserts, err = newDFFromColInfo(a.mci.info...); err != nil {
+		return nil, err
+	}
+	if p.updates, err = newDFFromColInfo(a.mci.info...); err != nil {
+		return nil, err
+	}
+	if p.deletes, err = newDFFromColInfo(a.mci.info[keyIdx]); err != nil {
+		return nil, err
+	}
+
+	for r := 0; r < a.RowCount(); r++ {
+		bRow, ok := bRows[a.keyAt(keyIdx, r)]
+		switch {
+		case !ok:
+			err = p.deletes.appendMappedRow(a, []int{keyIdx}, r)
+		case !rowsEqual(a, b, bIdx, r, bRow, o.eq):
+			err = p.updates.appendMappedRow(b, bIdx, bRow)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for r := 0; r < b.RowCount(); r++ {
+		if _, ok := aRows[b.keyAt(bIdx[keyIdx], r)]; ok {
+			continue
+		}
+		if err := p.inserts.appendMappedRow(b, bIdx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// patchKeys returns a map of the keys in the indexed column of the patch
+// dataframe to their rows. It returns an error if any key is not in the
+// dataframe being patched (or, for inserted rows, if it is).
+func patchKeys(pdf *DF, keyIdx int, dfRows map[any]int, action string,
+) (map[any]int, error) {
+	rows, err := keyRows(pdf, keyIdx)
+	if err != nil {
+		return nil, dfWrapf(err, "the rows to be %s", action)
+	}
+
+	insert := action == "inserted"
+	for r := 0; r < pdf.RowCount(); r++ {
+		_, ok := dfRows[pdf.keyAt(keyIdx, r)]
+		switch {
+		case insert && ok:
+			return nil, dfCodeErrorf(ErrCodeKeyNotUnique,
+				"the key %s of a row to be inserted is already in use",
+				diffKeyText(pdf, keyIdx, r))
+		case !insert && !ok:
+			return nil, dfKindErrorf(ErrNoSuchRow,
+				"there is no row with the key %s to be %s",
+				diffKeyText(pdf, keyIdx, r), action)
+		}
+	}
+	return rows, nil
+}
+
+// Apply returns a new dataframe made by applying the patch to df: the
+// deleted rows are removed, the updated rows are replaced by their new
+// values, with the other rows left in their original order, and the
+// inserted rows are added at the end. The dataframe must have the same
+// columns as the patch, though they may be in a different order, and the
+// result has the columns in the order of df. It returns an error if the
+// key of a row to be deleted or updated is not in df or if the key of a
+// row to be inserted already is.
+func (p *Patch) Apply(df *DF) (*DF, error) {
+	pIdx, err := df.matchCols(p.updates, dfEqualOpts{ignoreColOrder: true})
+	if err != nil {
+		return nil, dfWrapf(err, "the patch doesn't match the dataframe")
+	}
+	keyIdx, _ := df.mci.colIdx(p.keyCol)
+	pKeyIdx := pIdx[keyIdx]
+
+	dfRows, err := keyRows(df, keyIdx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := patchKeys(p.deletes, 0, dfRows, "deleted")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := patchKeys(p.updates, pKeyIdx, dfRows, "updated")
+	if err != nil {
+		return nil, err
+	}
+	_, err = patchKeys(p.inserts, pKeyIdx, dfRows, "inserted")
+	if err != nil {
+		return nil, err
+	}
+
+	rval := df.Clone()
+	for r := 0; r < df.RowCount(); r++ {
+		k := df.keyAt(keyIdx, r)
+		if _, ok := deleted[k]; ok {
+			continue
+		}
+		if ur, ok := updated[k]; ok {
+			if err := rval.appendMappedRow(p.updates, pIdx, ur); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rval.copyRowFrom(df, r)
+	}
+	for r := 0; r < p.inserts.RowCount(); r++ {
+		if err := rval.appendMappedRow(p.inserts, pIdx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	return rval, nil
+}
@@ -0,0 +1,141 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestPatch(t *testing.T) {
+	mk := func(content string) *dataframe.DF {
+		return mkTestDF(t, content,
+			dataframe.HasHeader, dataframe.AllowErrors,
+			dataframe.DFRColTypes(dataframe.ColTypeString,
+				dataframe.ColTypeFloat, dataframe.ColTypeString))
+	}
+	a := mk("id f s\nk1 1 a\nk2 2 b\nk3 3 c\n")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		b          *dataframe.DF
+		opts       []dataframe.DiffOpt
+		target     *dataframe.DF
+		expEmpty   bool
+		expInserts [][]string
+		expDeletes [][]string
+		expUpdates [][]string
+		expVals    [][]string
+	}{
+		{
+			ID:         testhelper.MkID("no differences"),
+			b:          mk("s f id\nc 3 k3\na 1 k1\nb 2 k2\n"),
+			expEmpty:   true,
+			expInserts: [][]string{},
+			expDeletes: [][]string{},
+			expUpdates: [][]string{},
+			expVals: [][]string{
+				{"k1", "1", "a"},
+				{"k2", "2", "b"},
+				{"k3", "3", "c"},
+			},
+		},
+		{
+			ID:         testhelper.MkID("insert, delete, update"),
+			b:          mk("s f id\nb 2.5 k2\nc 3 k3\nd NA k4\n"),
+			expInserts: [][]string{{"k4", "NA", "d"}},
+			expDeletes: [][]string{{"k1"}},
+			expUpdates: [][]string{{"k2", "2.5", "b"}},
+			expVals: [][]string{
+				{"k2", "2.5", "b"},
+				{"k3", "3", "c"},
+				{"k4", "NA", "d"},
+			},
+		},
+		{
+			ID: testhelper.MkID("applied to another dataframe"),
+			b:  mk("s f id\nb 2.5 k2\nc 3 k3\nd NA k4\n"),
+			target: mk("s f id\n" +
+				"z 9 k9\nc 3 k3\nb 2 k2\na 1 k1\n"),
+			expInserts: [][]string{{"k4", "NA", "d"}},
+			expDeletes: [][]string{{"k1"}},
+			expUpdates: [][]string{{"k2", "2.5", "b"}},
+			expVals: [][]string{
+				{"z", "9", "k9"},
+				{"c", "3", "k3"},
+				{"b", "2.5", "k2"},
+				{"d", "NA", "k4"},
+			},
+		},
+		{
+			ID: testhelper.MkID("within tolerance"),
+			b:  mk("id f s\nk1 1 a\nk2 2.5 b\nk3 3 c\n"),
+			opts: []dataframe.DiffOpt{
+				dataframe.DFDEqualOpts(dataframe.DFEAbsTol(1)),
+			},
+			expEmpty:   true,
+			expInserts: [][]string{},
+			expDeletes: [][]string{},
+			expUpdates: [][]string{},
+			expVals: [][]string{
+				{"k1", "1", "a"},
+				{"k2", "2", "b"},
+				{"k3", "3", "c"},
+			},
+		},
+		{
+			ID:         testhelper.MkID("bad: deleted row missing"),
+			b:          mk("id f s\nk2 2 b\nk3 3 c\n"),
+			target:     mk("id f s\nk2 2 b\nk3 3 c\n"),
+			expInserts: [][]string{},
+			expDeletes: [][]string{{"k1"}},
+			expUpdates: [][]string{},
+			ExpErr: testhelper.MkExpErr(
+				"there is no row with the key k1 to be deleted"),
+		},
+		{
+			ID:         testhelper.MkID("bad: inserted row present"),
+			b:          mk("id f s\nk1 1 a\nk2 2 b\nk3 3 c\nk4 4 d\n"),
+			target:     mk("id f s\nk4 4 d\n"),
+			expInserts: [][]string{{"k4", "4", "d"}},
+			expDeletes: [][]string{},
+			expUpdates: [][]string{},
+			ExpErr: testhelper.MkExpErr(
+				"the key k4 of a row to be inserted is already in use"),
+		},
+		{
+			ID:     testhelper.MkID("bad: repeated key"),
+			b:      mk("id f s\nk1 1 a\nk1 2 b\n"),
+			ExpErr: testhelper.MkExpErr("the second dataframe", "repeated"),
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DiffOpt{dataframe.DFDKeyCol("id")},
+			tc.opts...)
+		p, err := dataframe.MakePatch(a, tc.b, opts...)
+		if err == nil {
+			testhelper.DiffBool(t, tc.IDStr(), "IsEmpty",
+				p.IsEmpty(), tc.expEmpty)
+			checkDFVals(t, tc.IDStr()+": inserts", p.Inserts(), tc.expInserts)
+			checkDFVals(t, tc.IDStr()+": deletes", p.Deletes(), tc.expDeletes)
+			checkDFVals(t, tc.IDStr()+": updates", p.Updates(), tc.expUpdates)
+
+			target := a
+			if tc.target != nil {
+				target = tc.target
+			}
+			var rval *dataframe.DF
+			rval, err = p.Apply(target)
+			if err == nil {
+				checkDFVals(t, tc.IDStr(), rval, tc.expVals)
+			}
+		}
+		testhelper.CheckExpErr(t, err, tc)
+	}
+
+	_, err := dataframe.MakePatch(a, a)
+	testhelper.CheckExpErrWithID(t, "no key column", err,
+		testhelper.MkExpErr("no key column has been given"))
+}
@@ -0,0 +1,66 @@
+package dataframe
+
+import "strings"
+
+// DFRPercentages returns a function which will cause the DFReader to read
+// values given as percentages, such as "45%" or "3.5 %", as float values,
+// 0.45 and 0.035 in this example. The percentages are only recognised in
+// the named columns or in every column if no names are given; it may be
+// given more than once to add further columns. A column holding
+// percentages is guessed to be a float column. Values without a trailing
+// '%' are read as usual so "45" is still 45 rather than 0.45.
+//
+// Note that a value with a space before the '%' will be split into two
+// fields by the default field separator; a different separator must be
+// given (see SplitPattern) for such values to be read.
+func DFRPercentages(names ...string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if len(names) == 0 {
+			dfr.parseOpts.pctAll = true
+			return nil
+		}
+
+		pctCols := make(map[string]bool,
+			len(dfr.parseOpts.pctCols)+len(names))
+		for name := range dfr.parseOpts.pctCols {
+			pctCols[name] = true
+		}
+		for _, name := range names {
+			if name == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"the percentage column name must not be empty")
+			}
+			pctCols[name] = true
+		}
+		dfr.parseOpts.pctCols = pctCols
+		return nil
+	}
+}
+
+// isPctCol returns true if percentages are allowed in the named column
+func (po parseOpts) isPctCol(name string) bool {
+	if po.pctAll {
+		return true
+	}
+	_, ok := lookupName(po.pctCols, name, po.foldCase)
+	return ok
+}
+
+// parseFloat parses the value for the named column as a float64 according
+// to the number format, allowing the value to be a percentage if
+// percentages are allowed in the column and removing the currency symbol
+// and digit grouping if it is a currency column
+func (po parseOpts) parseFloat(name, s string) (float64, error) {
+	if cf, ok := lookupName(po.currency, name, po.foldCase); ok {
+		s = cf.strip(s)
+	}
+	if !po.isPctCol(name) || !strings.HasSuffix(s, "%") {
+		return po.numFmt.ParseFloat(s)
+	}
+
+	f, err := po.numFmt.ParseFloat(strings.TrimSpace(s[:len(s)-1]))
+	if err != nil {
+		return 0, err
+	}
+	return f / 100, nil
+}
@@ -0,0 +1,112 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRPercentages(t *testing.T) {
+	const content = `region,share,growth,count
+north,45%,3.5 %,10
+south,55%,-1%,20
+east,12.5%,40,30
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("no percentages"),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("share", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("growth", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("count", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"north", "45%", "3.5 %", "10"},
+				{"south", "55%", "-1%", "20"},
+				{"east", "12.5%", "40", "30"},
+			},
+		},
+		{
+			ID: testhelper.MkID("all columns"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRPercentages(),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("share", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("growth", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("count", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"north", "0.45", "0.035", "10"},
+				{"south", "0.55", "-0.01", "20"},
+				{"east", "0.125", "40", "30"},
+			},
+		},
+		{
+			ID: testhelper.MkID("one column"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRPercentages("growth"),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("share", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("growth", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("count", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"north", "45%", "0.035", "10"},
+				{"south", "55%", "-0.01", "20"},
+				{"east", "12.5%", "40", "30"},
+			},
+		},
+		{
+			ID: testhelper.MkID("given more than once"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRPercentages("growth"),
+				dataframe.DFRPercentages("share"),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("region", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("share", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("growth", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("count", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"north", "0.45", "0.035", "10"},
+				{"south", "0.55", "-0.01", "20"},
+				{"east", "0.125", "40", "30"},
+			},
+		},
+		{
+			ID: testhelper.MkID("empty name"),
+			ExpErr: testhelper.MkExpErr(
+				"the percentage column name must not be empty"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRPercentages("growth", ""),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DFReaderOpt{
+			dataframe.HasHeader,
+			dataframe.SplitPattern(","),
+		}, tc.opts...)
+		_, err := dataframe.NewDFReader(opts...)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+		df := mkTestDF(t, content, opts...)
+		checkColDetails(t, tc.IDStr(), df, tc.expCols)
+		checkDFVals(t, tc.IDStr(), df, tc.expVals)
+	}
+}
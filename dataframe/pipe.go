@@ -0,0 +1,102 @@
+package dataframe
+
+// Pipe allows a sequence of operations to be performed on a dataframe by
+// chaining method calls, for instance:
+//
+//	df, err := df.Pipe().Filter(f).Select("a", "b").Sort(k).Result()
+//
+// Each operation is performed on the result of the previous one. If an
+// operation fails the error is recorded and all subsequent operations are
+// skipped; the first error is returned by Result. Unlike a LazyDF, each
+// operation is performed immediately.
+type Pipe struct {
+	df   *DF
+	err  error
+	step int
+}
+
+// Pipe returns a Pipe with the dataframe as the starting point for the
+// operations
+func (df *DF) Pipe() *Pipe {
+	return &Pipe{df: df}
+}
+
+// do performs the operation unless an earlier operation has failed. If the
+// operation fails the error is recorded with the step number and name
+func (p *Pipe) do(name string, op func(df *DF) (*DF, error)) *Pipe {
+	p.step++
+	if p.err != nil {
+		return p
+	}
+
+	df, err := op(p.df)
+	if err != nil {
+		p.err = dfWrapf(err, "pipe step %d (%s)", p.step, name)
+		return p
+	}
+	p.df = df
+
+	return p
+}
+
+// Filter performs a DF.Filter operation
+func (p *Pipe) Filter(keep RowFilter) *Pipe {
+	return p.do("Filter", func(df *DF) (*DF, error) {
+		return df.Filter(keep), nil
+	})
+}
+
+// Select performs a DF.Select operation
+func (p *Pipe) Select(names ...string) *Pipe {
+	return p.do("Select", func(df *DF) (*DF, error) {
+		return df.Select(names...)
+	})
+}
+
+// Mutate performs a DF.Mutate operation
+func (p *Pipe) Mutate(name string, colType ColType, f RowFunc) *Pipe {
+	return p.do("Mutate", func(df *DF) (*DF, error) {
+		return df.Mutate(name, colType, f)
+	})
+}
+
+// Sort performs a DF.Sort operation
+func (p *Pipe) Sort(keys ...SortKey) *Pipe {
+	return p.do("Sort", func(df *DF) (*DF, error) {
+		return df.Sort(keys...)
+	})
+}
+
+// GroupBy performs a DF.GroupBy operation followed by a GroupedDF.Agg
+// operation
+func (p *Pipe) GroupBy(key string, aggs ...Agg) *Pipe {
+	return p.do("GroupBy", func(df *DF) (*DF, error) {
+		gdf, err := df.GroupBy(key)
+		if err != nil {
+			return nil, err
+		}
+		return gdf.Agg(aggs...)
+	})
+}
+
+// Apply performs the operation given by the function. This allows
+// operations not directly supported by the Pipe to be included in the
+// chain.
+func (p *Pipe) Apply(name string, op func(df *DF) (*DF, error)) *Pipe {
+	return p.do(name, op)
+}
+
+// Err returns the first error seen, if any
+func (p *Pipe) Err() error {
+	return p.err
+}
+
+// Result returns the dataframe produced by the chain of operations. If any
+// operation failed the dataframe will be nil and the first error will be
+// returned.
+func (p *Pipe) Result() (*DF, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.df, nil
+}
@@ -0,0 +1,151 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSort(t *testing.T) {
+	df := mkTestDF(t, lazyTestData,
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(
+			dataframe.ColTypeString,
+			dataframe.ColTypeInt,
+			dataframe.ColTypeFloat),
+		dataframe.AllowErrors)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		keys    []dataframe.SortKey
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("one key, ascending, NA last"),
+			keys: []dataframe.SortKey{
+				{Col: "qty"},
+			},
+			expVals: [][]string{
+				{"abc", "10", "1.5"},
+				{"xyz", "20", "2.5"},
+				{"abc", "30", "3.5"},
+				{"xyz", "40", "5.5"},
+				{"def", "NA", "4.5"},
+			},
+		},
+		{
+			ID: testhelper.MkID("one key, descending, NA last"),
+			keys: []dataframe.SortKey{
+				{Col: "qty", Desc: true},
+			},
+			expVals: [][]string{
+				{"xyz", "40", "5.5"},
+				{"abc", "30", "3.5"},
+				{"xyz", "20", "2.5"},
+				{"abc", "10", "1.5"},
+				{"def", "NA", "4.5"},
+			},
+		},
+		{
+			ID: testhelper.MkID("two keys"),
+			keys: []dataframe.SortKey{
+				{Col: "sym", Desc: true},
+				{Col: "price"},
+			},
+			expVals: [][]string{
+				{"xyz", "20", "2.5"},
+				{"xyz", "40", "5.5"},
+				{"def", "NA", "4.5"},
+				{"abc", "10", "1.5"},
+				{"abc", "30", "3.5"},
+			},
+		},
+		{
+			ID:     testhelper.MkID("no keys"),
+			ExpErr: testhelper.MkExpErr("no sort keys have been given"),
+		},
+		{
+			ID:     testhelper.MkID("bad key"),
+			keys:   []dataframe.SortKey{{Col: "nonesuch"}},
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		sdf, err := df.Sort(tc.keys...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkDFVals(t, tc.IDStr(), sdf, tc.expVals)
+		}
+	}
+
+	nanDF := mkTestDF(t, "a\n5.5\nNaN\n3.5\n1.5\nNA\nNaN\n4.5\n2.5\n0.5\n",
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(dataframe.ColTypeFloat),
+		dataframe.AllowErrors)
+	for _, k := range []dataframe.SortKey{{Col: "a"}, {Col: "a", Desc: true}} {
+		id := fmt.Sprintf("NaN values, descending: %t", k.Desc)
+		exp := []string{"0.5", "1.5", "2.5", "3.5", "4.5", "5.5"}
+		if k.Desc {
+			for i, j := 0, len(exp)-1; i < j; i, j = i+1, j-1 {
+				exp[i], exp[j] = exp[j], exp[i]
+			}
+		}
+		exp = append(exp, "NaN", "NaN", "NA")
+
+		sdf, err := nanDF.Sort(k)
+		if err != nil {
+			t.Fatal(id, ": unexpected error: ", err)
+		}
+		vals := make([][]string, 0, len(exp))
+		for _, v := range exp {
+			vals = append(vals, []string{v})
+		}
+		checkDFVals(t, id, sdf, vals)
+	}
+}
+
+func TestPipe(t *testing.T) {
+	df := mkTestDF(t, lazyTestData,
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(
+			dataframe.ColTypeString,
+			dataframe.ColTypeInt,
+			dataframe.ColTypeFloat),
+		dataframe.AllowErrors)
+
+	res, err := df.Pipe().
+		Filter(bigQty).
+		Mutate("cost", dataframe.ColTypeFloat, cost).
+		Select("sym", "cost").
+		Sort(dataframe.SortKey{Col: "cost", Desc: true}).
+		Result()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	checkDFVals(t, "good pipe", res, [][]string{
+		{"xyz", "220"},
+		{"abc", "105"},
+		{"xyz", "50"},
+	})
+
+	called := false
+	p := df.Pipe().
+		Select("sym", "nonesuch").
+		Apply("check", func(df *dataframe.DF) (*dataframe.DF, error) {
+			called = true
+			return df, nil
+		})
+	_, err = p.Result()
+	testhelper.CheckExpErrWithID(t, "bad pipe", err,
+		testhelper.MkExpErr(
+			`pipe step 1 (Select): Unknown column name: "nonesuch"`))
+	if p.Err() != err {
+		t.Error("the Err and Result methods give different errors")
+	}
+	if called {
+		t.Error("a step after the failure has been performed")
+	}
+}
@@ -0,0 +1,228 @@
+package dataframe
+
+import "strconv"
+
+// TypePromotion determines whether the DFReader changes the type of a
+// column when it finds a value that cannot be read as that type, such as a
+// value with a decimal point in an int column. The column types are
+// normally guessed from the initial lines and this allows a long file with
+// such values appearing late on to be read without errors.
+type TypePromotion uint
+
+// PromoteNone leaves the column types unchanged, a value that cannot be
+// read being reported as an error and given an NA value. This is the
+// default.
+//
+// PromoteToFloat changes an int column to a float column when a value can
+// be read as a float.
+//
+// PromoteToString changes an int column to a float column as for
+// PromoteToFloat and, as a last resort, changes a column of any type to a
+// string column when a value cannot otherwise be read. The values already
+// read are replaced by their string forms, as given by their String
+// methods, so they may differ from the original text.
+//
+// TypePromotionMaxVal is a guard value used to ensure validity
+const (
+	PromoteNone TypePromotion = iota
+	PromoteToFloat
+	PromoteToString
+	TypePromotionMaxVal
+)
+
+// String returns the name of the promotion
+func (p TypePromotion) String() string {
+	switch p {
+	case PromoteNone:
+		return "PromoteNone"
+	case PromoteToFloat:
+		return "PromoteToFloat"
+	case PromoteToString:
+		return "PromoteToString"
+	}
+	return "TypePromotion(" + strconv.FormatUint(uint64(p), 10) + ")"
+}
+
+// DFRPromoteTypes returns a function which will cause the DFReader to
+// change the types of columns as it reads the data according to the
+// TypePromotion. This applies to columns whose types are given as well as
+// to those whose types are guessed. Note that empty fields are handled
+// according to the EmptyFieldPolicy (see DFREmptyFields) before any
+// promotion is considered.
+func DFRPromoteTypes(p TypePromotion) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if p >= TypePromotionMaxVal {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"bad type promotion: %s", p)
+		}
+		dfr.parseOpts.promotion = p
+		return nil
+	}
+}
+
+// dropRLEKey returns the map of compressed values without the entry for
+// the value index vi and with the indexes above it reduced by one
+func dropRLEKey[T comparable](m map[int]*rleVals[T], vi int,
+) map[int]*rleVals[T] {
+	if len(m) == 0 {
+		return m
+	}
+
+	rval := make(map[int]*rleVals[T], len(m))
+	for k, v := range m {
+		switch {
+		case k < vi:
+			rval[k] = v
+		case k > vi:
+			rval[k-1] = v
+		}
+	}
+	return rval
+}
+
+// insertRLEKey returns the map of compressed values with the indexes at or
+// above the value index vi increased by one, leaving vi free
+func insertRLEKey[T comparable](m map[int]*rleVals[T], vi int,
+) map[int]*rleVals[T] {
+	if len(m) == 0 {
+		return m
+	}
+
+	rval := make(map[int]*rleVals[T], len(m))
+	for k, v := range m {
+		if k >= vi {
+			k++
+		}
+		rval[k] = v
+	}
+	return rval
+}
+
+// insertVals returns the slices of values with an empty slice inserted at
+// index vi
+func insertVals[T any](vals [][]T, vi int) [][]T {
+	vals = append(vals, nil)
+	copy(vals[vi+1:], vals[vi:])
+	vals[vi] = make([]T, 0)
+	return vals
+}
+
+// dropColVals removes the values of the indexed column from the
+// dataframe, adjusting the value indexes of any later columns of the same
+// type
+func (df *DF) dropColVals(colIdx int) {
+	vi := df.mci.valIdx[colIdx]
+	colType := df.mci.info[colIdx].colType
+
+	switch colType {
+	case ColTypeBool:
+		df.boolCols = append(df.boolCols[:vi], df.boolCols[vi+1:]...)
+		df.rleBoolCols = dropRLEKey(df.rleBoolCols, vi)
+	case ColTypeInt:
+		df.intCols = append(df.intCols[:vi], df.intCols[vi+1:]...)
+	case ColTypeFloat:
+		df.floatCols = append(df.floatCols[:vi], df.floatCols[vi+1:]...)
+	case ColTypeString:
+		df.stringCols = append(df.stringCols[:vi], df.stringCols[vi+1:]...)
+		df.rleStringCols = dropRLEKey(df.rleStringCols, vi)
+	}
+
+	for i := colIdx + 1; i < len(df.mci.info); i++ {
+		if df.mci.info[i].colType == colType {
+			df.mci.valIdx[i]--
+		}
+	}
+}
+
+// insertColVals adds an empty slice of values for the indexed column,
+// adjusting the value indexes of any later columns of the same type. The
+// values of the columns of each type are kept in column order.
+func (df *DF) insertColVals(colIdx int) {
+	colType := df.mci.info[colIdx].colType
+
+	vi := 0
+	for i := 0; i < colIdx; i++ {
+		if df.mci.info[i].colType == colType {
+			vi++
+		}
+	}
+	for i := colIdx + 1; i < len(df.mci.info); i++ {
+		if df.mci.info[i].colType == colType {
+			df.mci.valIdx[i]++
+		}
+	}
+	df.mci.valIdx[colIdx] = vi
+
+	switch colType {
+	case ColTypeBool:
+		df.boolCols = insertVals(df.boolCols, vi)
+		df.rleBoolCols = insertRLEKey(df.rleBoolCols, vi)
+	case ColTypeInt:
+		df.intCols = insertVals(df.intCols, vi)
+	case ColTypeFloat:
+		df.floatCols = insertVals(df.floatCols, vi)
+	case ColTypeString:
+		df.stringCols = insertVals(df.stringCols, vi)
+		df.rleStringCols = insertRLEKey(df.rleStringCols, vi)
+	}
+}
+
+// promoteCol changes the type of the indexed column, keeping just the
+// first n values converted to the new type. Any index on the column is
+// removed. It returns an error if any value cannot be converted, in which
+// case the column is unchanged.
+func (df *DF) promoteCol(colIdx int, to ColType, n int) error {
+	vals := make([]any, 0, n)
+	for r := 0; r < n; r++ {
+		v, _ := df.valAt(colIdx, r)
+		cv, err := convertVal(v, to)
+		if err != nil {
+			return err
+		}
+		vals = append(vals, cv)
+	}
+
+	df.dropColVals(colIdx)
+	df.mci.info[colIdx].colType = to
+	df.insertColVals(colIdx)
+	for _, v := range vals {
+		if err := df.appendVal(colIdx, v); err != nil {
+			return err
+		}
+	}
+	delete(df.indexes, colIdx)
+	if _, ok := df.summaries[colIdx]; ok {
+		df.summaries[colIdx] = newColSummary(df, colIdx)
+	}
+
+	return nil
+}
+
+// promoteText is called when the text could not be read as a value of the
+// indexed column, giving the error, and an NA value has been added in its
+// place. It changes the type of the column, according to the
+// TypePromotion, to one that the text can be read as and replaces the NA
+// value with the value read. If the column cannot be promoted the error is
+// returned unchanged.
+func (df *DF) promoteText(po parseOpts, colIdx int, s string, err error,
+) error {
+	n := df.colLen(colIdx) - 1
+	ci := df.mci.info[colIdx]
+
+	if ci.colType == ColTypeInt {
+		if _, fErr := po.parseFloat(ci.name, s); fErr == nil {
+			if err := df.promoteCol(colIdx, ColTypeFloat, n); err != nil {
+				return err
+			}
+			return df.appendText(po, colIdx, s)
+		}
+	}
+
+	if po.promotion != PromoteToString || ci.colType == ColTypeString {
+		return err
+	}
+	if err := df.promoteCol(colIdx, ColTypeString, n); err != nil {
+		return err
+	}
+	return df.appendText(po, colIdx, s)
+}
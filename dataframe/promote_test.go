@@ -0,0 +1,135 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRPromoteTypes(t *testing.T) {
+	const content = `a b c d
+1 10 x true
+2 20 y false
+3.5 30 z true
+4 40 w maybe
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		promotion dataframe.TypePromotion
+		expCols   []dataframe.ColInfo
+		expVals   [][]string
+		expErrCnt int64
+	}{
+		{
+			ID:        testhelper.MkID("no promotion"),
+			promotion: dataframe.PromoteNone,
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("d", dataframe.ColTypeBool),
+			},
+			expVals: [][]string{
+				{"1", "10", "x", "true"},
+				{"2", "20", "y", "false"},
+				{"NA", "30", "z", "true"},
+				{"4", "40", "w", "NA"},
+			},
+			expErrCnt: 2,
+		},
+		{
+			ID:        testhelper.MkID("promote to float"),
+			promotion: dataframe.PromoteToFloat,
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("d", dataframe.ColTypeBool),
+			},
+			expVals: [][]string{
+				{"1", "10", "x", "true"},
+				{"2", "20", "y", "false"},
+				{"3.5", "30", "z", "true"},
+				{"4", "40", "w", "NA"},
+			},
+			expErrCnt: 1,
+		},
+		{
+			ID:        testhelper.MkID("promote to string"),
+			promotion: dataframe.PromoteToString,
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("d", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"1", "10", "x", "true"},
+				{"2", "20", "y", "false"},
+				{"3.5", "30", "z", "true"},
+				{"4", "40", "w", "maybe"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		df := mkTestDF(t, content,
+			dataframe.HasHeader,
+			dataframe.AllowErrors,
+			dataframe.InitialLines(1),
+			dataframe.DFRPromoteTypes(tc.promotion))
+		checkColDetails(t, tc.IDStr(), df, tc.expCols)
+		checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		testhelper.DiffInt(t, tc.IDStr(), "error count",
+			df.ErrCount(), tc.expErrCnt)
+	}
+
+	_, err := dataframe.NewDFReader(
+		dataframe.DFRPromoteTypes(dataframe.TypePromotionMaxVal))
+	testhelper.CheckExpErrWithID(t, "bad promotion", err,
+		testhelper.MkExpErr("bad type promotion: TypePromotion(3)"))
+}
+
+func TestDFRPromoteTypesToString(t *testing.T) {
+	const content = `a b c
+1 2 3
+1.5 x 4
+abc 5 6
+`
+	id := "int to float to string"
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.InitialLines(1),
+		dataframe.DFRPromoteTypes(dataframe.PromoteToString))
+	checkColDetails(t, id, df, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+		dataframe.MustNewColInfo("b", dataframe.ColTypeString),
+		dataframe.MustNewColInfo("c", dataframe.ColTypeInt),
+	})
+	checkDFVals(t, id, df, [][]string{
+		{"1", "2", "3"},
+		{"1.5", "x", "4"},
+		{"abc", "5", "6"},
+	})
+
+	s, err := df.StringColByName("b")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	testhelper.DiffInt(t, id, "string column length", len(s), 3)
+	c, err := df.IntColByName("c")
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	testhelper.DiffInt(t, id, "int column length", len(c), 3)
+	testhelper.DiffString(t, id, "first value of c", c[0].String(), "3")
+
+	var chunks int
+	df.Chunks(2)(func(chunk *dataframe.DF) bool {
+		chunks++
+		return true
+	})
+	testhelper.DiffInt(t, id, "chunks", chunks, 2)
+}
@@ -0,0 +1,216 @@
+package dataframe
+
+import (
+	"math"
+	"sort"
+)
+
+// quantileDefaultCompression is the compression used by NewQuantileAcc if
+// none is given
+const quantileDefaultCompression = 100
+
+// centroid holds the mean of a number of values and how many there are
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// QuantileAcc accumulates values so that approximate quantiles (medians,
+// percentiles etc) of them can be found without holding all the values. It
+// uses a t-digest which summarises the values as a limited number of
+// clusters, kept smaller near the extremes so that the tails are more
+// accurate than the middle. The memory needed depends only on the
+// compression, not on how many values are added.
+//
+// It can be used to find the quantiles of a file too large to read at
+// once by adding the values of each batch in turn, as given by the Store
+// Scan method or DF Chunks. Accumulators filled separately, for instance by
+// different goroutines, can be combined with Merge.
+type QuantileAcc struct {
+	compression float64
+	centroids   []centroid
+	buf         []float64
+	count       int64
+	min, max    float64
+}
+
+// NewQuantileAcc returns a new, empty, QuantileAcc. The compression
+// controls the trade-off between the accuracy and the memory used; the
+// number of clusters kept is of the order of the compression. A
+// compression of 100 typically gives quantiles accurate to within a small
+// fraction of a percent and is used if the value given is less than 1.
+func NewQuantileAcc(compression float64) *QuantileAcc {
+	if !(compression >= 1) {
+		compression = quantileDefaultCompression
+	}
+	return &QuantileAcc{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add adds the value to the accumulator. NaN values are ignored.
+func (qa *QuantileAcc) Add(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+	if v < qa.min {
+		qa.min = v
+	}
+	if v > qa.max {
+		qa.max = v
+	}
+	qa.count++
+	qa.buf = append(qa.buf, v)
+	if float64(len(qa.buf)) >= 5*qa.compression {
+		qa.flush()
+	}
+}
+
+// AddCol adds the non-NA values in the named column of the dataframe to
+// the accumulator. It returns an error if there is no such column or if it
+// is not an int or a float column.
+func (qa *QuantileAcc) AddCol(df *DF, name string) error {
+	i, ok := df.mci.colIdx(name)
+	if !ok {
+		return errUnknownColName(name)
+	}
+
+	vi := df.mci.valIdx[i]
+	switch ct := df.mci.info[i].colType; ct {
+	case ColTypeInt:
+		for _, v := range df.intCols[vi] {
+			if !v.IsNA {
+				qa.Add(float64(v.Val))
+			}
+		}
+	case ColTypeFloat:
+		for _, v := range df.floatCols[vi] {
+			if !v.IsNA {
+				qa.Add(v.Val)
+			}
+		}
+	default:
+		return dfKindErrorf(ErrTypeMismatch,
+			"the quantiles of a %q column cannot be calculated", ct)
+	}
+	return nil
+}
+
+// Merge adds the values accumulated by other to qa. The other accumulator
+// is unchanged.
+func (qa *QuantileAcc) Merge(other *QuantileAcc) {
+	if other.count == 0 {
+		return
+	}
+	if other.min < qa.min {
+		qa.min = other.min
+	}
+	if other.max > qa.max {
+		qa.max = other.max
+	}
+	qa.count += other.count
+	qa.centroids = append(qa.centroids, other.centroids...)
+	for _, v := range other.buf {
+		qa.centroids = append(qa.centroids, centroid{mean: v, weight: 1})
+	}
+	qa.flush()
+}
+
+// Count returns the number of values added to the accumulator
+func (qa *QuantileAcc) Count() int64 {
+	return qa.count
+}
+
+// scale returns the value of the t-digest scale function for the quantile
+func (qa *QuantileAcc) scale(q float64) float64 {
+	return qa.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// scaleInv returns the quantile for the value of the scale function
+func (qa *QuantileAcc) scaleInv(k float64) float64 {
+	a := k * 2 * math.Pi / qa.compression
+	if a >= math.Pi/2 {
+		return 1
+	}
+	return (math.Sin(a) + 1) / 2
+}
+
+// flush merges the buffered values into the clusters, combining
+// neighbouring clusters while they are small enough for their position
+func (qa *QuantileAcc) flush() {
+	all := qa.centroids
+	for _, v := range qa.buf {
+		all = append(all, centroid{mean: v, weight: 1})
+	}
+	qa.buf = qa.buf[:0]
+	if len(all) == 0 {
+		return
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	var total float64
+	for _, c := range all {
+		total += c.weight
+	}
+
+	merged := make([]centroid, 0, len(qa.centroids)+1)
+	cur := all[0]
+	var soFar float64
+	limit := total * qa.scaleInv(qa.scale(0)+1)
+	for _, c := range all[1:] {
+		if soFar+cur.weight+c.weight <= limit {
+			cur.mean += (c.mean - cur.mean) * c.weight / (cur.weight + c.weight)
+			cur.weight += c.weight
+			continue
+		}
+		soFar += cur.weight
+		merged = append(merged, cur)
+		limit = total * qa.scaleInv(qa.scale(soFar/total)+1)
+		cur = c
+	}
+	qa.centroids = append(merged, cur)
+}
+
+// Quantile returns the approximate value below which the fraction q of the
+// values lie, so for instance Quantile(0.5) gives the median. A q of 0
+// gives the smallest value and 1 the largest. The value is NA if no values
+// have been added. It returns an error if q is not between 0 and 1.
+func (qa *QuantileAcc) Quantile(q float64) (FloatVal, error) {
+	if !(q >= 0 && q <= 1) {
+		return FloatVal{IsNA: true},
+			dfErrorf("the quantile must be between 0 and 1: %g", q)
+	}
+	if qa.count == 0 {
+		return FloatVal{IsNA: true}, nil
+	}
+	qa.flush()
+
+	switch q {
+	case 0:
+		return FloatVal{Val: qa.min}, nil
+	case 1:
+		return FloatVal{Val: qa.max}, nil
+	}
+
+	// each cluster's mean is taken to be at the middle of its weight and
+	// the value is interpolated between the neighbouring cluster means
+	// or, beyond the first and last, the smallest and largest values
+	target := q * float64(qa.count)
+	prevPos, prevVal := 0.0, qa.min
+	var soFar float64
+	for _, c := range qa.centroids {
+		pos := soFar + c.weight/2
+		if target < pos {
+			return FloatVal{
+				Val: prevVal + (c.mean-prevVal)*(target-prevPos)/(pos-prevPos),
+			}, nil
+		}
+		prevPos, prevVal = pos, c.mean
+		soFar += c.weight
+	}
+	return FloatVal{
+		Val: prevVal + (qa.max-prevVal)*(target-prevPos)/(soFar-prevPos),
+	}, nil
+}
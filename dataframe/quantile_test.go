@@ -0,0 +1,139 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestQuantileAcc(t *testing.T) {
+	// the values 1 to 10000 in a random order
+	var content strings.Builder
+	content.WriteString("n\n")
+	for _, v := range rand.New(rand.NewSource(1)).Perm(10000) {
+		fmt.Fprintln(&content, v+1)
+	}
+	big := mkTestDF(t, content.String(), dataframe.HasHeader)
+
+	chunked := dataframe.NewQuantileAcc(0)
+	big.Chunks(999)(func(c *dataframe.DF) bool {
+		if err := chunked.AddCol(c, "n"); err != nil {
+			t.Fatal("cannot add the chunk: ", err)
+		}
+		return true
+	})
+	merged := dataframe.NewQuantileAcc(0)
+	big.Chunks(2500)(func(c *dataframe.DF) bool {
+		acc := dataframe.NewQuantileAcc(0)
+		if err := acc.AddCol(c, "n"); err != nil {
+			t.Fatal("cannot add the chunk: ", err)
+		}
+		merged.Merge(acc)
+		return true
+	})
+	small := dataframe.NewQuantileAcc(0)
+	err := small.AddCol(
+		mkTestDF(t, "x\n3.5\nNA\n1.5\n2.5\n", dataframe.HasHeader,
+			dataframe.AllowErrors,
+			dataframe.DFRColTypes(dataframe.ColTypeFloat)),
+		"x")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot add the values: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		acc      *dataframe.QuantileAcc
+		q        float64
+		expVal   float64
+		tol      float64
+		expIsNA  bool
+		expCount int64
+	}{
+		{
+			ID:       testhelper.MkID("chunked, median"),
+			acc:      chunked,
+			q:        0.5,
+			expVal:   5000.5,
+			tol:      25,
+			expCount: 10000,
+		},
+		{
+			ID:       testhelper.MkID("chunked, 99th percentile"),
+			acc:      chunked,
+			q:        0.99,
+			expVal:   9900.5,
+			tol:      5,
+			expCount: 10000,
+		},
+		{
+			ID:       testhelper.MkID("chunked, max"),
+			acc:      chunked,
+			q:        1,
+			expVal:   10000,
+			expCount: 10000,
+		},
+		{
+			ID:       testhelper.MkID("merged, first percentile"),
+			acc:      merged,
+			q:        0.01,
+			expVal:   100.5,
+			tol:      5,
+			expCount: 10000,
+		},
+		{
+			ID:       testhelper.MkID("few values, median"),
+			acc:      small,
+			q:        0.5,
+			expVal:   2.5,
+			expCount: 3,
+		},
+		{
+			ID:       testhelper.MkID("few values, min"),
+			acc:      small,
+			q:        0,
+			expVal:   1.5,
+			expCount: 3,
+		},
+		{
+			ID:      testhelper.MkID("no values"),
+			acc:     dataframe.NewQuantileAcc(50),
+			q:       0.5,
+			expIsNA: true,
+		},
+		{
+			ID: testhelper.MkID("bad: quantile"),
+			ExpErr: testhelper.MkExpErr(
+				"the quantile must be between 0 and 1: 1.5"),
+			acc:      small,
+			q:        1.5,
+			expCount: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.acc.Quantile(tc.q)
+		testhelper.DiffInt(t, tc.IDStr(), "count", tc.acc.Count(), tc.expCount)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffBool(t, tc.IDStr(), "NA", v.IsNA, tc.expIsNA)
+			if !v.IsNA && math.Abs(v.Val-tc.expVal) > tc.tol {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: expected: %g (+/- %g), got: %g",
+					tc.expVal, tc.tol, v.Val)
+			}
+		}
+	}
+
+	err = small.AddCol(mkTestDF(t, "s\nabc\n", dataframe.HasHeader), "s")
+	testhelper.CheckExpErrWithID(t, "string column", err,
+		testhelper.MkExpErr(`the quantiles of a "String" column`))
+	err = small.AddCol(big, "nonesuch")
+	testhelper.CheckExpErrWithID(t, "no such column", err,
+		testhelper.MkExpErr(`"nonesuch"`))
+}
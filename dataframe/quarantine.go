@@ -0,0 +1,84 @@
+package dataframe
+
+// BadRowColText is the name of the column in the dataframe returned by
+// BadRows holding the text of the line. The other columns are named as for
+// ErrorsAsDF.
+const BadRowColText = "text"
+
+// DFRQuarantine will cause the DFReader to keep a copy of each line which
+// gives an error, such as a value that cannot be parsed or a line with the
+// wrong number of fields, in a separate dataframe which can be retrieved
+// from the dataframe read using BadRows. This allows the rejected lines to
+// be reported or corrected and read again. It can only be given with
+// AllowErrors.
+func DFRQuarantine(dfr *DFReader) error {
+	dfr.quarantine = true
+	return nil
+}
+
+// BadRows returns the dataframe of lines that gave errors when the
+// dataframe was read (see DFRQuarantine) or nil if the lines were not kept.
+// There is one row per line, even if the line gave several errors, and the
+// dataframe has the following columns:
+//
+//	source  (string) the name of the data source
+//	line    (int)    the line number in the source
+//	text    (string) the text of the line
+//	message (string) a description of the first problem with the line
+//
+// Note that the lines are given after any lines have been joined into a
+// single record, so the text may span several lines of the source. The
+// text of transposed data (see DFRTransposed) is the transposed row with
+// the fields separated by single spaces.
+func (df DF) BadRows() *DF {
+	return df.badRows
+}
+
+// startQuarantine prepares the dataframe to record the lines giving errors
+// if the DFReader is quarantining bad lines
+func (dfr *DFReader) startQuarantine(state *dfReadState, df *DF) error {
+	if !dfr.quarantine {
+		return nil
+	}
+
+	if df.badRows == nil {
+		var err error
+		df.badRows, err = newDFFromColInfo(
+			ColInfo{name: ErrColSource, colType: ColTypeString},
+			ColInfo{name: ErrColLine, colType: ColTypeInt},
+			ColInfo{name: BadRowColText, colType: ColTypeString},
+			ColInfo{name: ErrColMessage, colType: ColTypeString},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	df.errHook = state.recordLineErr
+	return nil
+}
+
+// recordLineErr records the error if it is the first for the current line
+func (state *dfReadState) recordLineErr(err error) {
+	if state.lineErr == nil {
+		state.lineErr = err
+	}
+}
+
+// quarantineLine adds the line to the dataframe of bad rows if any errors
+// have been recorded for it and then clears the recorded error
+func (state *dfReadState) quarantineLine(df *DF, text string, line int64) {
+	err := state.lineErr
+	if err == nil || df.badRows == nil {
+		return
+	}
+	state.lineErr = nil
+
+	msg := errText(err)
+	if pe, ok := err.(ParseError); ok {
+		msg = pe.Msg
+	}
+	_ = df.badRows.appendVal(0, state.loc.Source())
+	_ = df.badRows.appendVal(1, line)
+	_ = df.badRows.appendVal(2, text)
+	_ = df.badRows.appendVal(3, msg)
+}
@@ -0,0 +1,162 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRQuarantine(t *testing.T) {
+	const content = `a b
+1 x
+2 u
+two y
+
+3 z extra
+five v
+`
+	testCases := []struct {
+		testhelper.ID
+		opts       []dataframe.DFReaderOpt
+		expVals    [][]string
+		expBadRows [][]string
+	}{
+		{
+			ID: testhelper.MkID("cached lines"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.InitialLines(10),
+				dataframe.DFRColTypes(
+					dataframe.ColTypeInt, dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"1", "x"},
+				{"2", "u"},
+				{"NA", "y"},
+				{"NA", "v"},
+			},
+			expBadRows: [][]string{
+				{
+					"test data", "4", "two y",
+					`data row: 3 column: 0:` +
+						` strconv.ParseInt: parsing "two": invalid syntax`,
+				},
+				{"test data", "5", "", "unexpected blank line"},
+				{
+					"test data", "6", "3 z extra",
+					`the dataframe has 2 columns but this line has 3: ` +
+						` col 0: "3" col 1: "z" col 2: "extra"`,
+				},
+				{
+					"test data", "7", "five v",
+					`data row: 4 column: 0:` +
+						` strconv.ParseInt: parsing "five": invalid syntax`,
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("guessed types"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.InitialLines(1),
+				dataframe.SkipBlankLines,
+			},
+			expVals: [][]string{
+				{"1", "x"},
+				{"2", "u"},
+				{"NA", "y"},
+				{"NA", "v"},
+			},
+			expBadRows: [][]string{
+				{
+					"test data", "4", "two y",
+					`data row: 3 column: 0:` +
+						` strconv.ParseInt: parsing "two": invalid syntax`,
+				},
+				{
+					"test data", "6", "3 z extra",
+					`the dataframe has 2 columns but this line has 3: ` +
+						` col 0: "3" col 1: "z" col 2: "extra"`,
+				},
+				{
+					"test data", "7", "five v",
+					`data row: 4 column: 0:` +
+						` strconv.ParseInt: parsing "five": invalid syntax`,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		df := mkTestDF(t, content,
+			append([]dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.AllowErrors,
+				dataframe.DFRQuarantine,
+			}, tc.opts...)...)
+		checkDFVals(t, tc.IDStr(), df, tc.expVals)
+
+		bad := df.BadRows()
+		if bad == nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the bad rows should have been kept")
+			continue
+		}
+		checkDFVals(t, tc.IDStr()+": bad rows", bad, tc.expBadRows)
+	}
+
+	id := "not quarantined"
+	df := mkTestDF(t, content, dataframe.HasHeader, dataframe.AllowErrors)
+	if df.BadRows() != nil {
+		t.Log(id)
+		t.Errorf("\t: the bad rows should not have been kept")
+	}
+
+	_, err := dataframe.NewDFReader(dataframe.DFRQuarantine)
+	testhelper.CheckExpErrWithID(t, "errors not allowed", err,
+		testhelper.MkExpErr(
+			"bad lines can only be quarantined if errors are allowed"))
+}
+
+func TestDFRQuarantineTransposed(t *testing.T) {
+	const content = `n 1 2 3 x
+v 1.5 2.5 y 3.5
+`
+	id := "transposed"
+	df := mkTestDF(t, content,
+		dataframe.DFRTransposed,
+		dataframe.AllowErrors,
+		dataframe.DFRQuarantine,
+		dataframe.InitialLines(1))
+	checkDFVals(t, id, df, [][]string{
+		{"1", "1.5"},
+		{"2", "2.5"},
+		{"3", "NA"},
+		{"NA", "3.5"},
+	})
+	checkDFVals(t, id+": bad rows", df.BadRows(), [][]string{
+		{
+			"test data (transposed)", "4", "3 y",
+			`data row: 3 column: 1:` +
+				` strconv.ParseFloat: parsing "y": invalid syntax`,
+		},
+		{
+			"test data (transposed)", "5", "x 3.5",
+			`data row: 4 column: 0:` +
+				` strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+	})
+}
+
+func TestDFRQuarantineCached(t *testing.T) {
+	id := "errors in the cached lines"
+	df := mkTestDF(t, "a,b\n1,\n2,x\n,y\n",
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.DFRQuarantine,
+		dataframe.SplitPattern(","),
+		dataframe.DFREmptyFields(dataframe.EmptyIsError))
+	checkDFVals(t, id+": bad rows", df.BadRows(), [][]string{
+		{"test data", "2", "1,", "data row: 1 column: 1: the field is empty"},
+		{"test data", "4", ",y", "data row: 3 column: 0: the field is empty"},
+	})
+}
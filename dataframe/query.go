@@ -0,0 +1,713 @@
+package dataframe
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// qTokKind is the kind of a token in a query
+type qTokKind uint
+
+const (
+	qTokEOF qTokKind = iota
+	qTokIdent
+	qTokNum
+	qTokStr
+	qTokSym
+)
+
+// qToken is a token in a query. A quoted identifier is never a keyword
+type qToken struct {
+	kind   qTokKind
+	text   string
+	pos    int
+	quoted bool
+}
+
+// isKeyword returns true if the token is the given keyword, which must be
+// in upper case
+func (t qToken) isKeyword(kw string) bool {
+	return t.kind == qTokIdent && !t.quoted && strings.ToUpper(t.text) == kw
+}
+
+// qSymbols are the symbols recognised in a query, longest first
+var qSymbols = []string{
+	"<=", ">=", "!=", "<>", "=", "<", ">", "(", ")", ",", "*", "-", "+",
+	"/",
+}
+
+// tokeniseQuery splits the query into tokens. Identifiers may be quoted
+// with double quotes and strings are given in single quotes; a quote
+// character is included in either by doubling it. The what parameter
+// names the text being split ("query" or "expression") in any error.
+func tokeniseQuery(q, what string) ([]qToken, error) {
+	var toks []qToken
+	rs := []rune(q)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			s, n, ok := qQuoted(rs[i:])
+			if !ok {
+				return nil, dfErrorf(
+					"%s: position %d: no closing quote (%c)", what, i, r)
+			}
+			kind := qTokStr
+			if r == '"' {
+				kind = qTokIdent
+			}
+			toks = append(toks,
+				qToken{kind: kind, text: s, pos: i, quoted: true})
+			i += n
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(rs) &&
+				(unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) ||
+					rs[i] == '_') {
+				i++
+			}
+			toks = append(toks,
+				qToken{kind: qTokIdent, text: string(rs[start:i]), pos: start})
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(rs) &&
+				(unicode.IsDigit(rs[i]) || rs[i] == '.' ||
+					rs[i] == 'e' || rs[i] == 'E' ||
+					((rs[i] == '+' || rs[i] == '-') &&
+						(rs[i-1] == 'e' || rs[i-1] == 'E'))) {
+				i++
+			}
+			toks = append(toks,
+				qToken{kind: qTokNum, text: string(rs[start:i]), pos: start})
+		default:
+			sym := ""
+			for _, s := range qSymbols {
+				if strings.HasPrefix(string(rs[i:]), s) {
+					sym = s
+					break
+				}
+			}
+			if sym == "" {
+				return nil, dfErrorf("%s: position %d: unexpected %q",
+					what, i, r)
+			}
+			toks = append(toks, qToken{kind: qTokSym, text: sym, pos: i})
+			i += len([]rune(sym))
+		}
+	}
+
+	return append(toks, qToken{kind: qTokEOF, pos: len(rs)}), nil
+}
+
+// qQuoted returns the text of the quoted string at the start of rs, the
+// number of runes it occupies and true, or false if there is no closing
+// quote
+func qQuoted(rs []rune) (string, int, bool) {
+	quote := rs[0]
+	var b strings.Builder
+
+	for i := 1; i < len(rs); i++ {
+		if rs[i] != quote {
+			b.WriteRune(rs[i])
+			continue
+		}
+		if i+1 < len(rs) && rs[i+1] == quote {
+			b.WriteRune(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1, true
+	}
+
+	return "", 0, false
+}
+
+// qItem is an item in the SELECT list of a query. If isAgg is true the
+// item is an aggregation of the column (if any) and fnName is the name of
+// the aggregation function as given in the query
+type qItem struct {
+	col    string
+	isAgg  bool
+	fn     AggFunc
+	fnName string
+	alias  string
+}
+
+// name returns the name of the column in the result of the query
+func (it qItem) name() string {
+	switch {
+	case it.alias != "":
+		return it.alias
+	case !it.isAgg:
+		return it.col
+	case it.col == "":
+		return it.fnName + "(*)"
+	}
+	return it.fnName + "(" + it.col + ")"
+}
+
+// qOrderKey is an entry in the ORDER BY clause of a query, the column is
+// given either by name or by its position in the SELECT list
+type qOrderKey struct {
+	col  string
+	pos  int
+	desc bool
+}
+
+// qStmt is a parsed query
+type qStmt struct {
+	all     bool
+	items   []qItem
+	where   exprNode
+	groupBy []string
+	orderBy []qOrderKey
+	limit   int
+}
+
+// qAggFuncs maps the names of the aggregation functions to their AggFunc
+var qAggFuncs = map[string]AggFunc{
+	"COUNT": AggCount,
+	"SUM":   AggSum,
+	"AVG":   AggMean,
+	"MEAN":  AggMean,
+	"MIN":   AggMin,
+	"MAX":   AggMax,
+}
+
+// qParser holds the state of the parse of a query or an expression; what
+// names the text being parsed in any error
+type qParser struct {
+	toks []qToken
+	pos  int
+	what string
+}
+
+// peek returns the next token without consuming it
+func (p *qParser) peek() qToken {
+	return p.toks[p.pos]
+}
+
+// next consumes and returns the next token
+func (p *qParser) next() qToken {
+	t := p.toks[p.pos]
+	if t.kind != qTokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// errorf returns an error giving the position of the token
+func (p *qParser) errorf(t qToken, format string, args ...any) error {
+	return dfErrorf("%s: position %d: "+format,
+		append([]any{p.what, t.pos}, args...)...)
+}
+
+// unexpected returns an error reporting the token as unexpected
+func (p *qParser) unexpected(t qToken, want string) error {
+	if t.kind == qTokEOF {
+		return p.errorf(t, "expected %s, found the end of the %s",
+			want, p.what)
+	}
+	return p.errorf(t, "expected %s, found %q", want, t.text)
+}
+
+// acceptKeyword consumes the next token and returns true if it is the
+// keyword
+func (p *qParser) acceptKeyword(kw string) bool {
+	if p.peek().isKeyword(kw) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// acceptSym consumes the next token and returns true if it is the symbol
+func (p *qParser) acceptSym(sym string) bool {
+	if t := p.peek(); t.kind == qTokSym && t.text == sym {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// expectKeyword consumes the next token, returning an error if it is not
+// the keyword
+func (p *qParser) expectKeyword(kw string) error {
+	if t := p.next(); !t.isKeyword(kw) {
+		return p.unexpected(t, kw)
+	}
+	return nil
+}
+
+// expectSym consumes the next token, returning an error if it is not the
+// symbol
+func (p *qParser) expectSym(sym string) error {
+	if t := p.next(); t.kind != qTokSym || t.text != sym {
+		return p.unexpected(t, strconv.Quote(sym))
+	}
+	return nil
+}
+
+// ident consumes the next token and returns its text, returning an error
+// if it is not an identifier
+func (p *qParser) ident(what string) (string, error) {
+	t := p.next()
+	if t.kind != qTokIdent {
+		return "", p.unexpected(t, what)
+	}
+	return t.text, nil
+}
+
+// parseQuery parses the query into a qStmt
+func parseQuery(q string) (*qStmt, error) {
+	toks, err := tokeniseQuery(q, "query")
+	if err != nil {
+		return nil, err
+	}
+	p := &qParser{toks: toks, what: "query"}
+	stmt := &qStmt{limit: -1}
+
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	if err := p.parseItems(stmt); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	if _, err := p.ident("a table name"); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("WHERE") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("GROUP") {
+		if err := p.parseGroupBy(stmt); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("ORDER") {
+		if err := p.parseOrderBy(stmt); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		t := p.next()
+		n, err := strconv.Atoi(t.text)
+		if t.kind != qTokNum || err != nil || n < 0 {
+			return nil, p.unexpected(t, "a row count")
+		}
+		stmt.limit = n
+	}
+
+	if t := p.next(); t.kind != qTokEOF {
+		return nil, p.unexpected(t, "the end of the query")
+	}
+
+	return stmt, nil
+}
+
+// parseItems parses the SELECT list
+func (p *qParser) parseItems(stmt *qStmt) error {
+	if p.acceptSym("*") {
+		stmt.all = true
+		return nil
+	}
+
+	for {
+		it, err := p.parseItem()
+		if err != nil {
+			return err
+		}
+		stmt.items = append(stmt.items, it)
+		if !p.acceptSym(",") {
+			return nil
+		}
+	}
+}
+
+// parseItem parses a single entry in the SELECT list
+func (p *qParser) parseItem() (qItem, error) {
+	var it qItem
+
+	t := p.next()
+	if t.kind != qTokIdent {
+		return it, p.unexpected(t, "a column name or aggregation")
+	}
+
+	fn, isFunc := qAggFuncs[strings.ToUpper(t.text)]
+	if isFunc && !t.quoted && p.acceptSym("(") {
+		it.isAgg = true
+		it.fn = fn
+		it.fnName = strings.ToLower(t.text)
+		if fn != AggCount || !p.acceptSym("*") {
+			col, err := p.ident("a column name")
+			if err != nil {
+				return it, err
+			}
+			it.col = col
+		}
+		if err := p.expectSym(")"); err != nil {
+			return it, err
+		}
+	} else {
+		it.col = t.text
+	}
+
+	if p.acceptKeyword("AS") {
+		alias, err := p.ident("a column alias")
+		if err != nil {
+			return it, err
+		}
+		it.alias = alias
+	}
+
+	return it, nil
+}
+
+// parseGroupBy parses the column names in the GROUP BY clause
+func (p *qParser) parseGroupBy(stmt *qStmt) error {
+	if err := p.expectKeyword("BY"); err != nil {
+		return err
+	}
+
+	for {
+		col, err := p.ident("a column name")
+		if err != nil {
+			return err
+		}
+		stmt.groupBy = append(stmt.groupBy, col)
+		if !p.acceptSym(",") {
+			return nil
+		}
+	}
+}
+
+// parseOrderBy parses the entries in the ORDER BY clause
+func (p *qParser) parseOrderBy(stmt *qStmt) error {
+	if err := p.expectKeyword("BY"); err != nil {
+		return err
+	}
+
+	for {
+		var k qOrderKey
+
+		t := p.next()
+		switch t.kind {
+		case qTokIdent:
+			k.col = t.text
+		case qTokNum:
+			n, err := strconv.Atoi(t.text)
+			if err != nil || n < 1 {
+				return p.unexpected(t, "a column name or position")
+			}
+			k.pos = n
+		default:
+			return p.unexpected(t, "a column name or position")
+		}
+
+		if p.acceptKeyword("DESC") {
+			k.desc = true
+		} else {
+			p.acceptKeyword("ASC")
+		}
+		stmt.orderBy = append(stmt.orderBy, k)
+
+		if !p.acceptSym(",") {
+			return nil
+		}
+	}
+}
+
+// Query returns a new dataframe holding the results of the query, which is
+// given in a small subset of SQL. This offers an alternative to combining
+// calls to WhereMask, GroupByCols, Select and Sort. For instance:
+//
+//	df.Query("SELECT a, sum(b) FROM df WHERE c > 3 GROUP BY a ORDER BY 2 DESC")
+//
+// Note that this is unrelated to the Query function, which reads the
+// results of a query on a database into a dataframe.
+//
+// The query has the following form, where the parts in square brackets are
+// optional:
+//
+//	SELECT items FROM name [WHERE cond] [GROUP BY cols]
+//	    [ORDER BY keys] [LIMIT n]
+//
+// The keywords may be given in upper or lower case. The name after FROM is
+// ignored, the query is always on df.
+//
+// The items are either * (all the columns) or a comma-separated list of
+// column names and aggregations, each optionally followed by AS and the
+// name to give the column in the result. The aggregations are count(*),
+// count(col), sum(col), avg(col) (or mean), min(col) and max(col), which
+// are calculated as for the corresponding AggFunc. The name of an
+// aggregated column defaults to the aggregation as given, in lower case,
+// such as "sum(b)". If any aggregations are given then the other items
+// must be GROUP BY columns; if there is no GROUP BY clause the whole of the
+// data forms a single group.
+//
+// The WHERE condition is an expression as described for CompileExpr which
+// must give a bool value, such as "c > 3 AND (b * 2 < c OR a IS NULL)".
+// The rows for which it is false or NA are left out; as in SQL, a
+// comparison with an NA value is NA.
+//
+// The GROUP BY columns are grouped as for GroupByCols, so NA is treated as
+// a key value like any other.
+//
+// The ORDER BY keys are column names in the result or the positions of
+// items in the SELECT list, counting from 1, optionally followed by ASC or
+// DESC. The rows are sorted as for Sort.
+//
+// Column names may be given in double quotes if they are not simple
+// identifiers or are keywords, and a quote in a string or name is given by
+// doubling it. It returns an error if the query cannot be parsed or refers
+// to a column that does not exist.
+func (df *DF) Query(q string) (*DF, error) {
+	stmt, err := parseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rval := df
+	if stmt.where != nil {
+		mask, err := df.exprMask(stmt.where)
+		if err != nil {
+			return nil, dfWrapf(err, "query: bad WHERE clause")
+		}
+		if rval, err = df.WhereMask(mask); err != nil {
+			return nil, err
+		}
+	}
+
+	if rval, err = stmt.project(rval); err != nil {
+		return nil, err
+	}
+
+	if len(stmt.orderBy) > 0 {
+		if rval, err = stmt.sort(rval); err != nil {
+			return nil, err
+		}
+	}
+
+	if rval == df {
+		rval = df.Clone()
+		rval.maxErrors = df.maxErrors
+		for i := 0; i < df.RowCount(); i++ {
+			rval.copyRowFrom(df, i)
+		}
+	}
+
+	if stmt.limit >= 0 && stmt.limit < rval.RowCount() {
+		rval = rval.rowRange(0, stmt.limit)
+	}
+
+	return rval, nil
+}
+
+// hasAgg returns true if any of the items is an aggregation
+func (stmt *qStmt) hasAgg() bool {
+	for _, it := range stmt.items {
+		if it.isAgg {
+			return true
+		}
+	}
+	return false
+}
+
+// project returns the dataframe with the columns given by the SELECT list,
+// grouping and aggregating the rows if necessary
+func (stmt *qStmt) project(df *DF) (*DF, error) {
+	if stmt.all {
+		if len(stmt.groupBy) > 0 {
+			return nil, dfErrorf("query: SELECT * cannot be used with GROUP BY")
+		}
+		return df, nil
+	}
+
+	if len(stmt.groupBy) == 0 && !stmt.hasAgg() {
+		srcNames := make([]string, 0, len(stmt.items))
+		for _, it := range stmt.items {
+			srcNames = append(srcNames, it.col)
+		}
+		return stmt.selectAs(df, srcNames)
+	}
+
+	grouped, srcNames, err := stmt.aggregate(df)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.selectAs(grouped, srcNames)
+}
+
+// aggregate groups the rows of df and calculates the aggregations, it
+// returns the grouped dataframe and the names of the columns in it
+// corresponding to each item
+func (stmt *qStmt) aggregate(df *DF) (*DF, []string, error) {
+	keys := make([]string, 0, len(stmt.groupBy))
+	for _, key := range stmt.groupBy {
+		i, ok := df.mci.colIdx(key)
+		if !ok {
+			return nil, nil, errUnknownColName(key)
+		}
+		keys = append(keys, df.mci.info[i].name)
+	}
+
+	var aggs []Agg
+	srcNames := make([]string, 0, len(stmt.items))
+	for _, it := range stmt.items {
+		if !it.isAgg {
+			name, err := stmt.groupKeyName(df, keys, it.col)
+			if err != nil {
+				return nil, nil, err
+			}
+			srcNames = append(srcNames, name)
+			continue
+		}
+
+		col := it.col
+		if col != "" {
+			i, ok := df.mci.colIdx(col)
+			if !ok {
+				return nil, nil, errUnknownColName(col)
+			}
+			col = df.mci.info[i].name
+		}
+		name := "\x00" + strconv.Itoa(len(aggs))
+		aggs = append(aggs, Agg{Col: col, Func: it.fn, Name: name})
+		srcNames = append(srcNames, name)
+	}
+
+	if len(keys) == 0 {
+		grouped, err := aggregateAll(df, aggs)
+		return grouped, srcNames, err
+	}
+
+	gdf, err := df.GroupByCols(keys...)
+	if err != nil {
+		return nil, nil, err
+	}
+	grouped, err := gdf.Agg(aggs...)
+	return grouped, srcNames, err
+}
+
+// groupKeyName returns the name of the GROUP BY column matching the column
+// name or an error if it is not one of the keys
+func (stmt *qStmt) groupKeyName(df *DF, keys []string, col string) (
+	string, error,
+) {
+	i, ok := df.mci.colIdx(col)
+	if !ok {
+		return "", errUnknownColName(col)
+	}
+	name := df.mci.info[i].name
+
+	for _, k := range keys {
+		if k == name {
+			return name, nil
+		}
+	}
+	return "", dfErrorf("query: column %q must be aggregated"+
+		" or given in the GROUP BY clause", col)
+}
+
+// aggregateAll returns a dataframe with a single row holding the
+// aggregations calculated over all the rows of df
+func aggregateAll(df *DF, aggs []Agg) (*DF, error) {
+	cis := make([]ColInfo, 0, len(aggs))
+	for _, a := range aggs {
+		ci, err := a.colInfo(df.mci.info)
+		if err != nil {
+			return nil, err
+		}
+		cis = append(cis, ci)
+	}
+
+	rval, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	for ai, a := range aggs {
+		var acc aggAcc
+		ci := -1
+		if a.Col != "" {
+			ci, _ = df.mci.colIdx(a.Col)
+		}
+		for row := 0; row < df.RowCount(); row++ {
+			var v any
+			if ci >= 0 {
+				v, _ = df.valAt(ci, row)
+			}
+			acc.add(v)
+		}
+		if err := rval.appendVal(ai, acc.val(a.Func)); err != nil {
+			return nil, err
+		}
+	}
+
+	return rval, nil
+}
+
+// selectAs returns a dataframe holding the named columns of df, renamed as
+// given by the SELECT list
+func (stmt *qStmt) selectAs(df *DF, srcNames []string) (*DF, error) {
+	rval, err := df.Select(srcNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rval.mci.valIdx) == 0 {
+		// the columns have no types so only the names can be set
+		names := make([]string, 0, len(stmt.items))
+		for _, it := range stmt.items {
+			names = append(names, it.name())
+		}
+		if err := rval.SetColNames(names...); err != nil {
+			return nil, dfWrapf(err, "query: bad SELECT list")
+		}
+		return rval, nil
+	}
+
+	cis := make([]ColInfo, 0, len(stmt.items))
+	for i, it := range stmt.items {
+		ci := rval.mci.info[i]
+		ci.name = it.name()
+		cis = append(cis, ci)
+	}
+
+	mci, err := NewMultiColInfo(cis...)
+	if err != nil {
+		return nil, dfWrapf(err, "query: bad SELECT list")
+	}
+	rval.mci = *mci
+
+	return rval, nil
+}
+
+// sort sorts the rows of the result according to the ORDER BY clause
+func (stmt *qStmt) sort(df *DF) (*DF, error) {
+	keys := make([]SortKey, 0, len(stmt.orderBy))
+	for _, k := range stmt.orderBy {
+		col := k.col
+		if k.pos > 0 {
+			if k.pos > len(df.mci.info) {
+				return nil, dfErrorf(
+					"query: ORDER BY position %d: there are only %d columns",
+					k.pos, len(df.mci.info))
+			}
+			col = df.mci.info[k.pos-1].name
+		}
+		keys = append(keys, SortKey{Col: col, Desc: k.desc})
+	}
+
+	return df.Sort(keys...)
+}
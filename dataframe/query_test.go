@@ -0,0 +1,171 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFQuery(t *testing.T) {
+	const content = `a,b,c,ok
+x,1,5.5,true
+y,2,1.5,false
+x,3,4,true
+z,4,,true
+y,5,7.5,false
+`
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.SplitPattern(","),
+		dataframe.DFREmptyFields(dataframe.EmptyAsNA),
+		dataframe.DFRColTypes(dataframe.ColTypeString, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeBool))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		query    string
+		expCols  []dataframe.ColInfo
+		expVals  [][]string
+		skipCols bool
+	}{
+		{
+			ID:    testhelper.MkID("select all"),
+			query: "SELECT * FROM df",
+			expVals: [][]string{
+				{"x", "1", "5.5", "true"},
+				{"y", "2", "1.5", "false"},
+				{"x", "3", "4", "true"},
+				{"z", "4", "NA", "true"},
+				{"y", "5", "7.5", "false"},
+			},
+			skipCols: true,
+		},
+		{
+			ID:    testhelper.MkID("select columns, where, alias"),
+			query: "select b AS num, a from df where c > 3 and ok = true",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("num", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+			},
+			expVals: [][]string{{"1", "x"}, {"3", "x"}},
+		},
+		{
+			ID:       testhelper.MkID("where NA"),
+			query:    "SELECT b FROM df WHERE NOT c < 5",
+			expVals:  [][]string{{"1"}, {"5"}},
+			skipCols: true,
+		},
+		{
+			ID:       testhelper.MkID("where IS NULL"),
+			query:    "SELECT b FROM df WHERE c IS NULL OR a = 'y'",
+			expVals:  [][]string{{"2"}, {"4"}, {"5"}},
+			skipCols: true,
+		},
+		{
+			ID: testhelper.MkID("where IS NOT NULL, parentheses"),
+			query: "SELECT b FROM df" +
+				" WHERE c IS NOT NULL AND (b < 2 OR b >= 5)",
+			expVals:  [][]string{{"1"}, {"5"}},
+			skipCols: true,
+		},
+		{
+			ID:       testhelper.MkID("where arithmetic"),
+			query:    "SELECT b FROM df WHERE b * 2 - 1 > c OR ok = false",
+			expVals:  [][]string{{"2"}, {"3"}, {"5"}},
+			skipCols: true,
+		},
+		{
+			ID: testhelper.MkID("group by, order by position"),
+			query: "SELECT a, sum(b), count(*) FROM df" +
+				" WHERE b > 1 GROUP BY a ORDER BY 2 DESC",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("sum(b)", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("count(*)", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"y", "7", "2"},
+				{"z", "4", "1"},
+				{"x", "3", "1"},
+			},
+		},
+		{
+			ID:    testhelper.MkID("aggregate all, limit"),
+			query: "SELECT max(c) AS top, avg(b) FROM df LIMIT 1",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("top", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("avg(b)", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{{"7.5", "3"}},
+		},
+		{
+			ID:       testhelper.MkID("order by name, limit"),
+			query:    `SELECT a, "b" FROM df ORDER BY a, b DESC LIMIT 3`,
+			expVals:  [][]string{{"x", "3"}, {"x", "1"}, {"y", "5"}},
+			skipCols: true,
+		},
+		{
+			ID:    testhelper.MkID("bad: no FROM"),
+			query: "SELECT a",
+			ExpErr: testhelper.MkExpErr(
+				"expected FROM, found the end of the query"),
+		},
+		{
+			ID:    testhelper.MkID("bad: trailing text"),
+			query: "SELECT a FROM df b",
+			ExpErr: testhelper.MkExpErr(
+				`position 17: expected the end of the query, found "b"`),
+		},
+		{
+			ID:     testhelper.MkID("bad: unknown column"),
+			query:  "SELECT a FROM df WHERE nonesuch = 1",
+			ExpErr: testhelper.MkExpErr(`Unknown column name: "nonesuch"`),
+		},
+		{
+			ID:    testhelper.MkID("bad: type mismatch"),
+			query: "SELECT a FROM df WHERE a > 1",
+			ExpErr: testhelper.MkExpErr("bad WHERE clause:",
+				"the operands of > must be of comparable types,"+
+					" not String and Int"),
+		},
+		{
+			ID:    testhelper.MkID("bad: not grouped"),
+			query: "SELECT b, sum(c) FROM df GROUP BY a",
+			ExpErr: testhelper.MkExpErr(`column "b" must be aggregated` +
+				" or given in the GROUP BY clause"),
+		},
+		{
+			ID:     testhelper.MkID("bad: unclosed string"),
+			query:  "SELECT a FROM df WHERE a = 'x",
+			ExpErr: testhelper.MkExpErr("position 27: no closing quote"),
+		},
+		{
+			ID:    testhelper.MkID("bad: order by position"),
+			query: "SELECT a FROM df ORDER BY 2",
+			ExpErr: testhelper.MkExpErr(
+				"ORDER BY position 2: there are only 1 columns"),
+		},
+	}
+
+	for _, tc := range testCases {
+		rval, err := df.Query(tc.query)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			if !tc.skipCols {
+				checkColDetails(t, tc.IDStr(), rval, tc.expCols)
+			}
+			checkDFVals(t, tc.IDStr(), rval, tc.expVals)
+		}
+	}
+
+	headerOnly := mkTestDF(t, "a b\n", dataframe.HasHeader)
+	rval, err := headerOnly.Query("SELECT b AS x, a FROM df LIMIT 1")
+	if err != nil {
+		t.Fatal("header only: unexpected error: ", err)
+	}
+	testhelper.DiffString(t, "header only", "columns",
+		fmt.Sprint(rval.Columns()), "[x(Unknown) a(Unknown)]")
+	testhelper.DiffInt(t, "header only", "rows", rval.RowCount(), 0)
+}
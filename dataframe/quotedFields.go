@@ -0,0 +1,58 @@
+package dataframe
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+)
+
+// DFRQuotedFields will cause the DFReader to treat fields starting with a
+// double quote as quoted, in the same way as CSV: the field runs to the
+// matching closing quote, which must be followed by the separator or the
+// end of the line, and a doubled quote within the field stands for a
+// single quote. A quoted field may contain the separator and may contain
+// newlines, in which case the record continues onto the following lines
+// until the quotes balance. Any line numbers reported for such a record
+// are those of its last line. The quotes are removed from the value. It
+// cannot be given with DFRRoundTrip which has its own quoting rules.
+func DFRQuotedFields(dfr *DFReader) error {
+	dfr.quotedFields = true
+	return nil
+}
+
+// csvUnquote unquotes the CSV quoted string at the start of s
+func csvUnquote(s string) (string, int, error) {
+	for i := 1; i < len(s); i++ {
+		if s[i] != '"' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '"' {
+			i++
+			continue
+		}
+		return strings.ReplaceAll(s[1:i], `""`, `"`), i + 1, nil
+	}
+	return "", -1, nil
+}
+
+// hasOpenQuote returns true if the line ends within a quoted field
+func (dfr *DFReader) hasOpenQuote(line string) bool {
+	_, _, err := splitQuotedLine(line, dfr.splitRegex, csvUnquote)
+	return errors.Is(err, errNoClosingQuote)
+}
+
+// completeRecord adds lines from the scanner to the current line, joined
+// by newlines, until the record has no unclosed quoted field or there are
+// no more lines. It does nothing unless quoted fields are allowed.
+func (dfr *DFReader) completeRecord(scanner *bufio.Scanner,
+	state *dfReadState,
+) {
+	if !dfr.quotedFields {
+		return
+	}
+
+	for dfr.hasOpenQuote(state.line) && scanner.Scan() {
+		state.loc.Incr()
+		state.line += "\n" + scanner.Text()
+	}
+}
@@ -0,0 +1,99 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestQuotedFields(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("embedded newlines and separators"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader, dataframe.DFRQuotedFields,
+				dataframe.SplitPattern(","),
+			},
+			data: "id,note,n\n" +
+				"1,\"line one\nline two\",10\n" +
+				"2,\"a, b\",20\n" +
+				"3,\"say \"\"hi\"\"\n\n!\",30\n" +
+				"4,plain,40\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("id", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("note", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("n", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"1", "line one\nline two", "10"},
+				{"2", "a, b", "20"},
+				{"3", "say \"hi\"\n\n!", "30"},
+				{"4", "plain", "40"},
+			},
+		},
+		{
+			ID: testhelper.MkID("whitespace separated"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader, dataframe.DFRQuotedFields,
+			},
+			data: "name n\n" +
+				"\"J Smith\" 1\n" +
+				"\"multi\nline\" 2\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("n", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"J Smith", "1"},
+				{"multi\nline", "2"},
+			},
+		},
+		{
+			ID: testhelper.MkID("unclosed quote"),
+			ExpErr: testhelper.MkExpErr("test data:3:",
+				"field 1: no closing quote"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader, dataframe.DFRQuotedFields,
+				dataframe.SplitPattern(","),
+			},
+			data: "a,b\n1,\"x\n2,y\n",
+		},
+		{
+			ID: testhelper.MkID("text after the closing quote"),
+			ExpErr: testhelper.MkExpErr("test data:2:",
+				"field 0: unexpected text after the closing quote"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader, dataframe.DFRQuotedFields,
+				dataframe.SplitPattern(","),
+			},
+			data: "a,b\n\"x\"y,1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(tc.data), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+
+	_, err := dataframe.NewDFReader(dataframe.DFRQuotedFields,
+		dataframe.DFRRoundTrip)
+	testhelper.CheckExpErrWithID(t, "with round-trip mode", err,
+		testhelper.MkExpErr(
+			"quoted fields cannot be given in round-trip mode"))
+}
@@ -0,0 +1,90 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadComments(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("anchored"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.CommentPattern(`\s*#`),
+				dataframe.DFRAnchoredComments,
+				dataframe.SkipBlankLines,
+			},
+			data: "item#3 1 # a comment\n" +
+				"# whole line\n" +
+				"item#4 2#not-a-comment\n",
+			expVals: [][]string{
+				{"item#3", "1"},
+				{"item#4", "2#not-a-comment"},
+			},
+		},
+		{
+			ID: testhelper.MkID("anchored, pattern includes space"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.CommentPattern(`\s+#.*$`),
+				dataframe.DFRAnchoredComments,
+			},
+			data: "a#1 1 # a comment\n" +
+				"b#2 2\n",
+			expVals: [][]string{{"a#1", "1"}, {"b#2", "2"}},
+		},
+		{
+			ID: testhelper.MkID("anchored, quoted fields"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.CommentPattern(`\s*#`),
+				dataframe.DFRAnchoredComments,
+				dataframe.DFRQuotedFields,
+				dataframe.SplitPattern(","),
+			},
+			data: "\"x, # y\",1 # comment\n" +
+				"z,2\n",
+			expVals: [][]string{{"x, # y", "1"}, {"z", "2"}},
+		},
+		{
+			ID: testhelper.MkID("skip comment lines"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.CommentPattern(`\s*#`),
+				dataframe.DFRSkipCommentLines,
+			},
+			data: "# header comment\n" +
+				"  # indented comment\n" +
+				"a 1\n" +
+				"b 2 # trailing\n",
+			expVals: [][]string{{"a", "1"}, {"b", "2"}},
+		},
+		{
+			ID: testhelper.MkID("comment lines are blank by default"),
+			ExpErr: testhelper.MkExpErr("test data:1:",
+				"unexpected blank line"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.CommentPattern(`\s*#`),
+			},
+			data: "# header comment\n" +
+				"a 1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(tc.data), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package dataframe
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// jsonObject reads the next JSON object from the decoder returning its
+// entries as a record. Any new keys are added to names (and recorded in
+// keys) in the order in which they appear.
+func jsonObject(dec *json.Decoder, keys map[string]bool, names []string,
+) (map[string]any, []string, error) {
+	rec := map[string]any{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, names, err
+		}
+		k, ok := t.(string)
+		if !ok {
+			return nil, names, dfErrorf("bad object key: %v", t)
+		}
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, names, err
+		}
+		rec[k] = v
+		if !keys[k] {
+			keys[k] = true
+			names = append(names, k)
+		}
+	}
+	if _, err := dec.Token(); err != nil { // the closing brace
+		return nil, names, err
+	}
+	return rec, names, nil
+}
+
+// jsonRecords reads the JSON objects from the decoder, either as the
+// elements of an array or as a sequence of objects, and returns them with
+// the keys in the order in which they first appear
+func jsonRecords(dec *json.Decoder) ([]map[string]any, []string, error) {
+	keys := map[string]bool{}
+	names := []string{}
+	recs := []map[string]any{}
+
+	t, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return recs, names, nil
+	}
+	inArray := t == json.Delim('[')
+
+	for err == nil {
+		if inArray {
+			if !dec.More() {
+				break
+			}
+			if t, err = dec.Token(); err != nil {
+				break
+			}
+		}
+		if t != json.Delim('{') {
+			return nil, nil, dfErrorf("record %d: expected an object, found %v",
+				len(recs), t)
+		}
+
+		var rec map[string]any
+		rec, names, err = jsonObject(dec, keys, names)
+		if err != nil {
+			return nil, nil, dfWrapf(err, "record %d", len(recs))
+		}
+		recs = append(recs, rec)
+
+		if !inArray {
+			t, err = dec.Token()
+			if errors.Is(err, io.EOF) {
+				return recs, names, nil
+			}
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := dec.Token(); err != nil { // the closing bracket
+		return nil, nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, nil, dfErrorf("unexpected data after the array")
+	}
+	return recs, names, nil
+}
+
+// ReadJSON reads JSON data holding one object per row, either as an array
+// of objects (as written by WriteJSON with OrientRecords) or as a sequence
+// of objects such as one per line (the JSON Lines format, as written with
+// OrientLines). There is a column for each key found in any of the
+// objects, in the order in which they first appear, and the column types
+// are found from the values as for FromRecords; numbers without a
+// fraction or exponent give int values where possible. A null value or a
+// missing key gives an NA value. Nested objects and arrays give an error.
+func ReadJSON(r io.Reader) (*DF, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	recs, names, err := jsonRecords(dec)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the JSON data")
+	}
+	df, err := fromRecords(recs, names)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the JSON data")
+	}
+	return df, nil
+}
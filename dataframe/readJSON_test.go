@@ -0,0 +1,78 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadJSON(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		input   string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:    testhelper.MkID("array"),
+			input: `[{"s":"x","i":1,"f":1.5},{"i":2,"f":3,"b":true}]`,
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("f", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeBool),
+			},
+			expVals: [][]string{
+				{"x", "1", "1.5", "NA"},
+				{"NA", "2", "3", "true"},
+			},
+		},
+		{
+			ID:    testhelper.MkID("lines"),
+			input: "{\"a\":null,\"b\":\"x\"}\n{\"a\":2,\"b\":\"y\"}\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeString),
+			},
+			expVals: [][]string{{"NA", "x"}, {"2", "y"}},
+		},
+		{
+			ID:      testhelper.MkID("empty"),
+			input:   "",
+			expCols: []dataframe.ColInfo{},
+			expVals: [][]string{},
+		},
+		{
+			ID:    testhelper.MkID("bad: not an object"),
+			input: `[{"a":1},2]`,
+			ExpErr: testhelper.MkExpErr(
+				"record 1: expected an object, found 2"),
+		},
+		{
+			ID:     testhelper.MkID("bad: nested value"),
+			input:  `{"a":[1,2]}`,
+			ExpErr: testhelper.MkExpErr("unsupported value type"),
+		},
+		{
+			ID:     testhelper.MkID("bad: trailing data"),
+			input:  `[{"a":1}] {"a":2}`,
+			ExpErr: testhelper.MkExpErr("unexpected data after the array"),
+		},
+		{
+			ID:     testhelper.MkID("bad: type mismatch"),
+			input:  `{"a":1} {"a":"x"}`,
+			ExpErr: testhelper.MkExpErr(`record 1, key "a"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.ReadJSON(strings.NewReader(tc.input))
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package dataframe
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// kvBlock holds the values from a block of key-value lines and the number
+// of the line on which the block starts
+type kvBlock struct {
+	line int64
+	vals map[string]string
+}
+
+// kvColType returns the column type for the values in the named column of
+// the blocks, guessed as for the values read by a DFReader. A column with
+// no values is a String column.
+func kvColType(name string, blocks []kvBlock) ColType {
+	var rows [][]string
+	for _, b := range blocks {
+		if v, ok := b.vals[name]; ok {
+			rows = append(rows, []string{v})
+		}
+	}
+	if len(rows) == 0 {
+		return ColTypeString
+	}
+	return guessColTypes(parseOpts{}, []ColInfo{{name: name}}, rows)[0]
+}
+
+// ReadKeyValue reads records given as blocks of 'key: value' lines, one
+// block per row, separated by one or more blank lines, such as:
+//
+//	name: apple
+//	qty:  3
+//
+//	name:  pear
+//	price: 1.25
+//
+// There is a column for each key found in any of the blocks, in the order
+// in which they first appear, and the column types are guessed from the
+// values as for a DFReader. The key is the text before the first ':' and
+// the value is the rest of the line; both have any surrounding white
+// space removed. A missing key or an empty value gives an NA value. Lines
+// starting with '#' are ignored. It is an error for a line to have no ':',
+// for a key to be empty or for a key to appear twice in a block. The
+// source is used to identify the data in any error messages.
+func ReadKeyValue(r io.Reader, source string) (*DF, error) {
+	var blocks []kvBlock
+	var names []string
+	seen := map[string]bool{}
+
+	var block *kvBlock
+	scanner := bufio.NewScanner(r)
+	num := int64(0)
+	for scanner.Scan() {
+		num++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			block = nil
+			continue
+		}
+		if line[0] == '#' {
+			continue
+		}
+
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			return nil, dfErrorf("%s:%d: expected 'key: value', found %q",
+				source, num, line)
+		}
+		key := strings.TrimSpace(line[:i])
+		if key == "" {
+			return nil, dfErrorf("%s:%d: the key is empty", source, num)
+		}
+
+		if block == nil {
+			blocks = append(blocks, kvBlock{
+				line: num,
+				vals: map[string]string{},
+			})
+			block = &blocks[len(blocks)-1]
+		}
+		if _, dup := block.vals[key]; dup {
+			return nil, dfErrorf("%s:%d: duplicate key: %q", source, num, key)
+		}
+		if val := strings.TrimSpace(line[i+1:]); val != "" {
+			block.vals[key] = val
+		}
+		if !seen[key] {
+			seen[key] = true
+			names = append(names, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, dfWrapf(err, "%s: cannot read the key-value data", source)
+	}
+
+	cis := make([]ColInfo, 0, len(names))
+	for _, name := range names {
+		cis = append(cis, ColInfo{name: name, colType: kvColType(name, blocks)})
+	}
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range blocks {
+		cols := make([]string, len(names))
+		isNA := make([]bool, len(names))
+		for i, name := range names {
+			v, ok := b.vals[name]
+			cols[i], isNA[i] = v, !ok
+		}
+		err := df.addRowFromText(parseOpts{}, cols, isNA,
+			source, b.line, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return df, nil
+}
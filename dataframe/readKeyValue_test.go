@@ -0,0 +1,77 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadKeyValue(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data    string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("key union, types guessed"),
+			data: "# fruit\n" +
+				"name: apple\n" +
+				"qty:  12\n" +
+				"fresh: true\n" +
+				"\n" +
+				"\n" +
+				"name:  pear: williams\n" +
+				"price: 1.25\n" +
+				"qty:\n" +
+				"  \n" +
+				"price: 2\n" +
+				"note:\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("fresh", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("note", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"apple", "12", "true", "NA", "NA"},
+				{"pear: williams", "NA", "NA", "1.25", "NA"},
+				{"NA", "NA", "NA", "2", "NA"},
+			},
+		},
+		{
+			ID:   testhelper.MkID("empty"),
+			data: "\n# nothing\n",
+		},
+		{
+			ID: testhelper.MkID("no colon"),
+			ExpErr: testhelper.MkExpErr(
+				`test data:2: expected 'key: value', found "b"`),
+			data: "a: 12\nb\n",
+		},
+		{
+			ID:     testhelper.MkID("empty key"),
+			ExpErr: testhelper.MkExpErr("test data:1: the key is empty"),
+			data:   ": 12\n",
+		},
+		{
+			ID: testhelper.MkID("duplicate key"),
+			ExpErr: testhelper.MkExpErr(
+				`test data:3: duplicate key: "a"`),
+			data: "a: 12\nb: 13\na: 14\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.ReadKeyValue(strings.NewReader(tc.data),
+			"test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
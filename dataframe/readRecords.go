@@ -0,0 +1,146 @@
+package dataframe
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// skipEmptyRecord checks to see if the record has no fields and if so
+// treats it in the same way as a blank line
+func skipEmptyRecord(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
+	if len(state.cols) != 0 {
+		return false, nil
+	}
+	return skipBlankLine(dfr, state, df)
+}
+
+// usesLineOpts returns true if any options have been given which apply
+// only to reading lines of text (see Read) and so are ignored when reading
+// records
+func (dfr *DFReader) usesLineOpts() bool {
+	return dfr.splitRegex != defaultSplitRegex ||
+		dfr.commentRegex != nil ||
+		dfr.maxCols >= 0 ||
+		len(dfr.skipRegexes) != 0 ||
+		len(dfr.stopRegexes) != 0 ||
+		dfr.roundTrip ||
+		dfr.quotedFields
+}
+
+// readRecords constructs a dataframe from the records returned by next,
+// which should return io.EOF when there are no more records. The records
+// are treated in the same way as the lines read by Read once they have
+// been split into columns.
+func (dfr *DFReader) readRecords(source string,
+	next func() ([]string, error), extraOpts []DFReaderOpt,
+) (*DF, error) {
+	dfr, err := dfr.withOpts(extraOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if dfr.roundTrip {
+		return nil, dfErrorf("records cannot be read in round-trip mode")
+	}
+
+	state := newDFReadState(dfr, source)
+	return dfr.guardRead(state, func() (*DF, error) {
+		return dfr.readRecs(state, next)
+	})
+}
+
+// readRecs constructs a dataframe from the records returned by next
+func (dfr *DFReader) readRecs(state *dfReadState,
+	next func() ([]string, error),
+) (*DF, error) {
+	df, err := dfr.makeDF()
+	if err != nil {
+		return nil, err
+	}
+
+	dfr.startErrStream(df)
+	operations := []lineHandler{
+		skipLine,
+		skipEmptyRecord,
+		removeSkipCols,
+		collectTransposed,
+		handleHeaderLines,
+		handleLine1,
+		checkColumns,
+		cacheData,
+		handleData,
+	}
+
+Loop:
+	for {
+		rec, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, dfWrapf(err, "%s: cannot read record %d",
+				state.loc.Source(), state.loc.Idx()+1)
+		}
+		state.loc.Incr()
+		state.cols = append([]string(nil), rec...)
+		state.isNA = nil
+
+		for _, op := range operations {
+			skip, err := op(dfr, state, df)
+			if err != nil {
+				return nil, err
+			}
+			if skip {
+				continue Loop
+			}
+		}
+	}
+
+	if dfr.transposed {
+		return dfr.finishTransposed(state, df)
+	}
+	return dfr.finishRead(state, df)
+}
+
+// ReadRecords constructs a dataframe from records which have already been
+// split into fields, such as those returned by the ReadAll method of a
+// csv.Reader. Each record is treated in the same way as a line read by
+// Read once it has been split into columns, so the column names and types
+// are found, columns are skipped and errors are reported in the same way
+// and all the checks are made. A record with no fields is treated as a
+// blank line. Any comment pattern and split pattern are ignored as are any
+// maximum number of columns, and the line numbers given in errors are the
+// record numbers, starting from 1. Round-trip mode is not supported. Any
+// extra options apply only to this read, see Read.
+func (dfr *DFReader) ReadRecords(recs [][]string, source string,
+	extraOpts ...DFReaderOpt,
+) (*DF, error) {
+	i := 0
+	return dfr.readRecords(source, func() ([]string, error) {
+		if i >= len(recs) {
+			return nil, io.EOF
+		}
+		i++
+		return recs[i-1], nil
+	}, extraOpts)
+}
+
+// ReadCSVRecords constructs a dataframe from the records read from the
+// csv.Reader as for ReadRecords. Any settings of the csv.Reader, such as
+// the separator or comment character, are used as they are. A record with
+// the wrong number of fields is passed on to be reported by the DFReader,
+// so it can be allowed with AllowErrors, but any other error from the
+// csv.Reader stops the read. Any extra options apply only to this read, see
+// Read.
+func (dfr *DFReader) ReadCSVRecords(cr *csv.Reader, source string,
+	extraOpts ...DFReaderOpt,
+) (*DF, error) {
+	return dfr.readRecords(source, func() ([]string, error) {
+		rec, err := cr.Read()
+		if errors.Is(err, csv.ErrFieldCount) {
+			err = nil
+		}
+		return rec, err
+	}, extraOpts)
+}
@@ -0,0 +1,112 @@
+package dataframe_test
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadCSVRecords(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts      []dataframe.DFReaderOpt
+		data      string
+		expCols   []dataframe.ColInfo
+		expVals   [][]string
+		expErrCnt int64
+	}{
+		{
+			ID:   testhelper.MkID("quoted fields, types guessed"),
+			opts: []dataframe.DFReaderOpt{dataframe.HasHeader},
+			data: "name,n,x\n" +
+				`"Smith, J",1,1.5` + "\n" +
+				`"say ""hi""",2,2` + "\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("n", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("x", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"Smith, J", "1", "1.5"},
+				{`say "hi"`, "2", "2"},
+			},
+		},
+		{
+			ID: testhelper.MkID("wrong field count allowed"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader, dataframe.AllowErrors,
+			},
+			data: "a,b\n1,2\n3\n4,5\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+			},
+			expVals:   [][]string{{"1", "2"}, {"4", "5"}},
+			expErrCnt: 1,
+		},
+		{
+			ID:     testhelper.MkID("wrong field count"),
+			ExpErr: testhelper.MkExpErr("test data:3:", "this line has 1"),
+			opts:   []dataframe.DFReaderOpt{dataframe.HasHeader},
+			data:   "a,b\n1,2\n3\n4,5\n",
+		},
+		{
+			ID:     testhelper.MkID("bad CSV"),
+			ExpErr: testhelper.MkExpErr("test data: cannot read record 2"),
+			opts:   []dataframe.DFReaderOpt{dataframe.HasHeader},
+			data:   "a,b\n\"1,2\n",
+		},
+		{
+			ID: testhelper.MkID("round-trip mode"),
+			ExpErr: testhelper.MkExpErr(
+				"records cannot be read in round-trip mode"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRRoundTrip},
+			data: "a,b\n1,2\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+		}
+		cr := csv.NewReader(strings.NewReader(tc.data))
+		df, err := dfr.ReadCSVRecords(cr, "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+			testhelper.DiffInt(t, tc.IDStr(), "error count",
+				df.ErrCount(), tc.expErrCnt)
+		}
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	dfr, err := dataframe.NewDFReader(dataframe.HasHeader,
+		dataframe.SkipBlankLines, dataframe.DFRSkipCols(1))
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+	}
+
+	df, err := dfr.ReadRecords([][]string{
+		{"a", "skip", "b"},
+		{"true", "x", "a b"},
+		{},
+		{"false", "y", ""},
+	}, "records")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	checkColDetails(t, "ReadRecords", df, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("a", dataframe.ColTypeBool),
+		dataframe.MustNewColInfo("b", dataframe.ColTypeString),
+	})
+	checkDFVals(t, "ReadRecords", df, [][]string{
+		{"true", "a b"},
+		{"false", ""},
+	})
+}
@@ -0,0 +1,152 @@
+package dataframe
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// sqlDBTypes maps the database type names of SQL columns to the column
+// types to use when the driver doesn't give a useful scan type. Any name
+// not in the map gives a string column.
+var sqlDBTypes = map[string]ColType{
+	"BOOL":    ColTypeBool,
+	"BOOLEAN": ColTypeBool,
+	"BIT":     ColTypeBool,
+
+	"INT":       ColTypeInt,
+	"INTEGER":   ColTypeInt,
+	"TINYINT":   ColTypeInt,
+	"SMALLINT":  ColTypeInt,
+	"MEDIUMINT": ColTypeInt,
+	"BIGINT":    ColTypeInt,
+	"INT2":      ColTypeInt,
+	"INT4":      ColTypeInt,
+	"INT8":      ColTypeInt,
+	"SERIAL":    ColTypeInt,
+	"BIGSERIAL": ColTypeInt,
+
+	"REAL":             ColTypeFloat,
+	"FLOAT":            ColTypeFloat,
+	"FLOAT4":           ColTypeFloat,
+	"FLOAT8":           ColTypeFloat,
+	"DOUBLE":           ColTypeFloat,
+	"DOUBLE PRECISION": ColTypeFloat,
+	"NUMERIC":          ColTypeFloat,
+	"DECIMAL":          ColTypeFloat,
+}
+
+// sqlColType returns the column type to use for the SQL column. The scan
+// type given by the driver is used if it is a bool, integer, float or
+// string type or one of the matching sql.Null... types. Otherwise the
+// database type name is used (see sqlDBTypes).
+func sqlColType(ct *sql.ColumnType) ColType {
+	if st := ct.ScanType(); st != nil {
+		if colType, ok := nullTypes[st]; ok {
+			return colType
+		}
+		if colType, ok := kindColType(st.Kind()); ok {
+			return colType
+		}
+	}
+
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(strings.TrimPrefix(name, "UNSIGNED "))
+	name = strings.TrimSpace(strings.TrimSuffix(name, " UNSIGNED"))
+	if colType, ok := sqlDBTypes[name]; ok {
+		return colType
+	}
+	return ColTypeString
+}
+
+// sqlScanDest returns a value to pass to Scan for the column type
+func sqlScanDest(ct ColType) any {
+	switch ct {
+	case ColTypeBool:
+		return &sql.NullBool{}
+	case ColTypeInt:
+		return &sql.NullInt64{}
+	case ColTypeFloat:
+		return &sql.NullFloat64{}
+	case ColTypeString:
+		return &sql.NullString{}
+	}
+	panic(dfErrorf("Unexpected column type: %q", ct))
+}
+
+// appendSQLVal appends the scanned value to the indexed column
+func (df *DF) appendSQLVal(colIdx int, dest any) {
+	vi := df.mci.valIdx[colIdx]
+
+	switch d := dest.(type) {
+	case *sql.NullBool:
+		df.appendBoolVal(vi, BoolVal{Val: d.Bool, IsNA: !d.Valid})
+	case *sql.NullInt64:
+		df.intCols[vi] = append(df.intCols[vi],
+			IntVal{Val: d.Int64, IsNA: !d.Valid})
+	case *sql.NullFloat64:
+		df.floatCols[vi] = append(df.floatCols[vi],
+			FloatVal{Val: d.Float64, IsNA: !d.Valid})
+	case *sql.NullString:
+		df.appendStringVal(vi, StringVal{Val: d.String, IsNA: !d.Valid})
+	}
+}
+
+// FromSQLRows returns a new dataframe holding the rows, with one column
+// for each column in the result. The column types are found from the
+// types the driver reports: bool, integer, float and string types (and the
+// matching sql.Null... types) give bool, int, float and string columns;
+// other types, such as times and byte slices, give string columns (times
+// are formatted as RFC 3339 strings). If the driver doesn't report a
+// useful type the database type name is used. NULL values give NA values.
+// The rows are closed before it returns.
+func FromSQLRows(rows *sql.Rows) (*DF, error) {
+	defer rows.Close()
+
+	sqlCols, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, dfWrapf(err, "cannot get the SQL column types")
+	}
+
+	cis := make([]ColInfo, 0, len(sqlCols))
+	for _, sc := range sqlCols {
+		cis = append(cis, ColInfo{name: sc.Name(), colType: sqlColType(sc)})
+	}
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	dests := make([]any, len(cis))
+	for i, ci := range cis {
+		dests[i] = sqlScanDest(ci.colType)
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dests...); err != nil {
+			return nil, dfWrapf(err, "cannot scan SQL row %d", df.RowCount())
+		}
+		for i, d := range dests {
+			df.appendSQLVal(i, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dfWrapf(err, "cannot read the SQL rows")
+	}
+	return df, nil
+}
+
+// Query runs the query on the database and returns the results as a new
+// dataframe. See FromSQLRows for details of how the column types are
+// chosen.
+func Query(ctx context.Context, db *sql.DB, query string, args ...any,
+) (*DF, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dfWrapf(err, "the SQL query failed")
+	}
+	return FromSQLRows(rows)
+}
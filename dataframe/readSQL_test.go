@@ -0,0 +1,175 @@
+package dataframe_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// fakeResult is the result of a query on the fake database
+type fakeResult struct {
+	cols      []string
+	scanTypes []reflect.Type
+	dbTypes   []string
+	data      [][]driver.Value
+}
+
+// fakeResults maps the queries on the fake database to their results
+var fakeResults = map[string]fakeResult{
+	"typed": {
+		cols: []string{"b", "i", "f", "s", "t"},
+		scanTypes: []reflect.Type{
+			reflect.TypeOf(sql.NullBool{}),
+			reflect.TypeOf(int32(0)),
+			reflect.TypeOf(float64(0)),
+			reflect.TypeOf(""),
+			reflect.TypeOf(time.Time{}),
+		},
+		dbTypes: []string{"BOOL", "INT4", "FLOAT8", "TEXT", "TIMESTAMP"},
+		data: [][]driver.Value{
+			{
+				true, int64(1), 1.5, "a",
+				time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+			{nil, nil, nil, nil, nil},
+		},
+	},
+	"untyped": {
+		cols: []string{"i", "f", "s"},
+		scanTypes: []reflect.Type{
+			reflect.TypeOf((*any)(nil)).Elem(),
+			reflect.TypeOf((*any)(nil)).Elem(),
+			reflect.TypeOf((*any)(nil)).Elem(),
+		},
+		dbTypes: []string{"bigint unsigned", "DECIMAL(10,2)", "BLOB"},
+		data: [][]driver.Value{
+			{int64(7), "2.25", []byte("xyz")},
+		},
+	},
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(q string) (driver.Stmt, error) {
+	return fakeStmt(q), nil
+}
+
+func (fakeConn) Close() error { return nil }
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type fakeStmt string
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	res, ok := fakeResults[string(s)]
+	if !ok {
+		return nil, errors.New("no such table")
+	}
+	return &fakeRows{res: res}, nil
+}
+
+type fakeRows struct {
+	res fakeResult
+	i   int
+}
+
+func (r *fakeRows) Columns() []string { return r.res.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.res.data) {
+		return io.EOF
+	}
+	copy(dest, r.res.data[r.i])
+	r.i++
+	return nil
+}
+
+func (r *fakeRows) ColumnTypeScanType(i int) reflect.Type {
+	return r.res.scanTypes[i]
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(i int) string {
+	return r.res.dbTypes[i]
+}
+
+func init() {
+	sql.Register("dataframe-fake", fakeDriver{})
+}
+
+func TestQuery(t *testing.T) {
+	db, err := sql.Open("dataframe-fake", "")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot open the fake database: ", err)
+	}
+	defer db.Close()
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		query   string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:    testhelper.MkID("scan types"),
+			query: "typed",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("b", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("f", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("t", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"true", "1", "1.5", "a", "2024-01-02T03:04:05Z"},
+				{"NA", "NA", "NA", "NA", "NA"},
+			},
+		},
+		{
+			ID:    testhelper.MkID("database type names"),
+			query: "untyped",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("f", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+			},
+			expVals: [][]string{{"7", "2.25", "xyz"}},
+		},
+		{
+			ID: testhelper.MkID("bad query"),
+			ExpErr: testhelper.MkExpErr(
+				"the SQL query failed", "no such table"),
+			query: "nonesuch",
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.Query(context.Background(), db, tc.query)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
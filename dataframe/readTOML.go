@@ -0,0 +1,324 @@
+package dataframe
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	tomlBareKeyRE = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	tomlIntRE     = regexp.MustCompile(`^[-+]?(0|[1-9](_?[0-9])*)$`)
+	tomlFloatRE   = regexp.MustCompile(
+		`^[-+]?(0|[1-9](_?[0-9])*)` +
+			`((\.[0-9](_?[0-9])*)([eE][-+]?[0-9](_?[0-9])*)?` +
+			`|[eE][-+]?[0-9](_?[0-9])*)$`)
+	tomlDateTimeRE = regexp.MustCompile(
+		`^([0-9]{4}-[0-9]{2}-[0-9]{2}([Tt ][0-9]{2}:[0-9]{2}` +
+			`|$)|[0-9]{2}:[0-9]{2}:[0-9]{2})`)
+)
+
+// tomlKey parses the key at the start of s, returning the key and the
+// rest of the text. Dotted keys are not supported.
+func tomlKey(s string) (string, string, error) {
+	s = strings.TrimLeft(s, " \t")
+	if s == "" {
+		return "", "", dfErrorf("missing key")
+	}
+
+	var key, rest string
+	switch s[0] {
+	case '"', '\'':
+		k, r, err := tomlString(s)
+		if err != nil {
+			return "", "", err
+		}
+		key, rest = k, r
+	default:
+		i := strings.IndexAny(s, " \t=.]")
+		if i < 0 {
+			i = len(s)
+		}
+		key, rest = s[:i], s[i:]
+		if !tomlBareKeyRE.MatchString(key) {
+			return "", "", dfErrorf("bad key: %q", key)
+		}
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	if strings.HasPrefix(rest, ".") {
+		return "", "", dfErrorf("dotted keys are not supported: %q", s)
+	}
+	return key, rest, nil
+}
+
+// tomlString parses the basic ("...") or literal ('...') string at the
+// start of s, returning its value and the rest of the text
+func tomlString(s string) (string, string, error) {
+	if strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "'''") {
+		return "", "", dfErrorf("multi-line strings are not supported")
+	}
+
+	if s[0] == '\'' {
+		i := strings.IndexByte(s[1:], '\'')
+		if i < 0 {
+			return "", "", dfErrorf("unterminated string: %s", s)
+		}
+		return s[1 : i+1], s[i+2:], nil
+	}
+
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			v, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", dfErrorf("bad string: %s", s[:i+1])
+			}
+			return v, s[i+1:], nil
+		}
+	}
+	return "", "", dfErrorf("unterminated string: %s", s)
+}
+
+// tomlVal returns the value of the TOML value at the start of s, which
+// may only be followed by a comment. Dates and times are returned as
+// strings; arrays and inline tables are not supported.
+func tomlVal(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, dfErrorf("missing value")
+	}
+
+	var v any
+	rest := ""
+	switch s[0] {
+	case '"', '\'':
+		str, r, err := tomlString(s)
+		if err != nil {
+			return nil, err
+		}
+		v, rest = str, r
+	case '[', '{':
+		return nil, dfErrorf("unsupported TOML value: %q"+
+			" (only scalar values are allowed)", s)
+	default:
+		if i := strings.IndexByte(s, '#'); i >= 0 {
+			s, rest = strings.TrimSpace(s[:i]), s[i:]
+		}
+		var err error
+		if v, err = tomlScalar(s); err != nil {
+			return nil, err
+		}
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest != "" && rest[0] != '#' {
+		return nil, dfErrorf("unexpected text after the value: %q", rest)
+	}
+	return v, nil
+}
+
+// tomlScalar returns the value of the unquoted TOML value
+func tomlScalar(s string) (any, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+
+	switch {
+	case tomlIntRE.MatchString(s):
+		i, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64)
+		if err != nil {
+			return nil, dfErrorf("bad integer: %s", s)
+		}
+		return i, nil
+	case strings.HasPrefix(s, "0x"),
+		strings.HasPrefix(s, "0o"),
+		strings.HasPrefix(s, "0b"):
+		i, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return nil, dfErrorf("bad integer: %s", s)
+		}
+		return i, nil
+	case tomlFloatRE.MatchString(s):
+		f, err := strconv.ParseFloat(strings.ReplaceAll(s, "_", ""), 64)
+		if err != nil {
+			return nil, dfErrorf("bad float: %s", s)
+		}
+		return f, nil
+	case tomlDateTimeRE.MatchString(s):
+		return s, nil
+	}
+	return nil, dfErrorf("bad value: %q", s)
+}
+
+// tomlHeader parses a table header line, returning the table name and
+// whether it is an array of tables header
+func tomlHeader(s string) (string, bool, error) {
+	isArray := strings.HasPrefix(s, "[[")
+	open, closing := "[", "]"
+	if isArray {
+		open, closing = "[[", "]]"
+	}
+
+	var parts []string
+	rest := strings.TrimPrefix(s, open)
+	for {
+		key, r, err := tomlKeyPart(rest)
+		if err != nil {
+			return "", false, err
+		}
+		parts = append(parts, key)
+		if !strings.HasPrefix(r, ".") {
+			rest = r
+			break
+		}
+		rest = r[1:]
+	}
+
+	if !strings.HasPrefix(rest, closing) {
+		return "", false, dfErrorf("bad table header: %q", s)
+	}
+	rest = strings.TrimSpace(rest[len(closing):])
+	if rest != "" && rest[0] != '#' {
+		return "", false, dfErrorf("unexpected text after the header: %q",
+			rest)
+	}
+	return strings.Join(parts, "."), isArray, nil
+}
+
+// tomlKeyPart parses one part of a dotted table name
+func tomlKeyPart(s string) (string, string, error) {
+	s = strings.TrimLeft(s, " \t")
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		k, r, err := tomlString(s)
+		return k, strings.TrimLeft(r, " \t"), err
+	}
+	i := strings.IndexAny(s, " \t.]")
+	if i < 0 {
+		i = len(s)
+	}
+	if !tomlBareKeyRE.MatchString(s[:i]) {
+		return "", "", dfErrorf("bad key: %q", s[:i])
+	}
+	return s[:i], strings.TrimLeft(s[i:], " \t"), nil
+}
+
+// ReadTOML reads the TOML array of tables with the given name, such as:
+//
+//	[[fruit]]
+//	name = "apple"
+//	qty = 3
+//
+//	[[fruit]]
+//	name = "pear"
+//	price = 1.25
+//
+// as a dataframe with one row per table. There is a column for each key
+// found in any of the tables, in the order in which they first appear,
+// and the column types are found from the values as for FromRecords. A
+// missing key gives an NA value. Dates and times are read as strings. Any
+// other tables and keys in the document are ignored (and are not checked
+// for validity) but tables nested
+// within the array, dotted keys, arrays, inline tables and multi-line
+// strings are not supported and give an error. Double-quoted strings may
+// use the escape sequences allowed in Go strings. If the table name is
+// empty the first array of tables in the document is read. It returns an
+// error if there is no array of tables with the given name.
+func ReadTOML(r io.Reader, table string) (*DF, error) {
+	var recs []map[string]any
+	var names []string
+	seen := map[string]bool{}
+	found := false
+
+	var rec map[string]any
+	scanner := bufio.NewScanner(r)
+	num := 0
+	for scanner.Scan() {
+		num++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name, isArray, err := tomlHeader(line)
+			if err != nil && rec == nil {
+				continue // perhaps part of an array outside the table
+			}
+			if err != nil {
+				return nil, dfErrorf("cannot read the TOML data: line %d: %s",
+					num, errText(err))
+			}
+			rec = nil
+			if table == "" && isArray {
+				table = name
+			}
+			switch {
+			case name == table && isArray:
+				found = true
+				rec = map[string]any{}
+				recs = append(recs, rec)
+			case name == table || strings.HasPrefix(name, table+"."):
+				return nil, dfErrorf("cannot read the TOML data: line %d:"+
+					" table [%s] is not supported", num, name)
+			}
+			continue
+		}
+		if rec == nil {
+			continue
+		}
+
+		key, rest, err := tomlKey(line)
+		if err == nil && !strings.HasPrefix(rest, "=") {
+			err = dfErrorf("expected 'key = value', found %q", line)
+		}
+		var v any
+		if err == nil {
+			v, err = tomlVal(rest[1:])
+		}
+		if err != nil {
+			return nil, dfErrorf("cannot read the TOML data: line %d: %s",
+				num, errText(err))
+		}
+		if _, dup := rec[key]; dup {
+			return nil, dfErrorf("cannot read the TOML data: line %d:"+
+				" duplicate key: %q", num, key)
+		}
+		rec[key] = v
+		if !seen[key] {
+			seen[key] = true
+			names = append(names, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, dfWrapf(err, "cannot read the TOML data")
+	}
+	if !found && table == "" {
+		return nil, dfErrorf("cannot read the TOML data:" +
+			" there is no array of tables")
+	}
+	if !found {
+		return nil, dfErrorf("cannot read the TOML data:"+
+			" there is no array of tables named %q", table)
+	}
+
+	df, err := fromRecords(recs, names)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the TOML data")
+	}
+	return df, nil
+}
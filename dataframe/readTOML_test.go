@@ -0,0 +1,120 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadTOML(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		table   string
+		data    string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID:    testhelper.MkID("key union, types inferred"),
+			table: "fruit",
+			data: "title = \"stock\"\n" +
+				"tags = [\n" +
+				"  [1, 2],\n" +
+				"]\n" +
+				"\n" +
+				"[[fruit]]  # first\n" +
+				"name = \"apple\"\n" +
+				"qty = 1_000\n" +
+				"fresh = true\n" +
+				"picked = 2024-05-27T07:32:00Z\n" +
+				"\n" +
+				"[owner]\n" +
+				"name = \"ignored\"\n" +
+				"\n" +
+				"[[ fruit ]]\n" +
+				"'name' = 'C:\\pear'\n" +
+				"price = 1.25 # each\n" +
+				"qty = 0x10\n" +
+				"\n" +
+				"[[other]]\n" +
+				"x = 1\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("fresh", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("picked", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{
+				{"apple", "1000", "true", "2024-05-27T07:32:00Z", "NA"},
+				{`C:\pear`, "16", "NA", "NA", "1.25"},
+			},
+		},
+		{
+			ID:    testhelper.MkID("first table"),
+			table: "",
+			data:  "[owner]\nname = \"x\"\n[[veg]]\nname = \"kale\"\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+			},
+			expVals: [][]string{{"kale"}},
+		},
+		{
+			ID:     testhelper.MkID("no table"),
+			table:  "",
+			ExpErr: testhelper.MkExpErr("there is no array of tables"),
+			data:   "[owner]\nname = \"x\"\n",
+		},
+		{
+			ID:    testhelper.MkID("no such table"),
+			table: "veg",
+			ExpErr: testhelper.MkExpErr(
+				`there is no array of tables named "veg"`),
+			data: "[[fruit]]\nname = \"apple\"\n",
+		},
+		{
+			ID:    testhelper.MkID("nested table"),
+			table: "fruit",
+			ExpErr: testhelper.MkExpErr(
+				"line 3: table [fruit.colour] is not supported"),
+			data: "[[fruit]]\nname = \"apple\"\n[fruit.colour]\nred = 1\n",
+		},
+		{
+			ID:    testhelper.MkID("array value"),
+			table: "fruit",
+			ExpErr: testhelper.MkExpErr("line 2:",
+				`unsupported TOML value: "[1, 2]"`),
+			data: "[[fruit]]\nsizes = [1, 2]\n",
+		},
+		{
+			ID:    testhelper.MkID("dotted key"),
+			table: "fruit",
+			ExpErr: testhelper.MkExpErr("line 2:",
+				"dotted keys are not supported"),
+			data: "[[fruit]]\ncolour.red = 1\n",
+		},
+		{
+			ID:     testhelper.MkID("duplicate key"),
+			table:  "fruit",
+			ExpErr: testhelper.MkExpErr(`line 3: duplicate key: "a"`),
+			data:   "[[fruit]]\na = 1\na = 2\n",
+		},
+		{
+			ID:     testhelper.MkID("bad value"),
+			table:  "fruit",
+			ExpErr: testhelper.MkExpErr(`line 2: bad value: "apple"`),
+			data:   "[[fruit]]\na = apple\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.ReadTOML(strings.NewReader(tc.data), tc.table)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
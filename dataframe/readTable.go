@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/nickwells/check.mod/v2/check"
 	"github.com/nickwells/location.mod/location"
@@ -23,17 +26,81 @@ type dfReadState struct {
 	dataLineNum int64
 	line        string
 	cols        []string
+	isNA        []bool
 	cache       [][]string
+	cacheLines  []int64
+
+	colsChecked bool
+	colChecks   []colCheckAt
+
+	// stopped is set when a line matching a StopAtLine pattern is read
+	stopped bool
+
+	// headerRows holds the header lines read so far and headerMeta the
+	// column metadata taken from them (see DFRHeaderLines)
+	headerRows [][]string
+	headerMeta []colMetaSetting
+
+	// transposed holds the split lines when reading transposed data (see
+	// DFRTransposed)
+	transposed [][]string
+
+	// rowLines holds the source line number of each row (see
+	// DFRLineNumCol)
+	rowLines []int64
+
+	// cacheText holds the text of each of the cached lines and lineErr the
+	// first error for the current line (see DFRQuarantine)
+	cacheText []string
+	lineErr   error
+
+	// keepErrText is set if the text of the line is to be recorded in any
+	// ParseError (see DFRErrorText)
+	keepErrText bool
+
+	// sampleRand chooses the rows to keep and sampleSeen counts the rows
+	// read so far (see SampleRows)
+	sampleRand *rand.Rand
+	sampleSeen int64
+}
+
+// parseError returns a ParseError of the given kind and code for the
+// current line.
+func (state *dfReadState) parseError(kind dfError, code ErrorCode,
+	msg string,
+) ParseError {
+	return ParseError{
+		Source: state.loc.Source(),
+		Line:   state.loc.Idx(),
+		Text:   state.lineText(state.line),
+		Col:    -1,
+		Msg:    msg,
+		Code:   code,
+		kind:   kind,
+	}
+}
+
+// lineText returns the text of the line to be recorded in any ParseError:
+// the text itself if DFRErrorText was given and otherwise the empty string
+func (state *dfReadState) lineText(text string) string {
+	if !state.keepErrText {
+		return ""
+	}
+	return text
 }
 
 // newDFReadState creates a dfReadState in an initial state
 func newDFReadState(dfr *DFReader, source string) *dfReadState {
 	state := &dfReadState{
-		loc: location.New(source),
+		loc:         location.New(source),
+		keepErrText: dfr.errorText,
+	}
+	if dfr.sampleRows > 0 {
+		state.sampleRand = rand.New(rand.NewSource(dfr.sampleSeed))
 	}
 
-	if dfr.initialLines > 0 {
-		state.cache = make([][]string, 0, dfr.initialLines)
+	if n := dfr.cacheLines(); n > 0 {
+		state.cache = make([][]string, 0, n)
 	}
 
 	return state
@@ -41,23 +108,71 @@ func newDFReadState(dfr *DFReader, source string) *dfReadState {
 
 const defaultSplitPattern = `\s+`
 
+// defaultSplitRegex is the compiled defaultSplitPattern. It is shared by
+// every DFReader not given a SplitPattern so that a DFReader can tell
+// whether one has been given (see usesLineOpts).
+var defaultSplitRegex = regexp.MustCompile(defaultSplitPattern)
+
 // DFReader holds the configurable options for building a dataframe from
-// an io.Reader
+// an io.Reader.
+//
+// A DFReader is not changed once it has been created: all the state of a
+// read is held separately for each call and any extra options given to a
+// read are applied to a copy. So a single DFReader may be used by several
+// goroutines at once to read different sources. Note that this relies on
+// the options not being applied directly to a DFReader after it has been
+// created and on any check functions (see DFRColCheck) being safe to call
+// concurrently.
 type DFReader struct {
 	hasHeader      bool
+	headerLines    int
+	headerMetaKeys []string
 	skipBlankLines bool
 	allowErrors    bool
-
-	commentRegex *regexp.Regexp
+	quarantine     bool
+	errorText      bool
+	maxErrors      int
+	failAfter      int64
+	recoverPanics  bool
+	onError        func(error)
+	roundTrip      bool
+	quotedFields   bool
+	transposed     bool
+
+	commentRegex     *regexp.Regexp
+	anchoredComments bool
+	skipCommentLines bool
 
 	colNames     []string
 	colTypes     []ColType
 	skipLines    int64
+	skipRegexes  []*regexp.Regexp
+	stopRegexes  []*regexp.Regexp
 	initialLines int64
 	skipCols     map[int]bool
 
 	maxCols    int
 	splitRegex *regexp.Regexp
+
+	requiredCols   []string
+	numericCols    []string
+	stringCols     []string
+	strictTypes    bool
+	schema         *Schema
+	colChecks      []colCheck
+	dropFailedRows bool
+	colMeta        []colMetaSetting
+	compressCols   []string
+	autoCompress   float64
+	summaryCols    []string
+	summarise      bool
+	sampleRows     int
+	sampleSeed     int64
+
+	rowIDCol   string
+	lineNumCol string
+
+	parseOpts parseOpts
 }
 
 type DFReaderOpt func(*DFReader) error
@@ -67,30 +182,110 @@ type DFReaderOpt func(*DFReader) error
 func NewDFReader(opts ...DFReaderOpt) (*DFReader, error) {
 	dfr := &DFReader{
 		initialLines: 10,
-		splitRegex:   regexp.MustCompile(defaultSplitPattern),
+		splitRegex:   defaultSplitRegex,
 		skipCols:     make(map[int]bool),
 		maxCols:      -1,
+		maxErrors:    -1,
 	}
+	if err := dfr.applyOpts(opts); err != nil {
+		return nil, err
+	}
+
+	return dfr, nil
+}
+
+// applyOpts applies the options to the DFReader and then checks that the
+// resulting settings are consistent
+func (dfr *DFReader) applyOpts(opts []DFReaderOpt) error {
 	for _, o := range opts {
 		err := o(dfr)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	if dfr.initialLines == 0 && len(dfr.colTypes) == 0 {
-		return nil, ErrNoTypeInfo
+	if dfr.quotedFields && dfr.roundTrip {
+		return dfErrorf("quoted fields cannot be given in round-trip" +
+			" mode, which has its own quoting")
 	}
 
-	if dfr.hasHeader {
-		dfr.initialLines++
+	if dfr.transposed && dfr.roundTrip {
+		return dfErrorf("transposed data cannot be read in round-trip mode")
 	}
 
-	if len(dfr.colTypes) != 0 {
-		dfr.initialLines = 0
+	if dfr.transposed && dfr.headerLines > 1 {
+		return dfErrorf("a header spanning several lines cannot be given" +
+			" with transposed data")
 	}
 
-	return dfr, nil
+	if dfr.headerLines > 1 && dfr.roundTrip {
+		return dfErrorf("a header spanning several lines cannot be given" +
+			" in round-trip mode")
+	}
+
+	if dfr.quarantine && !dfr.allowErrors {
+		return dfErrorf("bad lines can only be quarantined if errors" +
+			" are allowed")
+	}
+
+	if dfr.initialLines == 0 && len(dfr.colTypes) == 0 && !dfr.roundTrip {
+		return ErrNoTypeInfo
+	}
+
+	return nil
+}
+
+// clone returns a copy of the DFReader which shares no mutable state with
+// the original so options can be applied to it without changing the
+// original
+func (dfr *DFReader) clone() *DFReader {
+	c := *dfr
+
+	c.skipCols = make(map[int]bool, len(dfr.skipCols))
+	for k, v := range dfr.skipCols {
+		c.skipCols[k] = v
+	}
+	c.headerMetaKeys = append([]string(nil), dfr.headerMetaKeys...)
+	c.colNames = append([]string(nil), dfr.colNames...)
+	c.colTypes = append([]ColType(nil), dfr.colTypes...)
+	c.skipRegexes = append([]*regexp.Regexp(nil), dfr.skipRegexes...)
+	c.stopRegexes = append([]*regexp.Regexp(nil), dfr.stopRegexes...)
+	c.requiredCols = append([]string(nil), dfr.requiredCols...)
+	c.numericCols = append([]string(nil), dfr.numericCols...)
+	c.stringCols = append([]string(nil), dfr.stringCols...)
+	c.colChecks = append([]colCheck(nil), dfr.colChecks...)
+	c.colMeta = append([]colMetaSetting(nil), dfr.colMeta...)
+	c.compressCols = append([]string(nil), dfr.compressCols...)
+	c.summaryCols = append([]string(nil), dfr.summaryCols...)
+
+	return &c
+}
+
+// withOpts returns the DFReader to use for a single read. If there are no
+// extra options this is the DFReader itself, otherwise it is a copy with
+// the options applied.
+func (dfr *DFReader) withOpts(extraOpts []DFReaderOpt) (*DFReader, error) {
+	if len(extraOpts) == 0 {
+		return dfr, nil
+	}
+
+	c := dfr.clone()
+	if err := c.applyOpts(extraOpts); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// cacheLines returns the number of lines to be cached at the start of the
+// input so that the column types can be found
+func (dfr *DFReader) cacheLines() int64 {
+	if len(dfr.colTypes) != 0 || dfr.roundTrip {
+		return 0
+	}
+	if dfr.hasHeader {
+		return dfr.initialLines + 1
+	}
+	return dfr.initialLines
 }
 
 // HasHeader will cause the DFReader to treat the first line
@@ -118,34 +313,42 @@ func AllowErrors(dfr *DFReader) error {
 	return nil
 }
 
+// DFRErrorText will cause the DFReader to record the text of the line in
+// each ParseError found while reading (see ParseError.Text) so that the
+// line can be seen without going back to the source. Note that this will
+// keep the text of up to MaxErrors lines.
+func DFRErrorText(dfr *DFReader) error {
+	dfr.errorText = true
+	return nil
+}
+
 // DFRSkipCols returns a function which will specify the columns in the
 // source data to be skipped. Note that columns are numbered from zero not
 // one.
 func DFRSkipCols(skips ...int) DFReaderOpt {
-	if len(skips) == 0 {
-		panic(ErrNoSkipColsGiven)
-	}
-
-	if err := check.SliceAll[[]int](check.ValGE(int(0)))(skips); err != nil {
-		panic(dfErrorf("a negative skip index has been given: %s", err))
-	}
-
-	if err := check.SliceHasNoDups(skips); err != nil {
-		panic(dfErrorf("a duplicate skip index has been given: %s", err))
-	}
-
 	return func(dfr *DFReader) error {
+		if len(skips) == 0 {
+			return ErrNoSkipColsGiven
+		}
+
 		if len(dfr.skipCols) != 0 {
 			return ErrSkipIndexesAlreadySet
 		}
 
 		for i, si := range skips {
-			// we must repeat the duplicate test in case this is called twice
-			if _, ok := dfr.skipCols[si]; ok {
-				return dfErrorf(
-					"a duplicate skip index has been given: skips[%d] == %d",
+			if si < 0 {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"a negative skip index has been given: skips[%d] == %d",
 					i, si)
 			}
+		}
+
+		if err := check.SliceHasNoDups(skips); err != nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"a duplicate skip index has been given: %s", err)
+		}
+
+		for _, si := range skips {
 			dfr.skipCols[si] = true
 		}
 
@@ -153,6 +356,50 @@ func DFRSkipCols(skips ...int) DFReaderOpt {
 	}
 }
 
+// DFRRequireCols returns a function which will cause the DFReader to check
+// that the named columns are all present, in any order. The check is made
+// as soon as the column names are known, typically from the header line,
+// and if any are missing the Read fails, regardless of AllowErrors, with an
+// error listing all the missing columns. The error will match
+// ErrUnknownColumn when tested using errors.Is.
+func DFRRequireCols(names ...string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if len(names) == 0 {
+			return ErrNoNamesGiven
+		}
+		for i, n := range names {
+			if n == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"required column %d has an empty name", i)
+			}
+		}
+		if err := check.SliceHasNoDups(names); err != nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"a required column is duplicated: %s", err)
+		}
+
+		dfr.requiredCols = append(dfr.requiredCols, names...)
+		return nil
+	}
+}
+
+// checkRequiredCols returns an error listing any required columns which
+// are not in the dataframe
+func (dfr *DFReader) checkRequiredCols(state *dfReadState, df *DF) error {
+	var missing []string
+	for _, name := range dfr.requiredCols {
+		if _, ok := df.mci.colIdx(name); !ok {
+			missing = append(missing, strconv.Quote(name))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return dfKindErrorf(ErrUnknownColumn,
+		"%s: %d required columns are missing: %s",
+		state.loc.Source(), len(missing), strings.Join(missing, ", "))
+}
+
 // DFRColNames returns a function which will specify the column names
 // for the DFReader to use
 func DFRColNames(names ...string) DFReaderOpt {
@@ -170,7 +417,7 @@ func DFRColNames(names ...string) DFReaderOpt {
 		}
 
 		if len(dfr.colTypes) != 0 && len(dfr.colTypes) != len(names) {
-			return dfErrorf(
+			return dfCodeErrorf(ErrCodeBadOption,
 				"the number of column types (%d) and names (%d) differ",
 				len(dfr.colTypes), len(names))
 		}
@@ -194,7 +441,7 @@ func DFRColTypes(types ...ColType) DFReaderOpt {
 		}
 
 		if len(dfr.colNames) != 0 && len(dfr.colNames) != len(types) {
-			return dfErrorf(
+			return dfCodeErrorf(ErrCodeBadOption,
 				"the number of column types (%d) and names (%d) differ",
 				len(types), len(dfr.colNames))
 		}
@@ -207,14 +454,51 @@ func DFRColTypes(types ...ColType) DFReaderOpt {
 
 // SkipLines returns a function which will specify the number of lines for
 // the DFReader to skip at the start of the input. The default is zero. It
-// will panic if the number of lines passed is less than 0.
+// will return an error if the number of lines passed is less than 0.
 func SkipLines(n int64) DFReaderOpt {
-	if n < 0 {
-		panic(dfErrorf("the number of lines to skip (%d) must be >= 0", n))
+	return func(dfr *DFReader) error {
+		if n < 0 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the number of lines to skip (%d) must be >= 0", n)
+		}
+		dfr.skipLines = n
+		return nil
 	}
+}
 
+// SkipLinesMatching returns a function which will cause the DFReader to
+// skip any line which matches the regular expression, such as repeated
+// page headers or separator rules ("^-+$") in a report. The line is
+// matched before any comments are stripped. It may be given more than once
+// in which case lines matching any of the patterns are skipped. The
+// pattern is not used by ReadRecords or ReadCSVRecords.
+func SkipLinesMatching(pattern string) DFReaderOpt {
 	return func(dfr *DFReader) error {
-		dfr.skipLines = n
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the pattern for lines to skip is invalid: %s", err)
+		}
+		dfr.skipRegexes = append(dfr.skipRegexes, re)
+		return nil
+	}
+}
+
+// StopAtLine returns a function which will cause the DFReader to stop
+// reading when it reads a line which matches the regular expression, such
+// as a trailer ("^END OF REPORT"). The matching line and any lines after
+// it are not read. Lines skipped by SkipLines are not matched. It may be
+// given more than once in which case reading stops at the first line
+// matching any of the patterns. The pattern is not used by ReadRecords or
+// ReadCSVRecords.
+func StopAtLine(pattern string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the pattern for the line to stop at is invalid: %s", err)
+		}
+		dfr.stopRegexes = append(dfr.stopRegexes, re)
 		return nil
 	}
 }
@@ -226,7 +510,7 @@ func SkipLines(n int64) DFReaderOpt {
 func InitialLines(n int64) DFReaderOpt {
 	return func(dfr *DFReader) error {
 		if n < 0 {
-			return dfErrorf(
+			return dfCodeErrorf(ErrCodeBadOption,
 				"the number of lines to decide column type (%d) must be >= 0",
 				n)
 		}
@@ -244,13 +528,34 @@ func CommentPattern(pattern string) DFReaderOpt {
 		dfr.commentRegex, err = regexp.Compile(pattern)
 
 		if err != nil {
-			err = dfErrorf("the regexp to strip comments is invalid: %s", err)
+			err = dfCodeErrorf(ErrCodeBadOption,
+				"the regexp to strip comments is invalid: %s", err)
 		}
 
 		return err
 	}
 }
 
+// DFRAnchoredComments will cause the DFReader to only treat a match of the
+// comment pattern as the start of a comment if it is at the start of the
+// line or is preceded by (or starts with) white space. If quoted fields are
+// allowed (see DFRQuotedFields and DFRRoundTrip) a match within a quoted
+// field is also ignored. This stops values such as "item#3" from being
+// mangled.
+func DFRAnchoredComments(dfr *DFReader) error {
+	dfr.anchoredComments = true
+	return nil
+}
+
+// DFRSkipCommentLines will cause the DFReader to skip lines which hold
+// only a comment (possibly preceded by white space) rather than treating
+// them as blank lines, so they are allowed even if SkipBlankLines has not
+// been given.
+func DFRSkipCommentLines(dfr *DFReader) error {
+	dfr.skipCommentLines = true
+	return nil
+}
+
 // SplitPattern returns a function which will specify the regular expression
 // used by the DFReader when splitting lines into columns.
 func SplitPattern(pattern string) DFReaderOpt {
@@ -259,24 +564,45 @@ func SplitPattern(pattern string) DFReaderOpt {
 
 		dfr.splitRegex, err = regexp.Compile(pattern)
 		if err != nil {
-			err = dfErrorf("the pattern for splitting lines is invalid: %s",
-				err)
+			err = dfCodeErrorf(ErrCodeBadOption,
+				"the pattern for splitting lines is invalid: %s", err)
 		}
 		return err
 	}
 }
 
-// ReadFile reads a file and converts the rows into a DataFrame.
+// ReadFile reads a file and converts the rows into a DataFrame. If a
+// format has been registered (see RegisterFormat) for the file's extension
+// the file is read using that format, otherwise it is read by a DFReader
+// as a table of values. The options are passed to the format's Read
+// function; a format which is not read by a DFReader may ignore them. A
+// csv or tsv file is read as a table of values if any options are given
+// which apply only to lines of text, such as SplitPattern. It returns an
+// error if the registered format cannot be read.
 func ReadFile(filename string, opts ...DFReaderOpt) (*DF, error) {
-	dfr, err := NewDFReader(opts...)
+	f, ok := FormatForFile(filename)
+	if !ok {
+		f, _ = FormatByName("table")
+	}
+	if f.Read == nil {
+		return nil, dfErrorf("file: %s: the %q format cannot be read",
+			filename, f.Name)
+	}
+
+	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
-	return dfr.ReadFile(filename)
+
+	defer file.Close()
+
+	return f.Read(file, "file: "+filename, opts...)
 }
 
-// ReadFile reads from the named file and populates the dataframe
-func (dfr *DFReader) ReadFile(filename string) (*DF, error) {
+// ReadFile reads from the named file and populates the dataframe. Any
+// extra options apply only to this read, see Read.
+func (dfr *DFReader) ReadFile(filename string, extraOpts ...DFReaderOpt,
+) (*DF, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -284,7 +610,7 @@ func (dfr *DFReader) ReadFile(filename string) (*DF, error) {
 
 	defer file.Close()
 
-	return dfr.Read(file, "file: "+filename)
+	return dfr.Read(file, "file: "+filename, extraOpts...)
 }
 
 // setColNames sets the column names either according to the option
@@ -305,14 +631,22 @@ func (dfr *DFReader) setColNames(state *dfReadState, df *DF) (bool, error) {
 	return false, df.SetColNames(names...)
 }
 
-// setColTypes sets the column names either according to the option
-// values or else to their default values
-func (dfr DFReader) setColTypes(df *DF, cache [][]string) error {
+// setColTypes sets the column types, if they have not already been set,
+// to those guessed from the cached lines. If the guessed types fail the
+// strict typing checks (see strictColTypes) then colErr is returned and
+// the types are not set.
+func (dfr DFReader) setColTypes(state *dfReadState, df *DF) (
+	colErr, err error,
+) {
 	if len(dfr.colTypes) != 0 {
-		return nil // the column types are already set
+		return nil, nil // the column types are already set
 	}
 
-	return df.SetColTypes(guessColTypes(df.mci.info, cache)...)
+	types := guessColTypes(dfr.parseOpts, df.mci.info, state.cache)
+	if err := dfr.strictColTypes(state, df.mci, types); err != nil {
+		return err, nil
+	}
+	return nil, df.SetColTypes(types...)
 }
 
 // makeDF will create a dataframe and then populate those members that can be
@@ -322,6 +656,10 @@ func (dfr DFReader) makeDF() (*DF, error) {
 	if err != nil {
 		return nil, err
 	}
+	df.mci.foldCase = dfr.parseOpts.foldCase
+	if dfr.maxErrors >= 0 {
+		df.maxErrors = dfr.maxErrors
+	}
 
 	if len(dfr.colNames) > 0 {
 		err := df.SetColNames(dfr.colNames...)
@@ -351,23 +689,49 @@ func canBeInt(v uint64) bool { return v&BitFlagInt == BitFlagInt }
 func canBeFloat(v uint64) bool { return v&BitFlagFloat == BitFlagFloat }
 
 // tryParse will try parsing each column in the rows slice with multiple parsing
-// routines and set the bits in canBeTypes appropriately
-func tryParse(canBeTypes []uint64, rows [][]string) {
+// routines and set the bits in canBeTypes appropriately. The column names are
+// taken from the ColInfo values. Empty fields are ignored unless the
+// EmptyFieldPolicy is EmptyAsString; a column with nothing but ignored
+// fields can only be a string column.
+func tryParse(po parseOpts, ci []ColInfo, canBeTypes []uint64,
+	rows [][]string,
+) {
+	ignoreEmpty := po.emptyFields != EmptyAsString
+	hasVals := make([]bool, len(canBeTypes))
+
 	for _, row := range rows {
 		for i, col := range row {
-			if _, err := strconv.ParseBool(col); err != nil {
+			if col == "" && ignoreEmpty {
+				continue
+			}
+			hasVals[i] = true
+
+			name := ""
+			if i < len(ci) {
+				name = ci[i].name
+			}
+
+			if _, err := po.boolFmt.ParseBool(col); err != nil {
 				canBeTypes[i] &= ^BitFlagBool
 			}
 
-			if _, err := strconv.ParseInt(col, 0, 64); err != nil {
+			if _, err := po.numFmt.ParseInt(col); err != nil {
 				canBeTypes[i] &= ^BitFlagInt
 			}
 
-			if _, err := strconv.ParseFloat(col, 64); err != nil {
+			if _, err := po.parseFloat(name, col); err != nil {
 				canBeTypes[i] &= ^BitFlagFloat
 			}
 		}
 	}
+
+	if ignoreEmpty {
+		for i, ok := range hasVals {
+			if !ok {
+				canBeTypes[i] = 0
+			}
+		}
+	}
 }
 
 // initTypeSlice will set the initial type values to all the possible values
@@ -378,8 +742,8 @@ func initTypeSlice(canBeTypes []uint64) {
 }
 
 // guessColTypes examines the set of strings and tries to work out what the
-// column types could be.
-func guessColTypes(ci []ColInfo, rows [][]string) []ColType {
+// column types could be, parsing the strings according to the parseOpts.
+func guessColTypes(po parseOpts, ci []ColInfo, rows [][]string) []ColType {
 	if len(ci) == 0 {
 		return nil
 	}
@@ -387,7 +751,7 @@ func guessColTypes(ci []ColInfo, rows [][]string) []ColType {
 	canBeTypes := make([]uint64, len(ci))
 	initTypeSlice(canBeTypes)
 
-	tryParse(canBeTypes, rows)
+	tryParse(po, ci, canBeTypes, rows)
 
 	types := make([]ColType, len(ci))
 	for i, v := range canBeTypes {
@@ -409,24 +773,105 @@ func guessColTypes(ci []ColInfo, rows [][]string) []ColType {
 	return types
 }
 
+// inQuotes returns true if the end of the text is within a quoted field,
+// treating quotes according to whether the reader is in round-trip mode
+// (Go quoting) or has quoted fields (CSV quoting). It always returns false
+// if fields cannot be quoted.
+func (dfr *DFReader) inQuotes(text string) bool {
+	if !dfr.roundTrip && !dfr.quotedFields {
+		return false
+	}
+
+	inQuote := false
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			if inQuote && dfr.roundTrip {
+				i++
+			}
+		case '"':
+			inQuote = !inQuote
+		}
+	}
+	return inQuote
+}
+
+// commentStart returns the offset of the start of the comment in the line
+// or -1 if there is no comment
+func (dfr *DFReader) commentStart(line string) int {
+	if !dfr.anchoredComments {
+		loc := dfr.commentRegex.FindStringIndex(line)
+		if loc == nil {
+			return -1
+		}
+		return loc[0]
+	}
+
+	for _, loc := range dfr.commentRegex.FindAllStringIndex(line, -1) {
+		start := loc[0]
+		if start != 0 &&
+			!unicode.IsSpace(rune(line[start-1])) &&
+			!(loc[1] > start && unicode.IsSpace(rune(line[start]))) {
+			continue
+		}
+		if dfr.inQuotes(line[:start]) {
+			continue
+		}
+		return start
+	}
+	return -1
+}
+
 // stripComments removes any comments from the line and returns the stripped
-// line
+// line. If comment lines are to be skipped and the line holds only a
+// comment then skip is set to true.
 func stripComments(dfr *DFReader, state *dfReadState, _ *DF) (bool, error) {
 	if dfr.commentRegex == nil {
 		return false, nil
 	}
 
-	parts := dfr.commentRegex.Split(state.line, -1)
-	state.line = parts[0]
+	start := dfr.commentStart(state.line)
+	if start < 0 {
+		return false, nil
+	}
+	if dfr.skipCommentLines && strings.TrimSpace(state.line[:start]) == "" {
+		return true, nil
+	}
+	state.line = state.line[:start]
 	return false, nil
 }
 
 // splitLine will first split the line into a slice of strings and then
-// remove from that slice those columns to be skipped. It will return an
-// error if any of the columns to be skipped has an index greater than the
-// maximum index into the slice.
+// remove from that slice those columns to be skipped (see removeSkipCols).
 func splitLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
-	state.cols = dfr.splitRegex.Split(state.line, dfr.maxCols)
+	if dfr.roundTrip || dfr.quotedFields {
+		unquote := goUnquote
+		if dfr.quotedFields {
+			unquote = csvUnquote
+		}
+		cols, isNA, err := splitQuotedLine(state.line, dfr.splitRegex, unquote)
+		if err != nil {
+			err := state.parseError(ErrParse, ErrCodeBadQuoting, err.Error())
+			df.addError(err)
+			if dfr.allowErrors {
+				return true, nil
+			}
+			return true, err
+		}
+		state.cols = cols
+		if dfr.roundTrip {
+			state.isNA = isNA
+		}
+	} else {
+		state.cols = dfr.splitRegex.Split(state.line, dfr.maxCols)
+	}
+	return removeSkipCols(dfr, state, df)
+}
+
+// removeSkipCols removes from the columns those which are to be skipped. It
+// will return an error if any of the columns to be skipped has an index
+// greater than the maximum index into the slice.
+func removeSkipCols(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 	colsToSkip := len(dfr.skipCols)
 	if colsToSkip == 0 {
 		return false, nil
@@ -436,6 +881,9 @@ func splitLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 		if dfr.skipCols[i] {
 			// remove the ith column
 			state.cols = append(state.cols[:i], state.cols[i+1:]...)
+			if state.isNA != nil {
+				state.isNA = append(state.isNA[:i], state.isNA[i+1:]...)
+			}
 			colsToSkip--
 			if colsToSkip == 0 {
 				break
@@ -443,8 +891,7 @@ func splitLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 		}
 	}
 	if colsToSkip > 0 {
-		errStr := fmt.Sprintf(
-			"%s: some skip columns are after the end of the line: ", state.loc)
+		errStr := "some skip columns are after the end of the line: "
 		maxIdx := len(state.cols) - 1
 		sep := ""
 		for i := range dfr.skipCols {
@@ -453,7 +900,8 @@ func splitLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 				sep = ", "
 			}
 		}
-		err := dfError(errStr)
+		err := state.parseError(ErrDimensionMismatch,
+			ErrCodeWrongFieldCount, errStr)
 		df.addError(err)
 		return false, err
 	}
@@ -471,6 +919,31 @@ func skipLine(dfr *DFReader, state *dfReadState, _ *DF) (bool, error) {
 	return false, nil
 }
 
+// stopAtLine checks to see if the line matches any of the patterns of
+// lines at which to stop and if so it records that reading has stopped and
+// sets skip to true. The error is always nil.
+func stopAtLine(dfr *DFReader, state *dfReadState, _ *DF) (bool, error) {
+	for _, re := range dfr.stopRegexes {
+		if re.MatchString(state.line) {
+			state.stopped = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// skipMatchingLine checks to see if the line matches any of the patterns
+// of lines to be skipped and if so sets skip to true. The error is always
+// nil.
+func skipMatchingLine(dfr *DFReader, state *dfReadState, _ *DF) (bool, error) {
+	for _, re := range dfr.skipRegexes {
+		if re.MatchString(state.line) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // skipBlankLine checks to see if the line is blank and if so sets skip to
 // true. It may also set an error if blank lines are not expected.
 func skipBlankLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
@@ -482,7 +955,8 @@ func skipBlankLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 		return true, nil
 	}
 
-	var err error = dfErrorf("%s: unexpected blank line", state.loc)
+	var err error = state.parseError(ErrParse, ErrCodeBlankLine,
+		"unexpected blank line")
 	df.addError(err)
 	if dfr.allowErrors {
 		err = nil
@@ -504,6 +978,9 @@ func handleLine1(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 	if dfr.allowErrors {
 		err = nil
 	}
+	if reqErr := dfr.checkRequiredCols(state, df); reqErr != nil {
+		return skip, reqErr
+	}
 	return skip, err
 }
 
@@ -519,10 +996,16 @@ func cacheData(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 
 	var err error
 	state.cache = append(state.cache, state.cols)
+	state.cacheLines = append(state.cacheLines, state.loc.Idx())
+	state.cacheText = append(state.cacheText, state.line)
 	if len(state.cache) == cap(state.cache) { // cache is full
-		err = populateDF(dfr, state, df)
+		var colErr error
+		colErr, err = populateDF(dfr, state, df)
 		state.cache = nil // we're finished with the cache now so clear it
 
+		if colErr != nil {
+			return true, colErr
+		}
 		if dfr.allowErrors {
 			err = nil
 		}
@@ -536,9 +1019,14 @@ func cacheData(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 // guessed. If the cache is full then the data is added to the dataframe
 // directly.
 func handleData(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
-	df.AddRowFromText(state.cols)
-	if !dfr.allowErrors && df.errCount != 0 {
-		return false, dfErrorf("%s: parsing errors", state.loc)
+	if err := dfr.checkCols(state, df); err != nil {
+		return false, err
+	}
+
+	err := dfr.addRow(state, df, state.cols, state.isNA,
+		state.loc.Idx(), state.line)
+	if !dfr.allowErrors && err != nil {
+		return false, dfWrapf(err, "%s: parsing errors", state.loc)
 	}
 	return false, nil
 }
@@ -553,12 +1041,13 @@ func checkColumns(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 	}
 
 	errStr := fmt.Sprintf(
-		"%s: the dataframe has %d columns but this line has %d: ",
-		state.loc, len(df.mci.info), len(state.cols))
+		"the dataframe has %d columns but this line has %d: ",
+		len(df.mci.info), len(state.cols))
 	for i, col := range state.cols {
 		errStr += fmt.Sprintf(" col %d: %q", i, col)
 	}
-	var err error = dfError(errStr)
+	var err error = state.parseError(ErrDimensionMismatch,
+		ErrCodeWrongFieldCount, errStr)
 	df.addError(err)
 	if dfr.allowErrors {
 		err = nil
@@ -566,21 +1055,51 @@ func checkColumns(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
 	return true, err
 }
 
-// Read will construct a DataFrame from the data read off the Reader.
-func (dfr *DFReader) Read(rd io.Reader, source string) (*DF, error) {
-	df, err := dfr.makeDF()
+// Read will construct a DataFrame from the data read off the Reader. Any
+// extra options are applied to a copy of the DFReader which is used for
+// this read only, so a shared configuration can be adjusted for a
+// particular source (for instance, with a different SkipLines value)
+// without changing the DFReader. An option which conflicts with the
+// DFReader's settings (for instance, giving column types when they have
+// already been given) returns an error as it would for NewDFReader.
+func (dfr *DFReader) Read(rd io.Reader, source string,
+	extraOpts ...DFReaderOpt,
+) (*DF, error) {
+	dfr, err := dfr.withOpts(extraOpts)
 	if err != nil {
 		return nil, err
 	}
 
 	state := newDFReadState(dfr, source)
+	return dfr.guardRead(state, func() (*DF, error) {
+		return dfr.read(rd, state)
+	})
+}
+
+// read constructs a DataFrame from the data read off the Reader
+func (dfr *DFReader) read(rd io.Reader, state *dfReadState) (*DF, error) {
+	df, err := dfr.makeDF()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dfr.startQuarantine(state, df); err != nil {
+		return nil, err
+	}
+	dfr.startErrStream(df)
 	operations := []lineHandler{
 		skipLine,
+		stopAtLine,
+		handleMetaLine,
+		skipMatchingLine,
 		stripComments,
 		skipBlankLine,
 		splitLine,
+		collectTransposed,
+		handleHeaderLines,
 		handleLine1,
 		checkColumns,
+		handleTypesLine,
 		cacheData,
 		handleData,
 	}
@@ -588,14 +1107,22 @@ func (dfr *DFReader) Read(rd io.Reader, source string) (*DF, error) {
 	scanner := bufio.NewScanner(rd)
 Loop:
 	for scanner.Scan() {
+		state.quarantineLine(df, state.line, state.loc.Idx())
+		if err := dfr.checkErrCount(state, df); err != nil {
+			return nil, err
+		}
 		state.loc.Incr()
 		state.line = scanner.Text()
+		dfr.completeRecord(scanner, state)
 
 		for _, op := range operations {
 			skip, err := op(dfr, state, df)
 			if err != nil {
 				return nil, err
 			}
+			if state.stopped {
+				break Loop
+			}
 			if skip {
 				continue Loop
 			}
@@ -604,34 +1131,153 @@ Loop:
 	if err = scanner.Err(); err != nil {
 		return nil, err
 	}
+	state.quarantineLine(df, state.line, state.loc.Idx())
+
+	if dfr.transposed {
+		return dfr.finishTransposed(state, df)
+	}
+	return dfr.finishRead(state, df)
+}
 
-	err = populateDF(dfr, state, df)
+// finishRead completes the dataframe once all the lines have been read,
+// populating it from any cached lines and making the final checks
+func (dfr *DFReader) finishRead(state *dfReadState, df *DF) (*DF, error) {
+	if state.dataLineNum == 0 {
+		if err := dfr.checkRequiredCols(state, df); err != nil {
+			return nil, err
+		}
+	}
+
+	colErr, err := populateDF(dfr, state, df)
+	if colErr != nil {
+		return nil, colErr
+	}
 
 	if !dfr.allowErrors && err != nil {
 		return nil, err
 	}
+	if err := dfr.checkErrCount(state, df); err != nil {
+		return nil, err
+	}
+
+	if err := dfr.checkCols(state, df); err != nil {
+		return nil, err
+	}
+
+	if err := dfr.addTraceCols(state, df); err != nil {
+		return nil, err
+	}
+	if dfr.sampleRows > 0 {
+		if err := df.Compress(dfr.compressCols...); err != nil {
+			return nil, dfWrapf(err, "%s: cannot compress the columns",
+				state.loc.Source())
+		}
+	}
+	if dfr.autoCompress > 0 {
+		df.AutoCompress(dfr.autoCompress)
+	}
+	df.updateSummaries()
+	df.errHook = nil
 
 	return df, nil
 }
 
 // populateDF populates the Dataframe from the values in the cache of initial
 // lines. It will use those values to guess at the data types of the columns
-// and only then will it populate the values.
-func populateDF(dfr *DFReader, state *dfReadState, df *DF) error {
+// and only then will it populate the values. If the columns fail the
+// checks (see checkCols) then colErr is returned and no values are added.
+func populateDF(dfr *DFReader, state *dfReadState, df *DF) (colErr, err error) {
 	if len(state.cache) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	err := dfr.setColTypes(df, state.cache)
-	if err != nil {
-		return err
+	colErr, err = dfr.setColTypes(state, df)
+	if colErr != nil || err != nil {
+		return colErr, err
+	}
+	if err := dfr.checkCols(state, df); err != nil {
+		return err, nil
+	}
+
+	state.quarantineLine(df, state.line, state.loc.Idx())
+
+	var firstErr error
+	var errCount int
+	for i, cols := range state.cache {
+		err := dfr.addRow(state, df, cols, nil,
+			state.cacheLines[i], state.cacheText[i])
+		state.quarantineLine(df, state.cacheText[i], state.cacheLines[i])
+		if err != nil {
+			errCount++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
 	}
-	df.AddRowsFromText(state.cache)
 
-	if df.errCount != 0 {
-		return dfErrorf("%s: %d errors parsing initial lines (first error: %s)",
-			state.loc.Source(), df.errCount, df.errors[0])
+	if firstErr != nil {
+		return nil, dfWrapf(firstErr,
+			"%s: errors parsing %d of the initial lines, the first is",
+			state.loc.Source(), errCount)
 	}
 
-	return nil
+	return nil, nil
+}
+
+// addRow adds a row to the dataframe from the text values and then applies
+// any checks to the new row. The line is the line number in the source and
+// the text is the text of the line. The first error found (if any) is
+// returned.
+func (dfr *DFReader) addRow(state *dfReadState, df *DF,
+	cols []string, isNA []bool, line int64, text string,
+) error {
+	text = state.lineText(text)
+	rows := df.RowCount()
+	err := df.addRowFromText(dfr.parseOpts,
+		cols, isNA, state.loc.Source(), line, text)
+	nullErr := dfr.checkSchemaNulls(state, df, cols, isNA, line, text)
+	if err == nil {
+		err = nullErr
+	}
+	ckErr := dfr.applyColChecks(state, df, cols, line, text)
+	if err == nil {
+		err = ckErr
+	}
+	dfr.recordRowLine(state, df, line)
+	if df.RowCount() > rows {
+		dfr.sampleRow(state, df)
+	}
+	return err
+}
+
+// checkCols checks the columns of the dataframe against the DFReader's
+// schema, if any, sets any column metadata, compresses any columns given
+// by DFRCompress (unless the rows are being sampled, see SampleRows) and
+// finds the columns for any column checks. The check is only made once,
+// as soon as the column names and types are known.
+func (dfr *DFReader) checkCols(state *dfReadState, df *DF) error {
+	if state.colsChecked {
+		return nil
+	}
+	state.colsChecked = true
+
+	if err := dfr.checkSchemaCols(state, df); err != nil {
+		return err
+	}
+	if err := dfr.setColMeta(state, df); err != nil {
+		return err
+	}
+	if dfr.sampleRows == 0 {
+		if err := df.Compress(dfr.compressCols...); err != nil {
+			return dfWrapf(err, "%s: cannot compress the columns",
+				state.loc.Source())
+		}
+	}
+	if dfr.summarise {
+		if err := df.TrackColSummaries(dfr.summaryCols...); err != nil {
+			return dfWrapf(err, "%s: cannot summarise the columns",
+				state.loc.Source())
+		}
+	}
+	return dfr.findColChecks(state, df)
 }
@@ -117,7 +117,7 @@ func TestTryParse(t *testing.T) {
 		canBeTypes := make([]uint64, len(tc.data[0]))
 
 		initTypeSlice(canBeTypes)
-		tryParse(canBeTypes, tc.data)
+		tryParse(parseOpts{}, nil, canBeTypes, tc.data)
 
 		for j, colT := range canBeTypes {
 			if colT != tc.expectedTypeFlags[j] {
@@ -1,6 +1,10 @@
 package dataframe_test
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/nickwells/dataframe.mod/dataframe"
@@ -40,10 +44,10 @@ func TestReadTable(t *testing.T) {
 			ID:       testhelper.MkID("good file - one line, four columns"),
 			fileName: fileNameLines1Cols4,
 			expCols: []dataframe.ColInfo{
-				dataframe.NewColInfo("V0", dataframe.ColTypeBool),
-				dataframe.NewColInfo("V1", dataframe.ColTypeInt),
-				dataframe.NewColInfo("V2", dataframe.ColTypeInt),
-				dataframe.NewColInfo("V3", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("V0", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("V1", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("V2", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("V3", dataframe.ColTypeInt),
 			},
 			expRowCount: 1,
 		},
@@ -68,3 +72,300 @@ func TestReadTable(t *testing.T) {
 		}
 	}
 }
+
+func TestDFRRequireCols(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data string
+		opts []dataframe.DFReaderOpt
+	}{
+		{
+			ID:   testhelper.MkID("all present, any order"),
+			data: "value extra timestamp\n1 a 100\n",
+			opts: []dataframe.DFReaderOpt{dataframe.HasHeader},
+		},
+		{
+			ID: testhelper.MkID("some missing"),
+			ExpErr: testhelper.MkExpErr(
+				`test data: 2 required columns are missing:` +
+					` "timestamp", "value"`),
+			data: "ts val\n100 1\n",
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader, dataframe.AllowErrors,
+			},
+		},
+		{
+			ID: testhelper.MkID("given names, one missing"),
+			ExpErr: testhelper.MkExpErr(
+				`test data: 1 required columns are missing: "value"`),
+			data: "100 1\n",
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColNames("timestamp", "val"),
+			},
+		},
+		{
+			ID: testhelper.MkID("empty input"),
+			ExpErr: testhelper.MkExpErr(
+				"2 required columns are missing"),
+			opts: []dataframe.DFReaderOpt{dataframe.HasHeader},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(append(tc.opts,
+			dataframe.DFRRequireCols("timestamp", "value"))...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+		}
+		_, err = dfr.Read(strings.NewReader(tc.data), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err != nil {
+			testhelper.DiffBool(t, tc.IDStr(), "unknown column error",
+				errors.Is(err, dataframe.ErrUnknownColumn), true)
+		}
+	}
+}
+
+func TestDFRRequireColsOpt(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		names []string
+	}{
+		{
+			ID:    testhelper.MkID("good"),
+			names: []string{"a", "b"},
+		},
+		{
+			ID:     testhelper.MkID("no names"),
+			ExpErr: testhelper.MkExpErr("no column names have been given"),
+		},
+		{
+			ID:     testhelper.MkID("empty name"),
+			ExpErr: testhelper.MkExpErr("required column 1 has an empty name"),
+			names:  []string{"a", ""},
+		},
+		{
+			ID:     testhelper.MkID("duplicate name"),
+			ExpErr: testhelper.MkExpErr("a required column is duplicated"),
+			names:  []string{"a", "b", "a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := dataframe.NewDFReader(
+			dataframe.DFRRequireCols(tc.names...))
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestSkipLinesMatching(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("page headers and rules"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.SkipLinesMatching(`^-+$`),
+				dataframe.SkipLinesMatching(`^Page [0-9]+`),
+			},
+			data: "name qty\n" +
+				"--------\n" +
+				"a 1\n" +
+				"Page 2 of 3\n" +
+				"--------\n" +
+				"b 2\n",
+			expVals: [][]string{{"a", "1"}, {"b", "2"}},
+		},
+		{
+			ID: testhelper.MkID("bad pattern"),
+			ExpErr: testhelper.MkExpErr(
+				"the pattern for lines to skip is invalid"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.SkipLinesMatching("*"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			df, err := dfr.Read(strings.NewReader(tc.data), "test data")
+			if err != nil {
+				t.Fatal("unexpected error: ", err)
+			}
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+
+	_, err := dataframe.NewDFReader(dataframe.SkipLines(-1))
+	testhelper.CheckExpErrWithID(t, "SkipLines: negative", err,
+		testhelper.MkExpErr("the number of lines to skip (-1) must be >= 0"))
+}
+
+func TestStopAtLine(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("trailer"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.StopAtLine(`^END OF REPORT`),
+			},
+			data: "name qty\n" +
+				"a 1\n" +
+				"b 2\n" +
+				"END OF REPORT\n" +
+				"Total: 3\n",
+			expVals: [][]string{{"a", "1"}, {"b", "2"}},
+		},
+		{
+			ID: testhelper.MkID("skipped lines are not matched"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.SkipLines(1),
+				dataframe.StopAtLine(`^=+$`),
+			},
+			data: "=====\n" +
+				"a 10\n" +
+				"=====\n" +
+				"b 20\n",
+			expVals: [][]string{{"a", "10"}},
+		},
+		{
+			ID: testhelper.MkID("bad pattern"),
+			ExpErr: testhelper.MkExpErr(
+				"the pattern for the line to stop at is invalid"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.StopAtLine("*"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			df, err := dfr.Read(strings.NewReader(tc.data), "test data")
+			if err != nil {
+				t.Fatal("unexpected error: ", err)
+			}
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
+
+func TestReadExtraOpts(t *testing.T) {
+	dfr, err := dataframe.NewDFReader(dataframe.HasHeader,
+		dataframe.DFRColTypes(dataframe.ColTypeString, dataframe.ColTypeInt))
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expVals [][]string
+	}{
+		{
+			ID:      testhelper.MkID("extra SkipLines"),
+			opts:    []dataframe.DFReaderOpt{dataframe.SkipLines(2)},
+			data:    "Report\n\nname qty\na 1\n",
+			expVals: [][]string{{"a", "1"}},
+		},
+		{
+			ID:      testhelper.MkID("shared settings unchanged"),
+			data:    "name qty\nb 2\n",
+			expVals: [][]string{{"b", "2"}},
+		},
+		{
+			ID: testhelper.MkID("extra skip columns"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRSkipCols(1),
+			},
+			data:    "name junk qty\nc x 3\n",
+			expVals: [][]string{{"c", "3"}},
+		},
+		{
+			ID:      testhelper.MkID("skip columns not kept"),
+			data:    "name qty\nd 4\n",
+			expVals: [][]string{{"d", "4"}},
+		},
+		{
+			ID: testhelper.MkID("conflicting option"),
+			ExpErr: testhelper.MkExpErr(
+				"the column types have already been set"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColTypes(dataframe.ColTypeInt),
+			},
+			data: "name qty\ne 5\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dfr.Read(strings.NewReader(tc.data), "test data",
+			tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
+
+func TestDFReaderConcurrent(t *testing.T) {
+	dfr, err := dataframe.NewDFReader(dataframe.HasHeader,
+		dataframe.DFRSkipCols(1),
+		dataframe.DFRColMeta("n", dataframe.MetaUnit, "kg"),
+		dataframe.InitialLines(2))
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+	}
+
+	const readers = 8
+	results := make([]*dataframe.DF, readers)
+	errs := make([]error, readers)
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var sb strings.Builder
+			sb.WriteString("name skip n\n")
+			for r := 0; r <= i; r++ {
+				fmt.Fprintf(&sb, "r%d x %d\n", r, i*100+r+10)
+			}
+			var opts []dataframe.DFReaderOpt
+			if i%2 == 1 {
+				opts = append(opts, dataframe.DFRRequireCols("name"))
+			}
+			results[i], errs[i] = dfr.Read(strings.NewReader(sb.String()),
+				fmt.Sprintf("source %d", i), opts...)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, df := range results {
+		id := fmt.Sprintf("reader %d", i)
+		if errs[i] != nil {
+			t.Errorf("%s: unexpected error: %s", id, errs[i])
+			continue
+		}
+		expVals := [][]string{}
+		for r := 0; r <= i; r++ {
+			expVals = append(expVals,
+				[]string{fmt.Sprintf("r%d", r), fmt.Sprint(i*100 + r + 10)})
+		}
+		checkDFVals(t, id, df, expVals)
+		unit, _, _ := df.ColMeta("n", dataframe.MetaUnit)
+		testhelper.DiffString(t, id, "unit", unit, "kg")
+	}
+}
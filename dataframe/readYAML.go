@@ -0,0 +1,280 @@
+package dataframe
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	yamlIntRE   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloatRE = regexp.MustCompile(
+		`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// yamlLine holds a significant line of a YAML document
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlPlainVal returns the value of a plain (unquoted) YAML scalar
+// resolved according to the YAML 1.2 core schema: null, bool, int, float
+// or, failing those, string
+func yamlPlainVal(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+
+	if yamlIntRE.MatchString(s) {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	}
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0o") {
+		if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+			return i
+		}
+	}
+	if yamlFloatRE.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// yamlQuoted parses the quoted scalar at the start of s, returning its
+// value and the rest of the text
+func yamlQuoted(s string) (string, string, error) {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '\'' && s[i] == '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return strings.ReplaceAll(s[1:i], "''", "'"), s[i+1:], nil
+		case q == '"' && s[i] == '\\':
+			i++
+		case q == '"' && s[i] == '"':
+			v, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", dfErrorf("bad double-quoted string: %s",
+					s[:i+1])
+			}
+			return v, s[i+1:], nil
+		}
+	}
+	return "", "", dfErrorf("unterminated quoted string: %s", s)
+}
+
+// yamlScalar returns the value of the YAML scalar, which may be followed
+// by a comment
+func yamlScalar(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	switch s[0] {
+	case '"', '\'':
+		v, rest, err := yamlQuoted(s)
+		if err != nil {
+			return nil, err
+		}
+		rest = strings.TrimSpace(rest)
+		if rest != "" && rest[0] != '#' {
+			return nil, dfErrorf("unexpected text after the quoted string: %q",
+				rest)
+		}
+		return v, nil
+	case '{', '[', '|', '>', '&', '*', '!', '@', '`':
+		return nil, dfErrorf("unsupported YAML value: %q"+
+			" (only scalar values are allowed)", s)
+	case '#':
+		return nil, nil
+	}
+
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return yamlPlainVal(s), nil
+}
+
+// yamlKeyVal splits the text of a mapping entry into the key and the value
+func yamlKeyVal(s string) (string, any, error) {
+	var key, rest string
+	if s[0] == '"' || s[0] == '\'' {
+		k, r, err := yamlQuoted(s)
+		if err != nil {
+			return "", nil, err
+		}
+		key, rest = k, strings.TrimLeft(r, " ")
+		if !strings.HasPrefix(rest, ":") {
+			return "", nil, dfErrorf("missing ':' after the key: %q", s)
+		}
+		rest = rest[1:]
+	} else {
+		i := strings.Index(s, ": ")
+		switch {
+		case i >= 0:
+			key, rest = s[:i], s[i+1:]
+		case strings.HasSuffix(s, ":"):
+			key, rest = s[:len(s)-1], ""
+		default:
+			return "", nil, dfErrorf("expected 'key: value', found %q", s)
+		}
+		key = strings.TrimSpace(key)
+	}
+	if rest != "" && rest[0] != ' ' {
+		return "", nil, dfErrorf("missing space after the ':': %q", s)
+	}
+
+	v, err := yamlScalar(rest)
+	if err != nil {
+		return "", nil, dfWrapf(err, "key %q", key)
+	}
+	return key, v, nil
+}
+
+// yamlLines returns the significant lines of the YAML document: blank
+// lines, comment lines and any document start marker are removed
+func yamlLines(r io.Reader) ([]yamlLine, error) {
+	var lines []yamlLine
+	scanner := bufio.NewScanner(r)
+	num := 0
+	for scanner.Scan() {
+		num++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		text := strings.TrimLeft(line, " ")
+		if text == "" || text[0] == '#' ||
+			(len(lines) == 0 && (text == "---" ||
+				strings.HasPrefix(text, "%"))) {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, dfErrorf("line %d: tabs must not be used for"+
+				" indentation", num)
+		}
+		if text == "---" || text == "..." {
+			return nil, dfErrorf("line %d: only a single YAML document"+
+				" is allowed", num)
+		}
+		lines = append(lines, yamlLine{
+			num:    num,
+			indent: len(line) - len(text),
+			text:   text,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, dfWrapf(err, "cannot read the YAML data")
+	}
+	return lines, nil
+}
+
+// yamlRecords parses the lines as a sequence of mappings, returning the
+// records and the keys in the order in which they first appear
+func yamlRecords(lines []yamlLine) ([]map[string]any, []string, error) {
+	var recs []map[string]any
+	var names []string
+	seen := map[string]bool{}
+
+	if len(lines) == 1 && lines[0].text == "[]" {
+		return recs, names, nil
+	}
+
+	seqIndent := 0
+	mapIndent := -1
+	var rec map[string]any
+	for i, l := range lines {
+		if i == 0 {
+			seqIndent = l.indent
+		}
+
+		text := l.text
+		switch {
+		case l.indent == seqIndent &&
+			(text == "-" || strings.HasPrefix(text, "- ")):
+			rec = map[string]any{}
+			recs = append(recs, rec)
+			text = strings.TrimLeft(strings.TrimPrefix(text, "-"), " ")
+			mapIndent = l.indent + len(l.text) - len(text)
+			if text == "" || text == "{}" {
+				mapIndent = -1
+				continue
+			}
+		case rec == nil:
+			return nil, nil, dfErrorf("line %d: the YAML document must be"+
+				" a sequence of mappings", l.num)
+		case mapIndent < 0 && l.indent > seqIndent:
+			mapIndent = l.indent
+		case l.indent != mapIndent:
+			return nil, nil, dfErrorf("line %d: bad indentation"+
+				" (nested values are not supported)", l.num)
+		}
+
+		k, v, err := yamlKeyVal(text)
+		if err != nil {
+			return nil, nil, dfWrapf(err, "line %d", l.num)
+		}
+		if _, dup := rec[k]; dup {
+			return nil, nil, dfErrorf("line %d: duplicate key: %q", l.num, k)
+		}
+		rec[k] = v
+		if !seen[k] {
+			seen[k] = true
+			names = append(names, k)
+		}
+	}
+	return recs, names, nil
+}
+
+// ReadYAML reads a YAML document which is a sequence of mappings, one per
+// row, such as:
+//
+//   - name: apple
+//     qty: 3
+//   - name: pear
+//     price: 1.25
+//
+// There is a column for each key found in any of the mappings, in the
+// order in which they first appear, and the column types are found from
+// the values as for FromRecords. Unquoted values are resolved according
+// to the YAML core schema so null (or ~ or an empty value) gives an NA
+// value as does a missing key. Only block-style mappings of scalar
+// values are supported; flow-style collections, nested values, anchors,
+// tags and multi-line strings give an error as do multiple documents.
+// Double-quoted strings may use the escape sequences allowed in Go
+// strings.
+func ReadYAML(r io.Reader) (*DF, error) {
+	lines, err := yamlLines(r)
+	if err != nil {
+		return nil, err
+	}
+	recs, names, err := yamlRecords(lines)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the YAML data")
+	}
+	df, err := fromRecords(recs, names)
+	if err != nil {
+		return nil, dfWrapf(err, "cannot read the YAML data")
+	}
+	return df, nil
+}
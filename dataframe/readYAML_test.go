@@ -0,0 +1,108 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadYAML(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data    string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("key union, types inferred"),
+			data: "---\n" +
+				"# fruit\n" +
+				"- name: apple   # a comment\n" +
+				"  qty: 3\n" +
+				"  fresh: true\n" +
+				"\n" +
+				"- name: 'pear''s'\n" +
+				"  price: 1.25\n" +
+				"  qty: ~\n" +
+				"-\n" +
+				"  name: \"fig\\tx\"\n" +
+				"  price: 2\n" +
+				"  note: null\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("fresh", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("note", dataframe.ColTypeString),
+			},
+			expVals: [][]string{
+				{"apple", "3", "true", "NA", "NA"},
+				{"pear's", "NA", "NA", "1.25", "NA"},
+				{"fig\tx", "NA", "NA", "2", "NA"},
+			},
+		},
+		{
+			ID: testhelper.MkID("indented sequence, quoted keys"),
+			data: "  - \"a b\": 0x10\n" +
+				"    c: 1e3\n" +
+				"  - c: .inf\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a b", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeFloat),
+			},
+			expVals: [][]string{{"16", "1000"}, {"NA", "+Inf"}},
+		},
+		{
+			ID:   testhelper.MkID("empty sequence"),
+			data: "[]\n",
+		},
+		{
+			ID: testhelper.MkID("not a sequence"),
+			ExpErr: testhelper.MkExpErr(
+				"line 1: the YAML document must be a sequence of mappings"),
+			data: "a: 1\n",
+		},
+		{
+			ID: testhelper.MkID("nested value"),
+			ExpErr: testhelper.MkExpErr("line 3: bad indentation",
+				"nested values are not supported"),
+			data: "- a:\n" +
+				"  b:\n" +
+				"    c: 1\n",
+		},
+		{
+			ID: testhelper.MkID("flow value"),
+			ExpErr: testhelper.MkExpErr(`line 1: key "a"`,
+				`unsupported YAML value: "[1, 2]"`),
+			data: "- a: [1, 2]\n",
+		},
+		{
+			ID:     testhelper.MkID("duplicate key"),
+			ExpErr: testhelper.MkExpErr(`line 2: duplicate key: "a"`),
+			data:   "- a: 1\n  a: 2\n",
+		},
+		{
+			ID: testhelper.MkID("type mismatch"),
+			ExpErr: testhelper.MkExpErr(`record 1, key "a"`,
+				"the value type (String) doesn't match the column type (Int)"),
+			data: "- a: 1\n- a: x\n",
+		},
+		{
+			ID: testhelper.MkID("multiple documents"),
+			ExpErr: testhelper.MkExpErr(
+				"line 2: only a single YAML document is allowed"),
+			data: "- a: 1\n---\n- a: 2\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.ReadYAML(strings.NewReader(tc.data))
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package dataframe
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// nativeVal returns the value at the indexed column and row as a bool,
+// int64, float64 or string, or nil if it is NA
+func (df *DF) nativeVal(colIdx, rowIdx int) any {
+	v, isNA := df.valAt(colIdx, rowIdx)
+	if isNA {
+		return nil
+	}
+	switch v := v.(type) {
+	case BoolVal:
+		return v.Val
+	case IntVal:
+		return v.Val
+	case FloatVal:
+		return v.Val
+	case StringVal:
+		return v.Val
+	}
+	panic(dfErrorf("Unexpected value type: %T", v))
+}
+
+// ToRecords returns the rows of the dataframe as a slice of maps, one per
+// row, mapping the column names to the values. The values are of type
+// bool, int64, float64 or string according to the column type and NA
+// values are nil. This suits code which expects loosely-typed records such
+// as JSON encoders and templates.
+func (df *DF) ToRecords() []map[string]any {
+	recs := make([]map[string]any, 0, df.RowCount())
+	for r := 0; r < df.RowCount(); r++ {
+		rec := make(map[string]any, len(df.mci.info))
+		for c, ci := range df.mci.info {
+			rec[ci.name] = df.nativeVal(c, r)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+// recordVal converts the value from a record into a bool, int64, float64
+// or string, or nil for an NA value, and returns it with its column type.
+// Any integer type and any of the Val types are allowed as are
+// json.Number values (as an int64 if possible).
+func recordVal(v any) (any, ColType, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, ColTypeUnknown, nil
+	case bool:
+		return v, ColTypeBool, nil
+	case float64:
+		return v, ColTypeFloat, nil
+	case float32:
+		return float64(v), ColTypeFloat, nil
+	case string:
+		return v, ColTypeString, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, ColTypeInt, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, ColTypeUnknown,
+				dfErrorf("bad number: %q: %s", v, errText(err))
+		}
+		return f, ColTypeFloat, nil
+	case BoolVal:
+		if v.IsNA {
+			return nil, ColTypeUnknown, nil
+		}
+		return v.Val, ColTypeBool, nil
+	case IntVal:
+		if v.IsNA {
+			return nil, ColTypeUnknown, nil
+		}
+		return v.Val, ColTypeInt, nil
+	case FloatVal:
+		if v.IsNA {
+			return nil, ColTypeUnknown, nil
+		}
+		return v.Val, ColTypeFloat, nil
+	case StringVal:
+		if v.IsNA {
+			return nil, ColTypeUnknown, nil
+		}
+		return v.Val, ColTypeString, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return rv.Int(), ColTypeInt, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return nil, ColTypeUnknown,
+				dfErrorf("the value is too large: %d", rv.Uint())
+		}
+		return int64(rv.Uint()), ColTypeInt, nil
+	}
+	return nil, ColTypeUnknown,
+		dfKindErrorf(ErrTypeMismatch, "unsupported value type: %T", v)
+}
+
+// mergeRecordType returns the column type which can hold values of both
+// types. Int and float values can be held in a float column; otherwise
+// the types must be the same.
+func mergeRecordType(ct, vt ColType) (ColType, bool) {
+	switch {
+	case ct == ColTypeUnknown || ct == vt:
+		return vt, true
+	case vt == ColTypeUnknown:
+		return ct, true
+	case ct == ColTypeInt && vt == ColTypeFloat,
+		ct == ColTypeFloat && vt == ColTypeInt:
+		return ColTypeFloat, true
+	}
+	return ct, false
+}
+
+// FromRecords returns a new dataframe made from the records, one row per
+// record, with a column for each key found in any record. The columns are
+// in order of their names. The column types are found from the values:
+// bool values give a bool column, values of any integer type give an int
+// column, float values give a float column (as do a mix of ints and
+// floats) and string values give a string column. The Val types and
+// json.Number values are also allowed; note that numbers decoded from JSON
+// are float64 values unless the decoder's UseNumber method has been
+// called. A nil value or a missing key gives an NA value and a column
+// with only NA values is a string column. It returns an error if a value
+// is of any other type or if a column has values of incompatible types.
+func FromRecords(recs []map[string]any) (*DF, error) {
+	keys := map[string]bool{}
+	names := []string{}
+	for _, rec := range recs {
+		for k := range rec {
+			if !keys[k] {
+				keys[k] = true
+				names = append(names, k)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return fromRecords(recs, names)
+}
+
+// fromRecords returns a new dataframe made from the records as for
+// FromRecords but with the columns in the order given by names which must
+// include every key in the records
+func fromRecords(recs []map[string]any, names []string) (*DF, error) {
+	types := make(map[string]ColType, len(names))
+	for _, name := range names {
+		types[name] = ColTypeUnknown
+	}
+
+	conv := make([]map[string]any, 0, len(recs))
+	for i, rec := range recs {
+		cRec := make(map[string]any, len(rec))
+		for k, v := range rec {
+			cv, vt, err := recordVal(v)
+			if err != nil {
+				return nil, dfWrapf(err, "record %d, key %q", i, k)
+			}
+			ct, ok := mergeRecordType(types[k], vt)
+			if !ok {
+				return nil, dfKindErrorf(ErrTypeMismatch,
+					"record %d, key %q: the value type (%s)"+
+						" doesn't match the column type (%s)", i, k, vt, ct)
+			}
+			types[k] = ct
+			cRec[k] = cv
+		}
+		conv = append(conv, cRec)
+	}
+
+	cis := make([]ColInfo, 0, len(names))
+	for _, name := range names {
+		ct := types[name]
+		if ct == ColTypeUnknown {
+			ct = ColTypeString
+		}
+		cis = append(cis, ColInfo{name: name, colType: ct})
+	}
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rec := range conv {
+		for c, name := range names {
+			if err := df.appendVal(c, rec[name]); err != nil {
+				return nil, dfWrapf(err, "record %d, key %q", i, name)
+			}
+		}
+	}
+	return df, nil
+}
@@ -0,0 +1,109 @@
+package dataframe_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFromRecords(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		recs    []map[string]any
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("mixed types, missing keys"),
+			recs: []map[string]any{
+				{"s": "a", "i": 1, "b": true, "f": 1.5, "n": nil},
+				{"s": "b", "i": uint8(2), "f": 2},
+				{"i": dataframe.IntVal{IsNA: true}, "u": int32(3)},
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("b", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("f", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("n", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("u", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"true", "1.5", "1", "NA", "a", "NA"},
+				{"NA", "2", "2", "NA", "b", "NA"},
+				{"NA", "NA", "NA", "NA", "NA", "3"},
+			},
+		},
+		{
+			ID: testhelper.MkID("json.Number"),
+			recs: []map[string]any{
+				{"i": json.Number("1"), "f": json.Number("1")},
+				{"i": json.Number("2"), "f": json.Number("2.5")},
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("f", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"1", "1"}, {"2.5", "2"}},
+		},
+		{
+			ID: testhelper.MkID("no records"),
+		},
+		{
+			ID: testhelper.MkID("type mismatch"),
+			ExpErr: testhelper.MkExpErr(`record 1, key "x"`,
+				"the value type (String)",
+				"doesn't match the column type (Int)"),
+			recs: []map[string]any{{"x": 1}, {"x": "a"}},
+		},
+		{
+			ID: testhelper.MkID("unsupported type"),
+			ExpErr: testhelper.MkExpErr(`record 0, key "x"`,
+				"unsupported value type: []int"),
+			recs: []map[string]any{{"x": []int{1}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := dataframe.FromRecords(tc.recs)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+	}
+}
+
+func TestToRecords(t *testing.T) {
+	df := mkTestDF(t, "b i f s\ntrue 1 1.5 a\nx y z b\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeBool, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+
+	recs := df.ToRecords()
+	exp := []map[string]any{
+		{"b": true, "i": int64(1), "f": 1.5, "s": "a"},
+		{"b": nil, "i": nil, "f": nil, "s": "b"},
+	}
+	if !reflect.DeepEqual(recs, exp) {
+		t.Log("ToRecords")
+		t.Logf("\t: expected: %v\n", exp)
+		t.Logf("\t:      got: %v\n", recs)
+		t.Error("\t: unexpected records")
+	}
+
+	rtDF, err := dataframe.FromRecords(recs)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	var sb strings.Builder
+	if err := rtDF.WriteCSV(&sb); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	testhelper.DiffString(t, "FromRecords(ToRecords())", "CSV",
+		sb.String(), "b,f,i,s\ntrue,1.5,1,a\n,,,b\n")
+}
@@ -0,0 +1,34 @@
+package dataframe
+
+// DFRRecoverPanics will cause the DFReader to recover from any panic during
+// a read, whether from a bug in this package or from a function supplied
+// by the caller such as a column check, and return it as an error instead.
+// The error gives the source and the line being read when the panic
+// occurred and will match ErrInternal when tested using errors.Is. This
+// allows a long-running service to survive a malformed input. Note that
+// the line number given may not be that of the line at fault if the panic
+// occurs once the whole of the data has been read.
+func DFRRecoverPanics(dfr *DFReader) error {
+	dfr.recoverPanics = true
+	return nil
+}
+
+// guardRead calls the read function, recovering from any panic and
+// returning it as an error if the DFReader is recovering panics
+func (dfr *DFReader) guardRead(state *dfReadState,
+	read func() (*DF, error),
+) (df *DF, err error) {
+	if !dfr.recoverPanics {
+		return read()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			df, err = nil, dfKindErrorf(ErrInternal,
+				"%s:%d: the read failed unexpectedly: %v",
+				state.loc.Source(), state.loc.Idx(), p)
+		}
+	}()
+
+	return read()
+}
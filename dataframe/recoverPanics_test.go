@@ -0,0 +1,74 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRRecoverPanics(t *testing.T) {
+	panicker := dataframe.DFRColCheck("i", func(v int64) error {
+		if v == 3 {
+			panic("bad value")
+		}
+		return nil
+	})
+	dfr, err := dataframe.NewDFReader(
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(dataframe.ColTypeInt),
+		dataframe.DFRRecoverPanics,
+		panicker)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		read func() (*dataframe.DF, error)
+	}{
+		{
+			ID: testhelper.MkID("Read, no panic"),
+			read: func() (*dataframe.DF, error) {
+				return dfr.Read(strings.NewReader("i\n1\n2\n"), "test data")
+			},
+		},
+		{
+			ID: testhelper.MkID("Read, panic"),
+			read: func() (*dataframe.DF, error) {
+				return dfr.Read(strings.NewReader("i\n1\n3\n4\n"), "test data")
+			},
+			ExpErr: testhelper.MkExpErr(
+				"test data:3: the read failed unexpectedly: bad value"),
+		},
+		{
+			ID: testhelper.MkID("ReadRecords, panic"),
+			read: func() (*dataframe.DF, error) {
+				return dfr.ReadRecords(
+					[][]string{{"i"}, {"3"}, {"1"}}, "test records")
+			},
+			ExpErr: testhelper.MkExpErr(
+				"test records:2: the read failed unexpectedly: bad value"),
+		},
+	}
+
+	for _, tc := range testCases {
+		df, err := tc.read()
+		if testhelper.CheckExpErr(t, err, tc) {
+			if err == nil {
+				continue
+			}
+			if df != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: no dataframe should be returned")
+			}
+			if !errors.Is(err, dataframe.ErrInternal) {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: the error should match ErrInternal")
+			}
+		}
+	}
+}
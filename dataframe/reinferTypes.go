@@ -0,0 +1,73 @@
+package dataframe
+
+// reinferColType returns the type that the values in the string column
+// would be given if they were read by a DFReader. NA values are ignored
+// and a column with no other values stays a string column.
+func (df *DF) reinferColType(i int) ColType {
+	vi := df.mci.valIdx[i]
+
+	var rows [][]string
+	for r := 0; r < df.stringColLen(vi); r++ {
+		if v := df.stringAt(vi, r); !v.IsNA {
+			rows = append(rows, []string{v.Val})
+		}
+	}
+	if len(rows) == 0 {
+		return ColTypeString
+	}
+	return guessColTypes(parseOpts{},
+		[]ColInfo{{name: df.mci.info[i].name}}, rows)[0]
+}
+
+// ReinferTypes returns a new dataframe with the types of the named string
+// columns, or of all the string columns if no names are given, guessed
+// again from all their values rather than just from the initial lines read
+// by the DFReader. This is useful where the initial lines were not
+// representative. A column whose values can all be read as bools, as ints
+// or as floats, taking the first that fits as when the data is read, is
+// converted to that type; any other column is left as a string column. NA
+// values are ignored and stay NA. It returns an error if any name is not
+// found or is not a string column.
+func (df *DF) ReinferTypes(names ...string) (*DF, error) {
+	cis := cloneColInfoSlice(df.mci.info)
+	if len(names) == 0 {
+		for i, ci := range cis {
+			if ci.colType == ColTypeString {
+				cis[i].colType = df.reinferColType(i)
+			}
+		}
+	}
+	for _, name := range names {
+		i, ok := df.mci.colIdx(name)
+		if !ok {
+			return nil, errUnknownColName(name)
+		}
+		err := assertTypeByName(cis[i].colType, ColTypeString, cis[i].name)
+		if err != nil {
+			return nil, err
+		}
+		cis[i].colType = df.reinferColType(i)
+	}
+
+	rval, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	rval.maxErrors = df.maxErrors
+	rval.meta = cloneMeta(df.meta)
+	rval.mci.foldCase = df.mci.foldCase
+
+	for r := 0; r < df.RowCount(); r++ {
+		for c, ci := range cis {
+			v, _ := df.valAt(c, r)
+			if v, err = convertVal(v, ci.colType); err != nil {
+				return nil, dfWrapf(err, "column %q: row %d", ci.name, r)
+			}
+			if err := rval.appendVal(c, v); err != nil {
+				return nil, dfWrapf(err, "column %q: row %d", ci.name, r)
+			}
+		}
+	}
+
+	return rval, nil
+}
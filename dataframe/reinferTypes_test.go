@@ -0,0 +1,102 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReinferTypes(t *testing.T) {
+	const content = `a b c d e
+1 1.5 true x 7
+2 2 false y 8
+`
+	df := mkTestDF(t, content,
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(
+			dataframe.ColTypeString, dataframe.ColTypeString,
+			dataframe.ColTypeString, dataframe.ColTypeString,
+			dataframe.ColTypeInt))
+	if err := df.SetMeta("src", "test"); err != nil {
+		t.Fatal("BAD TEST - cannot set the metadata: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		names   []string
+		expCols []dataframe.ColInfo
+	}{
+		{
+			ID: testhelper.MkID("all string columns"),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeBool),
+				dataframe.MustNewColInfo("d", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("e", dataframe.ColTypeInt),
+			},
+		},
+		{
+			ID:    testhelper.MkID("named columns"),
+			names: []string{"b", "d"},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("c", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("d", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("e", dataframe.ColTypeInt),
+			},
+		},
+		{
+			ID:    testhelper.MkID("unknown column"),
+			names: []string{"nonesuch"},
+			ExpErr: testhelper.MkExpErr(
+				`Unknown column name: "nonesuch"`),
+		},
+		{
+			ID:    testhelper.MkID("not a string column"),
+			names: []string{"e"},
+			ExpErr: testhelper.MkExpErr(
+				`The column named "e" is of type "Int" not "String"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		rval, err := df.ReinferTypes(tc.names...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), rval, tc.expCols)
+			checkDFVals(t, tc.IDStr(), rval, [][]string{
+				{"1", "1.5", "true", "x", "7"},
+				{"2", "2", "false", "y", "8"},
+			})
+			v, _ := rval.Meta("src")
+			testhelper.DiffString(t, tc.IDStr(), "meta", v, "test")
+		}
+	}
+}
+
+func TestReinferTypesNA(t *testing.T) {
+	const content = `a b
+String String
+"1" NA
+NA NA
+"3" NA
+`
+	df := mkTestDF(t, content, dataframe.DFRRoundTrip)
+	rval, err := df.ReinferTypes()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	id := "NA values"
+	checkColDetails(t, id, rval, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("a", dataframe.ColTypeInt),
+		dataframe.MustNewColInfo("b", dataframe.ColTypeString),
+	})
+	checkDFVals(t, id, rval, [][]string{
+		{"1", "NA"},
+		{"NA", "NA"},
+		{"3", "NA"},
+	})
+}
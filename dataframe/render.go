@@ -0,0 +1,40 @@
+package dataframe
+
+import (
+	"io"
+	"text/template"
+)
+
+// rowMap returns a map from the column names to the values in the i'th row
+// of the dataframe. The values are the Val types (BoolVal, IntVal etc).
+func (df *DF) rowMap(i int) map[string]any {
+	m := make(map[string]any, len(df.mci.info))
+	for c, ci := range df.mci.info {
+		v, _ := df.valAt(c, i)
+		m[ci.name] = v
+	}
+	return m
+}
+
+// RenderRows executes the template once for each row of the dataframe,
+// writing the results to the io.Writer. The template is given a map from
+// the column names to the values in the row; the values are the Val types
+// (BoolVal, IntVal etc) so {{.qty}} writes the value (or NA),
+// {{.qty.Val}} gives the underlying value and {{.qty.IsNA}} reports
+// whether it is available. A column name which is not a valid template
+// identifier can be given using the index function: {{index . "unit cost"}}.
+//
+// Rendering stops at the first row for which the template fails and the
+// error is returned.
+func (df *DF) RenderRows(w io.Writer, tmpl *template.Template) error {
+	if tmpl == nil {
+		return dfErrorf("no template has been given")
+	}
+
+	for i := 0; i < df.RowCount(); i++ {
+		if err := tmpl.Execute(w, df.rowMap(i)); err != nil {
+			return dfWrapf(err, "cannot render row %d", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestRenderRows(t *testing.T) {
+	df := mkTestDF(t,
+		"name qty price\n"+
+			"apple 3 0.5\n"+
+			"pear NA 0.75\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeString, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		tmpl string
+		exp  string
+	}{
+		{
+			ID:   testhelper.MkID("simple"),
+			tmpl: "{{.name}}: {{.qty}} @ {{.price}}\n",
+			exp:  "apple: 3 @ 0.5\npear: NA @ 0.75\n",
+		},
+		{
+			ID: testhelper.MkID("NA check and underlying value"),
+			tmpl: `{{.name}}={{if .qty.IsNA}}unknown` +
+				`{{else}}{{printf "%03d" .qty.Val}}{{end}};`,
+			exp: "apple=003;pear=unknown;",
+		},
+		{
+			ID:   testhelper.MkID("index by name"),
+			tmpl: `{{index . "name"}} `,
+			exp:  "apple pear ",
+		},
+		{
+			ID:     testhelper.MkID("bad template"),
+			ExpErr: testhelper.MkExpErr("cannot render row 0"),
+			tmpl:   "{{.name.nonesuch}}",
+		},
+	}
+
+	for _, tc := range testCases {
+		tmpl := template.Must(template.New(tc.IDStr()).Parse(tc.tmpl))
+		var sb strings.Builder
+		err := df.RenderRows(&sb, tmpl)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+		}
+	}
+}
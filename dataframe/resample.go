@@ -0,0 +1,240 @@
+package dataframe
+
+import (
+	"strconv"
+	"time"
+)
+
+// CalendarInterval identifies the calendar period into which Resample
+// divides the times
+type CalendarInterval uint
+
+// CalDay is a calendar day, from midnight to midnight
+// CalBusinessDay is a day from Monday to Friday; times at the weekend are
+// put in the period for the Friday before
+// CalWeek is a week starting at midnight on Monday
+// CalMonth is a calendar month
+// CalQuarter is a calendar quarter, starting in January, April, July or
+// October
+// CalMaxVal is a guard value used to ensure validity
+const (
+	CalDay CalendarInterval = iota
+	CalBusinessDay
+	CalWeek
+	CalMonth
+	CalQuarter
+	CalMaxVal
+)
+
+// String returns the name of the calendar interval
+func (ci CalendarInterval) String() string {
+	switch ci {
+	case CalDay:
+		return "Day"
+	case CalBusinessDay:
+		return "BusinessDay"
+	case CalWeek:
+		return "Week"
+	case CalMonth:
+		return "Month"
+	case CalQuarter:
+		return "Quarter"
+	}
+	return "CalendarInterval(" + strconv.FormatUint(uint64(ci), 10) + ")"
+}
+
+// start returns the start of the period holding the time, in the location
+func (ci CalendarInterval) start(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	y, m, d := t.Date()
+
+	switch ci {
+	case CalBusinessDay:
+		switch t.Weekday() {
+		case time.Saturday:
+			d--
+		case time.Sunday:
+			d -= 2
+		}
+	case CalWeek:
+		d -= (int(t.Weekday()) + 6) % 7
+	case CalMonth:
+		d = 1
+	case CalQuarter:
+		m = (m-1)/3*3 + 1
+		d = 1
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// next returns the start of the period after the one starting at the time
+func (ci CalendarInterval) next(start time.Time) time.Time {
+	y, m, d := start.Date()
+
+	switch ci {
+	case CalBusinessDay:
+		d++
+		switch start.Weekday() {
+		case time.Friday:
+			d += 2
+		case time.Saturday:
+			d++
+		}
+	case CalWeek:
+		d += 7
+	case CalMonth:
+		m++
+	case CalQuarter:
+		m += 3
+	default:
+		d++
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, start.Location())
+}
+
+// timeAt returns the time in the indexed column and row, which must be
+// either a string column holding RFC 3339 times or an int column holding
+// Unix times in seconds, and true if the value is NA
+func (df *DF) timeAt(colIdx, row int) (time.Time, bool, error) {
+	v, isNA := df.valAt(colIdx, row)
+	if isNA {
+		return time.Time{}, true, nil
+	}
+
+	switch v := v.(type) {
+	case IntVal:
+		return time.Unix(v.Val, 0), false, nil
+	case StringVal:
+		t, err := time.Parse(time.RFC3339Nano, v.Val)
+		if err != nil {
+			return t, false, dfErrorf("row %d: bad time: %q", row, v.Val)
+		}
+		return t, false, nil
+	}
+	return time.Time{}, false, dfKindErrorf(ErrTypeMismatch,
+		"the times must be in a String or Int column, not %s",
+		df.mci.info[colIdx].colType)
+}
+
+// Resample divides the times in the named column into calendar periods,
+// given by the interval, and calculates the aggregations for each period.
+// The times may be held in a string column, as RFC 3339 times, or in an
+// int column, as Unix times in seconds; rows with NA times are ignored.
+//
+// The periods start at midnight in the location. If loc is nil, the
+// location named by the column's MetaTimeZone metadata is used or, if
+// there is none, UTC. So, for instance, a day starts at local midnight and
+// daylight saving changes give days of 23 or 25 hours.
+//
+// It returns a dataframe with one row for each period from the first time
+// to the last, including any periods with no times. The first column,
+// having the name of the time column, holds the start of each period as an
+// RFC 3339 time, with the name of the location as its MetaTimeZone
+// metadata; the remaining columns hold the aggregated values, as for
+// GroupBy. It returns an error if there is no such column, if it does not
+// hold times or if an aggregation is invalid.
+func (df *DF) Resample(timeCol string, iv CalendarInterval,
+	loc *time.Location, aggs ...Agg,
+) (*DF, error) {
+	if iv >= CalMaxVal {
+		return nil, dfErrorf("bad calendar interval: %s", iv)
+	}
+	colIdx, ok := df.mci.colIdx(timeCol)
+	if !ok {
+		return nil, errUnknownColName(timeCol)
+	}
+	ci := df.mci.info[colIdx]
+	if loc == nil {
+		loc = time.UTC
+		if tz, ok := ci.Meta(MetaTimeZone); ok {
+			var err error
+			if loc, err = time.LoadLocation(tz); err != nil {
+				return nil, dfWrapf(err, "column %q: bad time zone", ci.name)
+			}
+		}
+	}
+
+	outCIs := []ColInfo{
+		ColInfo{name: ci.name, colType: ColTypeString}.
+			WithMeta(MetaTimeZone, loc.String()),
+	}
+	aggIdxs := make([]int, 0, len(aggs))
+	names := map[string]bool{ci.name: true}
+	for _, a := range aggs {
+		aci, err := a.colInfo(df.mci.info)
+		if err != nil {
+			return nil, err
+		}
+		if names[aci.name] {
+			return nil, dfCodeErrorf(ErrCodeDuplicateColName,
+				"duplicate column name: %q", aci.name)
+		}
+		names[aci.name] = true
+		outCIs = append(outCIs, aci)
+		i := -1
+		if a.Col != "" {
+			i, _ = df.mci.colIdx(a.Col)
+		}
+		aggIdxs = append(aggIdxs, i)
+	}
+	rval, err := newDFFromColInfo(outCIs...)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := map[int64][]aggAcc{}
+	var first, last time.Time
+	found := false
+	for r := 0; r < df.RowCount(); r++ {
+		t, isNA, err := df.timeAt(colIdx, r)
+		if err != nil {
+			return nil, dfWrapf(err, "column %q", ci.name)
+		}
+		if isNA {
+			continue
+		}
+		start := iv.start(t, loc)
+		if !found || start.Before(first) {
+			first = start
+		}
+		if !found || start.After(last) {
+			last = start
+		}
+		found = true
+
+		accs, ok := periods[start.Unix()]
+		if !ok {
+			accs = make([]aggAcc, len(aggs))
+			periods[start.Unix()] = accs
+		}
+		for i, ai := range aggIdxs {
+			var v any
+			if ai >= 0 {
+				v, _ = df.valAt(ai, r)
+			}
+			accs[i].add(v)
+		}
+	}
+	if !found {
+		return rval, nil
+	}
+
+	empty := make([]aggAcc, len(aggs))
+	for start := first; !start.After(last); start = iv.next(start) {
+		accs, ok := periods[start.Unix()]
+		if !ok {
+			accs = empty
+		}
+		err := rval.appendVal(0, start.Format(time.RFC3339))
+		if err != nil {
+			return nil, err
+		}
+		for i, a := range aggs {
+			if err := rval.appendVal(i+1, accs[i].val(a.Func)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return rval, nil
+}
@@ -0,0 +1,171 @@
+package dataframe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestResample(t *testing.T) {
+	nyc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("the time zone database is not available: ", err)
+	}
+
+	// the clocks in New York went forward on Sunday 10th March 2024
+	strTimes := mkTestDF(t, "t v\n"+
+		"2024-03-08T10:00:00-05:00 1\n"+
+		"2024-03-09T23:30:00-05:00 2\n"+
+		"2024-03-11T01:00:00-04:00 4\n"+
+		"2024-03-11T03:30:00Z 8\n",
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(dataframe.ColTypeString, dataframe.ColTypeInt))
+	zoned := mkTestDF(t, "t v\n2024-03-09T23:30:00-05:00 2\n",
+		dataframe.HasHeader,
+		dataframe.DFRColTypes(dataframe.ColTypeString, dataframe.ColTypeInt))
+	err = zoned.SetColMeta("t", dataframe.MetaTimeZone, "America/New_York")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot set the column metadata: ", err)
+	}
+	// 15th January and 1st March 2024, UTC
+	intTimes := mkTestDF(t, "t v\n1705276800 1\n1709251200 2\nNA 3\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeInt))
+
+	aggs := []dataframe.Agg{
+		{Func: dataframe.AggCount, Name: "n"},
+		{Col: "v", Func: dataframe.AggSum, Name: "sum"},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		df      *dataframe.DF
+		iv      dataframe.CalendarInterval
+		loc     *time.Location
+		aggs    []dataframe.Agg
+		expVals [][]string
+		expTZ   string
+	}{
+		{
+			ID:  testhelper.MkID("days, across a clock change"),
+			df:  strTimes,
+			iv:  dataframe.CalDay,
+			loc: nyc,
+			expVals: [][]string{
+				{"2024-03-08T00:00:00-05:00", "1", "1"},
+				{"2024-03-09T00:00:00-05:00", "1", "2"},
+				{"2024-03-10T00:00:00-05:00", "1", "8"},
+				{"2024-03-11T00:00:00-04:00", "1", "4"},
+			},
+			expTZ: "America/New_York",
+		},
+		{
+			ID:  testhelper.MkID("business days"),
+			df:  strTimes,
+			iv:  dataframe.CalBusinessDay,
+			loc: nyc,
+			expVals: [][]string{
+				{"2024-03-08T00:00:00-05:00", "3", "11"},
+				{"2024-03-11T00:00:00-04:00", "1", "4"},
+			},
+			expTZ: "America/New_York",
+		},
+		{
+			ID:  testhelper.MkID("weeks"),
+			df:  strTimes,
+			iv:  dataframe.CalWeek,
+			loc: nyc,
+			expVals: [][]string{
+				{"2024-03-04T00:00:00-05:00", "3", "11"},
+				{"2024-03-11T00:00:00-04:00", "1", "4"},
+			},
+			expTZ: "America/New_York",
+		},
+		{
+			ID: testhelper.MkID("days, UTC by default"),
+			df: strTimes,
+			iv: dataframe.CalDay,
+			expVals: [][]string{
+				{"2024-03-08T00:00:00Z", "1", "1"},
+				{"2024-03-09T00:00:00Z", "0", "NA"},
+				{"2024-03-10T00:00:00Z", "1", "2"},
+				{"2024-03-11T00:00:00Z", "2", "12"},
+			},
+			expTZ: "UTC",
+		},
+		{
+			ID: testhelper.MkID("zone from the column metadata"),
+			df: zoned,
+			iv: dataframe.CalDay,
+			expVals: [][]string{
+				{"2024-03-09T00:00:00-05:00", "1", "2"},
+			},
+			expTZ: "America/New_York",
+		},
+		{
+			ID: testhelper.MkID("months, Unix times"),
+			df: intTimes,
+			iv: dataframe.CalMonth,
+			expVals: [][]string{
+				{"2024-01-01T00:00:00Z", "1", "1"},
+				{"2024-02-01T00:00:00Z", "0", "NA"},
+				{"2024-03-01T00:00:00Z", "1", "2"},
+			},
+			expTZ: "UTC",
+		},
+		{
+			ID: testhelper.MkID("quarters, Unix times"),
+			df: intTimes,
+			iv: dataframe.CalQuarter,
+			expVals: [][]string{
+				{"2024-01-01T00:00:00Z", "2", "3"},
+			},
+			expTZ: "UTC",
+		},
+		{
+			ID: testhelper.MkID("bad: not times"),
+			ExpErr: testhelper.MkExpErr(`column "t"`,
+				"the times must be in a String or Int column, not Float"),
+			df:   mkTestDF(t, "t\n1.5\n", dataframe.HasHeader),
+			iv:   dataframe.CalDay,
+			aggs: []dataframe.Agg{},
+		},
+		{
+			ID: testhelper.MkID("bad: not a time"),
+			ExpErr: testhelper.MkExpErr(`column "t"`,
+				`row 0: bad time: "2024-03-09"`),
+			df:   mkTestDF(t, "t\n2024-03-09\n", dataframe.HasHeader),
+			iv:   dataframe.CalDay,
+			aggs: []dataframe.Agg{},
+		},
+		{
+			ID:     testhelper.MkID("bad: interval"),
+			ExpErr: testhelper.MkExpErr("bad calendar interval"),
+			df:     strTimes,
+			iv:     dataframe.CalMaxVal,
+		},
+		{
+			ID: testhelper.MkID("bad: aggregation"),
+			ExpErr: testhelper.MkExpErr(
+				`cannot calculate the Sum of column "t"`),
+			df:   strTimes,
+			iv:   dataframe.CalDay,
+			aggs: []dataframe.Agg{{Col: "t", Func: dataframe.AggSum}},
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.aggs == nil {
+			tc.aggs = aggs
+		}
+		df, err := tc.df.Resample("t", tc.iv, tc.loc, tc.aggs...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+			tz, _, _ := df.ColMeta("t", dataframe.MetaTimeZone)
+			testhelper.DiffString(t, tc.IDStr(), "time zone", tz, tc.expTZ)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package dataframe
+
+import "sort"
+
+// rleVals holds a run-length encoded sequence of values. Each distinct run
+// of equal values is recorded just once together with the index just past
+// the end of the run. This allows the i'th value to be found by a binary
+// search of the run ends.
+type rleVals[T comparable] struct {
+	vals []T
+	ends []int
+}
+
+// rleEncode returns the run-length encoded form of the values
+func rleEncode[T comparable](vals []T) *rleVals[T] {
+	r := &rleVals[T]{}
+	for _, v := range vals {
+		r.append(v)
+	}
+	return r
+}
+
+// len returns the number of values
+func (r *rleVals[T]) len() int {
+	if len(r.ends) == 0 {
+		return 0
+	}
+	return r.ends[len(r.ends)-1]
+}
+
+// runCount returns the number of runs
+func (r *rleVals[T]) runCount() int {
+	return len(r.vals)
+}
+
+// at returns the i'th value. The index must be in range.
+func (r *rleVals[T]) at(i int) T {
+	return r.vals[sort.SearchInts(r.ends, i+1)]
+}
+
+// append adds the value to the end of the sequence, extending the last run
+// if the value is the same
+func (r *rleVals[T]) append(v T) {
+	last := len(r.vals) - 1
+	if last >= 0 && r.vals[last] == v {
+		r.ends[last]++
+		return
+	}
+	r.vals = append(r.vals, v)
+	r.ends = append(r.ends, r.len()+1)
+}
+
+// truncate removes values from the end of the sequence so that it holds
+// just the first n values
+func (r *rleVals[T]) truncate(n int) {
+	if n >= r.len() {
+		return
+	}
+	runs := sort.SearchInts(r.ends, n) // the run holding the n'th value
+	if n > 0 {
+		r.ends[runs] = n
+		runs++
+	}
+	r.vals = r.vals[:runs]
+	r.ends = r.ends[:runs]
+}
+
+// runCount returns the number of runs of equal values in the slice
+func runCount[T comparable](vals []T) int {
+	n := 0
+	for i, v := range vals {
+		if i == 0 || v != vals[i-1] {
+			n++
+		}
+	}
+	return n
+}
+
+// expand returns a new slice holding the full sequence of values
+func (r *rleVals[T]) expand() []T {
+	rval := make([]T, 0, r.len())
+	start := 0
+	for i, v := range r.vals {
+		for j := start; j < r.ends[i]; j++ {
+			rval = append(rval, v)
+		}
+		start = r.ends[i]
+	}
+	return rval
+}
+
+// boolColLen returns the number of values in the bool column
+func (df DF) boolColLen(vi int) int {
+	if r, ok := df.rleBoolCols[vi]; ok {
+		return r.len()
+	}
+	return len(df.boolCols[vi])
+}
+
+// stringColLen returns the number of values in the string column
+func (df DF) stringColLen(vi int) int {
+	if r, ok := df.rleStringCols[vi]; ok {
+		return r.len()
+	}
+	return len(df.stringCols[vi])
+}
+
+// boolAt returns the i'th value of the bool column
+func (df DF) boolAt(vi, i int) BoolVal {
+	if r, ok := df.rleBoolCols[vi]; ok {
+		return r.at(i)
+	}
+	return df.boolCols[vi][i]
+}
+
+// stringAt returns the i'th value of the string column
+func (df DF) stringAt(vi, i int) StringVal {
+	if r, ok := df.rleStringCols[vi]; ok {
+		return r.at(i)
+	}
+	return df.stringCols[vi][i]
+}
+
+// appendBoolVal adds the value to the end of the bool column
+func (df *DF) appendBoolVal(vi int, v BoolVal) {
+	if r, ok := df.rleBoolCols[vi]; ok {
+		r.append(v)
+		return
+	}
+	df.boolCols[vi] = append(df.boolCols[vi], v)
+}
+
+// appendStringVal adds the value to the end of the string column
+func (df *DF) appendStringVal(vi int, v StringVal) {
+	if r, ok := df.rleStringCols[vi]; ok {
+		r.append(v)
+		return
+	}
+	df.stringCols[vi] = append(df.stringCols[vi], v)
+}
+
+// boolValsCopy returns a new slice holding the values of the bool column
+func (df DF) boolValsCopy(vi int) []BoolVal {
+	if r, ok := df.rleBoolCols[vi]; ok {
+		return r.expand()
+	}
+	return cloneValSlice(df.boolCols[vi])
+}
+
+// stringValsCopy returns a new slice holding the values of the string
+// column
+func (df DF) stringValsCopy(vi int) []StringVal {
+	if r, ok := df.rleStringCols[vi]; ok {
+		return r.expand()
+	}
+	return cloneValSlice(df.stringCols[vi])
+}
+
+// decompressBool restores the bool column to its uncompressed form
+func (df DF) decompressBool(vi int) {
+	if r, ok := df.rleBoolCols[vi]; ok {
+		df.boolCols[vi] = r.expand()
+		delete(df.rleBoolCols, vi)
+	}
+}
+
+// decompressString restores the string column to its uncompressed form
+func (df DF) decompressString(vi int) {
+	if r, ok := df.rleStringCols[vi]; ok {
+		df.stringCols[vi] = r.expand()
+		delete(df.rleStringCols, vi)
+	}
+}
+
+// compressibleColIdx returns the index of the named column checking that
+// it can be compressed
+func (df DF) compressibleColIdx(name string) (int, error) {
+	i, ok := df.mci.colIdx(name)
+	if !ok {
+		return 0, errUnknownColName(name)
+	}
+
+	if ct := df.mci.info[i].colType; ct != ColTypeBool && ct != ColTypeString {
+		return 0, dfKindErrorf(ErrTypeMismatch,
+			"The column named %q is of type %q, only %q and %q columns"+
+				" can be compressed",
+			name, ct, ColTypeBool, ColTypeString)
+	}
+
+	return i, nil
+}
+
+// Compress changes the storage of the named columns to use run-length
+// encoding. Each run of identical values is then stored just once which
+// can greatly reduce the memory needed for columns holding flags or a few
+// distinct strings in long runs (such as data sorted by that column). Only
+// bool and string columns can be compressed.
+//
+// The compression is transparent: the column values are available through
+// all the usual methods. Values can still be added to the column though
+// access to a particular row is slower. Note that a View of a compressed
+// column (such as given by BoolColByNameView) is a copy of the values
+// rather than the dataframe's own storage.
+//
+// It returns an error if any column does not exist or cannot be
+// compressed. In that case none of the columns are compressed.
+func (df *DF) Compress(names ...string) error {
+	idxs := make([]int, 0, len(names))
+	for _, name := range names {
+		i, err := df.compressibleColIdx(name)
+		if err != nil {
+			return err
+		}
+		idxs = append(idxs, i)
+	}
+
+	for _, i := range idxs {
+		vi := df.mci.valIdx[i]
+		switch df.mci.info[i].colType {
+		case ColTypeBool:
+			if _, ok := df.rleBoolCols[vi]; ok {
+				continue
+			}
+			if df.rleBoolCols == nil {
+				df.rleBoolCols = map[int]*rleVals[BoolVal]{}
+			}
+			df.rleBoolCols[vi] = rleEncode(df.boolCols[vi])
+			df.boolCols[vi] = nil
+		case ColTypeString:
+			if _, ok := df.rleStringCols[vi]; ok {
+				continue
+			}
+			if df.rleStringCols == nil {
+				df.rleStringCols = map[int]*rleVals[StringVal]{}
+			}
+			df.rleStringCols[vi] = rleEncode(df.stringCols[vi])
+			df.stringCols[vi] = nil
+		}
+	}
+
+	return nil
+}
+
+// Decompress changes the storage of the named columns back to the
+// uncompressed form. It is not an error to decompress a column that is not
+// compressed. It returns an error if any column does not exist or is of a
+// type that cannot be compressed.
+func (df *DF) Decompress(names ...string) error {
+	idxs := make([]int, 0, len(names))
+	for _, name := range names {
+		i, err := df.compressibleColIdx(name)
+		if err != nil {
+			return err
+		}
+		idxs = append(idxs, i)
+	}
+
+	for _, i := range idxs {
+		vi := df.mci.valIdx[i]
+		switch df.mci.info[i].colType {
+		case ColTypeBool:
+			df.decompressBool(vi)
+		case ColTypeString:
+			df.decompressString(vi)
+		}
+	}
+
+	return nil
+}
+
+// IsCompressed reports whether the named column is stored in compressed
+// form and, if so, how many runs of values are stored. It returns an error
+// if there is no such column.
+func (df DF) IsCompressed(name string) (bool, int, error) {
+	i, ok := df.mci.colIdx(name)
+	if !ok {
+		return false, 0, errUnknownColName(name)
+	}
+
+	switch df.mci.info[i].colType {
+	case ColTypeBool:
+		if r, ok := df.rleBoolCols[df.mci.valIdx[i]]; ok {
+			return true, r.runCount(), nil
+		}
+	case ColTypeString:
+		if r, ok := df.rleStringCols[df.mci.valIdx[i]]; ok {
+			return true, r.runCount(), nil
+		}
+	}
+
+	return false, 0, nil
+}
+
+// AutoCompress compresses each bool and string column whose values are in
+// runs with an average length of at least minRatio, that is, where the
+// number of rows is at least minRatio times the number of runs. This
+// allows the columns worth compressing to be found without knowing the
+// data in advance. It returns the names of the columns that were
+// compressed, not including any that were already compressed.
+func (df *DF) AutoCompress(minRatio float64) []string {
+	rows := float64(df.RowCount())
+	var names []string
+	for i, ci := range df.mci.info {
+		if ci.colType != ColTypeBool && ci.colType != ColTypeString {
+			continue
+		}
+		vi := df.mci.valIdx[i]
+		runs := 0
+		switch ci.colType {
+		case ColTypeBool:
+			if _, ok := df.rleBoolCols[vi]; ok {
+				continue
+			}
+			runs = runCount(df.boolCols[vi])
+		case ColTypeString:
+			if _, ok := df.rleStringCols[vi]; ok {
+				continue
+			}
+			runs = runCount(df.stringCols[vi])
+		default:
+			continue
+		}
+		if runs > 0 && rows >= minRatio*float64(runs) {
+			names = append(names, ci.name)
+		}
+	}
+
+	_ = df.Compress(names...)
+	return names
+}
+
+// DFRCompress returns a function which will cause the DFReader to compress
+// the named columns (see Compress) as soon as the column types are known
+// so that the values are compressed as they are read. It is an error if
+// the data read has no column with that name or if it is not a bool or
+// string column.
+func DFRCompress(names ...string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		for _, name := range names {
+			if name == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"the column name must not be empty")
+			}
+		}
+		dfr.compressCols = append(dfr.compressCols, names...)
+		return nil
+	}
+}
+
+// DFRAutoCompress returns a function which will cause the DFReader to
+// compress the bool and string columns whose values are in runs with an
+// average length of at least minRatio once all the values have been read
+// (see AutoCompress). The ratio must be at least 1.
+func DFRAutoCompress(minRatio float64) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if !(minRatio >= 1) {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the compression ratio must be at least 1: %g", minRatio)
+		}
+		dfr.autoCompress = minRatio
+		return nil
+	}
+}
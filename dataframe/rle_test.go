@@ -0,0 +1,191 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestCompress(t *testing.T) {
+	const content = `flag state n
+true on 1
+true on 2
+true off 3
+false off 4
+false off 5
+`
+	df := mkTestDF(t, content, dataframe.HasHeader)
+	expVals := [][]string{
+		{"true", "on", "1"},
+		{"true", "on", "2"},
+		{"true", "off", "3"},
+		{"false", "off", "4"},
+		{"false", "off", "5"},
+	}
+
+	err := df.Compress("n")
+	testhelper.CheckExpErrWithID(t, "compress an int column", err,
+		testhelper.MkExpErr(`The column named "n" is of type "Int",`+
+			` only "Bool" and "String" columns can be compressed`))
+
+	if err = df.Compress("flag", "state"); err != nil {
+		t.Fatal("unexpected error compressing the columns: ", err)
+	}
+	checkDFVals(t, "compressed", df, expVals)
+
+	for _, tc := range []struct {
+		name    string
+		expRuns int
+	}{
+		{name: "flag", expRuns: 2},
+		{name: "state", expRuns: 2},
+	} {
+		isCompressed, runs, err := df.IsCompressed(tc.name)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		testhelper.DiffBool(t, tc.name, "is compressed", isCompressed, true)
+		testhelper.DiffInt(t, tc.name, "runs", runs, tc.expRuns)
+	}
+
+	df.AddRowFromText([]string{"false", "on", "6"})
+	expVals = append(expVals, []string{"false", "on", "6"})
+	checkDFVals(t, "compressed, after adding a row", df, expVals)
+	_, runs, _ := df.IsCompressed("state")
+	testhelper.DiffInt(t, "after adding a row", "state runs", runs, 3)
+
+	states, err := df.StringColByName("state")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	testhelper.DiffInt(t, "copy of a compressed column", "length",
+		len(states), 6)
+
+	fdf := df.Filter(func(r *dataframe.Row) bool {
+		v, _, _ := r.ValByName("flag")
+		return v.(dataframe.BoolVal).Val
+	})
+	isCompressed, _, _ := fdf.IsCompressed("flag")
+	testhelper.DiffBool(t, "filtered", "is compressed", isCompressed, true)
+	checkDFVals(t, "filtered", fdf, expVals[:3])
+
+	flags, err := df.BoolColByNameView("flag")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	testhelper.DiffInt(t, "view of a compressed column", "length",
+		len(flags), 6)
+	isCompressed, _, _ = df.IsCompressed("flag")
+	testhelper.DiffBool(t, "after a view", "is compressed",
+		isCompressed, true)
+
+	if err = df.Decompress("state"); err != nil {
+		t.Fatal("unexpected error decompressing the column: ", err)
+	}
+	isCompressed, _, _ = df.IsCompressed("state")
+	testhelper.DiffBool(t, "after Decompress", "is compressed",
+		isCompressed, false)
+	checkDFVals(t, "decompressed", df, expVals)
+}
+
+func TestAutoCompress(t *testing.T) {
+	const content = `flag state id n
+true off a 1
+true off b 2
+true off c 3
+true off d 4
+false off e 5
+false off f 6
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts          []dataframe.DFReaderOpt
+		expCompressed []string
+	}{
+		{
+			ID: testhelper.MkID("auto, ratio 3"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRAutoCompress(3),
+			},
+			expCompressed: []string{"flag", "state"},
+		},
+		{
+			ID: testhelper.MkID("auto, ratio 3.5"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRAutoCompress(3.5),
+			},
+			expCompressed: []string{"state"},
+		},
+		{
+			ID: testhelper.MkID("named and auto"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRCompress("id"),
+				dataframe.DFRAutoCompress(3.5),
+			},
+			expCompressed: []string{"state", "id"},
+		},
+		{
+			ID: testhelper.MkID("bad: int column"),
+			ExpErr: testhelper.MkExpErr("cannot compress the columns",
+				`The column named "n" is of type "Int"`),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRCompress("n")},
+		},
+		{
+			ID: testhelper.MkID("bad: ratio"),
+			ExpErr: testhelper.MkExpErr(
+				"the compression ratio must be at least 1: 0.5"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRAutoCompress(0.5)},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DFReaderOpt{dataframe.HasHeader},
+			tc.opts...)
+		dfr, err := dataframe.NewDFReader(opts...)
+		var df *dataframe.DF
+		if err == nil {
+			df, err = dfr.Read(strings.NewReader(content), "test data")
+		}
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		var compressed []string
+		for _, ci := range df.Columns() {
+			if ok, _, _ := df.IsCompressed(ci.Name()); ok {
+				compressed = append(compressed, ci.Name())
+			}
+		}
+		testhelper.DiffStringSlice(t, tc.IDStr(), "compressed columns",
+			compressed, tc.expCompressed)
+		vals, _ := df.StringColByName("state")
+		testhelper.DiffInt(t, tc.IDStr(), "state values", len(vals), 6)
+	}
+
+	df := mkTestDF(t, content, dataframe.HasHeader)
+	testhelper.DiffStringSlice(t, "AutoCompress", "compressed columns",
+		df.AutoCompress(2), []string{"flag", "state"})
+	testhelper.DiffStringSlice(t, "AutoCompress again", "compressed columns",
+		df.AutoCompress(2), nil)
+
+	dfr, err := dataframe.NewDFReader(dataframe.HasHeader,
+		dataframe.DFRAutoCompress(1))
+	if err != nil {
+		t.Fatal("cannot make the DFReader: ", err)
+	}
+	df, err = dfr.Read(strings.NewReader("a b\n"), "test data")
+	if err != nil {
+		t.Fatal("header only: unexpected error: ", err)
+	}
+	testhelper.DiffStringSlice(t, "AutoCompress header only",
+		"compressed columns", df.AutoCompress(1), nil)
+	compressed, _, err := df.IsCompressed("a")
+	if err != nil {
+		t.Fatal("header only: unexpected error: ", err)
+	}
+	testhelper.DiffBool(t, "IsCompressed header only", "compressed",
+		compressed, false)
+}
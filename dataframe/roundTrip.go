@@ -0,0 +1,246 @@
+package dataframe
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DFWRoundTrip will cause Write to produce output which a DFReader created
+// with the DFRRoundTrip option will read back into an identical dataframe:
+// the same column names, the same column types and the same values,
+// including NA values.
+//
+// The first line holds the column names and the second line the column
+// types. Column names and string values are written as Go quoted strings
+// (see strconv.Quote) so they may contain spaces, quotes or newlines. NA
+// values are written as an unquoted NA, so an NA string value is
+// distinguished from the string "NA". The columns are separated by a single
+// space.
+//
+// Any dataframe metadata (see SetMeta) is written before the column names,
+// one line per key in key order, as "#meta" followed by the quoted key and
+// value.
+//
+// It cannot be combined with any options which change the text written
+// (column formats, the separator, the NA string or DFWNoHeader) and an
+// error is returned by Write if any of these has been given.
+func DFWRoundTrip(dfw *DFWriter) error {
+	dfw.roundTrip = true
+	return nil
+}
+
+// roundTripLineFormat returns the lineFormat for the round-trip format,
+// checking that no incompatible options have been given
+func (dfw DFWriter) roundTripLineFormat() (lineFormat, error) {
+	lf := lineFormat{
+		sep:      " ",
+		na:       DefaultNAString,
+		quote:    func(s string) string { return s },
+		strQuote: strconv.Quote,
+		types:    true,
+	}
+
+	switch {
+	case len(dfw.colFmts) > 0:
+		return lf, dfErrorf("column formats cannot be used in round-trip mode")
+	case dfw.sep != "":
+		return lf, dfErrorf("a separator cannot be set in round-trip mode")
+	case dfw.hasNAStr:
+		return lf, dfErrorf("an NA string cannot be set in round-trip mode")
+	case dfw.noHeader:
+		return lf, dfErrorf("the header is required in round-trip mode")
+	}
+	return lf, nil
+}
+
+// metaLinePrefix starts each line of dataframe metadata in the round-trip
+// format
+const metaLinePrefix = "#meta "
+
+// writeMetaLines writes the dataframe metadata, if any, to the io.Writer
+// in the round-trip format
+func (dfw DFWriter) writeMetaLines(w io.Writer, df *DF) error {
+	if len(df.meta) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	for _, k := range df.MetaKeys() {
+		sb.WriteString(metaLinePrefix + strconv.Quote(k) + " " +
+			strconv.Quote(df.meta[k]) + dfw.lineEnd)
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return dfWrapf(err, "cannot write the dataframe metadata")
+	}
+	return nil
+}
+
+// handleMetaLine sets the dataframe metadata from a metadata line before
+// the column names when reading in round-trip mode and sets skip to true.
+// Otherwise it does nothing.
+func handleMetaLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
+	if !dfr.roundTrip || state.dataLineNum != 0 ||
+		!strings.HasPrefix(state.line, metaLinePrefix) {
+		return false, nil
+	}
+
+	rest := strings.TrimPrefix(state.line, metaLinePrefix)
+	kv, _, err := splitQuotedLine(rest, dfr.splitRegex, goUnquote)
+	if err == nil && len(kv) != 2 {
+		err = dfErrorf("expected a key and a value, found %d fields", len(kv))
+	}
+	if err == nil {
+		err = df.SetMeta(kv[0], kv[1])
+	}
+	if err != nil {
+		err := state.parseError(ErrParse, ErrCodeBadMetaLine,
+			"bad metadata line: "+errText(err))
+		df.addError(err)
+		if dfr.allowErrors {
+			return true, nil
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// DFRRoundTrip will cause the DFReader to read data in the format written
+// by a DFWriter with the DFWRoundTrip option. The first line is taken as the
+// column names and the second line as the column types, after any lines of
+// dataframe metadata. Fields starting
+// with a double quote are read as Go quoted strings and unquoted fields
+// holding just NA are read as NA values. The column names and types cannot
+// also be given as options.
+func DFRRoundTrip(dfr *DFReader) error {
+	if len(dfr.colNames) != 0 {
+		return ErrHasNamesAndHeader
+	}
+	if len(dfr.colTypes) != 0 {
+		return dfCodeErrorf(ErrCodeBadOption,
+			"column types cannot be given in round-trip mode")
+	}
+	dfr.roundTrip = true
+	dfr.hasHeader = true
+	return nil
+}
+
+// quotedLen returns the length of the Go quoted string at the start of s or
+// -1 if there is no closing quote
+func quotedLen(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// errNoClosingQuote is returned (wrapped) by splitQuotedLine if a quoted
+// field has no closing quote
+var errNoClosingQuote = dfError("no closing quote")
+
+// unquoteFunc unquotes the quoted field at the start of s returning the
+// unquoted value and the length of the quoted text or -1 if there is no
+// closing quote
+type unquoteFunc func(s string) (string, int, error)
+
+// goUnquote unquotes the Go quoted string at the start of s
+func goUnquote(s string) (string, int, error) {
+	n := quotedLen(s)
+	if n < 0 {
+		return "", n, nil
+	}
+	field, err := strconv.Unquote(s[:n])
+	if err != nil {
+		return "", n, dfErrorf("bad quoted string: %s: %s", s[:n], err)
+	}
+	return field, n, nil
+}
+
+// splitQuotedLine splits the line into fields separated by matches of the
+// separator. Fields starting with a double quote are unquoted by the
+// unquote function and may contain the separator. The returned bool slice
+// records, for each field, whether it is an unquoted NA.
+func splitQuotedLine(line string, sep *regexp.Regexp, unquote unquoteFunc,
+) ([]string, []bool, error) {
+	var cols []string
+	var isNA []bool
+
+	for {
+		var field string
+		quoted := strings.HasPrefix(line, `"`)
+
+		if quoted {
+			var n int
+			var err error
+			field, n, err = unquote(line)
+			if err != nil {
+				return nil, nil, dfWrapf(err, "field %d", len(cols))
+			}
+			if n < 0 {
+				return nil, nil,
+					dfWrapf(errNoClosingQuote, "field %d", len(cols))
+			}
+			line = line[n:]
+		}
+
+		loc := sep.FindStringIndex(line)
+		if quoted && len(line) > 0 && (loc == nil || loc[0] != 0) {
+			return nil, nil,
+				dfErrorf("field %d: unexpected text after the closing quote",
+					len(cols))
+		}
+		if !quoted {
+			field = line
+			if loc != nil {
+				field = line[:loc[0]]
+			}
+		}
+
+		cols = append(cols, field)
+		isNA = append(isNA, !quoted && field == DefaultNAString)
+
+		if loc == nil {
+			return cols, isNA, nil
+		}
+		line = line[loc[1]:]
+	}
+}
+
+// colTypeByName returns the ColType with the given name
+func colTypeByName(name string) (ColType, error) {
+	for ct := ColTypeBool; ct < ColTypeMaxVal; ct++ {
+		if ct.String() == name {
+			return ct, nil
+		}
+	}
+	return ColTypeUnknown, dfErrorf("unknown column type: %q", name)
+}
+
+// handleTypesLine sets the column types from the second line when reading
+// in round-trip mode and sets skip to true. Otherwise it does nothing.
+func handleTypesLine(dfr *DFReader, state *dfReadState, df *DF) (bool, error) {
+	if !dfr.roundTrip || state.dataLineNum != 2 {
+		return false, nil
+	}
+
+	types := make([]ColType, 0, len(state.cols))
+	for i, name := range state.cols {
+		ct, err := colTypeByName(name)
+		if err != nil {
+			pErr := state.parseError(ErrParse, ErrCodeBadTypesLine,
+				"bad column types line: "+errText(err))
+			pErr.Col = i
+			pErr.Field = name
+			return true, pErr
+		}
+		types = append(types, ct)
+	}
+
+	return true, df.SetColTypes(types...)
+}
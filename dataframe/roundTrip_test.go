@@ -0,0 +1,146 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+type roundTripRec struct {
+	B  *bool    `df:"a bool"`
+	I  *int64   `df:"int"`
+	F  *float64 `df:"float"`
+	S  *string  `df:"str"`
+	S2 string   `df:"\"quoted\" name"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	bTrue, i, f := true, int64(-42), 1.0/3
+	strs := []string{"", "NA", "a b", `say "hi"`, "line1\nline2", "tab\there"}
+
+	recs := []roundTripRec{
+		{B: &bTrue, I: &i, F: &f, S: &strs[0], S2: strs[1]},
+		{S: &strs[1], S2: strs[0]},
+	}
+	for _, s := range strs[2:] {
+		s := s
+		recs = append(recs, roundTripRec{S: &s, S2: s})
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		df func() (*dataframe.DF, error)
+	}{
+		{
+			ID: testhelper.MkID("all types, NA values, awkward strings"),
+			df: func() (*dataframe.DF, error) {
+				return dataframe.FromStructs(recs)
+			},
+		},
+		{
+			ID: testhelper.MkID("no rows"),
+			df: func() (*dataframe.DF, error) {
+				return dataframe.FromStructs([]roundTripRec{})
+			},
+		},
+		{
+			ID: testhelper.MkID("bool-like ints and int-like strings"),
+			df: func() (*dataframe.DF, error) {
+				return dataframe.NewDFFromCols(
+					mkIntCol("i", 0, 1, 1),
+					mkStringCol("s", "1", "2", "true"))
+			},
+		},
+	}
+
+	dfw, err := dataframe.NewDFWriter(dataframe.DFWRoundTrip)
+	if err != nil {
+		t.Fatal("cannot make the DFWriter: ", err)
+	}
+	dfr, err := dataframe.NewDFReader(dataframe.DFRRoundTrip)
+	if err != nil {
+		t.Fatal("cannot make the DFReader: ", err)
+	}
+
+	for _, tc := range testCases {
+		df, err := tc.df()
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+		}
+
+		var sb strings.Builder
+		if err := dfw.Write(&sb, df); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot write the dataframe: %s", err)
+			continue
+		}
+		rtDF, err := dfr.Read(strings.NewReader(sb.String()), "round trip")
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Log("\t: data:\n" + sb.String())
+			t.Errorf("\t: cannot read the dataframe back: %s", err)
+			continue
+		}
+
+		checkColDetails(t, tc.IDStr(), rtDF, df.Columns())
+		checkDFVals(t, tc.IDStr(), rtDF, dfVals(t, df))
+		testhelper.DiffInt(t, tc.IDStr(), "error count", rtDF.ErrCount(), 0)
+	}
+}
+
+func TestRoundTripFormat(t *testing.T) {
+	df, err := dataframe.NewDFFromCols(
+		mkIntCol("i", 1, 2),
+		mkStringCol("my str", "a b", "NA"))
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the dataframe: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts []dataframe.DFWriterOpt
+		exp  string
+	}{
+		{
+			ID:   testhelper.MkID("good"),
+			opts: []dataframe.DFWriterOpt{dataframe.DFWRoundTrip},
+			exp: `"i" "my str"` + "\n" +
+				"Int String\n" +
+				`1 "a b"` + "\n" +
+				`2 "NA"` + "\n",
+		},
+		{
+			ID: testhelper.MkID("with a separator"),
+			ExpErr: testhelper.MkExpErr(
+				"a separator cannot be set in round-trip mode"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWRoundTrip,
+				dataframe.DFWSeparator(","),
+			},
+		},
+		{
+			ID: testhelper.MkID("with a column format"),
+			ExpErr: testhelper.MkExpErr(
+				"column formats cannot be used in round-trip mode"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWRoundTrip,
+				dataframe.DFWIntBase("i", 16),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfw, err := dataframe.NewDFWriter(tc.opts...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFWriter: ", err)
+		}
+		var sb strings.Builder
+		err = dfw.Write(&sb, df)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package dataframe
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // RowData holds all the data for a row. It has any bool values in the
 // boolVals slice, ints in the intVals slice and so on. It should be used
 // with a MultiColInfo which maps the values to names (and vice versa).
@@ -94,6 +100,119 @@ func (r *Row) AddString(name string, v StringVal) error {
 	return nil
 }
 
+// colIdxOfType returns the index of the named column, returning an error if
+// there is no such column or it is not of the given type
+func (r *Row) colIdxOfType(name string, colType ColType) (int, error) {
+	idx, ok := r.mci.colIdx(name)
+	if !ok {
+		return -1, errUnknownColName(name)
+	}
+	if ct := r.mci.info[idx].colType; ct != colType {
+		return -1, dfKindErrorf(ErrTypeMismatch,
+			"%s: cannot set a %s value", r.mci.ColDesc(idx), colType)
+	}
+	return idx, nil
+}
+
+// SetBool sets the value of the named bool column in the row. It returns
+// an error if there is no such column or it is not a bool column.
+func (r *Row) SetBool(name string, v BoolVal) error {
+	idx, err := r.colIdxOfType(name, ColTypeBool)
+	if err != nil {
+		return err
+	}
+	r.rd.boolVals[r.mci.valIdx[idx]] = v
+	return nil
+}
+
+// SetInt sets the value of the named int column in the row. It returns an
+// error if there is no such column or it is not an int column.
+func (r *Row) SetInt(name string, v IntVal) error {
+	idx, err := r.colIdxOfType(name, ColTypeInt)
+	if err != nil {
+		return err
+	}
+	r.rd.intVals[r.mci.valIdx[idx]] = v
+	return nil
+}
+
+// SetFloat sets the value of the named float column in the row. It returns
+// an error if there is no such column or it is not a float column.
+func (r *Row) SetFloat(name string, v FloatVal) error {
+	idx, err := r.colIdxOfType(name, ColTypeFloat)
+	if err != nil {
+		return err
+	}
+	r.rd.floatVals[r.mci.valIdx[idx]] = v
+	return nil
+}
+
+// SetString sets the value of the named string column in the row. It
+// returns an error if there is no such column or it is not a string column.
+func (r *Row) SetString(name string, v StringVal) error {
+	idx, err := r.colIdxOfType(name, ColTypeString)
+	if err != nil {
+		return err
+	}
+	r.rd.stringVals[r.mci.valIdx[idx]] = v
+	return nil
+}
+
+// SetByIdx sets the value of the column in the row with the given index,
+// converting it to the column type. The value may be of the column's value
+// type (such as FloatVal) or the corresponding Go type (such as float64); a
+// float column also accepts an int, an int64 or an IntVal and a nil value
+// sets the column to NA. It returns an error if the index is out of range
+// or the value cannot be converted.
+func (r *Row) SetByIdx(idx int, v any) error {
+	if idx < 0 || idx >= len(r.mci.info) {
+		return errUnknownColIdx(idx, len(r.mci.info))
+	}
+
+	vi := r.mci.valIdx[idx]
+
+	switch ct := r.mci.info[idx].colType; ct {
+	case ColTypeBool:
+		bv, err := boolValOf(v)
+		if err != nil {
+			return dfWrapf(err, "%s", r.mci.ColDesc(idx))
+		}
+		r.rd.boolVals[vi] = bv
+	case ColTypeInt:
+		iv, err := intValOf(v)
+		if err != nil {
+			return dfWrapf(err, "%s", r.mci.ColDesc(idx))
+		}
+		r.rd.intVals[vi] = iv
+	case ColTypeFloat:
+		fv, err := floatValOf(v)
+		if err != nil {
+			return dfWrapf(err, "%s", r.mci.ColDesc(idx))
+		}
+		r.rd.floatVals[vi] = fv
+	case ColTypeString:
+		sv, err := stringValOf(v)
+		if err != nil {
+			return dfWrapf(err, "%s", r.mci.ColDesc(idx))
+		}
+		r.rd.stringVals[vi] = sv
+	default:
+		return dfErrorf("Unexpected column type: %q", ct)
+	}
+
+	return nil
+}
+
+// SetByName sets the value of the named column in the row as for
+// SetByIdx. It returns an error if there is no such column.
+func (r *Row) SetByName(name string, v any) error {
+	idx, ok := r.mci.colIdx(name)
+	if !ok {
+		return errUnknownColName(name)
+	}
+	return r.SetByIdx(idx, v)
+}
+
 // ValByIdx returns a value and its associated type from the Row
 // corresponding to the supplied column index. If the column index is not
 // recognised then an error is returned.
@@ -101,8 +220,7 @@ func (r *Row) ValByIdx(idx int) (any, ColType, error) {
 	if idx < 0 || idx >= len(r.mci.info) {
 		return nil,
 			ColTypeUnknown,
-			dfErrorf("There is no column %d (valid range: 0-%d)",
-				idx, len(r.mci.info)-1)
+			errUnknownColIdx(idx, len(r.mci.info))
 	}
 	cType := r.mci.info[idx].colType
 	switch cType {
@@ -123,9 +241,9 @@ func (r *Row) ValByIdx(idx int) (any, ColType, error) {
 // corresponding to the supplied column name. If the column name is not
 // recognised then an error is returned.
 func (r *Row) ValByName(name string) (any, ColType, error) {
-	ci, ok := r.mci.nameToCol[name]
+	ci, ok := r.mci.colIdx(name)
 	if !ok {
-		return nil, ColTypeUnknown, dfErrorf("Unknown column name: %q", name)
+		return nil, ColTypeUnknown, errUnknownColName(name)
 	}
 	return r.ValByIdx(ci)
 }
@@ -150,8 +268,7 @@ func (r *Row) ColsByIdx(indexes ...int) ([]Column, error) {
 
 	for _, i := range indexes {
 		if i < 0 || i >= len(r.mci.info) {
-			return nil, dfErrorf("There is no column %d (valid range: 0-%d)",
-				i, len(r.mci.info)-1)
+			return nil, errUnknownColIdx(i, len(r.mci.info))
 		}
 		col := Column{ci: r.mci.info[i]}
 		switch col.ci.colType {
@@ -177,9 +294,9 @@ func (r *Row) ColsByName(names ...string) ([]Column, error) {
 	rval := make([]Column, 0, len(names))
 
 	for _, name := range names {
-		i, ok := r.mci.nameToCol[name]
+		i, ok := r.mci.colIdx(name)
 		if !ok {
-			return nil, dfErrorf("Unknown column name: %q", name)
+			return nil, errUnknownColName(name)
 		}
 		cols, err := r.ColsByIdx(i)
 		if err != nil {
@@ -199,3 +316,132 @@ func (r *Row) ColsByNameOrPanic(names ...string) []Column {
 	}
 	return cols
 }
+
+// addVal adds a new value to the row converting it to the given column
+// type. If the name is already in the row or the value cannot be converted
+// an error is returned
+func (r *Row) addVal(name string, colType ColType, v any) error {
+	switch colType {
+	case ColTypeBool:
+		bv, err := boolValOf(v)
+		if err != nil {
+			return err
+		}
+		return r.AddBool(name, bv)
+	case ColTypeInt:
+		iv, err := intValOf(v)
+		if err != nil {
+			return err
+		}
+		return r.AddInt(name, iv)
+	case ColTypeFloat:
+		fv, err := floatValOf(v)
+		if err != nil {
+			return err
+		}
+		return r.AddFloat(name, fv)
+	case ColTypeString:
+		sv, err := stringValOf(v)
+		if err != nil {
+			return err
+		}
+		return r.AddString(name, sv)
+	}
+
+	return dfErrorf("Unexpected column type: %q", colType)
+}
+
+// project returns a new row holding just the named columns in the order
+// given. It returns an error if any name is not found
+func (r *Row) project(names ...string) (*Row, error) {
+	rval, err := NewRow()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		v, colType, err := r.ValByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := rval.addVal(name, colType, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return rval, nil
+}
+
+// rowValText returns the text of the value in the indexed column; string
+// values are quoted if quote is true so that they can be told apart from
+// NA values
+func (r *Row) rowValText(idx int, quote bool) string {
+	v, _, _ := r.ValByIdx(idx)
+	if sv, ok := v.(StringVal); ok && quote && !sv.IsNA {
+		return strconv.Quote(sv.Val)
+	}
+	return fmt.Sprint(v)
+}
+
+// String returns the row as a space-separated list of name=value pairs in
+// column order. String values are shown quoted and NA values as an
+// unquoted NA, so a string holding "NA" can be told apart from an NA
+// value. A name which holds white space, an '=' or a double quote is also
+// quoted.
+func (r *Row) String() string {
+	var b strings.Builder
+	for i, ci := range r.mci.info {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		name := ci.name
+		if strings.ContainsAny(name, " \t\n=\"") {
+			name = strconv.Quote(name)
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(r.rowValText(i, true))
+	}
+	return b.String()
+}
+
+// Format returns the layout with each {name} replaced by the value of the
+// named column, as given by the String method of the value so an NA value
+// is shown as NA. A doubled brace ({{ or }}) gives a single brace. A name
+// which is not a column of the row is shown as {?name} and an unclosed
+// brace is shown as it is. For example:
+//
+//	r.Format("{id}: {price} ({currency})")
+func (r *Row) Format(layout string) string {
+	var b strings.Builder
+	for len(layout) > 0 {
+		i := strings.IndexAny(layout, "{}")
+		if i < 0 {
+			b.WriteString(layout)
+			break
+		}
+		b.WriteString(layout[:i])
+		layout = layout[i:]
+
+		if len(layout) > 1 && layout[1] == layout[0] {
+			b.WriteByte(layout[0])
+			layout = layout[2:]
+			continue
+		}
+		end := strings.IndexByte(layout, '}')
+		if layout[0] == '}' || end < 0 {
+			b.WriteByte(layout[0])
+			layout = layout[1:]
+			continue
+		}
+
+		name := layout[1:end]
+		layout = layout[end+1:]
+		if idx, ok := r.mci.colIdx(name); ok {
+			b.WriteString(r.rowValText(idx, false))
+		} else {
+			b.WriteString("{?" + name + "}")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package dataframe
+
+// AddRowIDCol adds an int column with the given name to the end of the
+// dataframe holding the index of each row (0 for the first row and so on).
+// As the column is an ordinary column it is carried through filtering,
+// sorting and joining so the rows of the result can be traced back to the
+// rows of the original dataframe. It returns an error if the name is blank
+// or already in use.
+func (df *DF) AddRowIDCol(name string) error {
+	var c Column
+	c.ci = ColInfo{name: name, colType: ColTypeInt}
+	c.intVals = make([]IntVal, 0, df.RowCount())
+	for i := 0; i < df.RowCount(); i++ {
+		c.intVals = append(c.intVals, IntVal{Val: int64(i)})
+	}
+	return df.AddCol(c)
+}
+
+// DFRRowIDCol returns a function which will cause the DFReader to add an
+// int column with the given name to the end of the dataframe holding the
+// index of each row as it was read, as for DF.AddRowIDCol. It is an error
+// if the data read already has a column with that name.
+func DFRRowIDCol(name string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if name == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the row ID column name must not be empty")
+		}
+		dfr.rowIDCol = name
+		return nil
+	}
+}
+
+// DFRLineNumCol returns a function which will cause the DFReader to add an
+// int column with the given name to the end of the dataframe holding the
+// number of the line in the source from which each row was read (the
+// first line being line 1). For a record spanning several lines (see
+// DFRQuotedFields) this is the last line; when reading records (see
+// ReadRecords) it is the record number and when reading transposed data
+// (see DFRTransposed) it is the row number in the transposed data. It is
+// an error if the data read already has a column with that name.
+func DFRLineNumCol(name string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if name == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the line number column name must not be empty")
+		}
+		dfr.lineNumCol = name
+		return nil
+	}
+}
+
+// recordRowLine records the source line number of any rows just added to
+// the dataframe, forgetting those of any rows which have been removed. It
+// does nothing unless a line number column is to be added.
+func (dfr *DFReader) recordRowLine(state *dfReadState, df *DF, line int64) {
+	if dfr.lineNumCol == "" {
+		return
+	}
+
+	n := df.RowCount()
+	if len(state.rowLines) > n {
+		state.rowLines = state.rowLines[:n]
+	}
+	for len(state.rowLines) < n {
+		state.rowLines = append(state.rowLines, line)
+	}
+}
+
+// addTraceCols adds any row ID and line number columns to the dataframe
+func (dfr *DFReader) addTraceCols(state *dfReadState, df *DF) error {
+	if dfr.rowIDCol != "" {
+		if err := df.AddRowIDCol(dfr.rowIDCol); err != nil {
+			return dfWrapf(err, "%s: cannot add the row ID column",
+				state.loc.Source())
+		}
+	}
+
+	if dfr.lineNumCol != "" {
+		var c Column
+		c.ci = ColInfo{name: dfr.lineNumCol, colType: ColTypeInt}
+		for _, line := range state.rowLines {
+			c.intVals = append(c.intVals, IntVal{Val: line})
+		}
+		if err := df.AddCol(c); err != nil {
+			return dfWrapf(err, "%s: cannot add the line number column",
+				state.loc.Source())
+		}
+	}
+
+	return nil
+}
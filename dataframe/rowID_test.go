@@ -0,0 +1,110 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRRowIDCol(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("row ID and line number"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.SkipBlankLines,
+				dataframe.DFRRowIDCol("id"),
+				dataframe.DFRLineNumCol("line"),
+			},
+			data: "x\n12\n\n13\n14\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("x", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("id", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("line", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{
+				{"12", "0", "2"},
+				{"13", "1", "4"},
+				{"14", "2", "5"},
+			},
+		},
+		{
+			ID: testhelper.MkID("line number, failed rows dropped"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.AllowErrors,
+				dataframe.DFRColTypes(dataframe.ColTypeInt),
+				dataframe.DFRColCheck("x", func(v int64) error {
+					if v == 13 {
+						return errors.New("bad value")
+					}
+					return nil
+				}),
+				dataframe.DFRDropFailedRows,
+				dataframe.DFRLineNumCol("line"),
+			},
+			data: "x\n12\n13\n14\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("x", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("line", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"12", "2"}, {"14", "4"}},
+		},
+		{
+			ID: testhelper.MkID("name in use"),
+			ExpErr: testhelper.MkExpErr("test data:",
+				"cannot add the row ID column",
+				"Column name already used"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.DFRRowIDCol("x"),
+			},
+			data: "x\n12\n",
+		},
+		{
+			ID: testhelper.MkID("no name"),
+			ExpErr: testhelper.MkExpErr(
+				"the line number column name must not be empty"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRLineNumCol("")},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if err == nil {
+			var df *dataframe.DF
+			df, err = dfr.Read(strings.NewReader(tc.data), "test data")
+			if err == nil {
+				checkColDetails(t, tc.IDStr(), df, tc.expCols)
+				checkDFVals(t, tc.IDStr(), df, tc.expVals)
+			}
+		}
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestAddRowIDCol(t *testing.T) {
+	df := mkTestDF(t, "x\n13\n12\n", dataframe.HasHeader)
+	if err := df.AddRowIDCol("id"); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	sorted, err := df.Sort(dataframe.SortKey{Col: "x"})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	checkDFVals(t, "sorted", sorted, [][]string{{"12", "1"}, {"13", "0"}})
+
+	err = df.AddRowIDCol("id")
+	testhelper.CheckExpErrWithID(t, "duplicate", err,
+		testhelper.MkExpErr("Column name already used"))
+}
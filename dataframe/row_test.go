@@ -1,6 +1,7 @@
 package dataframe_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/nickwells/dataframe.mod/dataframe"
@@ -447,3 +448,179 @@ func TestColsByIdx(t *testing.T) {
 		}
 	}
 }
+
+func TestRowSet(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		set    func(r *dataframe.Row) error
+		name   string
+		expVal any
+	}{
+		{
+			ID: testhelper.MkID("SetBool"),
+			set: func(r *dataframe.Row) error {
+				return r.SetBool("boolCol", dataframe.BoolVal{IsNA: true})
+			},
+			name:   "boolCol",
+			expVal: dataframe.BoolVal{IsNA: true},
+		},
+		{
+			ID: testhelper.MkID("SetInt"),
+			set: func(r *dataframe.Row) error {
+				return r.SetInt("intCol", dataframe.IntVal{Val: 7})
+			},
+			name:   "intCol",
+			expVal: dataframe.IntVal{Val: 7},
+		},
+		{
+			ID: testhelper.MkID("SetFloat"),
+			set: func(r *dataframe.Row) error {
+				return r.SetFloat("floatCol", dataframe.FloatVal{Val: 2.5})
+			},
+			name:   "floatCol",
+			expVal: dataframe.FloatVal{Val: 2.5},
+		},
+		{
+			ID: testhelper.MkID("SetString"),
+			set: func(r *dataframe.Row) error {
+				return r.SetString("stringCol", dataframe.StringVal{Val: "x"})
+			},
+			name:   "stringCol",
+			expVal: dataframe.StringVal{Val: "x"},
+		},
+		{
+			ID: testhelper.MkID("SetFloat - wrong type"),
+			ExpErr: testhelper.MkExpErr(
+				`Column 1 ("intCol": "Int"): cannot set a Float value`),
+			set: func(r *dataframe.Row) error {
+				return r.SetFloat("intCol", dataframe.FloatVal{Val: 2.5})
+			},
+		},
+		{
+			ID: testhelper.MkID("SetInt - bad name"),
+			ExpErr: testhelper.MkExpErr(
+				`Unknown column name: "nonesuch"`),
+			set: func(r *dataframe.Row) error {
+				return r.SetInt("nonesuch", dataframe.IntVal{Val: 7})
+			},
+		},
+		{
+			ID: testhelper.MkID("SetByIdx - int into float"),
+			set: func(r *dataframe.Row) error {
+				return r.SetByIdx(2, 3)
+			},
+			name:   "floatCol",
+			expVal: dataframe.FloatVal{Val: 3},
+		},
+		{
+			ID: testhelper.MkID("SetByIdx - nil"),
+			set: func(r *dataframe.Row) error {
+				return r.SetByIdx(3, nil)
+			},
+			name:   "stringCol",
+			expVal: dataframe.StringVal{IsNA: true},
+		},
+		{
+			ID: testhelper.MkID("SetByIdx - bad index"),
+			ExpErr: testhelper.MkExpErr(
+				"There is no column 4 (valid range: 0-3)"),
+			set: func(r *dataframe.Row) error {
+				return r.SetByIdx(4, true)
+			},
+		},
+		{
+			ID: testhelper.MkID("SetByIdx - bad value"),
+			ExpErr: testhelper.MkExpErr(`Column 0 ("boolCol": "Bool")`,
+				"cannot convert a value of type string into a BoolVal"),
+			set: func(r *dataframe.Row) error {
+				return r.SetByIdx(0, "true")
+			},
+		},
+		{
+			ID: testhelper.MkID("SetByName"),
+			set: func(r *dataframe.Row) error {
+				return r.SetByName("intCol", int64(-3))
+			},
+			name:   "intCol",
+			expVal: dataframe.IntVal{Val: -3},
+		},
+		{
+			ID: testhelper.MkID("SetByName - bad name"),
+			ExpErr: testhelper.MkExpErr(
+				`Unknown column name: "nonesuch"`),
+			set: func(r *dataframe.Row) error {
+				return r.SetByName("nonesuch", 1)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		r := makeTestRow()
+		err := tc.set(r)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			v, _, err := r.ValByName(tc.name)
+			if err != nil {
+				t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+			}
+			if v != tc.expVal {
+				t.Log(tc.IDStr())
+				t.Logf("\t: expected: %#v", tc.expVal)
+				t.Logf("\t:   actual: %#v", v)
+				t.Errorf("\t: unexpected value")
+			}
+		}
+	}
+}
+
+func TestRowString(t *testing.T) {
+	r := makeTestRow()
+	for _, name := range []string{"NA str", "a=b"} {
+		err := r.AddString(name, dataframe.StringVal{Val: "NA"})
+		if err != nil {
+			t.Fatal("BAD TEST - cannot add the column: ", err)
+		}
+	}
+	if err := r.AddInt("na", dataframe.IntVal{IsNA: true}); err != nil {
+		t.Fatal("BAD TEST - cannot add the column: ", err)
+	}
+
+	testhelper.DiffString(t, "String", "row", r.String(),
+		`boolCol=true intCol=42 floatCol=3.14159`+
+			` stringCol="Hello, World!" "NA str"="NA" "a=b"="NA" na=NA`)
+	testhelper.DiffString(t, "fmt", "row", fmt.Sprint(r), r.String())
+}
+
+func TestRowFormat(t *testing.T) {
+	r := makeTestRow()
+	if err := r.AddFloat("na", dataframe.FloatVal{IsNA: true}); err != nil {
+		t.Fatal("BAD TEST - cannot add the column: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		layout string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("plain text"),
+			layout: "no values",
+			expStr: "no values",
+		},
+		{
+			ID:     testhelper.MkID("values"),
+			layout: "{stringCol} {intCol}/{floatCol}: {boolCol} {na}",
+			expStr: "Hello, World! 42/3.14159: true NA",
+		},
+		{
+			ID:     testhelper.MkID("braces"),
+			layout: "{{intCol}} = {intCol} {nonesuch} } {intCol",
+			expStr: "{intCol} = 42 {?nonesuch} } {intCol",
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "formatted row",
+			r.Format(tc.layout), tc.expStr)
+	}
+}
@@ -0,0 +1,54 @@
+package dataframe
+
+// SampleRows returns a function which will cause the DFReader to keep just
+// a uniform random sample of n rows of the data, every row read having the
+// same chance of being kept. This uses reservoir sampling so the input can
+// be arbitrarily long without needing more memory than the n rows, which
+// allows the contents of a very large file to be quickly profiled. If there
+// are no more than n rows then all of them are kept. The rows are chosen
+// by a random number generator started with the seed, so reading the same
+// data with the same seed gives the same sample.
+//
+// The rows kept are not in the order in which they were read. Any columns
+// to be compressed (see DFRCompress) are compressed once the sample is
+// complete. It is an error if n is less than 1.
+func SampleRows(n int, seed int64) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if n < 1 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the number of rows to sample (%d) must be >= 1", n)
+		}
+		dfr.sampleRows = n
+		dfr.sampleSeed = seed
+		return nil
+	}
+}
+
+// sampleRow is called when a row has been added to the end of the
+// dataframe. If the rows are being sampled and the sample is full it
+// either replaces a randomly chosen row of the sample with the new row or
+// discards it, so that every row read is equally likely to be in the
+// sample.
+func (dfr *DFReader) sampleRow(state *dfReadState, df *DF) {
+	if dfr.sampleRows == 0 {
+		return
+	}
+
+	state.sampleSeen++
+	if state.sampleSeen <= int64(dfr.sampleRows) {
+		return
+	}
+
+	last := df.RowCount() - 1
+	if i := state.sampleRand.Int63n(state.sampleSeen); i < int64(last) {
+		if len(state.rowLines) > last {
+			state.rowLines[i] = state.rowLines[last]
+		}
+		df.moveLastRow(int(i))
+	} else {
+		df.truncateRows(last)
+	}
+	if len(state.rowLines) > last {
+		state.rowLines = state.rowLines[:last]
+	}
+}
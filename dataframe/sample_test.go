@@ -0,0 +1,118 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// sampleContent returns a header line followed by n lines, line i (counting
+// the header as line 1) holding the values i and "x" or "y" by turns
+func sampleContent(n int) string {
+	var b strings.Builder
+	b.WriteString("line s\n")
+	for i := 2; i < n+2; i++ {
+		s := "x"
+		if i%2 != 0 {
+			s = "y"
+		}
+		fmt.Fprintln(&b, i, s)
+	}
+	return b.String()
+}
+
+func TestSampleRows(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		rows    int
+		n       int
+		seed    int64
+		expRows int
+		expVals [][]string
+	}{
+		{
+			ID:      testhelper.MkID("sample of a long input"),
+			rows:    1000,
+			n:       10,
+			seed:    1,
+			expRows: 10,
+		},
+		{
+			ID:      testhelper.MkID("sample of a long input, another seed"),
+			rows:    1000,
+			n:       10,
+			seed:    2,
+			expRows: 10,
+		},
+		{
+			ID:      testhelper.MkID("fewer rows than the sample size"),
+			rows:    3,
+			n:       5,
+			expRows: 3,
+			expVals: [][]string{
+				{"2", "x", "2"},
+				{"3", "y", "3"},
+				{"4", "x", "4"},
+			},
+		},
+		{
+			ID: testhelper.MkID("bad: sample size"),
+			ExpErr: testhelper.MkExpErr(
+				"the number of rows to sample (0) must be >= 1"),
+			rows: 3,
+		},
+	}
+
+	samples := map[string]bool{}
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(dataframe.HasHeader,
+			dataframe.SampleRows(tc.n, tc.seed),
+			dataframe.DFRLineNumCol("srcLine"),
+			dataframe.DFRCompress("s"))
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+		content := sampleContent(tc.rows)
+		df, err := dfr.Read(strings.NewReader(content), "test data")
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error: %s", err)
+			continue
+		}
+		testhelper.DiffInt(t, tc.IDStr(), "rows", df.RowCount(), tc.expRows)
+		if tc.expVals != nil {
+			checkDFVals(t, tc.IDStr(), df, tc.expVals)
+		}
+		compressed, _, _ := df.IsCompressed("s")
+		testhelper.DiffBool(t, tc.IDStr(), "compressed", compressed, true)
+
+		// every row must be a distinct row of the input, with the line
+		// number it was read from
+		lines, _ := df.IntColByNameView("line")
+		srcLines, _ := df.IntColByNameView("srcLine")
+		seen := map[int64]bool{}
+		for i, line := range lines {
+			if seen[line.Val] || line != srcLines[i] {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: bad row %d: line %s, source line %s",
+					i, line, srcLines[i])
+			}
+			seen[line.Val] = true
+		}
+		samples[fmt.Sprint(lines)] = true
+
+		again, err := dfr.Read(strings.NewReader(content), "test data")
+		if err != nil {
+			t.Fatal("cannot read the data again: ", err)
+		}
+		if err := df.Equal(again); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the same seed gave a different sample: %s", err)
+		}
+	}
+	testhelper.DiffInt(t, "samples", "distinct samples", len(samples), 3)
+}
@@ -0,0 +1,208 @@
+package dataframe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaCol describes a column expected by a Schema
+type SchemaCol struct {
+	// Name is the name of the column
+	Name string
+	// Type is the type of the column. ColTypeUnknown allows any type
+	Type ColType
+	// Nullable, if true, allows the column to have NA values
+	Nullable bool
+}
+
+// Schema describes the columns that a dataframe is expected to have. It can
+// be used to check a dataframe once it has been made (see Validate) or to
+// check the data as it is read (see DFRSchema) so that changes in the
+// format of the data are found as early as possible.
+type Schema struct {
+	// Cols are the expected columns
+	Cols []SchemaCol
+	// Ordered, if true, requires the columns to be in the same order as
+	// in Cols
+	Ordered bool
+	// AllowExtra, if true, allows the dataframe to have columns which are
+	// not in Cols. If Ordered is also true the expected columns must still
+	// be in order relative to each other
+	AllowExtra bool
+}
+
+// check returns an error if the schema is invalid
+func (s Schema) check() error {
+	if len(s.Cols) == 0 {
+		return dfErrorf("the schema has no columns")
+	}
+
+	seen := make(map[string]bool, len(s.Cols))
+	for i, sc := range s.Cols {
+		if sc.Name == "" {
+			return dfErrorf("schema column %d has no name", i)
+		}
+		if seen[sc.Name] {
+			return dfErrorf("schema column %d (%q) is a duplicate", i, sc.Name)
+		}
+		seen[sc.Name] = true
+		if sc.Type >= ColTypeMaxVal {
+			return dfErrorf("schema column %d (%q) has a bad type: %s",
+				i, sc.Name, sc.Type)
+		}
+	}
+	return nil
+}
+
+// colProblems returns a description of each difference between the
+// columns of the schema and the columns given
+func (s Schema) colProblems(mci MultiColInfo) []string {
+	var problems []string
+
+	lastIdx := -1
+	for _, sc := range s.Cols {
+		idx, ok := mci.colIdx(sc.Name)
+		if !ok {
+			problems = append(problems,
+				fmt.Sprintf("column %q is missing", sc.Name))
+			continue
+		}
+		ci := mci.info[idx]
+		if sc.Type != ColTypeUnknown && ci.colType != sc.Type {
+			problems = append(problems,
+				fmt.Sprintf("column %q is of type %s not %s",
+					sc.Name, ci.colType, sc.Type))
+		}
+		if s.Ordered {
+			if idx < lastIdx {
+				problems = append(problems,
+					fmt.Sprintf("column %q is out of order", sc.Name))
+			}
+			lastIdx = idx
+		}
+	}
+
+	if !s.AllowExtra {
+		expected := make(map[string]bool, len(s.Cols))
+		for _, sc := range s.Cols {
+			expected[sc.Name] = true
+		}
+		for _, ci := range mci.info {
+			if !expected[ci.name] {
+				problems = append(problems,
+					fmt.Sprintf("column %q is not expected", ci.name))
+			}
+		}
+	}
+
+	return problems
+}
+
+// schemaError returns an error describing the problems
+func schemaError(problems []string) error {
+	return dfKindErrorf(ErrSchemaMismatch,
+		"the dataframe does not match the schema: %s",
+		strings.Join(problems, "; "))
+}
+
+// Validate checks that the dataframe matches the schema and returns an
+// error describing every difference if not. As well as the columns, their
+// types and their order (if Ordered is true), it checks that there are no NA
+// values in any column that is not Nullable. The error will match
+// ErrSchemaMismatch when tested using errors.Is.
+func (s Schema) Validate(df *DF) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+
+	problems := s.colProblems(df.mci)
+
+	for _, sc := range s.Cols {
+		idx, ok := df.mci.colIdx(sc.Name)
+		if !ok || sc.Nullable {
+			continue
+		}
+		naCount := 0
+		for r := 0; r < df.RowCount(); r++ {
+			if _, isNA := df.valAt(idx, r); isNA {
+				naCount++
+			}
+		}
+		if naCount > 0 {
+			problems = append(problems,
+				fmt.Sprintf("column %q has %d NA values", sc.Name, naCount))
+		}
+	}
+
+	if len(problems) > 0 {
+		return schemaError(problems)
+	}
+	return nil
+}
+
+// DFRSchema returns a function which will cause the DFReader to check the
+// data against the schema as it is read. The columns are checked as soon as
+// their names and types are known and, if they don't match, the Read fails
+// regardless of AllowErrors. Explicit NA values in a column that is not
+// Nullable are reported as errors in the same way as values that cannot be
+// parsed. Note that values that cannot be parsed are already reported and
+// are not reported again.
+func DFRSchema(s Schema) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if err := s.check(); err != nil {
+			return err
+		}
+		dfr.schema = &s
+		return nil
+	}
+}
+
+// checkSchemaCols checks the columns of the dataframe against the
+// DFReader's schema, if any
+func (dfr *DFReader) checkSchemaCols(state *dfReadState, df *DF) error {
+	if dfr.schema == nil {
+		return nil
+	}
+
+	if problems := dfr.schema.colProblems(df.mci); len(problems) > 0 {
+		return dfWrapf(schemaError(problems), "%s", state.loc.Source())
+	}
+	return nil
+}
+
+// checkSchemaNulls records an error for each explicit NA value in the last
+// row of the dataframe which is in a column that the DFReader's schema
+// doesn't allow to be NA. The first error (if any) is returned.
+func (dfr *DFReader) checkSchemaNulls(state *dfReadState, df *DF,
+	cols []string, isNA []bool, line int64, text string,
+) error {
+	if dfr.schema == nil || isNA == nil {
+		return nil
+	}
+
+	var firstErr error
+	for _, sc := range dfr.schema.Cols {
+		if sc.Nullable {
+			continue
+		}
+		idx, ok := df.mci.colIdx(sc.Name)
+		if !ok || !isNA[idx] {
+			continue
+		}
+		err := ParseError{
+			Source: state.loc.Source(),
+			Line:   line,
+			Text:   text,
+			Col:    idx,
+			Field:  cols[idx],
+			Msg:    fmt.Sprintf("column %q must not be NA", sc.Name),
+			Code:   ErrCodeNAValue,
+			kind:   ErrSchemaMismatch,
+		}
+		df.addError(err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
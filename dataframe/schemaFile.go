@@ -0,0 +1,137 @@
+package dataframe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// schemaFileCol is a column in a JSON schema file
+type schemaFileCol struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// colTypeByNameFold returns the ColType with the given name, ignoring case
+func colTypeByNameFold(name string) (ColType, error) {
+	for ct := ColTypeBool; ct < ColTypeMaxVal; ct++ {
+		if strings.EqualFold(ct.String(), name) {
+			return ct, nil
+		}
+	}
+	return ColTypeUnknown, dfErrorf("unknown column type: %q", name)
+}
+
+// parseJSONSchemaFile parses the content of a JSON schema file which is
+// either an array of columns or an object with a "columns" entry holding
+// the array
+func parseJSONSchemaFile(content []byte) ([]string, []ColType, error) {
+	var cols []schemaFileCol
+	if bytes.HasPrefix(content, []byte("{")) {
+		var obj struct {
+			Columns []schemaFileCol `json:"columns"`
+		}
+		if err := json.Unmarshal(content, &obj); err != nil {
+			return nil, nil, dfWrapf(err, "bad JSON")
+		}
+		cols = obj.Columns
+	} else if err := json.Unmarshal(content, &cols); err != nil {
+		return nil, nil, dfWrapf(err, "bad JSON")
+	}
+
+	names := make([]string, 0, len(cols))
+	types := make([]ColType, 0, len(cols))
+	for i, c := range cols {
+		ct, err := colTypeByNameFold(c.Type)
+		if err != nil {
+			return nil, nil, dfWrapf(err, "column %d (%q)", i, c.Name)
+		}
+		names = append(names, c.Name)
+		types = append(types, ct)
+	}
+	return names, types, nil
+}
+
+// parseTextSchemaFile parses the content of a text schema file which has
+// one line per column giving the column name and type
+func parseTextSchemaFile(content []byte) ([]string, []ColType, error) {
+	var names []string
+	var types []ColType
+
+	sep := regexp.MustCompile(defaultSplitPattern)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		fields, _, err := splitQuotedLine(line, sep, goUnquote)
+		if err != nil {
+			return nil, nil, dfWrapf(err, "line %d", lineNum)
+		}
+		if len(fields) != 2 {
+			return nil, nil, dfErrorf("line %d: expected 'name type',"+
+				" found %d fields", lineNum, len(fields))
+		}
+		ct, err := colTypeByNameFold(fields[1])
+		if err != nil {
+			return nil, nil, dfWrapf(err, "line %d", lineNum)
+		}
+		names = append(names, fields[0])
+		types = append(types, ct)
+	}
+	return names, types, scanner.Err()
+}
+
+// DFRSchemaFile returns a function which will read the column names and
+// types from the named file, which is useful when the data files have no
+// header. The file is read when the option is applied. It may either be a
+// text file with one line per column giving the name and the type
+// (separated by white space, with the name in double quotes if it has
+// spaces; blank lines and lines starting with '#' are ignored):
+//
+//	id     Int
+//	"unit cost" Float
+//
+// or a JSON file holding an array of objects, each with a "name" and a
+// "type", or an object with a "columns" entry holding such an array:
+//
+//	{"columns": [{"name": "id", "type": "Int"}]}
+//
+// The type names are those given by ColType.String, in any case. It is an
+// error to give this together with HasHeader, DFRColNames or DFRColTypes.
+func DFRSchemaFile(path string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return dfWrapf(err, "cannot read the schema file")
+		}
+		content = bytes.TrimSpace(content)
+
+		var names []string
+		var types []ColType
+		if bytes.HasPrefix(content, []byte("[")) ||
+			bytes.HasPrefix(content, []byte("{")) {
+			names, types, err = parseJSONSchemaFile(content)
+		} else {
+			names, types, err = parseTextSchemaFile(content)
+		}
+		if err != nil {
+			return dfWrapf(err, "bad schema file: %s", path)
+		}
+
+		if err := DFRColNames(names...)(dfr); err != nil {
+			return dfWrapf(err, "schema file: %s", path)
+		}
+		if err := DFRColTypes(types...)(dfr); err != nil {
+			return dfWrapf(err, "schema file: %s", path)
+		}
+		return nil
+	}
+}
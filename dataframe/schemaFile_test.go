@@ -0,0 +1,83 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRSchemaFile(t *testing.T) {
+	expCols := []dataframe.ColInfo{
+		dataframe.MustNewColInfo("id", dataframe.ColTypeInt),
+		dataframe.MustNewColInfo("unit cost", dataframe.ColTypeFloat),
+		dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts []dataframe.DFReaderOpt
+	}{
+		{
+			ID: testhelper.MkID("text file"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRSchemaFile(testData + "/schema.txt"),
+			},
+		},
+		{
+			ID: testhelper.MkID("JSON file"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRSchemaFile(testData + "/schema.json"),
+			},
+		},
+		{
+			ID: testhelper.MkID("no such file"),
+			ExpErr: testhelper.MkExpErr("cannot read the schema file",
+				"no such file or directory"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRSchemaFile(fileNameNoSuchFile),
+			},
+		},
+		{
+			ID: testhelper.MkID("bad line"),
+			ExpErr: testhelper.MkExpErr("bad schema file",
+				"line 2: expected 'name type', found 1 fields"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRSchemaFile(testData + "/schemaBadLine.txt"),
+			},
+		},
+		{
+			ID: testhelper.MkID("bad type"),
+			ExpErr: testhelper.MkExpErr("bad schema file",
+				`column 0 ("id"): unknown column type: "Date"`),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRSchemaFile(testData + "/schemaBadType.json"),
+			},
+		},
+		{
+			ID: testhelper.MkID("with header"),
+			ExpErr: testhelper.MkExpErr("you cannot give column names" +
+				" and take names from a header"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.DFRSchemaFile(testData + "/schema.txt"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			df, err := dfr.Read(strings.NewReader("1 2.5 a\n2 3 b\n"),
+				"test data")
+			if err != nil {
+				t.Fatal("unexpected error: ", err)
+			}
+			checkColDetails(t, tc.IDStr(), df, expCols)
+			checkDFVals(t, tc.IDStr(), df,
+				[][]string{{"1", "2.5", "a"}, {"2", "3", "b"}})
+		}
+	}
+}
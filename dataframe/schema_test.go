@@ -0,0 +1,176 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	df := mkTestDF(t, "i f s\n1 1.5 a\n2 x b\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		schema dataframe.Schema
+	}{
+		{
+			ID: testhelper.MkID("exact match"),
+			schema: dataframe.Schema{
+				Cols: []dataframe.SchemaCol{
+					{Name: "i", Type: dataframe.ColTypeInt},
+					{Name: "f", Type: dataframe.ColTypeFloat, Nullable: true},
+					{Name: "s", Type: dataframe.ColTypeString},
+				},
+				Ordered: true,
+			},
+		},
+		{
+			ID: testhelper.MkID("any type, extra columns allowed"),
+			schema: dataframe.Schema{
+				Cols: []dataframe.SchemaCol{
+					{Name: "s"},
+					{Name: "i"},
+				},
+				AllowExtra: true,
+			},
+		},
+		{
+			ID: testhelper.MkID("many problems"),
+			ExpErr: testhelper.MkExpErr(
+				"the dataframe does not match the schema: ",
+				`column "s" is of type String not Int`,
+				`column "i" is out of order`,
+				`column "f" has 1 NA values`,
+				`column "x" is missing`),
+			schema: dataframe.Schema{
+				Cols: []dataframe.SchemaCol{
+					{Name: "s", Type: dataframe.ColTypeInt},
+					{Name: "i"},
+					{Name: "f"},
+					{Name: "x"},
+				},
+				Ordered: true,
+			},
+		},
+		{
+			ID: testhelper.MkID("unexpected column"),
+			ExpErr: testhelper.MkExpErr(
+				`column "f" is not expected`),
+			schema: dataframe.Schema{
+				Cols: []dataframe.SchemaCol{
+					{Name: "i"},
+					{Name: "s"},
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("bad schema"),
+			ExpErr: testhelper.MkExpErr(
+				`schema column 1 ("i") is a duplicate`),
+			schema: dataframe.Schema{
+				Cols: []dataframe.SchemaCol{
+					{Name: "i"},
+					{Name: "i"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.schema.Validate(df)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestDFRSchema(t *testing.T) {
+	schema := dataframe.Schema{
+		Cols: []dataframe.SchemaCol{
+			{Name: "i", Type: dataframe.ColTypeInt},
+			{Name: "s", Type: dataframe.ColTypeString, Nullable: true},
+		},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data      string
+		opts      []dataframe.DFReaderOpt
+		expErrCnt int64
+	}{
+		{
+			ID:   testhelper.MkID("good, guessed types"),
+			data: "i s\n1 a\n2 b\n",
+			opts: []dataframe.DFReaderOpt{dataframe.HasHeader},
+		},
+		{
+			ID: testhelper.MkID("bad, guessed types"),
+			ExpErr: testhelper.MkExpErr(
+				`column "i" is of type Float not Int`),
+			data: "i s\n1.5 a\n2 b\n",
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader, dataframe.AllowErrors,
+			},
+		},
+		{
+			ID: testhelper.MkID("bad, given types"),
+			ExpErr: testhelper.MkExpErr(
+				`column "s" is of type Bool not String`),
+			data: "i s\n1 true\n",
+			opts: []dataframe.DFReaderOpt{
+				dataframe.HasHeader,
+				dataframe.DFRColTypes(
+					dataframe.ColTypeInt, dataframe.ColTypeBool),
+			},
+		},
+		{
+			ID: testhelper.MkID("missing column, no data"),
+			ExpErr: testhelper.MkExpErr(
+				`column "s" is missing`),
+			data: "i\n",
+			opts: []dataframe.DFReaderOpt{dataframe.HasHeader},
+		},
+		{
+			ID:   testhelper.MkID("NA values, round trip, errors allowed"),
+			data: "\"i\" \"s\"\nInt String\nNA NA\n1 NA\nNA \"x\"\n",
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRRoundTrip, dataframe.AllowErrors,
+			},
+			expErrCnt: 2,
+		},
+		{
+			ID: testhelper.MkID("NA values, round trip"),
+			ExpErr: testhelper.MkExpErr(
+				`round trip:4: column "i" must not be NA`),
+			data: "\"i\" \"s\"\nInt String\n1 NA\nNA \"x\"\n",
+			opts: []dataframe.DFReaderOpt{dataframe.DFRRoundTrip},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(
+			append(tc.opts, dataframe.DFRSchema(schema))...)
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(tc.data), "round trip")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffInt(t, tc.IDStr(), "error count",
+				df.ErrCount(), tc.expErrCnt)
+			for _, e := range df.Errors() {
+				testhelper.DiffBool(t, tc.IDStr(), "schema error",
+					errors.Is(e, dataframe.ErrSchemaMismatch), true)
+			}
+		}
+		if err != nil {
+			testhelper.DiffBool(t, tc.IDStr(), "schema error",
+				errors.Is(err, dataframe.ErrSchemaMismatch), true)
+		}
+	}
+}
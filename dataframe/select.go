@@ -0,0 +1,85 @@
+package dataframe
+
+// selectColInfo returns the ColInfo values for the named columns in the
+// order given. It returns an error if any name is not found
+func selectColInfo(mci MultiColInfo, names []string) ([]ColInfo, error) {
+	if len(names) == 0 {
+		return nil, ErrNoNamesGiven
+	}
+
+	cis := make([]ColInfo, 0, len(names))
+	for _, name := range names {
+		i, ok := mci.colIdx(name)
+		if !ok {
+			return nil, errUnknownColName(name)
+		}
+		cis = append(cis, mci.info[i])
+	}
+
+	return cis, nil
+}
+
+// untypedSelection returns a new dataframe having just the named columns
+// but, like df, no column types and so no values
+func untypedSelection(df *DF, cis []ColInfo) (*DF, error) {
+	rval, err := NewDF()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cis))
+	for _, ci := range cis {
+		names = append(names, ci.name)
+	}
+	if err := rval.SetColNames(names...); err != nil {
+		return nil, err
+	}
+	for i, ci := range cis {
+		rval.mci.info[i].meta = ci.meta
+	}
+	rval.maxErrors = df.maxErrors
+	rval.meta = cloneMeta(df.meta)
+
+	return rval, nil
+}
+
+// Select returns a new dataframe holding just the named columns in the
+// order given. It returns an error if any name is not found or if any name
+// is repeated. The values are copied so subsequent changes to df will not
+// affect the new dataframe. If df has no column types, as for a dataframe
+// read from input having a header but no data, neither will the new one.
+func (df *DF) Select(names ...string) (*DF, error) {
+	cis, err := selectColInfo(df.mci, names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(df.mci.valIdx) == 0 {
+		return untypedSelection(df, cis)
+	}
+
+	rval, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	rval.maxErrors = df.maxErrors
+	rval.meta = cloneMeta(df.meta)
+
+	for i, name := range names {
+		srcIdx, _ := df.mci.colIdx(name)
+		srcVI := df.mci.valIdx[srcIdx]
+		vi := rval.mci.valIdx[i]
+
+		switch cis[i].colType {
+		case ColTypeBool:
+			rval.boolCols[vi] = df.boolValsCopy(srcVI)
+		case ColTypeInt:
+			rval.intCols[vi] = cloneValSlice(df.intCols[srcVI])
+		case ColTypeFloat:
+			rval.floatCols[vi] = cloneValSlice(df.floatCols[srcVI])
+		case ColTypeString:
+			rval.stringCols[vi] = df.stringValsCopy(srcVI)
+		}
+	}
+
+	return rval, nil
+}
@@ -0,0 +1,117 @@
+package dataframe
+
+import "fmt"
+
+// SeriesGroup holds the x and y values for one group of rows as returned
+// by SeriesByGroup. The Key is the value of the group column for the
+// rows in the group.
+type SeriesGroup struct {
+	Key    string
+	Xs, Ys []float64
+}
+
+// numericColIdx returns the index of the named column or an error if there
+// is no such column or if it is not an int or float column
+func (df *DF) numericColIdx(name string) (int, error) {
+	i, ok := df.mci.colIdx(name)
+	if !ok {
+		return 0, errUnknownColName(name)
+	}
+
+	if ct := df.mci.info[i].colType; ct != ColTypeInt && ct != ColTypeFloat {
+		return 0, dfKindErrorf(ErrTypeMismatch,
+			"The column named %q is of type %q, only %q and %q columns"+
+				" can be used in a series",
+			name, ct, ColTypeInt, ColTypeFloat)
+	}
+	return i, nil
+}
+
+// floatAt returns the value of the indexed int or float column and row as
+// a float64 and whether or not it is NA
+func (df *DF) floatAt(colIdx, rowIdx int) (float64, bool) {
+	v, isNA := df.valAt(colIdx, rowIdx)
+	if isNA {
+		return 0, true
+	}
+	switch v := v.(type) {
+	case IntVal:
+		return float64(v.Val), false
+	case FloatVal:
+		return v.Val, false
+	}
+	panic(dfErrorf("Unexpected value type: %T", v))
+}
+
+// Series returns the values of the named x and y columns as float64 slices
+// suitable for passing to a plotting library. Rows where either value is
+// NA are dropped so the two slices always have the same length. It returns
+// an error if either column doesn't exist or is not an int or float
+// column.
+func (df *DF) Series(xCol, yCol string) (xs, ys []float64, err error) {
+	xi, err := df.numericColIdx(xCol)
+	if err != nil {
+		return nil, nil, err
+	}
+	yi, err := df.numericColIdx(yCol)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	xs = make([]float64, 0, df.RowCount())
+	ys = make([]float64, 0, df.RowCount())
+	for r := 0; r < df.RowCount(); r++ {
+		x, xIsNA := df.floatAt(xi, r)
+		y, yIsNA := df.floatAt(yi, r)
+		if xIsNA || yIsNA {
+			continue
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+	}
+	return xs, ys, nil
+}
+
+// SeriesByGroup is like Series but splits the values into a separate
+// series for each value of the group column, one series per line on a
+// plot, for instance. The groups are returned in the order in which their
+// key values first appear. The group column may be of any type; rows with
+// an NA group value are dropped as are rows where either the x or y value
+// is NA.
+func (df *DF) SeriesByGroup(xCol, yCol, groupCol string,
+) ([]SeriesGroup, error) {
+	xi, err := df.numericColIdx(xCol)
+	if err != nil {
+		return nil, err
+	}
+	yi, err := df.numericColIdx(yCol)
+	if err != nil {
+		return nil, err
+	}
+	gi, ok := df.mci.colIdx(groupCol)
+	if !ok {
+		return nil, errUnknownColName(groupCol)
+	}
+
+	groupIdx := map[any]int{}
+	groups := []SeriesGroup{}
+	for r := 0; r < df.RowCount(); r++ {
+		gv, gIsNA := df.valAt(gi, r)
+		x, xIsNA := df.floatAt(xi, r)
+		y, yIsNA := df.floatAt(yi, r)
+		if gIsNA || xIsNA || yIsNA {
+			continue
+		}
+
+		k := df.keyAt(gi, r)
+		idx, ok := groupIdx[k]
+		if !ok {
+			idx = len(groups)
+			groupIdx[k] = idx
+			groups = append(groups, SeriesGroup{Key: fmt.Sprint(gv)})
+		}
+		groups[idx].Xs = append(groups[idx].Xs, x)
+		groups[idx].Ys = append(groups[idx].Ys, y)
+	}
+	return groups, nil
+}
@@ -0,0 +1,97 @@
+package dataframe_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSeries(t *testing.T) {
+	df := mkTestDF(t,
+		"x y g s\n"+
+			"1 1.5 a p\n"+
+			"2 x b q\n"+
+			"3 3.5 x r\n"+
+			"4 4.5 b s\n"+
+			"5 5.5 a t\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeFloat,
+			dataframe.ColTypeBool, dataframe.ColTypeString))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		xCol, yCol string
+		expXs      []float64
+		expYs      []float64
+	}{
+		{
+			ID:    testhelper.MkID("NA dropped"),
+			xCol:  "x",
+			yCol:  "y",
+			expXs: []float64{1, 3, 4, 5},
+			expYs: []float64{1.5, 3.5, 4.5, 5.5},
+		},
+		{
+			ID:   testhelper.MkID("non-numeric column"),
+			xCol: "x",
+			yCol: "s",
+			ExpErr: testhelper.MkExpErr(`The column named "s"`,
+				`only "Int" and "Float" columns can be used in a series`),
+		},
+		{
+			ID:     testhelper.MkID("no such column"),
+			xCol:   "nonesuch",
+			yCol:   "y",
+			ExpErr: testhelper.MkExpErr(`"nonesuch"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		xs, ys, err := df.Series(tc.xCol, tc.yCol)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			if !reflect.DeepEqual(xs, tc.expXs) ||
+				!reflect.DeepEqual(ys, tc.expYs) {
+				t.Log(tc.IDStr())
+				t.Logf("\t: expected: %v %v\n", tc.expXs, tc.expYs)
+				t.Logf("\t:      got: %v %v\n", xs, ys)
+				t.Error("\t: unexpected series")
+			}
+		}
+	}
+}
+
+func TestSeriesByGroup(t *testing.T) {
+	df := mkTestDF(t,
+		"x y g\n"+
+			"1 1.5 a\n"+
+			"2 x b\n"+
+			"3 3.5 NA\n"+
+			"4 4.5 b\n"+
+			"5 5.5 a\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeFloat,
+			dataframe.ColTypeString))
+
+	groups, err := df.SeriesByGroup("x", "y", "g")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	exp := []dataframe.SeriesGroup{
+		{Key: "a", Xs: []float64{1, 5}, Ys: []float64{1.5, 5.5}},
+		{Key: "NA", Xs: []float64{3}, Ys: []float64{3.5}},
+		{Key: "b", Xs: []float64{4}, Ys: []float64{4.5}},
+	}
+	if !reflect.DeepEqual(groups, exp) {
+		t.Log("SeriesByGroup")
+		t.Logf("\t: expected: %v\n", exp)
+		t.Logf("\t:      got: %v\n", groups)
+		t.Error("\t: unexpected groups")
+	}
+
+	_, err = df.SeriesByGroup("x", "y", "nonesuch")
+	testhelper.CheckExpErrWithID(t, "no such group column", err,
+		testhelper.MkExpErr(`"nonesuch"`))
+}
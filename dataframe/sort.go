@@ -0,0 +1,168 @@
+package dataframe
+
+import (
+	"math"
+	"sort"
+)
+
+// SortKey gives a column to sort by and the direction of the sort
+type SortKey struct {
+	Col  string
+	Desc bool
+}
+
+// cmpVals compares two values of the same Val type returning a negative
+// number if a is less than b, a positive number if a is greater than b and
+// zero if they are equal. NA values are treated as equal to each other and
+// greater than any other value. Float NaN values are likewise treated as
+// equal to each other and greater than any number but less than NA.
+func cmpVals(a, b any) int {
+	ak, aIsNA := keyOf(a)
+	bk, bIsNA := keyOf(b)
+
+	switch {
+	case aIsNA && bIsNA:
+		return 0
+	case aIsNA:
+		return 1
+	case bIsNA:
+		return -1
+	}
+
+	switch av := ak.(type) {
+	case bool:
+		bv := bk.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		}
+		return 1
+	case int64:
+		bv := bk.(int64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	case float64:
+		bv := bk.(float64)
+		aIsNaN, bIsNaN := math.IsNaN(av), math.IsNaN(bv)
+		switch {
+		case aIsNaN && bIsNaN:
+			return 0
+		case aIsNaN:
+			return 1
+		case bIsNaN:
+			return -1
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	case string:
+		bv := bk.(string)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	}
+	return 0
+}
+
+// sortKeyIdxs returns the indexes of the sort key columns. It returns an
+// error if any key column does not exist.
+func (df *DF) sortKeyIdxs(keys []SortKey) ([]int, error) {
+	colIdxs := make([]int, 0, len(keys))
+	for _, k := range keys {
+		i, ok := df.mci.colIdx(k.Col)
+		if !ok {
+			return nil, errUnknownColName(k.Col)
+		}
+		colIdxs = append(colIdxs, i)
+	}
+	return colIdxs, nil
+}
+
+// isNAOrNaN returns true if the value is NA or a float NaN value. Such
+// values sort last whatever the direction of the sort.
+func isNAOrNaN(v any) bool {
+	k, isNA := keyOf(v)
+	if f, ok := k.(float64); ok && math.IsNaN(f) {
+		return true
+	}
+	return isNA
+}
+
+// cmpRows compares row ar of a with row br of b, which must have the same
+// columns, by the values in the sort key columns, whose indexes are given.
+// It returns a negative number if row ar sorts before row br, a positive
+// number if it sorts after and zero if the key values are equal.
+func cmpRows(a *DF, ar int, b *DF, br int, keys []SortKey, colIdxs []int,
+) int {
+	for ki, k := range keys {
+		av, _ := a.valAt(colIdxs[ki], ar)
+		bv, _ := b.valAt(colIdxs[ki], br)
+		c := cmpVals(av, bv)
+		if c == 0 {
+			continue
+		}
+		if k.Desc && !isNAOrNaN(av) && !isNAOrNaN(bv) {
+			c = -c
+		}
+		return c
+	}
+	return 0
+}
+
+// sortedRowIdxs returns the row indexes in the order given by the sort
+// keys. It returns an error if any key column does not exist.
+func (df *DF) sortedRowIdxs(keys []SortKey) ([]int, error) {
+	colIdxs, err := df.sortKeyIdxs(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	rowIdxs := make([]int, df.RowCount())
+	for i := range rowIdxs {
+		rowIdxs[i] = i
+	}
+
+	sort.SliceStable(rowIdxs, func(i, j int) bool {
+		return cmpRows(df, rowIdxs[i], df, rowIdxs[j], keys, colIdxs) < 0
+	})
+
+	return rowIdxs, nil
+}
+
+// Sort returns a new dataframe with the rows of df sorted by the values in
+// the key columns. The rows are ordered by the first key and then, for rows
+// with equal values, by the next key and so on. The sort is stable so rows
+// with equal values in all the key columns keep their original order. NA
+// values always sort after all other values, whatever the direction of the
+// sort, and float NaN values sort after all numbers but before NA. It
+// returns an error if no keys are given or if any key column does not
+// exist.
+func (df *DF) Sort(keys ...SortKey) (*DF, error) {
+	if len(keys) == 0 {
+		return nil, dfErrorf("no sort keys have been given")
+	}
+
+	rowIdxs, err := df.sortedRowIdxs(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+
+	for _, i := range rowIdxs {
+		rval.copyRowFrom(df, i)
+	}
+
+	return rval, nil
+}
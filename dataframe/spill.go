@@ -0,0 +1,464 @@
+package dataframe
+
+import (
+	"container/heap"
+	"os"
+	"unsafe"
+)
+
+// spillDefaultMemLimit is the default memory budget for the Store Sort and
+// GroupBy methods
+const spillDefaultMemLimit = 64 << 20
+
+// spillFanIn is the most runs that are merged at once. If there are more
+// runs they are merged in several passes.
+const spillFanIn = 16
+
+// spillChunks is the number of batches, each of a similar size, into which
+// the memory budget is divided when writing sorted runs. It is big enough
+// that spillFanIn batches being merged together with the merged output fit
+// in the budget.
+const spillChunks = 2 * spillFanIn
+
+// spillOpts holds the settings controlling the Store Sort and GroupBy
+// methods
+type spillOpts struct {
+	memLimit int64
+	dir      string
+}
+
+// SpillOpt is the type of an option function for the Store Sort and
+// GroupBy methods
+type SpillOpt func(*spillOpts) error
+
+// SpillMemLimit returns a function which will set the approximate number
+// of bytes of data which the Store Sort and GroupBy methods will hold in
+// memory. Above this the data is sorted in runs which are written to
+// temporary files and then merged. The default limit is 64MiB.
+func SpillMemLimit(n int64) SpillOpt {
+	return func(o *spillOpts) error {
+		if n <= 0 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the memory limit must be greater than 0: %d", n)
+		}
+		o.memLimit = n
+		return nil
+	}
+}
+
+// SpillDir returns a function which will set the directory in which the
+// Store Sort and GroupBy methods create their temporary files. By default
+// the default directory for temporary files is used.
+func SpillDir(dir string) SpillOpt {
+	return func(o *spillOpts) error {
+		o.dir = dir
+		return nil
+	}
+}
+
+// makeSpillOpts returns the spill settings with the options applied
+func makeSpillOpts(opts []SpillOpt) (spillOpts, error) {
+	o := spillOpts{memLimit: spillDefaultMemLimit}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+// memSize returns an estimate of the number of bytes of memory used by the
+// values in the dataframe
+func (df *DF) memSize() int64 {
+	n := int64(df.RowCount())
+	size := int64(0)
+	for c, ci := range df.mci.info {
+		vi := df.mci.valIdx[c]
+		switch ci.colType {
+		case ColTypeBool:
+			size += n * int64(unsafe.Sizeof(BoolVal{}))
+		case ColTypeInt:
+			size += n * int64(unsafe.Sizeof(IntVal{}))
+		case ColTypeFloat:
+			size += n * int64(unsafe.Sizeof(FloatVal{}))
+		case ColTypeString:
+			size += n * int64(unsafe.Sizeof(StringVal{}))
+			for r := 0; r < int(n); r++ {
+				size += int64(len(df.stringAt(vi, r).Val))
+			}
+		}
+	}
+	return size
+}
+
+// chunkRows returns the number of rows of the dataframe making up one
+// chunk of the memory budget
+func (o spillOpts) chunkRows(df *DF) int {
+	rows := df.RowCount()
+	if rows == 0 {
+		return 1
+	}
+	rowSize := df.memSize()/int64(rows) + 1
+	if n := o.memLimit / spillChunks / rowSize; n > 1 {
+		return int(n)
+	}
+	return 1
+}
+
+// forChunks calls f with successive dataframes each holding the next
+// given number of rows of df
+func forChunks(df *DF, rows int, f func(batch *DF) error) error {
+	for lo := 0; lo < df.RowCount(); lo += rows {
+		hi := lo + rows
+		if hi > df.RowCount() {
+			hi = df.RowCount()
+		}
+		if err := f(df.rowRange(lo, hi)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batch reads the indexed batch from the store
+func (s *Store) batch(i int) (*DF, error) {
+	s.mu.Lock()
+	rec := s.recs[i]
+	s.mu.Unlock()
+
+	df, err := s.readRec(rec, true)
+	if err != nil {
+		return nil, dfWrapf(err, "store: %s: batch %d", s.filename, i)
+	}
+	return df, nil
+}
+
+// newSpillStore creates an empty Store in a temporary file in the
+// directory
+func newSpillStore(dir string) (*Store, error) {
+	file, err := os.CreateTemp(dir, "dataframe-*.dfspill")
+	if err != nil {
+		return nil, err
+	}
+	filename := file.Name()
+	file.Close()
+
+	s, err := OpenStore(filename)
+	if err != nil {
+		os.Remove(filename)
+		return nil, err
+	}
+	return s, nil
+}
+
+// removeSpillStores closes the stores and removes their files
+func removeSpillStores(stores []*Store) {
+	for _, s := range stores {
+		s.Close()
+		os.Remove(s.filename)
+	}
+}
+
+// runCursor records the position reached in a sorted run being merged
+type runCursor struct {
+	run   *Store
+	idx   int // the index of the run, used to keep the merge stable
+	batch int
+	df    *DF
+	row   int
+}
+
+// next moves the cursor to the next row, reading the next batch if
+// necessary. It returns false if there are no more rows.
+func (rc *runCursor) next() (bool, error) {
+	rc.row++
+	for rc.df == nil || rc.row >= rc.df.RowCount() {
+		if rc.batch >= rc.run.BatchCount() {
+			return false, nil
+		}
+		df, err := rc.run.batch(rc.batch)
+		if err != nil {
+			return false, err
+		}
+		rc.df, rc.row = df, 0
+		rc.batch++
+	}
+	return true, nil
+}
+
+// runHeap holds the cursors of the runs being merged, ordered by the sort
+// keys of their current rows
+type runHeap struct {
+	cursors []*runCursor
+	keys    []SortKey
+	colIdxs []int
+}
+
+func (h *runHeap) Len() int { return len(h.cursors) }
+
+func (h *runHeap) Less(i, j int) bool {
+	a, b := h.cursors[i], h.cursors[j]
+	if c := cmpRows(a.df, a.row, b.df, b.row, h.keys, h.colIdxs); c != 0 {
+		return c < 0
+	}
+	return a.idx < b.idx
+}
+
+func (h *runHeap) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+func (h *runHeap) Push(x any) { h.cursors = append(h.cursors, x.(*runCursor)) }
+
+func (h *runHeap) Pop() any {
+	n := len(h.cursors) - 1
+	rc := h.cursors[n]
+	h.cursors = h.cursors[:n]
+	return rc
+}
+
+// mergeRuns merges the sorted runs, passing the merged rows to f in
+// batches of the given number of rows
+func mergeRuns(runs []*Store, keys []SortKey, rows int,
+	f func(batch *DF) error,
+) error {
+	h := &runHeap{keys: keys}
+	var out *DF
+	for i, run := range runs {
+		rc := &runCursor{run: run, idx: i, row: -1}
+		ok, err := rc.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = rc.df.Clone()
+			if h.colIdxs, err = rc.df.sortKeyIdxs(keys); err != nil {
+				return err
+			}
+		}
+		h.cursors = append(h.cursors, rc)
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		rc := h.cursors[0]
+		out.copyRowFrom(rc.df, rc.row)
+		if out.RowCount() >= rows {
+			if err := f(out); err != nil {
+				return err
+			}
+			out = out.Clone()
+		}
+
+		ok, err := rc.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	if out != nil && out.RowCount() > 0 {
+		return f(out)
+	}
+	return nil
+}
+
+// sorted passes the rows of the store, sorted by the keys, to f in
+// batches. Rows are read until they exceed the memory limit and are then
+// sorted and written to a temporary Store as a run; the runs are then
+// merged. If all the rows fit within the memory limit no temporary files
+// are used.
+func (s *Store) sorted(keys []SortKey, o spillOpts, f func(batch *DF) error,
+) error {
+	var runs []*Store
+	defer func() { removeSpillStores(runs) }()
+
+	var pending []*DF
+	size := int64(0)
+	rows := 1
+	// sortPending sorts the pending batches and, if toRun is set, writes
+	// them to a new run, otherwise passes them to f
+	sortPending := func(toRun bool) error {
+		df, err := Concat(pending)
+		if err != nil {
+			return err
+		}
+		pending, size = nil, 0
+		if df, err = df.Sort(keys...); err != nil {
+			return err
+		}
+		rows = o.chunkRows(df)
+		if !toRun {
+			return forChunks(df, rows, f)
+		}
+
+		run, err := newSpillStore(o.dir)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+		return forChunks(df, rows, run.Append)
+	}
+
+	err := s.Scan(func(batch *DF) error {
+		pending = append(pending, batch)
+		size += batch.memSize()
+		if size > o.memLimit {
+			return sortPending(true)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		if err := sortPending(len(runs) > 0); err != nil {
+			return err
+		}
+	}
+
+	// each pass merges groups of consecutive runs so that the earlier rows
+	// stay in the earlier runs and the merge remains stable
+	for len(runs) > spillFanIn {
+		var merged []*Store
+		for len(runs) > 0 {
+			n := spillFanIn
+			if n > len(runs) {
+				n = len(runs)
+			}
+			run, err := newSpillStore(o.dir)
+			if err != nil {
+				runs = append(merged, runs...)
+				return err
+			}
+			merged = append(merged, run)
+			err = mergeRuns(runs[:n], keys, rows, run.Append)
+			removeSpillStores(runs[:n])
+			runs = runs[n:]
+			if err != nil {
+				runs = append(merged, runs...)
+				return err
+			}
+		}
+		runs = merged
+	}
+	return mergeRuns(runs, keys, rows, f)
+}
+
+// checkDst returns an error if the destination store is the same as the
+// store being read
+func (s *Store) checkDst(dst *Store) error {
+	if dst == s {
+		return dfErrorf("store: %s: the results cannot be written to the"+
+			" store being read", s.filename)
+	}
+	return nil
+}
+
+// Sort sorts the rows of the store by the values in the key columns, as
+// for the DF Sort method, and appends them to dst, which must be empty or
+// have the same columns. The rows need not fit in memory: once the rows
+// read exceed the memory limit (see SpillMemLimit) they are sorted and
+// written to a temporary file and the sorted files are then merged. This
+// is slower than sorting in memory but allows a store of any size to be
+// sorted.
+func (s *Store) Sort(dst *Store, keys []SortKey, opts ...SpillOpt) error {
+	if len(keys) == 0 {
+		return dfErrorf("no sort keys have been given")
+	}
+	if err := s.checkDst(dst); err != nil {
+		return err
+	}
+	o, err := makeSpillOpts(opts)
+	if err != nil {
+		return err
+	}
+
+	return s.sorted(keys, o, dst.Append)
+}
+
+// GroupBy calculates the aggregations for each distinct tuple of values of
+// the key columns, as for the DF GroupByCols method, and appends the
+// results to dst, which must be empty or have the same columns as the
+// results. The groups are in the order of their key values, with NA values
+// last. The rows are grouped by sorting them as for Sort, so they need not
+// fit in memory, and the groups are appended to dst in batches so neither
+// need the results.
+func (s *Store) GroupBy(dst *Store, keys []string, aggs []Agg,
+	opts ...SpillOpt,
+) error {
+	if err := s.checkDst(dst); err != nil {
+		return err
+	}
+	o, err := makeSpillOpts(opts)
+	if err != nil {
+		return err
+	}
+	g, err := newGrouper(s.Columns(), keys, true, aggs)
+	if err != nil {
+		return err
+	}
+
+	sortKeys := make([]SortKey, 0, len(keys))
+	for _, k := range keys {
+		sortKeys = append(sortKeys, SortKey{Col: k})
+	}
+	// this allows roughly 64 bytes for each key and aggregated value of a
+	// group
+	groupSize := int64(64 * (len(keys) + len(aggs)))
+	maxGroups := 1
+	if n := o.memLimit / spillChunks / groupSize; n > 1 {
+		maxGroups = int(n)
+	}
+
+	var prev *DF
+	var prevRow int
+	var colIdxs []int
+	err = s.sorted(sortKeys, o, func(batch *DF) error {
+		if colIdxs == nil {
+			var err error
+			if colIdxs, err = batch.sortKeyIdxs(sortKeys); err != nil {
+				return err
+			}
+		}
+		for r := 0; r < batch.RowCount(); r++ {
+			if len(g.groupKeys) >= maxGroups &&
+				cmpRows(prev, prevRow, batch, r, sortKeys, colIdxs) != 0 {
+				if err := g.appendTo(dst); err != nil {
+					return err
+				}
+			}
+			if err := g.addRow(batch.Row(r)); err != nil {
+				return err
+			}
+			prev, prevRow = batch, r
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(g.groupKeys) > 0 {
+		return g.appendTo(dst)
+	}
+	return nil
+}
+
+// appendTo appends the results for the groups to the store and clears the
+// groups
+func (g *grouper) appendTo(s *Store) error {
+	df, err := g.result()
+	if err != nil {
+		return err
+	}
+	g.groupIdx = map[string]int{}
+	g.groupKeys = nil
+	g.accs = nil
+	return s.Append(df)
+}
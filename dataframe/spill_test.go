@@ -0,0 +1,173 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkSpillTestStore returns a store holding the dataframe, appended in
+// batches of the given number of rows
+func mkSpillTestStore(t *testing.T, filename string, df *dataframe.DF,
+	rows int,
+) *dataframe.Store {
+	t.Helper()
+
+	s := openTestStore(t, filename)
+	df.Chunks(rows)(func(chunk *dataframe.DF) bool {
+		if err := s.Append(chunk); err != nil {
+			t.Fatal("cannot append to the store: ", err)
+		}
+		return true
+	})
+	return s
+}
+
+func TestStoreSortGroupBy(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("k s v\n")
+	for i := 0; i < 100; i++ {
+		v := fmt.Sprintf("%d.5", i)
+		if i%7 == 0 {
+			v = "NA"
+		}
+		fmt.Fprintf(&b, "%d %c %s\n", i*37%11, 'a'+byte(i%5), v)
+	}
+	df := mkTestDF(t, b.String(), dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeString,
+			dataframe.ColTypeFloat))
+
+	dir := t.TempDir()
+	spillDir := t.TempDir()
+	src := mkSpillTestStore(t, filepath.Join(dir, "src"), df, 5)
+	defer src.Close()
+
+	keys := []dataframe.SortKey{{Col: "s", Desc: true}, {Col: "k"}}
+	aggs := []dataframe.Agg{
+		{Func: dataframe.AggCount},
+		{Col: "v", Func: dataframe.AggSum},
+	}
+	expSorted, err := df.Sort(keys...)
+	if err != nil {
+		t.Fatal("BAD TEST - cannot sort the dataframe: ", err)
+	}
+	gdf, err := df.GroupByCols("k", "s")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot group the dataframe: ", err)
+	}
+	expGrouped, err := gdf.Agg(aggs...)
+	if err != nil {
+		t.Fatal("BAD TEST - cannot aggregate the dataframe: ", err)
+	}
+	expGrouped, err = expGrouped.Sort(
+		dataframe.SortKey{Col: "k"}, dataframe.SortKey{Col: "s"})
+	if err != nil {
+		t.Fatal("BAD TEST - cannot sort the groups: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		opts []dataframe.SpillOpt
+	}{
+		{
+			ID: testhelper.MkID("in memory"),
+		},
+		{
+			ID: testhelper.MkID("spilled, one merge pass"),
+			opts: []dataframe.SpillOpt{
+				dataframe.SpillMemLimit(1000), dataframe.SpillDir(spillDir),
+			},
+		},
+		{
+			ID: testhelper.MkID("spilled, several merge passes"),
+			opts: []dataframe.SpillOpt{
+				dataframe.SpillMemLimit(1), dataframe.SpillDir(spillDir),
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		sorted := openTestStore(t, filepath.Join(dir, fmt.Sprint("sorted", i)))
+		if err := src.Sort(sorted, keys, tc.opts...); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot sort the store: %s", err)
+		} else if err := mustRead(t, sorted).Equal(expSorted); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the sorted rows differ: %s", err)
+		}
+		sorted.Close()
+
+		grouped := openTestStore(t,
+			filepath.Join(dir, fmt.Sprint("grouped", i)))
+		err := src.GroupBy(grouped, []string{"k", "s"}, aggs, tc.opts...)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot group the store: %s", err)
+		} else if err := mustRead(t, grouped).Equal(expGrouped); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the groups differ: %s", err)
+		}
+		grouped.Close()
+
+		files, err := os.ReadDir(spillDir)
+		if err != nil {
+			t.Fatal("cannot read the spill directory: ", err)
+		}
+		testhelper.DiffInt(t, tc.IDStr(), "spill files left", len(files), 0)
+	}
+}
+
+func TestStoreSortErrors(t *testing.T) {
+	dir := t.TempDir()
+	df := mkTestDF(t, "a b\n2 x\n1 y\n", dataframe.HasHeader)
+	src := mkSpillTestStore(t, filepath.Join(dir, "src"), df, 1)
+	defer src.Close()
+	dst := openTestStore(t, filepath.Join(dir, "dst"))
+	defer dst.Close()
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		dst  *dataframe.Store
+		keys []dataframe.SortKey
+		opts []dataframe.SpillOpt
+	}{
+		{
+			ID:     testhelper.MkID("no keys"),
+			ExpErr: testhelper.MkExpErr("no sort keys have been given"),
+			dst:    dst,
+		},
+		{
+			ID:     testhelper.MkID("unknown column"),
+			ExpErr: testhelper.MkExpErr(`"c"`),
+			dst:    dst,
+			keys:   []dataframe.SortKey{{Col: "c"}},
+		},
+		{
+			ID: testhelper.MkID("sorted into itself"),
+			ExpErr: testhelper.MkExpErr(
+				"the results cannot be written to the store being read"),
+			dst:  src,
+			keys: []dataframe.SortKey{{Col: "a"}},
+		},
+		{
+			ID: testhelper.MkID("bad memory limit"),
+			ExpErr: testhelper.MkExpErr(
+				"the memory limit must be greater than 0: -1"),
+			dst:  dst,
+			keys: []dataframe.SortKey{{Col: "a"}},
+			opts: []dataframe.SpillOpt{dataframe.SpillMemLimit(-1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := src.Sort(tc.dst, tc.keys, tc.opts...)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+	testhelper.DiffInt(t, "after errors", "dst rows", dst.RowCount(), int64(0))
+}
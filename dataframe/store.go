@@ -0,0 +1,313 @@
+package dataframe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"os"
+	"sync"
+)
+
+// storeMagic is written at the start of a Store file to identify it
+const storeMagic = "DFSTORE\x01"
+
+// storeRecHdrLen is the length of the header before each batch in a Store
+// file: the length of the encoded batch (8 bytes), the number of rows (4
+// bytes) and the CRC-32 checksum of the encoded batch (4 bytes), all
+// big-endian
+const storeRecHdrLen = 16
+
+// storeRec records the position of a batch in a Store file
+type storeRec struct {
+	off  int64 // the offset of the encoded batch, after the header
+	size int64
+	rows int64
+	crc  uint32
+}
+
+// Store is an append-only file holding a sequence of batches of rows, all
+// with the same columns. Each batch is held in the columnar encoding
+// written by WriteMsgPack together with a checksum so that a batch which
+// was only partly written, for instance because the process stopped while
+// appending it, can be detected; it is removed when the Store is next
+// opened. The rows can be read back as a single dataframe by Read or a
+// batch at a time by Scan.
+//
+// The methods of a Store may be called concurrently but the file must not
+// be opened by more than one Store at a time.
+type Store struct {
+	mu       sync.Mutex
+	file     *os.File
+	filename string
+	cols     []ColInfo
+	recs     []storeRec
+	end      int64
+	rows     int64
+}
+
+// OpenStore opens the named Store file, creating it if it doesn't exist.
+// An incomplete batch at the end of the file is removed. It returns an
+// error if the file is not a Store file or if any other batch is corrupt.
+func OpenStore(filename string) (*Store, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{file: file, filename: filename}
+	if err := s.load(); err != nil {
+		file.Close()
+		return nil, dfWrapf(err, "store: %s", filename)
+	}
+	return s, nil
+}
+
+// load checks the file header and finds the batches in the file
+func (s *Store) load() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	if size == 0 {
+		if _, err := s.file.WriteAt([]byte(storeMagic), 0); err != nil {
+			return err
+		}
+		s.end = int64(len(storeMagic))
+		return s.file.Sync()
+	}
+
+	magic := make([]byte, len(storeMagic))
+	if _, err := s.file.ReadAt(magic, 0); err != nil ||
+		string(magic) != storeMagic {
+		return dfErrorf("the file is not a dataframe store")
+	}
+
+	hdr := make([]byte, storeRecHdrLen)
+	for off := int64(len(storeMagic)); off < size; {
+		if size-off < storeRecHdrLen {
+			return s.truncate(off)
+		}
+		if _, err := s.file.ReadAt(hdr, off); err != nil {
+			return err
+		}
+		rec := storeRec{
+			off:  off + storeRecHdrLen,
+			size: int64(binary.BigEndian.Uint64(hdr[0:8])),
+			rows: int64(binary.BigEndian.Uint32(hdr[8:12])),
+			crc:  binary.BigEndian.Uint32(hdr[12:16]),
+		}
+		if rec.size < 0 || rec.size > size-rec.off {
+			return s.truncate(off)
+		}
+		last := rec.off+rec.size == size
+		df, err := s.readRec(rec, len(s.recs) == 0)
+		if err != nil {
+			if last {
+				return s.truncate(off)
+			}
+			return dfWrapf(err, "batch %d", len(s.recs))
+		}
+		if df != nil {
+			s.cols = df.Columns()
+		}
+
+		s.recs = append(s.recs, rec)
+		s.rows += rec.rows
+		off = rec.off + rec.size
+	}
+	s.end = size
+	return nil
+}
+
+// truncate removes the incomplete batch starting at the offset
+func (s *Store) truncate(off int64) error {
+	if err := s.file.Truncate(off); err != nil {
+		return err
+	}
+	s.end = off
+	return s.file.Sync()
+}
+
+// readRec reads the batch and checks its checksum. If decode is set the
+// batch is also decoded and returned, otherwise the returned dataframe is
+// nil.
+func (s *Store) readRec(rec storeRec, decode bool) (*DF, error) {
+	buf := make([]byte, rec.size)
+	if _, err := s.file.ReadAt(buf, rec.off); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(buf) != rec.crc {
+		return nil, dfErrorf("the checksum is wrong")
+	}
+	if !decode {
+		return nil, nil
+	}
+
+	df, err := decodeMsgPack(buf)
+	if err != nil {
+		return nil, err
+	}
+	if int64(df.RowCount()) != rec.rows {
+		return nil, dfKindErrorf(ErrDimensionMismatch,
+			"the batch has %d rows, expected %d", df.RowCount(), rec.rows)
+	}
+	return df, nil
+}
+
+// checkCols returns an error if the columns of the dataframe differ from
+// those of the batches already in the store
+func (s *Store) checkCols(df *DF) error {
+	if len(s.recs) == 0 {
+		return nil
+	}
+	if len(df.mci.info) != len(s.cols) {
+		return dfKindErrorf(ErrSchemaMismatch,
+			"the batch has %d columns, the store has %d",
+			len(df.mci.info), len(s.cols))
+	}
+	for i, ci := range df.mci.info {
+		if ci.name != s.cols[i].name || ci.colType != s.cols[i].colType {
+			return dfKindErrorf(ErrSchemaMismatch,
+				"column %d of the batch is %q (%s), the store has %q (%s)",
+				i, ci.name, ci.colType, s.cols[i].name, s.cols[i].colType)
+		}
+	}
+	return nil
+}
+
+// Append adds the rows of the dataframe to the end of the store as a new
+// batch. The first batch appended sets the columns of the store and every
+// later batch must have the same columns, with the same names and types in
+// the same order. The file is synced before Append returns so the batch
+// will survive the process stopping.
+func (s *Store) Append(df *DF) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return dfErrorf("store: %s: the store has been closed", s.filename)
+	}
+	if err := s.checkCols(df); err != nil {
+		return dfWrapf(err, "store: %s", s.filename)
+	}
+	if uint64(df.RowCount()) > math.MaxUint32 {
+		return dfErrorf("store: %s: too many rows in the batch: %d",
+			s.filename, df.RowCount())
+	}
+
+	var data bytes.Buffer
+	data.Write(make([]byte, storeRecHdrLen))
+	if err := df.WriteMsgPack(&data); err != nil {
+		return err
+	}
+	buf := data.Bytes()
+	rec := storeRec{
+		off:  s.end + storeRecHdrLen,
+		size: int64(len(buf) - storeRecHdrLen),
+		rows: int64(df.RowCount()),
+		crc:  crc32.ChecksumIEEE(buf[storeRecHdrLen:]),
+	}
+	binary.BigEndian.PutUint64(buf[0:8], uint64(rec.size))
+	binary.BigEndian.PutUint32(buf[8:12], uint32(rec.rows))
+	binary.BigEndian.PutUint32(buf[12:16], rec.crc)
+
+	if _, err := s.file.WriteAt(buf, s.end); err != nil {
+		return dfWrapf(err, "store: %s: cannot append the batch", s.filename)
+	}
+	if err := s.file.Sync(); err != nil {
+		return dfWrapf(err, "store: %s: cannot append the batch", s.filename)
+	}
+
+	if len(s.recs) == 0 {
+		s.cols = df.Columns()
+	}
+	s.recs = append(s.recs, rec)
+	s.rows += rec.rows
+	s.end += int64(len(buf))
+	return nil
+}
+
+// RowCount returns the total number of rows in the store
+func (s *Store) RowCount() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rows
+}
+
+// BatchCount returns the number of batches in the store
+func (s *Store) BatchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.recs)
+}
+
+// Columns returns the details of the columns of the store. It is empty if
+// nothing has been appended.
+func (s *Store) Columns() []ColInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]ColInfo(nil), s.cols...)
+}
+
+// Scan calls f with each of the batches in the store in the order in which
+// they were appended, so that the rows can be processed without holding
+// them all in memory. It stops and returns the error if f returns an
+// error. Only the batches in the store when Scan is called are passed to
+// f; it may append to the store.
+func (s *Store) Scan(f func(batch *DF) error) error {
+	s.mu.Lock()
+	if s.file == nil {
+		s.mu.Unlock()
+		return dfErrorf("store: %s: the store has been closed", s.filename)
+	}
+	recs := s.recs[:len(s.recs):len(s.recs)]
+	s.mu.Unlock()
+
+	for i, rec := range recs {
+		df, err := s.readRec(rec, true)
+		if err != nil {
+			return dfWrapf(err, "store: %s: batch %d", s.filename, i)
+		}
+		if err := f(df); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read returns all the rows in the store as a single dataframe. The
+// dataframe metadata is taken from the batches as for Concat.
+func (s *Store) Read() (*DF, error) {
+	var batches []*DF
+	err := s.Scan(func(batch *DF) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return NewDF()
+	}
+	return Concat(batches)
+}
+
+// Close closes the store's file. The store cannot be used after it has
+// been closed.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
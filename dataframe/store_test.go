@@ -0,0 +1,139 @@
+package dataframe_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// openTestStore opens the store, failing the test if it cannot be opened
+func openTestStore(t *testing.T, filename string) *dataframe.Store {
+	t.Helper()
+
+	s, err := dataframe.OpenStore(filename)
+	if err != nil {
+		t.Fatal("cannot open the store: ", err)
+	}
+	return s
+}
+
+func TestStore(t *testing.T) {
+	mk := func(content string) *dataframe.DF {
+		return mkTestDF(t, content,
+			dataframe.HasHeader,
+			dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeFloat,
+				dataframe.ColTypeString))
+	}
+	filename := filepath.Join(t.TempDir(), "ts.dfs")
+
+	s := openTestStore(t, filename)
+	df, err := s.Read()
+	if err != nil {
+		t.Fatal("cannot read the empty store: ", err)
+	}
+	testhelper.DiffInt(t, "empty store", "columns", df.ColCount(), 0)
+
+	for _, content := range []string{
+		"t v s\n1 1.5 a\n2 2.5 b\n",
+		"t v s\n3 3.5 c\n",
+	} {
+		if err := s.Append(mk(content)); err != nil {
+			t.Fatal("cannot append to the store: ", err)
+		}
+	}
+	err = s.Append(mkTestDF(t, "t s v\n4 d 4.5\n", dataframe.HasHeader))
+	testhelper.CheckExpErrWithID(t, "bad batch", err,
+		testhelper.MkExpErr(`column 1 of the batch is "s" (String),`,
+			`the store has "v" (Float)`))
+	if err := s.Close(); err != nil {
+		t.Fatal("cannot close the store: ", err)
+	}
+	err = s.Append(mk("t v s\n4 4.5 d\n"))
+	testhelper.CheckExpErrWithID(t, "closed store", err,
+		testhelper.MkExpErr("the store has been closed"))
+
+	// simulate a batch being partly written when the process stopped
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal("cannot open the store file: ", err)
+	}
+	if _, err := f.Write([]byte{0, 0, 0, 0, 0, 0, 1, 0, 0, 0}); err != nil {
+		t.Fatal("cannot write to the store file: ", err)
+	}
+	f.Close()
+
+	s = openTestStore(t, filename)
+	defer s.Close()
+	testhelper.DiffInt(t, "reopened store", "batches", s.BatchCount(), 2)
+	testhelper.DiffInt(t, "reopened store", "rows", s.RowCount(), int64(3))
+	checkDFVals(t, "reopened store", mustRead(t, s), [][]string{
+		{"1", "1.5", "a"},
+		{"2", "2.5", "b"},
+		{"3", "3.5", "c"},
+	})
+
+	if err := s.Append(mk("t v s\n4 4.5 d\n")); err != nil {
+		t.Fatal("cannot append to the reopened store: ", err)
+	}
+	var rows []int
+	errStop := errors.New("stop")
+	err = s.Scan(func(batch *dataframe.DF) error {
+		rows = append(rows, batch.RowCount())
+		if len(rows) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	testhelper.DiffBool(t, "Scan", "stopped", err == errStop, true)
+	testhelper.DiffSlice(t, "Scan", "batch rows", rows, []int{2, 1})
+	checkColDetails(t, "Columns", mustRead(t, s), s.Columns())
+}
+
+// mustRead reads the store, failing the test if it cannot be read
+func mustRead(t *testing.T, s *dataframe.Store) *dataframe.DF {
+	t.Helper()
+
+	df, err := s.Read()
+	if err != nil {
+		t.Fatal("cannot read the store: ", err)
+	}
+	return df
+}
+
+func TestOpenStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	notStore := filepath.Join(dir, "notStore")
+	if err := os.WriteFile(notStore, []byte("a b\n1 2\n"), 0o600); err != nil {
+		t.Fatal("cannot write the test file: ", err)
+	}
+	_, err := dataframe.OpenStore(notStore)
+	testhelper.CheckExpErrWithID(t, "not a store", err,
+		testhelper.MkExpErr("the file is not a dataframe store"))
+
+	corrupt := filepath.Join(dir, "corrupt")
+	s := openTestStore(t, corrupt)
+	df := mkTestDF(t, "a\n1\n", dataframe.HasHeader)
+	for i := 0; i < 2; i++ {
+		if err := s.Append(df); err != nil {
+			t.Fatal("cannot append to the store: ", err)
+		}
+	}
+	s.Close()
+
+	data, err := os.ReadFile(corrupt)
+	if err != nil {
+		t.Fatal("cannot read the store file: ", err)
+	}
+	data[len(data)/2-1] ^= 0xff // corrupt the first batch
+	if err := os.WriteFile(corrupt, data, 0o600); err != nil {
+		t.Fatal("cannot rewrite the store file: ", err)
+	}
+	_, err = dataframe.OpenStore(corrupt)
+	testhelper.CheckExpErrWithID(t, "corrupt batch", err,
+		testhelper.MkExpErr("batch 0", "the checksum is wrong"))
+}
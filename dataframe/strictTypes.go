@@ -0,0 +1,266 @@
+package dataframe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickwells/check.mod/v2/check"
+)
+
+// DFRNumericCols returns a function which will cause the DFReader to
+// require the named columns to be numeric. If the column types are guessed
+// from the initial lines and the values in any of these columns cannot all
+// be read as ints or as floats then the Read fails with an error giving,
+// for each such column, the first value (and the line it is on) that
+// cannot be read as each type. A named column whose values can all be read
+// as bools, such as 0 and 1, is made an int column rather than a bool
+// column, and one with no values is made a float column. The columns must
+// be in the data. This has no effect if the column types are given (see
+// DFRColTypes and DFRRoundTrip). The error will match ErrTypeMismatch
+// when tested using errors.Is.
+func DFRNumericCols(names ...string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		if len(names) == 0 {
+			return ErrNoNamesGiven
+		}
+		for i, n := range names {
+			if n == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"numeric column %d has an empty name", i)
+			}
+		}
+		if err := check.SliceHasNoDups(names); err != nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"a numeric column is duplicated: %s", err)
+		}
+
+		dfr.numericCols = append(dfr.numericCols, names...)
+		return nil
+	}
+}
+
+// DFRStrictTypes returns a function which will cause the DFReader to
+// require every column other than the named string columns to have a
+// non-string type. If the column types are guessed from the initial lines
+// and the values in any other column cannot all be read as bools, as ints
+// or as floats then the Read fails with an error giving, for each such
+// column, the first value (and the line it is on) that cannot be read as
+// each type, rather than silently making it a string column. The string
+// columns must be in the data. This has no effect if the column types are
+// given (see DFRColTypes and DFRRoundTrip). The error will match
+// ErrTypeMismatch when tested using errors.Is.
+func DFRStrictTypes(stringCols ...string) DFReaderOpt {
+	return func(dfr *DFReader) error {
+		for i, n := range stringCols {
+			if n == "" {
+				return dfCodeErrorf(ErrCodeBadOption,
+					"string column %d has an empty name", i)
+			}
+		}
+		if err := check.SliceHasNoDups(stringCols); err != nil {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"a string column is duplicated: %s", err)
+		}
+
+		dfr.strictTypes = true
+		dfr.stringCols = append(dfr.stringCols, stringCols...)
+		return nil
+	}
+}
+
+// typeTest records a column type and how to tell if a value can be read
+// as that type
+type typeTest struct {
+	colType ColType
+	desc    string
+	ok      func(po parseOpts, name, s string) bool
+}
+
+var (
+	boolTest = typeTest{
+		colType: ColTypeBool,
+		desc:    "a bool",
+		ok: func(po parseOpts, _, s string) bool {
+			_, err := po.boolFmt.ParseBool(s)
+			return err == nil
+		},
+	}
+	intTest = typeTest{
+		colType: ColTypeInt,
+		desc:    "an int",
+		ok: func(po parseOpts, _, s string) bool {
+			_, err := po.numFmt.ParseInt(s)
+			return err == nil
+		},
+	}
+	floatTest = typeTest{
+		colType: ColTypeFloat,
+		desc:    "a float",
+		ok: func(po parseOpts, name, s string) bool {
+			_, err := po.parseFloat(name, s)
+			return err == nil
+		},
+	}
+)
+
+// cachedVals returns the values in column i of the cached initial lines
+// and the number of the line each is on. Empty values are left out unless
+// they are read as strings, as when guessing the column types.
+func (state *dfReadState) cachedVals(po parseOpts, i int) (
+	[]string, []int64,
+) {
+	var vals []string
+	var lines []int64
+	for r, row := range state.cache {
+		if i >= len(row) ||
+			(row[i] == "" && po.emptyFields != EmptyAsString) {
+			continue
+		}
+		vals = append(vals, row[i])
+		lines = append(lines, state.cacheLines[r])
+	}
+	return vals, lines
+}
+
+// orList returns the descriptions joined as a list of alternatives
+func orList(descs []string) string {
+	if len(descs) == 1 {
+		return descs[0]
+	}
+	return strings.Join(descs[:len(descs)-1], ", ") +
+		" or " + descs[len(descs)-1]
+}
+
+// typeProblem returns the type of the first test which all the values
+// pass. If none of them do it returns a description of the first value
+// failing each test, values failing several tests being given once.
+func typeProblem(po parseOpts, name string, tests []typeTest,
+	vals []string, lines []int64,
+) (ColType, string) {
+	var order []int
+	failed := map[int][]string{}
+	for _, t := range tests {
+		i := 0
+		for i < len(vals) && t.ok(po, name, vals[i]) {
+			i++
+		}
+		if i == len(vals) {
+			return t.colType, ""
+		}
+		if _, ok := failed[i]; !ok {
+			order = append(order, i)
+		}
+		failed[i] = append(failed[i], t.desc)
+	}
+
+	parts := make([]string, 0, len(order))
+	for _, i := range order {
+		parts = append(parts, fmt.Sprintf("%q (line %d) is not %s",
+			vals[i], lines[i], orList(failed[i])))
+	}
+	return ColTypeUnknown, strings.Join(parts, ", ")
+}
+
+// strictColNames returns the indexes of the numeric columns and of the
+// string columns or an error listing any which are not in the dataframe
+func (dfr *DFReader) strictColNames(state *dfReadState, mci MultiColInfo) (
+	numeric, str map[int]bool, err error,
+) {
+	numeric = map[int]bool{}
+	str = map[int]bool{}
+
+	var missing []string
+	for _, names := range []struct {
+		names []string
+		idx   map[int]bool
+	}{
+		{dfr.numericCols, numeric},
+		{dfr.stringCols, str},
+	} {
+		for _, name := range names.names {
+			idx, ok := mci.colIdx(name)
+			if !ok {
+				missing = append(missing, fmt.Sprintf("%q", name))
+				continue
+			}
+			names.idx[idx] = true
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, dfKindErrorf(ErrUnknownColumn,
+			"%s: columns given to be typed strictly are missing: %s",
+			state.loc.Source(), strings.Join(missing, ", "))
+	}
+	for idx := range mci.info {
+		if numeric[idx] && str[idx] {
+			return nil, nil, dfErrorf(
+				"%s: column %q is given as both numeric and a string column",
+				state.loc.Source(), mci.info[idx].name)
+		}
+	}
+	return numeric, str, nil
+}
+
+// strictColTypes checks the guessed column types against any numeric
+// columns (see DFRNumericCols) and, if types are to be strict (see
+// DFRStrictTypes), that no other columns are string columns. The types of
+// numeric columns guessed as bool or with no values are changed. It
+// returns an error describing every column which fails.
+func (dfr *DFReader) strictColTypes(state *dfReadState, mci MultiColInfo,
+	types []ColType,
+) error {
+	if len(dfr.numericCols) == 0 && !dfr.strictTypes {
+		return nil
+	}
+
+	numeric, str, err := dfr.strictColNames(state, mci)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for i, ci := range mci.info {
+		var tests []typeTest
+		msg := "the values have no common type"
+		switch {
+		case numeric[i]:
+			if types[i] == ColTypeInt || types[i] == ColTypeFloat {
+				continue
+			}
+			tests = []typeTest{intTest, floatTest}
+			msg = "the values are not all numbers"
+		case dfr.strictTypes && !str[i] && types[i] == ColTypeString:
+			tests = []typeTest{boolTest, intTest, floatTest}
+		default:
+			continue
+		}
+
+		vals, lines := state.cachedVals(dfr.parseOpts, i)
+		if len(vals) == 0 {
+			if numeric[i] {
+				types[i] = ColTypeFloat
+				continue
+			}
+			problems = append(problems, fmt.Sprintf(
+				"column %q: there are no values to find the type from",
+				ci.name))
+			continue
+		}
+
+		ct, problem := typeProblem(dfr.parseOpts, ci.name, tests, vals, lines)
+		if problem == "" {
+			types[i] = ct
+			continue
+		}
+		problems = append(problems,
+			fmt.Sprintf("column %q: %s: %s", ci.name, msg, problem))
+	}
+
+	if len(problems) > 0 {
+		return dfKindErrorf(ErrTypeMismatch,
+			"%s: cannot find the type of %d of the %d columns: %s",
+			state.loc.Source(), len(problems), len(mci.info),
+			strings.Join(problems, "; "))
+	}
+	return nil
+}
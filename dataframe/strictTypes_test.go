@@ -0,0 +1,155 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestStrictTypes(t *testing.T) {
+	const content = `name price qty flag
+apple 1.25 0 true
+pear n/a 1 false
+plum 2 x yes
+`
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		expCols []dataframe.ColInfo
+	}{
+		{
+			ID: testhelper.MkID("no strict typing"),
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("flag", dataframe.ColTypeString),
+			},
+		},
+		{
+			ID:   testhelper.MkID("numeric cols"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRNumericCols("price")},
+			ExpErr: testhelper.MkExpErr(
+				"test data: cannot find the type of 1 of the 4 columns:",
+				`column "price": the values are not all numbers:`+
+					` "1.25" (line 2) is not an int,`+
+					` "n/a" (line 3) is not a float`),
+		},
+		{
+			ID: testhelper.MkID("strict types"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRStrictTypes("name"),
+			},
+			ExpErr: testhelper.MkExpErr(
+				"test data: cannot find the type of 3 of the 4 columns:",
+				`column "price": the values have no common type:`+
+					` "1.25" (line 2) is not a bool or an int,`+
+					` "n/a" (line 3) is not a float;`,
+				`column "qty": the values have no common type:`+
+					` "x" (line 4) is not a bool, an int or a float;`,
+				`column "flag": the values have no common type:`+
+					` "yes" (line 4) is not a bool,`+
+					` "true" (line 2) is not an int or a float`),
+		},
+		{
+			ID: testhelper.MkID("strict types, missing string col"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRStrictTypes("nonesuch"),
+			},
+			ExpErr: testhelper.MkExpErr(
+				"columns given to be typed strictly are missing:",
+				`"nonesuch"`),
+		},
+		{
+			ID: testhelper.MkID("numeric and string col"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRNumericCols("price"),
+				dataframe.DFRStrictTypes("price"),
+			},
+			ExpErr: testhelper.MkExpErr(
+				`column "price" is given as both numeric and a string column`),
+		},
+		{
+			ID: testhelper.MkID("types given"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRStrictTypes(),
+				dataframe.DFRColTypes(
+					dataframe.ColTypeString, dataframe.ColTypeString,
+					dataframe.ColTypeString, dataframe.ColTypeString),
+			},
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("price", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("qty", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("flag", dataframe.ColTypeString),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfr, err := dataframe.NewDFReader(
+			append([]dataframe.DFReaderOpt{dataframe.HasHeader},
+				tc.opts...)...)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error: ", err)
+		}
+		df, err := dfr.Read(strings.NewReader(content), "test data")
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkColDetails(t, tc.IDStr(), df, tc.expCols)
+		}
+	}
+}
+
+func TestStrictTypesAgree(t *testing.T) {
+	id := "numeric cols"
+	df := mkTestDF(t, "a b c d\n0 1.5 x true\n1 2 y false\n",
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.DFRNumericCols("a", "b"),
+		dataframe.DFRStrictTypes("c"))
+	checkColDetails(t, id, df, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("a", dataframe.ColTypeInt),
+		dataframe.MustNewColInfo("b", dataframe.ColTypeFloat),
+		dataframe.MustNewColInfo("c", dataframe.ColTypeString),
+		dataframe.MustNewColInfo("d", dataframe.ColTypeBool),
+	})
+
+	id = "empty numeric col"
+	df = mkTestDF(t, "a,b\n,x\n,y\n",
+		dataframe.HasHeader,
+		dataframe.SplitPattern(","),
+		dataframe.DFREmptyFields(dataframe.EmptyAsNA),
+		dataframe.DFRNumericCols("a"))
+	checkColDetails(t, id, df, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("a", dataframe.ColTypeFloat),
+		dataframe.MustNewColInfo("b", dataframe.ColTypeString),
+	})
+
+	id = "errors allowed"
+	dfr, err := dataframe.NewDFReader(
+		dataframe.HasHeader,
+		dataframe.AllowErrors,
+		dataframe.DFRStrictTypes())
+	if err != nil {
+		t.Fatal(id, ": unexpected error: ", err)
+	}
+	_, err = dfr.Read(strings.NewReader("a\nx\n"), "test data")
+	testhelper.CheckExpErrWithID(t, id, err,
+		testhelper.MkExpErr(`column "a": the values have no common type:`,
+			`"x" (line 2) is not a bool, an int or a float`))
+	if !errors.Is(err, dataframe.ErrTypeMismatch) {
+		t.Log(id)
+		t.Errorf("\t: the error should match ErrTypeMismatch: %v", err)
+	}
+
+	_, err = dataframe.NewDFReader(dataframe.DFRNumericCols())
+	testhelper.CheckExpErrWithID(t, "no numeric cols", err,
+		testhelper.MkExpErr("no column names have been given"))
+	_, err = dataframe.NewDFReader(dataframe.DFRNumericCols("a", "a"))
+	testhelper.CheckExpErrWithID(t, "dup numeric cols", err,
+		testhelper.MkExpErr("a numeric column is duplicated"))
+}
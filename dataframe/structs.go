@@ -0,0 +1,337 @@
+package dataframe
+
+import (
+	"database/sql"
+	"math"
+	"reflect"
+)
+
+// structTagName is the name of the struct tag used to give the name of the
+// column corresponding to a struct field
+const structTagName = "df"
+
+var (
+	boolValType   = reflect.TypeOf(BoolVal{})
+	intValType    = reflect.TypeOf(IntVal{})
+	floatValType  = reflect.TypeOf(FloatVal{})
+	stringValType = reflect.TypeOf(StringVal{})
+
+	nullTypes = map[reflect.Type]ColType{
+		reflect.TypeOf(sql.NullBool{}):    ColTypeBool,
+		reflect.TypeOf(sql.NullByte{}):    ColTypeInt,
+		reflect.TypeOf(sql.NullInt16{}):   ColTypeInt,
+		reflect.TypeOf(sql.NullInt32{}):   ColTypeInt,
+		reflect.TypeOf(sql.NullInt64{}):   ColTypeInt,
+		reflect.TypeOf(sql.NullFloat64{}): ColTypeFloat,
+		reflect.TypeOf(sql.NullString{}):  ColTypeString,
+	}
+)
+
+// fieldKind records how the column value is held in the struct field
+type fieldKind int
+
+const (
+	fieldKindPlain fieldKind = iota // a bool, int, float or string
+	fieldKindPtr                    // a pointer to a plain value
+	fieldKindNull                   // one of the sql.Null... types
+	fieldKindVal                    // one of the ...Val types
+)
+
+// structField records the details of a struct field mapped to a column
+type structField struct {
+	index   int
+	name    string
+	colType ColType
+	kind    fieldKind
+}
+
+// kindColType returns the column type corresponding to the reflect.Kind
+func kindColType(k reflect.Kind) (ColType, bool) {
+	switch k {
+	case reflect.Bool:
+		return ColTypeBool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return ColTypeInt, true
+	case reflect.Float32, reflect.Float64:
+		return ColTypeFloat, true
+	case reflect.String:
+		return ColTypeString, true
+	}
+	return ColTypeUnknown, false
+}
+
+// fieldColType returns the column type and field kind for the struct
+// field type. It returns false if the type cannot be held in a column.
+func fieldColType(t reflect.Type) (ColType, fieldKind, bool) {
+	switch t {
+	case boolValType:
+		return ColTypeBool, fieldKindVal, true
+	case intValType:
+		return ColTypeInt, fieldKindVal, true
+	case floatValType:
+		return ColTypeFloat, fieldKindVal, true
+	case stringValType:
+		return ColTypeString, fieldKindVal, true
+	}
+
+	if ct, ok := nullTypes[t]; ok {
+		return ct, fieldKindNull, true
+	}
+
+	if t.Kind() == reflect.Pointer {
+		ct, ok := kindColType(t.Elem().Kind())
+		return ct, fieldKindPtr, ok
+	}
+
+	ct, ok := kindColType(t.Kind())
+	return ct, fieldKindPlain, ok
+}
+
+// structFields returns the details of the fields of the struct type that
+// map to columns. Unexported fields and fields with a tag of "-" are
+// ignored. The column name is taken from the tag if present, otherwise the
+// field name is used.
+func structFields(t reflect.Type) ([]structField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, dfErrorf("the type (%s) is not a struct", t)
+	}
+
+	fields := []structField{}
+	names := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup(structTagName); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		if other, ok := names[name]; ok {
+			return nil, dfErrorf(
+				"fields %s and %s both map to the column named %q",
+				other, f.Name, name)
+		}
+		names[name] = f.Name
+
+		ct, kind, ok := fieldColType(f.Type)
+		if !ok {
+			return nil, dfErrorf(
+				"field %s has a type (%s) which cannot be held in a column",
+				f.Name, f.Type)
+		}
+
+		fields = append(fields, structField{
+			index:   i,
+			name:    name,
+			colType: ct,
+			kind:    kind,
+		})
+	}
+
+	if len(fields) == 0 {
+		return nil, dfErrorf("the type (%s) has no fields to map to columns", t)
+	}
+
+	return fields, nil
+}
+
+// plainVal returns the column value corresponding to the plain value. It
+// returns an error if the value is an unsigned integer too large for an
+// int column.
+func plainVal(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return BoolVal{Val: v.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return IntVal{Val: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return nil, dfKindErrorf(ErrTypeMismatch,
+				"the value (%d) overflows an int column", v.Uint())
+		}
+		return IntVal{Val: int64(v.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return FloatVal{Val: v.Float()}, nil
+	case reflect.String:
+		return StringVal{Val: v.String()}, nil
+	}
+	return nil, nil
+}
+
+// val returns the column value corresponding to the field value
+func (sf structField) val(v reflect.Value) (any, error) {
+	switch sf.kind {
+	case fieldKindVal:
+		return v.Interface(), nil
+	case fieldKindNull:
+		if !v.FieldByName("Valid").Bool() {
+			return nil, nil
+		}
+		return plainVal(v.Field(0))
+	case fieldKindPtr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return plainVal(v.Elem())
+	}
+	return plainVal(v)
+}
+
+// setPlain sets the plain value from the column value which must not be NA
+func setPlain(v reflect.Value, cv any) error {
+	switch cv := cv.(type) {
+	case BoolVal:
+		v.SetBool(cv.Val)
+	case IntVal:
+		if v.CanInt() {
+			if v.OverflowInt(cv.Val) {
+				return dfKindErrorf(ErrTypeMismatch,
+					"the value (%d) overflows a field of type %s",
+					cv.Val, v.Type())
+			}
+			v.SetInt(cv.Val)
+		} else {
+			if cv.Val < 0 || v.OverflowUint(uint64(cv.Val)) {
+				return dfKindErrorf(ErrTypeMismatch,
+					"the value (%d) overflows a field of type %s",
+					cv.Val, v.Type())
+			}
+			v.SetUint(uint64(cv.Val))
+		}
+	case FloatVal:
+		v.SetFloat(cv.Val)
+	case StringVal:
+		v.SetString(cv.Val)
+	}
+	return nil
+}
+
+// set sets the field value from the column value
+func (sf structField) set(v reflect.Value, cv any, isNA bool) error {
+	switch sf.kind {
+	case fieldKindVal:
+		v.Set(reflect.ValueOf(cv))
+		return nil
+	case fieldKindNull:
+		v.Set(reflect.Zero(v.Type()))
+		if isNA {
+			return nil
+		}
+		v.FieldByName("Valid").SetBool(true)
+		return setPlain(v.Field(0), cv)
+	case fieldKindPtr:
+		if isNA {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		pv := reflect.New(v.Type().Elem())
+		if err := setPlain(pv.Elem(), cv); err != nil {
+			return err
+		}
+		v.Set(pv)
+		return nil
+	}
+
+	if isNA {
+		return dfKindErrorf(ErrTypeMismatch,
+			"an NA value cannot be held in a field of type %s",
+			v.Type())
+	}
+	return setPlain(v, cv)
+}
+
+// FromStructs creates a dataframe from the slice of structs. Each exported
+// field of the struct type becomes a column; the column name is given by
+// the "df" struct tag or, if there is no tag, by the field name. A field
+// with a tag of "-" is ignored.
+//
+// Fields may be of bool, integer, floating point or string type or one of
+// the corresponding Val types (BoolVal, IntVal etc), pointers to the plain
+// types or any of the sql.Null... types apart from sql.NullTime. A nil
+// pointer or a sql.Null... value which is not Valid gives an NA value. It
+// is an error if an unsigned integer value is too large for an int column.
+func FromStructs[T any](recs []T) (*DF, error) {
+	fields, err := structFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	cis := make([]ColInfo, 0, len(fields))
+	for _, f := range fields {
+		cis = append(cis, ColInfo{name: f.name, colType: f.colType})
+	}
+
+	df, err := newDFFromColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+
+	for r, rec := range recs {
+		rv := reflect.ValueOf(rec)
+		for i, f := range fields {
+			v, err := f.val(rv.Field(f.index))
+			if err != nil {
+				return nil, dfWrapf(err, "data row: %d: column %q", r, f.name)
+			}
+			if err := df.appendVal(i, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return df, nil
+}
+
+// ToStructs creates a slice of structs from the dataframe, one per row. The
+// struct fields are mapped to columns as described for FromStructs. Every
+// mapped field must have a corresponding column of the same type though
+// the dataframe may have columns which are not mapped to any field.
+//
+// An NA value will give a nil pointer or a sql.Null... value which is not
+// Valid; it is an error if the field cannot represent an NA value.
+func ToStructs[T any](df *DF) ([]T, error) {
+	fields, err := structFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	colIdx := make([]int, 0, len(fields))
+	for _, f := range fields {
+		i, ok := df.mci.colIdx(f.name)
+		if !ok {
+			return nil, errUnknownColName(f.name)
+		}
+		if err := assertTypeByName(df.mci.info[i].colType, f.colType,
+			f.name); err != nil {
+			return nil, err
+		}
+		colIdx = append(colIdx, i)
+	}
+
+	recs := make([]T, df.RowCount())
+	for row := range recs {
+		rv := reflect.ValueOf(&recs[row]).Elem()
+		for i, f := range fields {
+			cv, isNA := df.valAt(colIdx[i], row)
+			if err := f.set(rv.Field(f.index), cv, isNA); err != nil {
+				return nil, dfWrapf(err, "data row: %d: column %q",
+					row, f.name)
+			}
+		}
+	}
+
+	return recs, nil
+}
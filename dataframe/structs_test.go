@@ -0,0 +1,107 @@
+package dataframe_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+type testRec struct {
+	Name    string       `df:"name"`
+	Count   int32        `df:"count"`
+	Price   *float64     `df:"price"`
+	OK      sql.NullBool `df:"ok"`
+	Note    dataframe.StringVal
+	Ignored int `df:"-"`
+	private int
+}
+
+func TestStructs(t *testing.T) {
+	price := 1.5
+	recs := []testRec{
+		{
+			Name:  "a",
+			Count: 1,
+			Price: &price,
+			OK:    sql.NullBool{Bool: true, Valid: true},
+			Note:  dataframe.StringVal{Val: "hello"},
+		},
+		{
+			Name:    "b",
+			Count:   2,
+			Note:    dataframe.StringVal{IsNA: true},
+			Ignored: 42,
+		},
+	}
+
+	df, err := dataframe.FromStructs(recs)
+	if err != nil {
+		t.Fatal("unexpected error from FromStructs: ", err)
+	}
+	checkColDetails(t, "FromStructs", df, []dataframe.ColInfo{
+		dataframe.MustNewColInfo("name", dataframe.ColTypeString),
+		dataframe.MustNewColInfo("count", dataframe.ColTypeInt),
+		dataframe.MustNewColInfo("price", dataframe.ColTypeFloat),
+		dataframe.MustNewColInfo("ok", dataframe.ColTypeBool),
+		dataframe.MustNewColInfo("Note", dataframe.ColTypeString),
+	})
+	checkDFVals(t, "FromStructs", df, [][]string{
+		{"a", "1", "1.5", "true", "hello"},
+		{"b", "2", "NA", "NA", "NA"},
+	})
+
+	recs2, err := dataframe.ToStructs[testRec](df)
+	if err != nil {
+		t.Fatal("unexpected error from ToStructs: ", err)
+	}
+	recs[1].Ignored = 0
+	if err := testhelper.DiffVals(recs2, recs); err != nil {
+		t.Log("ToStructs")
+		t.Errorf("\t: the round trip has changed the records: %s", err)
+	}
+
+	type badNA struct {
+		Price float64 `df:"price"`
+	}
+	_, err = dataframe.ToStructs[badNA](df)
+	testhelper.CheckExpErrWithID(t, "ToStructs - NA into a float64", err,
+		testhelper.MkExpErr(`data row: 1: column "price":`+
+			` an NA value cannot be held in a field of type float64`))
+
+	type badType struct {
+		Name int `df:"name"`
+	}
+	_, err = dataframe.ToStructs[badType](df)
+	testhelper.CheckExpErrWithID(t, "ToStructs - wrong type", err,
+		testhelper.MkExpErr(`The column named "name" is of type "String"`))
+
+	type overflow struct {
+		Count uint8 `df:"count"`
+	}
+	df2, _ := dataframe.FromStructs([]testRec{{Count: 300}})
+	_, err = dataframe.ToStructs[overflow](df2)
+	testhelper.CheckExpErrWithID(t, "ToStructs - overflow", err,
+		testhelper.MkExpErr(`the value (300) overflows a field of type uint8`))
+
+	type bigUint struct {
+		Count uint64 `df:"count"`
+	}
+	_, err = dataframe.FromStructs([]bigUint{{Count: 1}, {Count: 1 << 63}})
+	testhelper.CheckExpErrWithID(t, "FromStructs - uint64 overflow", err,
+		testhelper.MkExpErr(`data row: 1: column "count":`+
+			` the value (9223372036854775808) overflows an int column`))
+
+	type badField struct {
+		M map[string]int
+	}
+	_, err = dataframe.FromStructs([]badField{})
+	testhelper.CheckExpErrWithID(t, "FromStructs - bad field type", err,
+		testhelper.MkExpErr(`field M has a type (map[string]int)`+
+			` which cannot be held in a column`))
+
+	_, err = dataframe.FromStructs([]int{1, 2})
+	testhelper.CheckExpErrWithID(t, "FromStructs - not a struct", err,
+		testhelper.MkExpErr(`the type (int) is not a struct`))
+}
@@ -0,0 +1,131 @@
+package dataframe
+
+import "sync"
+
+// SyncDF holds a dataframe which can be shared by many goroutines, for
+// instance a reference dataframe queried by the handlers of a server. Any
+// number of goroutines may read the dataframe at the same time but changes
+// are made one at a time while no goroutine is reading it.
+//
+// The dataframe should only be used through the SyncDF once it has been
+// passed to NewSyncDF.
+type SyncDF struct {
+	mu sync.RWMutex
+	df *DF
+}
+
+// NewSyncDF returns a SyncDF holding the dataframe
+func NewSyncDF(df *DF) *SyncDF {
+	df.updateIndexes()
+	return &SyncDF{df: df}
+}
+
+// Read calls f with the dataframe while holding a read lock so that it
+// won't be changed until f returns; other goroutines may read it at the
+// same time. The function must not change the dataframe nor keep it, or
+// any slices of its values, after it returns; a dataframe made from it, by
+// Select or Filter for instance, may be kept. It returns the error
+// returned by f.
+func (s *SyncDF) Read(f func(df *DF) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return f(s.df)
+}
+
+// Write calls f with the dataframe while holding a write lock so that no
+// other goroutine can read or change it until f returns. The function must
+// not keep the dataframe after it returns. Any indexes (see BuildIndex)
+// are brought up to date with the rows added by f. It returns the error
+// returned by f.
+func (s *SyncDF) Write(f func(df *DF) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	defer s.df.updateIndexes()
+	return f(s.df)
+}
+
+// Replace replaces the dataframe with a new one, for instance to load a
+// new version of a reference dataframe, and returns the old one. Any
+// goroutine already reading the old dataframe is allowed to finish before
+// it is replaced.
+func (s *SyncDF) Replace(df *DF) *DF {
+	df.updateIndexes()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.df
+	s.df = df
+	return old
+}
+
+// Snapshot returns a copy of the dataframe which may be used and changed
+// independently of the SyncDF. Any indexes are not copied.
+func (s *SyncDF) Snapshot() *DF {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.copyRows()
+}
+
+// RowCount returns the number of rows in the dataframe
+func (s *SyncDF) RowCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.RowCount()
+}
+
+// Row returns the indexed row of the dataframe, as for the DF Row method
+func (s *SyncDF) Row(i int) *Row {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.Row(i)
+}
+
+// LookupRows returns the indexes of the rows where the named column has
+// the given value, as for the DF LookupRows method
+func (s *SyncDF) LookupRows(col string, value any) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.LookupRows(col, value)
+}
+
+// RowByKey returns the row whose value in the key column matches the
+// value, as for the DF RowByKey method
+func (s *SyncDF) RowByKey(value any) (*Row, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.RowByKey(value)
+}
+
+// Filter returns a new dataframe holding the rows for which keep returns
+// true, as for the DF Filter method
+func (s *SyncDF) Filter(keep RowFilter) *DF {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.Filter(keep)
+}
+
+// Query runs the query against the dataframe and returns the results, as
+// for the DF Query method
+func (s *SyncDF) Query(q string) (*DF, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.Query(q)
+}
+
+// AddRow adds the row to the end of the dataframe, as for the DF AddRow
+// method
+func (s *SyncDF) AddRow(row *Row) error {
+	return s.Write(func(df *DF) error {
+		return df.AddRow(row)
+	})
+}
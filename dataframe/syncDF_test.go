@@ -0,0 +1,127 @@
+package dataframe_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSyncDF(t *testing.T) {
+	df := mkTestDF(t, "sym px\nabc 1.5\ndef 2.5\n", dataframe.HasHeader)
+	if err := df.SetIndex("sym"); err != nil {
+		t.Fatal("BAD TEST - cannot set the index: ", err)
+	}
+	sdf := dataframe.NewSyncDF(df)
+	newRow := df.Row(0)
+
+	const writers, readers = 4, 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers+readers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := sdf.Write(func(df *dataframe.DF) error {
+				df.AddRowFromText([]string{"ghi", "3.5"})
+				return df.AddRow(newRow)
+			})
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := sdf.RowByKey("def"); err != nil {
+				errs <- err
+			}
+			_, err := sdf.Query("SELECT sym FROM df WHERE px > 2")
+			if err != nil {
+				errs <- err
+			}
+			_ = sdf.Read(func(df *dataframe.DF) error {
+				_ = df.Row(df.RowCount() - 1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error("unexpected error: ", err)
+	}
+
+	testhelper.DiffInt(t, "after writes", "rows", sdf.RowCount(), 2+2*writers)
+	rows, err := sdf.LookupRows("sym", "ghi")
+	if err != nil {
+		t.Fatal("cannot look up the rows: ", err)
+	}
+	testhelper.DiffInt(t, "after writes", "indexed rows", len(rows), writers)
+	_, err = sdf.RowByKey("ghi")
+	testhelper.CheckExpErrWithID(t, "repeated key", err,
+		testhelper.MkExpErr("the key is not unique"))
+
+	snap := sdf.Snapshot()
+	old := sdf.Replace(mkTestDF(t, "sym px\nxyz 9\n", dataframe.HasHeader))
+	testhelper.DiffInt(t, "Replace", "old rows", old.RowCount(), 2+2*writers)
+	testhelper.DiffInt(t, "Replace", "snapshot rows",
+		snap.RowCount(), 2+2*writers)
+	checkDFVals(t, "Replace", sdf.Filter(func(*dataframe.Row) bool {
+		return true
+	}), [][]string{{"xyz", "9"}})
+}
+
+func TestSyncDFCompressedViews(t *testing.T) {
+	df := mkTestDF(t, "s b\nx true\nx true\ny false\n", dataframe.HasHeader)
+	if err := df.Compress("s", "b"); err != nil {
+		t.Fatal("BAD TEST - cannot compress the columns: ", err)
+	}
+	sdf := dataframe.NewSyncDF(df)
+
+	// the views must not change the dataframe as they are taken while
+	// holding only a read lock; run with -race to check this
+	const readers = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := sdf.Read(func(df *dataframe.DF) error {
+				if _, err := df.StringColByNameView("s"); err != nil {
+					return err
+				}
+				if _, err := df.BoolColByIdxView(1); err != nil {
+					return err
+				}
+				return nil
+			})
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error("unexpected error: ", err)
+	}
+
+	_ = sdf.Read(func(df *dataframe.DF) error {
+		vals, err := df.StringColByNameView("s")
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		testhelper.DiffInt(t, "view", "length", len(vals), 3)
+		for _, name := range []string{"s", "b"} {
+			compressed, _, _ := df.IsCompressed(name)
+			testhelper.DiffBool(t, "after the views: "+name,
+				"is compressed", compressed, true)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,123 @@
+package dataframe
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// topKHeap holds the indexes of the best rows of a group found so far with
+// the worst of them at the top, so that it can be replaced when a better
+// row is found
+type topKHeap struct {
+	df      *DF
+	keys    []SortKey
+	colIdxs []int
+	rows    []int
+}
+
+// before reports whether row a is better than row b; rows with equal
+// values are ordered by their position in the dataframe
+func (h *topKHeap) before(a, b int) bool {
+	c := cmpRows(h.df, a, h.df, b, h.keys, h.colIdxs)
+	return c < 0 || (c == 0 && a < b)
+}
+
+func (h *topKHeap) Len() int { return len(h.rows) }
+
+func (h *topKHeap) Less(i, j int) bool {
+	return h.before(h.rows[j], h.rows[i])
+}
+
+func (h *topKHeap) Swap(i, j int) {
+	h.rows[i], h.rows[j] = h.rows[j], h.rows[i]
+}
+
+func (h *topKHeap) Push(x any) { h.rows = append(h.rows, x.(int)) }
+
+func (h *topKHeap) Pop() any {
+	last := len(h.rows) - 1
+	r := h.rows[last]
+	h.rows = h.rows[:last]
+	return r
+}
+
+// add adds the row to the heap if it is one of the best k rows seen so far
+func (h *topKHeap) add(r, k int) {
+	if len(h.rows) < k {
+		heap.Push(h, r)
+		return
+	}
+	if h.before(r, h.rows[0]) {
+		h.rows[0] = r
+		heap.Fix(h, 0)
+	}
+}
+
+// TopK returns a new dataframe with the same columns as the grouped
+// dataframe holding the k best rows of each group, for instance the three
+// largest trades for each symbol. The rows are ranked by the values in the
+// byCol column, the largest values being best if desc is true and the
+// smallest otherwise; NA values always rank last and rows with equal
+// values keep their original order. Only the best k rows of each group are
+// kept while the rows are scanned so the dataframe is not sorted.
+//
+// The groups are given in the order in which they were first seen, the
+// rows of each group being in order from best to worst. A group with fewer
+// than k rows has all its rows given. It returns an error if k is less
+// than 1 or if there is no column called byCol.
+func (gdf *GroupedDF) TopK(k int, byCol string, desc bool) (*DF, error) {
+	if k < 1 {
+		return nil, dfErrorf("the number of rows (%d) must be >= 1", k)
+	}
+	df := gdf.df
+	keys := []SortKey{{Col: byCol, Desc: desc}}
+	colIdxs, err := df.sortKeyIdxs(keys)
+	if err != nil {
+		return nil, err
+	}
+	keyIdxs := make([]int, 0, len(gdf.keys))
+	for _, key := range gdf.keys {
+		i, ok := df.mci.colIdx(key)
+		if !ok {
+			return nil, errUnknownColName(key)
+		}
+		keyIdxs = append(keyIdxs, i)
+	}
+
+	groupIdx := map[string]int{}
+	var heaps []*topKHeap
+	kvs := make([]any, len(keyIdxs))
+Rows:
+	for r := 0; r < df.RowCount(); r++ {
+		for i, ki := range keyIdxs {
+			kv, isNA := df.valAt(ki, r)
+			if isNA && !gdf.naGroup {
+				continue Rows
+			}
+			kvs[i] = kv
+		}
+
+		gk := groupKey(kvs)
+		gi, ok := groupIdx[gk]
+		if !ok {
+			gi = len(heaps)
+			groupIdx[gk] = gi
+			heaps = append(heaps,
+				&topKHeap{df: df, keys: keys, colIdxs: colIdxs})
+		}
+		heaps[gi].add(r, k)
+	}
+
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+	for _, h := range heaps {
+		sort.Slice(h.rows, func(i, j int) bool {
+			return h.before(h.rows[i], h.rows[j])
+		})
+		for _, r := range h.rows {
+			rval.copyRowFrom(df, r)
+		}
+	}
+
+	return rval, nil
+}
@@ -0,0 +1,115 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestTopK(t *testing.T) {
+	df := mkTestDF(t, groupTestData, dataframe.DFRRoundTrip)
+	withNA := mkTestDF(t, "sym qty\nabc 10\nabc NA\nabc 30\nabc 10\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeString, dataframe.ColTypeInt))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		df      *dataframe.DF
+		keys    []string
+		k       int
+		byCol   string
+		desc    bool
+		expVals [][]string
+	}{
+		{
+			ID:    testhelper.MkID("largest two, NA keys ignored"),
+			df:    df,
+			keys:  []string{"region"},
+			k:     2,
+			byCol: "qty",
+			desc:  true,
+			expVals: [][]string{
+				{"north", "abc", "40"},
+				{"north", "xyz", "30"},
+				{"south", "NA", "60"},
+				{"south", "abc", "20"},
+				{"NA", "abc", "80"},
+			},
+		},
+		{
+			ID:    testhelper.MkID("smallest one, two keys with NA"),
+			df:    df,
+			keys:  []string{"sym", "region"},
+			k:     1,
+			byCol: "qty",
+			expVals: [][]string{
+				{"north", "abc", "10"},
+				{"south", "abc", "20"},
+				{"north", "xyz", "30"},
+				{"NA", "abc", "50"},
+				{"south", "NA", "60"},
+				{"NA", "abc", "80"},
+			},
+		},
+		{
+			ID:    testhelper.MkID("NA ranks last, ties keep their order"),
+			df:    withNA,
+			keys:  []string{"sym"},
+			k:     3,
+			byCol: "qty",
+			expVals: [][]string{
+				{"abc", "10"},
+				{"abc", "10"},
+				{"abc", "30"},
+			},
+		},
+		{
+			ID:    testhelper.MkID("NA ranks last, descending"),
+			df:    withNA,
+			keys:  []string{"sym"},
+			k:     10,
+			byCol: "qty",
+			desc:  true,
+			expVals: [][]string{
+				{"abc", "30"},
+				{"abc", "10"},
+				{"abc", "10"},
+				{"abc", "NA"},
+			},
+		},
+		{
+			ID:     testhelper.MkID("bad: k"),
+			ExpErr: testhelper.MkExpErr("the number of rows (0) must be >= 1"),
+			df:     df,
+			keys:   []string{"region"},
+			byCol:  "qty",
+		},
+		{
+			ID:     testhelper.MkID("bad: no such column"),
+			ExpErr: testhelper.MkExpErr(`"nonesuch"`),
+			df:     df,
+			keys:   []string{"region"},
+			k:      1,
+			byCol:  "nonesuch",
+		},
+	}
+
+	for _, tc := range testCases {
+		var gdf *dataframe.GroupedDF
+		var err error
+		if len(tc.keys) == 1 {
+			gdf, err = tc.df.GroupBy(tc.keys[0])
+		} else {
+			gdf, err = tc.df.GroupByCols(tc.keys...)
+		}
+		if err != nil {
+			t.Fatal("BAD TEST - cannot group the dataframe: ", err)
+		}
+		tdf, err := gdf.TopK(tc.k, tc.byCol, tc.desc)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			checkDFVals(t, tc.IDStr(), tdf, tc.expVals)
+		}
+	}
+}
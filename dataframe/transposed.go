@@ -0,0 +1,109 @@
+package dataframe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DFRTransposed will cause the DFReader to read data with the columns as
+// rows: each line gives the column name followed by the values in that
+// column, as in:
+//
+//	time  1    2    3
+//	temp  20.1 20.4 20.2
+//
+// The lines are transposed into a dataframe with a column for each line,
+// so the example gives the columns time and temp with three rows. Every
+// line must have the same number of fields. Lines are skipped, split and
+// have comments removed as usual and any columns to be skipped (see
+// DFRSkipCols) are the fields of the input line, field 0 being the name.
+// Any line numbers reported for errors in the values are those of the
+// rows in the transposed data, the names being row 1.
+//
+// The column names are always taken from the first field so it cannot be
+// given with DFRColNames. It also cannot be given with DFRRoundTrip or
+// with DFRHeaderLines.
+func DFRTransposed(dfr *DFReader) error {
+	if err := HasHeader(dfr); err != nil {
+		return err
+	}
+	dfr.transposed = true
+	return nil
+}
+
+// collectTransposed records the columns of the line to be transposed once
+// all the lines have been read and sets skip to true. It does nothing
+// unless the DFReader is reading transposed data. A line with a different
+// number of fields from the first is an error.
+func collectTransposed(dfr *DFReader, state *dfReadState, df *DF) (
+	bool, error,
+) {
+	if !dfr.transposed {
+		return false, nil
+	}
+
+	if len(state.transposed) > 0 &&
+		len(state.cols) != len(state.transposed[0]) {
+		var err error = state.parseError(ErrDimensionMismatch,
+			ErrCodeWrongFieldCount,
+			fmt.Sprintf("this line has %d fields but the first has %d",
+				len(state.cols), len(state.transposed[0])))
+		df.addError(err)
+		if dfr.allowErrors {
+			err = nil
+		}
+		return true, err
+	}
+
+	state.transposed = append(state.transposed, state.cols)
+	return true, nil
+}
+
+// finishTransposed transposes the lines collected by collectTransposed and
+// adds the resulting rows to the dataframe as if they had been read
+// directly, the first row giving the column names.
+func (dfr *DFReader) finishTransposed(state *dfReadState, df *DF) (
+	*DF, error,
+) {
+	tState := newDFReadState(dfr, state.loc.Source()+" (transposed)")
+	if err := dfr.startQuarantine(tState, df); err != nil {
+		return nil, err
+	}
+	dfr.startErrStream(df)
+	operations := []lineHandler{
+		handleLine1,
+		checkColumns,
+		cacheData,
+		handleData,
+	}
+
+	rowCount := 0
+	if len(state.transposed) > 0 {
+		rowCount = len(state.transposed[0])
+	}
+
+Loop:
+	for r := 0; r < rowCount; r++ {
+		tState.quarantineLine(df, tState.line, tState.loc.Idx())
+		tState.loc.Incr()
+		tState.cols = make([]string, 0, len(state.transposed))
+		for _, line := range state.transposed {
+			tState.cols = append(tState.cols, line[r])
+		}
+		tState.line = strings.Join(tState.cols, " ")
+
+		for _, op := range operations {
+			skip, err := op(dfr, tState, df)
+			if err != nil {
+				return nil, err
+			}
+			if skip {
+				continue Loop
+			}
+		}
+	}
+
+	tState.quarantineLine(df, tState.line, tState.loc.Idx())
+
+	return dfr.finishRead(tState, df)
+}
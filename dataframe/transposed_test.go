@@ -0,0 +1,110 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDFRTransposed(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts    []dataframe.DFReaderOpt
+		data    string
+		expCols []dataframe.ColInfo
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("good"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.SkipLines(1), dataframe.SkipBlankLines,
+			},
+			data: "# instrument export\n" +
+				"time 12 13 14\n" +
+				"\n" +
+				"temp 20.1 20.4 20.2\n" +
+				"ok true false true\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("time", dataframe.ColTypeInt),
+				dataframe.MustNewColInfo("temp", dataframe.ColTypeFloat),
+				dataframe.MustNewColInfo("ok", dataframe.ColTypeBool),
+			},
+			expVals: [][]string{
+				{"12", "20.1", "true"},
+				{"13", "20.4", "false"},
+				{"14", "20.2", "true"},
+			},
+		},
+		{
+			ID: testhelper.MkID("skip cols, given types"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRSkipCols(1),
+				dataframe.DFRColTypes(dataframe.ColTypeString,
+					dataframe.ColTypeInt),
+			},
+			data: "a x y\n" +
+				"b 12 13\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+			},
+			expVals: [][]string{{"y", "13"}},
+		},
+		{
+			ID: testhelper.MkID("names only"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColTypes(dataframe.ColTypeString,
+					dataframe.ColTypeInt),
+			},
+			data: "a\nb\n",
+			expCols: []dataframe.ColInfo{
+				dataframe.MustNewColInfo("a", dataframe.ColTypeString),
+				dataframe.MustNewColInfo("b", dataframe.ColTypeInt),
+			},
+		},
+		{
+			ID: testhelper.MkID("lines of different lengths"),
+			ExpErr: testhelper.MkExpErr("test data:2",
+				"this line has 2 fields but the first has 3"),
+			data: "a 12 13\nb 14\n",
+		},
+		{
+			ID:     testhelper.MkID("bad value"),
+			ExpErr: testhelper.MkExpErr("test data (transposed):3"),
+			opts: []dataframe.DFReaderOpt{
+				dataframe.DFRColTypes(dataframe.ColTypeInt),
+			},
+			data: "a 12 x\n",
+		},
+		{
+			ID: testhelper.MkID("with column names"),
+			ExpErr: testhelper.MkExpErr("you cannot give column names" +
+				" and take names from a header"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRColNames("a")},
+		},
+		{
+			ID: testhelper.MkID("round-trip"),
+			ExpErr: testhelper.MkExpErr(
+				"transposed data cannot be read in round-trip mode"),
+			opts: []dataframe.DFReaderOpt{dataframe.DFRRoundTrip},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append([]dataframe.DFReaderOpt{}, tc.opts...)
+		opts = append(opts, dataframe.DFRTransposed)
+		dfr, err := dataframe.NewDFReader(opts...)
+		if err == nil {
+			var df *dataframe.DF
+			df, err = dfr.Read(strings.NewReader(tc.data), "test data")
+			if err == nil {
+				checkColDetails(t, tc.IDStr(), df, tc.expCols)
+				checkDFVals(t, tc.IDStr(), df, tc.expVals)
+			}
+		}
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
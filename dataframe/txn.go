@@ -0,0 +1,263 @@
+package dataframe
+
+// copyRows returns a copy of the dataframe with the same columns, metadata
+// and rows. Any indexes are not copied.
+func (df *DF) copyRows() *DF {
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+	for i := 0; i < df.RowCount(); i++ {
+		rval.copyRowFrom(df, i)
+	}
+	return rval
+}
+
+// Txn is a batch of changes to a dataframe, made by Begin, which are
+// either all applied, by Commit, or all discarded, by Rollback. This is
+// useful where a change needs several steps any of which might fail. The
+// changes are made to a copy of the dataframe which replaces the
+// dataframe's values when the changes are committed.
+//
+// The dataframe must not be changed while the transaction is in progress.
+type Txn struct {
+	df   *DF
+	work *DF
+	done bool
+
+	// decompressed records the compressed columns that had to be
+	// decompressed to change their values; they are compressed again when
+	// the changes are committed
+	decompressed map[string]bool
+}
+
+// Begin starts a batch of changes to the dataframe. The changes are made
+// through the returned Txn and are not seen in the dataframe until Commit
+// is called. Beginning the changes takes a copy of the dataframe.
+func (df *DF) Begin() *Txn {
+	return &Txn{
+		df:           df,
+		work:         df.copyRows(),
+		decompressed: map[string]bool{},
+	}
+}
+
+// check returns an error if the transaction has been committed or rolled
+// back
+func (t *Txn) check() error {
+	if t.done {
+		return dfCodeErrorf(ErrCodeTxnFinished,
+			"the transaction has already been finished")
+	}
+	return nil
+}
+
+// DF returns the dataframe with the changes made so far. It must not be
+// changed directly and must not be used after the transaction has been
+// finished.
+func (t *Txn) DF() *DF {
+	return t.work
+}
+
+// AddRow adds the row to the end of the dataframe, as for the DF AddRow
+// method
+func (t *Txn) AddRow(row *Row) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	return t.work.AddRow(row)
+}
+
+// AddCol adds the column to the end of the dataframe, as for the DF AddCol
+// method
+func (t *Txn) AddCol(c Column) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	return t.work.AddCol(c)
+}
+
+// SetVal sets the value of the named column in the indexed row. The value
+// may be given either as one of the Val types or as the corresponding Go
+// type; a nil value sets it to NA. It returns an error if there is no such
+// column or row or if the value cannot be converted to the column type.
+func (t *Txn) SetVal(col string, row int, value any) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	df := t.work
+	i, ok := df.mci.colIdx(col)
+	if !ok {
+		return errUnknownColName(col)
+	}
+	if row < 0 || row >= df.RowCount() {
+		return dfKindErrorf(ErrNoSuchRow,
+			"There is no row %d (valid range: 0-%d)", row, df.RowCount()-1)
+	}
+
+	ci := df.mci.info[i]
+	if compressed, _, _ := df.IsCompressed(ci.name); compressed {
+		if err := df.Decompress(ci.name); err != nil {
+			return err
+		}
+		t.decompressed[ci.name] = true
+	}
+	vi := df.mci.valIdx[i]
+
+	switch ci.colType {
+	case ColTypeBool:
+		v, err := boolValOf(value)
+		if err != nil {
+			return err
+		}
+		df.boolCols[vi][row] = v
+	case ColTypeInt:
+		v, err := intValOf(value)
+		if err != nil {
+			return err
+		}
+		df.intCols[vi][row] = v
+	case ColTypeFloat:
+		v, err := floatValOf(value)
+		if err != nil {
+			return err
+		}
+		df.floatCols[vi][row] = v
+	case ColTypeString:
+		v, err := stringValOf(value)
+		if err != nil {
+			return err
+		}
+		df.stringCols[vi][row] = v
+	}
+	return nil
+}
+
+// RemoveRows removes the indexed rows from the dataframe. The indexes are
+// those of the rows before any are removed. It returns an error if any
+// row does not exist, in which case no rows are removed.
+func (t *Txn) RemoveRows(rows ...int) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	df := t.work
+	remove := make(map[int]bool, len(rows))
+	for _, r := range rows {
+		if r < 0 || r >= df.RowCount() {
+			return dfKindErrorf(ErrNoSuchRow,
+				"There is no row %d (valid range: 0-%d)", r, df.RowCount()-1)
+		}
+		remove[r] = true
+	}
+
+	rval := df.Clone()
+	rval.maxErrors = df.maxErrors
+	for r := 0; r < df.RowCount(); r++ {
+		if !remove[r] {
+			rval.copyRowFrom(df, r)
+		}
+	}
+	t.work = rval
+	return nil
+}
+
+// RemoveCol removes the named column from the dataframe. It returns an
+// error if there is no such column.
+func (t *Txn) RemoveCol(name string) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	i, ok := t.work.mci.colIdx(name)
+	if !ok {
+		return errUnknownColName(name)
+	}
+
+	names := make([]string, 0, len(t.work.mci.info)-1)
+	var compressed []string
+	for c, ci := range t.work.mci.info {
+		if c == i {
+			continue
+		}
+		names = append(names, ci.name)
+		if ok, _, _ := t.work.IsCompressed(ci.name); ok {
+			compressed = append(compressed, ci.name)
+		}
+	}
+	rval, err := t.work.Select(names...)
+	if err != nil {
+		return err
+	}
+	if err := rval.Compress(compressed...); err != nil {
+		return err
+	}
+	t.work = rval
+	return nil
+}
+
+// Commit applies all the changes to the dataframe. Any indexes and column
+// summaries on columns which still exist are rebuilt, as is the key set by
+// SetIndex. It returns an error if the values of the key column are no
+// longer unique, in which case the dataframe is unchanged and the
+// transaction may be rolled back.
+func (t *Txn) Commit() error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	df, work := t.df, t.work
+
+	for name := range t.decompressed {
+		if _, ok := work.mci.colIdx(name); ok {
+			if err := work.Compress(name); err != nil {
+				return err
+			}
+		}
+	}
+	work.indexes, work.hasKeyCol = nil, false
+	for i := range df.indexes {
+		name := df.mci.info[i].name
+		if _, ok := work.mci.colIdx(name); !ok {
+			continue
+		}
+		var err error
+		if df.hasKeyCol && i == df.keyCol {
+			err = work.SetIndex(name)
+		} else {
+			err = work.BuildIndex(name)
+		}
+		if err != nil {
+			return dfWrapf(err, "cannot commit the changes")
+		}
+	}
+	work.summaries = nil
+	for i := range df.summaries {
+		name := df.mci.info[i].name
+		if _, ok := work.mci.colIdx(name); ok {
+			if err := work.TrackColSummaries(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	df.mci = work.mci
+	df.floatCols = work.floatCols
+	df.intCols = work.intCols
+	df.boolCols = work.boolCols
+	df.stringCols = work.stringCols
+	df.rleBoolCols = work.rleBoolCols
+	df.rleStringCols = work.rleStringCols
+	df.indexes = work.indexes
+	df.summaries = work.summaries
+	df.keyCol = work.keyCol
+	df.hasKeyCol = work.hasKeyCol
+	df.meta = work.meta
+
+	t.done = true
+	t.work = nil
+	return nil
+}
+
+// Rollback discards all the changes, leaving the dataframe as it was when
+// the transaction began. It does nothing if the transaction has already
+// been finished so it may be deferred after Begin.
+func (t *Txn) Rollback() {
+	t.done = true
+	t.work = nil
+}
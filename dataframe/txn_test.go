@@ -0,0 +1,129 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestTxn(t *testing.T) {
+	mk := func() *dataframe.DF {
+		df := mkTestDF(t, "sym px side\nabc 1.5 b\ndef 2.5 s\nghi 3.5 b\n",
+			dataframe.HasHeader)
+		if err := df.Compress("side"); err != nil {
+			t.Fatal("BAD TEST - cannot compress the column: ", err)
+		}
+		if err := df.SetIndex("sym"); err != nil {
+			t.Fatal("BAD TEST - cannot set the index: ", err)
+		}
+		return df
+	}
+	orig := [][]string{
+		{"abc", "1.5", "b"},
+		{"def", "2.5", "s"},
+		{"ghi", "3.5", "b"},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		changes func(tx *dataframe.Txn) error
+		expVals [][]string
+	}{
+		{
+			ID: testhelper.MkID("several changes"),
+			changes: func(tx *dataframe.Txn) error {
+				if err := tx.SetVal("side", 0, "s"); err != nil {
+					return err
+				}
+				if err := tx.SetVal("px", 1, nil); err != nil {
+					return err
+				}
+				if err := tx.AddRow(tx.DF().Row(0)); err != nil {
+					return err
+				}
+				if err := tx.SetVal("sym", 3, "jkl"); err != nil {
+					return err
+				}
+				if err := tx.RemoveRows(2, 0); err != nil {
+					return err
+				}
+				return tx.AddCol(mkIntCol("qty", 10, 20))
+			},
+			expVals: [][]string{
+				{"def", "NA", "s", "10"},
+				{"jkl", "1.5", "s", "20"},
+			},
+		},
+		{
+			ID: testhelper.MkID("remove a column"),
+			changes: func(tx *dataframe.Txn) error {
+				return tx.RemoveCol("px")
+			},
+			expVals: [][]string{
+				{"abc", "b"},
+				{"def", "s"},
+				{"ghi", "b"},
+			},
+		},
+		{
+			ID: testhelper.MkID("bad: fails part way"),
+			ExpErr: testhelper.MkExpErr(
+				"cannot convert a value of type string into an IntVal"),
+			changes: func(tx *dataframe.Txn) error {
+				if err := tx.RemoveRows(0); err != nil {
+					return err
+				}
+				if err := tx.AddCol(mkIntCol("qty", 10, 20)); err != nil {
+					return err
+				}
+				return tx.SetVal("qty", 0, "x")
+			},
+			expVals: orig,
+		},
+		{
+			ID:     testhelper.MkID("bad: no such row"),
+			ExpErr: testhelper.MkExpErr("There is no row 3"),
+			changes: func(tx *dataframe.Txn) error {
+				return tx.RemoveRows(1, 3)
+			},
+			expVals: orig,
+		},
+		{
+			ID:     testhelper.MkID("bad: key no longer unique"),
+			ExpErr: testhelper.MkExpErr("cannot commit the changes", "unique"),
+			changes: func(tx *dataframe.Txn) error {
+				return tx.SetVal("sym", 0, "def")
+			},
+			expVals: orig,
+		},
+	}
+
+	for _, tc := range testCases {
+		df := mk()
+		tx := df.Begin()
+		err := tc.changes(tx)
+		if err == nil {
+			err = tx.Commit()
+		}
+		tx.Rollback()
+		testhelper.CheckExpErr(t, err, tc)
+		checkDFVals(t, tc.IDStr(), df, tc.expVals)
+
+		compressed, _, _ := df.IsCompressed("side")
+		testhelper.DiffBool(t, tc.IDStr(), "compressed", compressed, true)
+		if _, err := df.RowByKey(tc.expVals[0][0]); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot find the row by its key: %s", err)
+		}
+	}
+
+	tx := mk().Begin()
+	if err := tx.Commit(); err != nil {
+		t.Fatal("cannot commit an empty transaction: ", err)
+	}
+	err := tx.AddRow(nil)
+	testhelper.CheckExpErrWithID(t, "finished", err,
+		testhelper.MkExpErr("the transaction has already been finished"))
+}
@@ -9,14 +9,46 @@ type BoolVal struct {
 	IsNA bool
 }
 
+// String returns the value formatted as a string or "NA" if the value is
+// not available
+func (v BoolVal) String() string {
+	if v.IsNA {
+		return "NA"
+	}
+	return strconv.FormatBool(v.Val)
+}
+
 // SetVal will parse the string and set the value accordingly. If the
 // parsing fails IsNA will be set to true and a non-nil error will be
 // returned, otherwise the error will be nil.
 func (v *BoolVal) SetVal(s string) error {
+	return v.SetValWithFormat(s, BoolFormat{})
+}
+
+// SetValWithFormat is like SetVal but accepts just those values allowed by
+// the BoolFormat
+func (v *BoolVal) SetValWithFormat(s string, bf BoolFormat) error {
 	var err error
-	v.Val, err = strconv.ParseBool(s)
+	v.Val, err = bf.ParseBool(s)
 	if err != nil {
 		v.IsNA = true
 	}
 	return err
 }
+
+// boolValOf converts the value into a BoolVal. The value may be a BoolVal,
+// a bool or nil (which gives an NA value). Any other type will result in an
+// error.
+func boolValOf(v any) (BoolVal, error) {
+	switch v := v.(type) {
+	case BoolVal:
+		return v, nil
+	case bool:
+		return BoolVal{Val: v}, nil
+	case nil:
+		return BoolVal{IsNA: true}, nil
+	}
+	return BoolVal{IsNA: true},
+		dfKindErrorf(ErrTypeMismatch,
+			"cannot convert a value of type %T into a BoolVal", v)
+}
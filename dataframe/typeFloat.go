@@ -9,14 +9,52 @@ type FloatVal struct {
 	IsNA bool
 }
 
+// String returns the value formatted as a string or "NA" if the value is
+// not available
+func (v FloatVal) String() string {
+	if v.IsNA {
+		return "NA"
+	}
+	return strconv.FormatFloat(v.Val, 'g', -1, 64)
+}
+
 // SetVal will parse the string and set the value accordingly. If the
 // parsing fails IsNA will be set to true and a non-nil error will be
 // returned, otherwise the error will be nil.
 func (v *FloatVal) SetVal(s string) error {
+	return v.SetValWithFormat(s, NumFormat{})
+}
+
+// SetValWithFormat is like SetVal but accepts just those forms of float
+// allowed by the NumFormat
+func (v *FloatVal) SetValWithFormat(s string, nf NumFormat) error {
 	var err error
-	v.Val, err = strconv.ParseFloat(s, 64)
+	v.Val, err = nf.ParseFloat(s)
 	if err != nil {
 		v.IsNA = true
 	}
 	return err
 }
+
+// floatValOf converts the value into a FloatVal. The value may be a
+// FloatVal, a float64, an IntVal, an int64, an int or nil (which gives an
+// NA value). Any other type will result in an error.
+func floatValOf(v any) (FloatVal, error) {
+	switch v := v.(type) {
+	case FloatVal:
+		return v, nil
+	case float64:
+		return FloatVal{Val: v}, nil
+	case IntVal:
+		return FloatVal{Val: float64(v.Val), IsNA: v.IsNA}, nil
+	case int64:
+		return FloatVal{Val: float64(v)}, nil
+	case int:
+		return FloatVal{Val: float64(v)}, nil
+	case nil:
+		return FloatVal{IsNA: true}, nil
+	}
+	return FloatVal{IsNA: true},
+		dfKindErrorf(ErrTypeMismatch,
+			"cannot convert a value of type %T into a FloatVal", v)
+}
@@ -9,14 +9,48 @@ type IntVal struct {
 	IsNA bool
 }
 
+// String returns the value formatted as a string or "NA" if the value is
+// not available
+func (v IntVal) String() string {
+	if v.IsNA {
+		return "NA"
+	}
+	return strconv.FormatInt(v.Val, 10)
+}
+
 // SetVal will parse the string and set the value accordingly. If the
 // parsing fails IsNA will be set to true and a non-nil error will be
 // returned, otherwise the error will be nil.
 func (v *IntVal) SetVal(s string) error {
+	return v.SetValWithFormat(s, NumFormat{})
+}
+
+// SetValWithFormat is like SetVal but accepts just those forms of int
+// allowed by the NumFormat
+func (v *IntVal) SetValWithFormat(s string, nf NumFormat) error {
 	var err error
-	v.Val, err = strconv.ParseInt(s, 0, 64)
+	v.Val, err = nf.ParseInt(s)
 	if err != nil {
 		v.IsNA = true
 	}
 	return err
 }
+
+// intValOf converts the value into an IntVal. The value may be an IntVal,
+// an int, an int64 or nil (which gives an NA value). Any other type will
+// result in an error.
+func intValOf(v any) (IntVal, error) {
+	switch v := v.(type) {
+	case IntVal:
+		return v, nil
+	case int64:
+		return IntVal{Val: v}, nil
+	case int:
+		return IntVal{Val: int64(v)}, nil
+	case nil:
+		return IntVal{IsNA: true}, nil
+	}
+	return IntVal{IsNA: true},
+		dfKindErrorf(ErrTypeMismatch,
+			"cannot convert a value of type %T into an IntVal", v)
+}
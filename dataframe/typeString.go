@@ -6,3 +6,28 @@ type StringVal struct {
 	Val  string
 	IsNA bool
 }
+
+// String returns the value or "NA" if the value is not available
+func (v StringVal) String() string {
+	if v.IsNA {
+		return "NA"
+	}
+	return v.Val
+}
+
+// stringValOf converts the value into a StringVal. The value may be a
+// StringVal, a string or nil (which gives an NA value). Any other type will
+// result in an error.
+func stringValOf(v any) (StringVal, error) {
+	switch v := v.(type) {
+	case StringVal:
+		return v, nil
+	case string:
+		return StringVal{Val: v}, nil
+	case nil:
+		return StringVal{IsNA: true}, nil
+	}
+	return StringVal{IsNA: true},
+		dfKindErrorf(ErrTypeMismatch,
+			"cannot convert a value of type %T into a StringVal", v)
+}
@@ -0,0 +1,87 @@
+package dataframe
+
+import (
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// DefaultPrintHeadTail is the number of rows at the start and end of the
+// dataframe written by Print unless DFWHeadTail is given
+const DefaultPrintHeadTail = 5
+
+// alignedGap is the number of spaces between the columns written by
+// WriteAligned
+const alignedGap = 2
+
+// skippedRowsMark is written in every column of the separator row showing
+// where rows have been left out
+const skippedRowsMark = "..."
+
+// alignedEscaper replaces the characters which would break the alignment
+// of the columns
+var alignedEscaper = strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// WriteAligned writes the dataframe to the io.Writer with the values in
+// each column aligned, for display on a terminal. The columns are
+// separated by spaces and so any separator given by DFWSeparator is
+// ignored; tabs and newlines in the values are written as \t and \n. A
+// header line of column names is written unless DFWNoHeader has been
+// given. If DFWHeadTail has been given only the first and last rows are
+// written.
+func (dfw *DFWriter) WriteAligned(w io.Writer, df *DF) error {
+	lf := dfw.lineFormat("\t", DefaultNAString)
+	lf.sep = "\t"
+	lf.quote = alignedEscaper.Replace
+
+	tw := tabwriter.NewWriter(w, 0, 0, alignedGap, ' ', 0)
+	rw, err := dfw.newRowWriter(tw, df.mci, lf)
+	if err != nil {
+		return err
+	}
+
+	rowCount := df.RowCount()
+	for r := 0; r < rowCount; r++ {
+		if dfw.headTail > 0 && rowCount > 2*dfw.headTail &&
+			r == dfw.headTail {
+			for c := range rw.vals {
+				rw.vals[c] = skippedRowsMark
+			}
+			if err := rw.writeVals(); err != nil {
+				return dfWrapf(err, "cannot write the separator row")
+			}
+			r = rowCount - dfw.headTail
+		}
+		if err := rw.writeDFRow(df, r); err != nil {
+			return err
+		}
+	}
+
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+	if err := tw.Flush(); err != nil {
+		return dfWrapf(err, "cannot flush the aligned columns")
+	}
+	return nil
+}
+
+// Fprint writes the dataframe to the io.Writer with the columns aligned.
+// Only the first and last DefaultPrintHeadTail rows are written unless the
+// options include DFWHeadTail. The options are applied to a new DFWriter,
+// see DFWriter.WriteAligned for details.
+func (df *DF) Fprint(w io.Writer, opts ...DFWriterOpt) error {
+	dfw, err := NewDFWriter(
+		append([]DFWriterOpt{DFWHeadTail(DefaultPrintHeadTail)}, opts...)...)
+	if err != nil {
+		return err
+	}
+	return dfw.WriteAligned(w, df)
+}
+
+// Print writes the dataframe to the standard output with the columns
+// aligned, see Fprint for details.
+func (df *DF) Print(opts ...DFWriterOpt) error {
+	return df.Fprint(os.Stdout, opts...)
+}
@@ -0,0 +1,77 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFprint(t *testing.T) {
+	df := mkTestDF(t,
+		"id|name|x\n"+
+			"1|a|1.5\n"+
+			"2|long name|NA\n"+
+			"3|tab\there|3\n"+
+			"4|d|4\n"+
+			"5|e|5\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.SplitPattern(`\|`),
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeString,
+			dataframe.ColTypeFloat))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts []dataframe.DFWriterOpt
+		exp  string
+	}{
+		{
+			ID: testhelper.MkID("default - all rows fit"),
+			exp: "id  name       x\n" +
+				"1   a          1.5\n" +
+				"2   long name  NA\n" +
+				`3   tab\there  3` + "\n" +
+				"4   d          4\n" +
+				"5   e          5\n",
+		},
+		{
+			ID: testhelper.MkID("head and tail, no header"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWHeadTail(1),
+				dataframe.DFWNoHeader,
+				dataframe.DFWNAString("-"),
+			},
+			exp: "1    a    1.5\n" +
+				"...  ...  ...\n" +
+				"5    e    5\n",
+		},
+		{
+			ID: testhelper.MkID("head and tail covers all rows"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWHeadTail(3),
+				dataframe.DFWNoHeader,
+			},
+			exp: "1  a          1.5\n" +
+				"2  long name  NA\n" +
+				`3  tab\there  3` + "\n" +
+				"4  d          4\n" +
+				"5  e          5\n",
+		},
+		{
+			ID: testhelper.MkID("bad head and tail"),
+			ExpErr: testhelper.MkExpErr(
+				"the number of head and tail rows (-1) must not be negative"),
+			opts: []dataframe.DFWriterOpt{dataframe.DFWHeadTail(-1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		var sb strings.Builder
+		err := df.Fprint(&sb, tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+		}
+	}
+}
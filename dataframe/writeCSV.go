@@ -0,0 +1,76 @@
+package dataframe
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// csvQuote returns a function which will quote a field as needed for it to
+// be read back correctly as CSV. Fields are quoted if they contain the
+// separator, a double quote, a carriage return or a newline, or if they
+// start with a space or tab. If quoteAll is true every field is quoted.
+func csvQuote(sep string, quoteAll bool) func(string) string {
+	return func(s string) string {
+		if !quoteAll &&
+			!strings.Contains(s, sep) &&
+			!strings.ContainsAny(s, "\"\r\n") &&
+			!strings.HasPrefix(s, " ") &&
+			!strings.HasPrefix(s, "\t") {
+			return s
+		}
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+}
+
+// WriteCSV writes the dataframe to the io.Writer as CSV (RFC 4180). The
+// separator defaults to a comma and must be a single character other than
+// a double quote, carriage return or newline. NA values are written as an
+// empty field unless DFWNAString has been given. A header line of column
+// names is written unless DFWNoHeader has been given. Lines end with a
+// newline unless DFWCRLF has been given. Only those fields which need it are
+// quoted unless DFWQuoteAll has been given.
+func (dfw *DFWriter) WriteCSV(w io.Writer, df *DF) error {
+	lf, err := dfw.csvLineFormat()
+	if err != nil {
+		return err
+	}
+	return dfw.write(w, df, lf)
+}
+
+// csvLineFormat returns the lineFormat for the CSV format
+func (dfw DFWriter) csvLineFormat() (lineFormat, error) {
+	lf := dfw.lineFormat(",", "")
+	if utf8.RuneCountInString(lf.sep) != 1 ||
+		strings.ContainsAny(lf.sep, "\"\r\n") {
+		return lf, dfErrorf("bad CSV separator: %q", lf.sep)
+	}
+	lf.quote = csvQuote(lf.sep, dfw.quoteAll)
+	return lf, nil
+}
+
+// NewCSVRowWriter returns a RowWriter which will write rows having the
+// given columns to the io.Writer in the same format as WriteCSV. The header
+// line, if any, is written immediately.
+func (dfw *DFWriter) NewCSVRowWriter(w io.Writer, cis ...ColInfo,
+) (*RowWriter, error) {
+	mci, err := NewMultiColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	lf, err := dfw.csvLineFormat()
+	if err != nil {
+		return nil, err
+	}
+	return dfw.newRowWriter(w, *mci, lf)
+}
+
+// WriteCSV writes the dataframe to the io.Writer as CSV. The options are
+// applied to a new DFWriter, see DFWriter.WriteCSV for details.
+func (df *DF) WriteCSV(w io.Writer, opts ...DFWriterOpt) error {
+	dfw, err := NewDFWriter(opts...)
+	if err != nil {
+		return err
+	}
+	return dfw.WriteCSV(w, df)
+}
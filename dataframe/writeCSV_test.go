@@ -0,0 +1,76 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestWriteCSV(t *testing.T) {
+	df := mkTestDF(t,
+		"i|s\n"+
+			"1|plain\n"+
+			"2|a,b\n"+
+			`3|say "hi"`+"\n"+
+			"NA| lead\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.SplitPattern(`\|`),
+		dataframe.DFRColTypes(dataframe.ColTypeInt, dataframe.ColTypeString))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts []dataframe.DFWriterOpt
+		exp  string
+	}{
+		{
+			ID: testhelper.MkID("default"),
+			exp: "i,s\n" +
+				"1,plain\n" +
+				`2,"a,b"` + "\n" +
+				`3,"say ""hi"""` + "\n" +
+				`," lead"` + "\n",
+		},
+		{
+			ID: testhelper.MkID("quote all, CRLF, no header, NA string"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWQuoteAll,
+				dataframe.DFWCRLF,
+				dataframe.DFWNoHeader,
+				dataframe.DFWNAString("NULL"),
+			},
+			exp: `"1","plain"` + "\r\n" +
+				`"2","a,b"` + "\r\n" +
+				`"3","say ""hi"""` + "\r\n" +
+				`"NULL"," lead"` + "\r\n",
+		},
+		{
+			ID: testhelper.MkID("semicolon separated"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWSeparator(";"),
+			},
+			exp: "i;s\n" +
+				"1;plain\n" +
+				"2;a,b\n" +
+				`3;"say ""hi"""` + "\n" +
+				`;" lead"` + "\n",
+		},
+		{
+			ID:     testhelper.MkID("bad separator"),
+			ExpErr: testhelper.MkExpErr(`bad CSV separator: "::"`),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWSeparator("::"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var sb strings.Builder
+		err := df.WriteCSV(&sb, tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package dataframe
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Orientation determines the layout of the JSON written by WriteJSON
+type Orientation int
+
+const (
+	// OrientRecords writes an array with one object per row, mapping the
+	// column names to the values:
+	//
+	//	[{"a":1,"b":"x"},{"a":2,"b":"y"}]
+	OrientRecords Orientation = iota
+	// OrientColumns writes an object mapping each column name to an array
+	// of the values in that column:
+	//
+	//	{"a":[1,2],"b":["x","y"]}
+	OrientColumns
+	// OrientSplit writes an object with the column names and the rows
+	// given separately:
+	//
+	//	{"columns":["a","b"],"data":[[1,"x"],[2,"y"]]}
+	OrientSplit
+	// OrientLines writes one object per line, mapping the column names to
+	// the values as for OrientRecords but without the enclosing array
+	// (the JSON Lines format):
+	//
+	//	{"a":1,"b":"x"}
+	//	{"a":2,"b":"y"}
+	OrientLines
+)
+
+// String returns the name of the Orientation
+func (o Orientation) String() string {
+	switch o {
+	case OrientRecords:
+		return "records"
+	case OrientColumns:
+		return "columns"
+	case OrientSplit:
+		return "split"
+	case OrientLines:
+		return "lines"
+	}
+	return "Orientation(" + strconv.Itoa(int(o)) + ")"
+}
+
+// jsonString returns the JSON encoding of the string
+func jsonString(s string) []byte {
+	b, _ := json.Marshal(s) // a string can always be marshalled
+	return b
+}
+
+// appendJSONVal appends the JSON encoding of the value at the given row of
+// the given column to the buffer. NA values, and floats which have no JSON
+// representation (NaN and the infinities), are written as null.
+func (df *DF) appendJSONVal(buf []byte, colIdx, rowIdx int) []byte {
+	vi := df.mci.valIdx[colIdx]
+
+	switch ct := df.mci.info[colIdx].colType; ct {
+	case ColTypeBool:
+		v := df.boolAt(vi, rowIdx)
+		if !v.IsNA {
+			return strconv.AppendBool(buf, v.Val)
+		}
+	case ColTypeInt:
+		v := df.intCols[vi][rowIdx]
+		if !v.IsNA {
+			return strconv.AppendInt(buf, v.Val, 10)
+		}
+	case ColTypeFloat:
+		v := df.floatCols[vi][rowIdx]
+		if !v.IsNA && !math.IsNaN(v.Val) && !math.IsInf(v.Val, 0) {
+			return strconv.AppendFloat(buf, v.Val, 'g', -1, 64)
+		}
+	case ColTypeString:
+		v := df.stringAt(vi, rowIdx)
+		if !v.IsNA {
+			return append(buf, jsonString(v.Val)...)
+		}
+	default:
+		panic(dfErrorf("Unexpected column type: %q", ct))
+	}
+	return append(buf, "null"...)
+}
+
+// WriteJSON writes the dataframe to the io.Writer as JSON laid out
+// according to the orientation. The output is written as it is generated
+// rather than being built in memory first.
+func (df *DF) WriteJSON(w io.Writer, orient Orientation) error {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 64)
+
+	var err error
+	switch orient {
+	case OrientRecords:
+		err = df.writeJSONRecords(bw, buf, false)
+	case OrientLines:
+		err = df.writeJSONRecords(bw, buf, true)
+	case OrientColumns:
+		err = df.writeJSONColumns(bw, buf)
+	case OrientSplit:
+		err = df.writeJSONSplit(bw, buf)
+	default:
+		return dfErrorf("unknown JSON orientation: %s", orient)
+	}
+
+	if err != nil {
+		return dfWrapf(err, "cannot write the dataframe as JSON (%s)", orient)
+	}
+	if err := bw.Flush(); err != nil {
+		return dfWrapf(err, "cannot write the dataframe as JSON (%s)", orient)
+	}
+	return nil
+}
+
+// writeJSONRecords writes the dataframe as an array of objects, one per
+// row, or, if lines is set, as a sequence of objects, one per line
+func (df *DF) writeJSONRecords(bw *bufio.Writer, buf []byte, lines bool,
+) error {
+	names := make([][]byte, 0, len(df.mci.info))
+	for _, ci := range df.mci.info {
+		names = append(names, jsonString(ci.name))
+	}
+
+	buf = buf[:0]
+	if !lines {
+		buf = append(buf, '[')
+	}
+	for r := 0; r < df.RowCount(); r++ {
+		if r > 0 && !lines {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, '{')
+		for c, name := range names {
+			if c > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, name...)
+			buf = append(buf, ':')
+			buf = df.appendJSONVal(buf, c, r)
+		}
+		buf = append(buf, '}')
+		if lines {
+			buf = append(buf, '\n')
+		}
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+	if lines {
+		return nil
+	}
+	buf = append(buf, ']', '\n')
+	_, err := bw.Write(buf)
+	return err
+}
+
+// writeJSONColumns writes the dataframe as an object mapping the column
+// names to arrays of values
+func (df *DF) writeJSONColumns(bw *bufio.Writer, buf []byte) error {
+	buf = append(buf[:0], '{')
+	for c, ci := range df.mci.info {
+		if c > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, jsonString(ci.name)...)
+		buf = append(buf, ':', '[')
+		for r := 0; r < df.RowCount(); r++ {
+			if r > 0 {
+				buf = append(buf, ',')
+			}
+			buf = df.appendJSONVal(buf, c, r)
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+			buf = buf[:0]
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, '}', '\n')
+	_, err := bw.Write(buf)
+	return err
+}
+
+// writeJSONSplit writes the dataframe as an object holding an array of the
+// column names and an array of rows, each of which is an array of values
+func (df *DF) writeJSONSplit(bw *bufio.Writer, buf []byte) error {
+	buf = append(buf[:0], `{"columns":[`...)
+	for c, ci := range df.mci.info {
+		if c > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, jsonString(ci.name)...)
+	}
+	buf = append(buf, `],"data":[`...)
+	for r := 0; r < df.RowCount(); r++ {
+		if r > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, '[')
+		for c := range df.mci.info {
+			if c > 0 {
+				buf = append(buf, ',')
+			}
+			buf = df.appendJSONVal(buf, c, r)
+		}
+		buf = append(buf, ']')
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+	buf = append(buf, ']', '}', '\n')
+	_, err := bw.Write(buf)
+	return err
+}
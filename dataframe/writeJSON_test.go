@@ -0,0 +1,78 @@
+package dataframe_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// jsonDocs returns the separate JSON documents in the output: one per line
+// for OrientLines and otherwise the whole output
+func jsonDocs(orient dataframe.Orientation, s string) []string {
+	if orient == dataframe.OrientLines {
+		return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	}
+	return []string{s}
+}
+
+func TestWriteJSON(t *testing.T) {
+	df := mkTestDF(t,
+		"b i f s\n"+
+			"true 1 1.5 x\n"+
+			"NA NA NA \"y\"\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeBool, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		orient dataframe.Orientation
+		exp    string
+	}{
+		{
+			ID:     testhelper.MkID("records"),
+			orient: dataframe.OrientRecords,
+			exp: `[{"b":true,"i":1,"f":1.5,"s":"x"},` +
+				`{"b":null,"i":null,"f":null,"s":"\"y\""}]` + "\n",
+		},
+		{
+			ID:     testhelper.MkID("columns"),
+			orient: dataframe.OrientColumns,
+			exp: `{"b":[true,null],"i":[1,null],"f":[1.5,null],` +
+				`"s":["x","\"y\""]}` + "\n",
+		},
+		{
+			ID:     testhelper.MkID("split"),
+			orient: dataframe.OrientSplit,
+			exp: `{"columns":["b","i","f","s"],` +
+				`"data":[[true,1,1.5,"x"],[null,null,null,"\"y\""]]}` + "\n",
+		},
+		{
+			ID:     testhelper.MkID("lines"),
+			orient: dataframe.OrientLines,
+			exp: `{"b":true,"i":1,"f":1.5,"s":"x"}` + "\n" +
+				`{"b":null,"i":null,"f":null,"s":"\"y\""}` + "\n",
+		},
+		{
+			ID:     testhelper.MkID("bad orientation"),
+			ExpErr: testhelper.MkExpErr("unknown JSON orientation"),
+			orient: dataframe.Orientation(99),
+		},
+	}
+
+	for _, tc := range testCases {
+		var sb strings.Builder
+		err := df.WriteJSON(&sb, tc.orient)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+			for _, doc := range jsonDocs(tc.orient, sb.String()) {
+				testhelper.DiffBool(t, tc.IDStr(), "valid JSON",
+					json.Valid([]byte(doc)), true)
+			}
+		}
+	}
+}
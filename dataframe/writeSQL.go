@@ -0,0 +1,255 @@
+package dataframe
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultSQLBatchSize is the number of rows in each INSERT statement written
+// by WriteSQLInsert unless DFWSQLBatchSize is given
+const DefaultSQLBatchSize = 100
+
+// DFWSQLBatchSize returns a function which will set the maximum number of
+// rows in each INSERT statement written by WriteSQLInsert. It has no effect
+// on other formats.
+func DFWSQLBatchSize(n int) DFWriterOpt {
+	return func(dfw *DFWriter) error {
+		if n <= 0 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the SQL batch size must be > 0: %d", n)
+		}
+		dfw.sqlBatchSize = n
+		return nil
+	}
+}
+
+// sqlIdent returns the name quoted as an SQL identifier
+func sqlIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqlTableName returns the table name quoted as an SQL identifier. A name
+// of the form schema.table is quoted part by part.
+func sqlTableName(table string) (string, error) {
+	if table == "" {
+		return "", dfErrorf("the SQL table name must not be empty")
+	}
+	parts := strings.Split(table, ".")
+	for i, p := range parts {
+		if p == "" {
+			return "", dfErrorf("bad SQL table name: %q", table)
+		}
+		parts[i] = sqlIdent(p)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// sqlColList returns the parenthesised, comma-separated list of the
+// dataframe's column names quoted as SQL identifiers
+func (df *DF) sqlColList() string {
+	names := make([]string, 0, len(df.mci.info))
+	for _, ci := range df.mci.info {
+		names = append(names, sqlIdent(ci.name))
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// sqlLiteral returns the value at the given row of the given column as an
+// SQL literal. NA values are written as NULL.
+func (df *DF) sqlLiteral(colIdx, rowIdx int) string {
+	vi := df.mci.valIdx[colIdx]
+
+	switch ct := df.mci.info[colIdx].colType; ct {
+	case ColTypeBool:
+		v := df.boolAt(vi, rowIdx)
+		if v.IsNA {
+			return "NULL"
+		}
+		if v.Val {
+			return "TRUE"
+		}
+		return "FALSE"
+	case ColTypeInt:
+		v := df.intCols[vi][rowIdx]
+		if v.IsNA {
+			return "NULL"
+		}
+		return strconv.FormatInt(v.Val, 10)
+	case ColTypeFloat:
+		v := df.floatCols[vi][rowIdx]
+		switch {
+		case v.IsNA:
+			return "NULL"
+		case math.IsNaN(v.Val):
+			return "'NaN'"
+		case math.IsInf(v.Val, 1):
+			return "'Infinity'"
+		case math.IsInf(v.Val, -1):
+			return "'-Infinity'"
+		}
+		return strconv.FormatFloat(v.Val, 'g', -1, 64)
+	case ColTypeString:
+		v := df.stringAt(vi, rowIdx)
+		if v.IsNA {
+			return "NULL"
+		}
+		return "'" + strings.ReplaceAll(v.Val, "'", "''") + "'"
+	}
+	panic(dfErrorf("Unexpected column type: %q", df.mci.info[colIdx].colType))
+}
+
+// WriteSQLInsert writes the dataframe to the io.Writer as a series of SQL
+// INSERT statements into the named table, each inserting up to the batch
+// size rows (see DFWSQLBatchSize). The table and column names are quoted
+// as identifiers; a table name of the form schema.table is quoted part by
+// part. Strings are quoted with any single quotes doubled, bools are
+// written as TRUE or FALSE and NA values as NULL. Nothing is written if the
+// dataframe has no rows.
+func (dfw *DFWriter) WriteSQLInsert(w io.Writer, df *DF, table string) error {
+	tbl, err := sqlTableName(table)
+	if err != nil {
+		return err
+	}
+	if len(df.mci.info) == 0 {
+		return dfErrorf("the dataframe has no columns")
+	}
+
+	bw := bufio.NewWriter(w)
+	prefix := "INSERT INTO " + tbl + " " + df.sqlColList() + " VALUES" +
+		dfw.lineEnd
+
+	vals := make([]string, len(df.mci.info))
+	rowCount := df.RowCount()
+	for r := 0; r < rowCount; r++ {
+		var sb strings.Builder
+		if r%dfw.sqlBatchSize == 0 {
+			sb.WriteString(prefix)
+		}
+		for c := range df.mci.info {
+			vals[c] = df.sqlLiteral(c, r)
+		}
+		sb.WriteString("  (" + strings.Join(vals, ", ") + ")")
+		if r%dfw.sqlBatchSize == dfw.sqlBatchSize-1 || r == rowCount-1 {
+			sb.WriteString(";")
+		} else {
+			sb.WriteString(",")
+		}
+		sb.WriteString(dfw.lineEnd)
+
+		if _, err := bw.WriteString(sb.String()); err != nil {
+			return dfWrapf(err, "cannot write row %d", r)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return dfWrapf(err, "cannot write the SQL INSERT statements")
+	}
+	return nil
+}
+
+// pgCopyEscaper escapes the characters which are special in the text
+// format of the PostgreSQL COPY command
+var pgCopyEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"\t", `\t`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
+
+// pgCopyVal returns the value at the given row of the given column in the
+// text format of the PostgreSQL COPY command. NA values are written as \N.
+func (df *DF) pgCopyVal(colIdx, rowIdx int) string {
+	const null = `\N`
+
+	vi := df.mci.valIdx[colIdx]
+
+	switch ct := df.mci.info[colIdx].colType; ct {
+	case ColTypeBool:
+		v := df.boolAt(vi, rowIdx)
+		if v.IsNA {
+			return null
+		}
+		if v.Val {
+			return "t"
+		}
+		return "f"
+	case ColTypeInt, ColTypeFloat:
+		lit := df.sqlLiteral(colIdx, rowIdx)
+		if lit == "NULL" {
+			return null
+		}
+		return strings.Trim(lit, "'")
+	case ColTypeString:
+		v := df.stringAt(vi, rowIdx)
+		if v.IsNA {
+			return null
+		}
+		return pgCopyEscaper.Replace(v.Val)
+	}
+	panic(dfErrorf("Unexpected column type: %q", df.mci.info[colIdx].colType))
+}
+
+// WriteSQLCopy writes the dataframe to the io.Writer as a PostgreSQL COPY
+// command in text format, copying the rows into the named table from
+// stdin. The values are separated by tabs, NA values are written as \N
+// and backslashes, tabs, newlines and carriage returns in strings are
+// escaped. The data is terminated by a line holding just \. as expected by
+// psql.
+func (dfw *DFWriter) WriteSQLCopy(w io.Writer, df *DF, table string) error {
+	tbl, err := sqlTableName(table)
+	if err != nil {
+		return err
+	}
+	if len(df.mci.info) == 0 {
+		return dfErrorf("the dataframe has no columns")
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("COPY " + tbl + " " + df.sqlColList() +
+		" FROM stdin;\n"); err != nil {
+		return dfWrapf(err, "cannot write the SQL COPY command")
+	}
+
+	vals := make([]string, len(df.mci.info))
+	for r := 0; r < df.RowCount(); r++ {
+		for c := range df.mci.info {
+			vals[c] = df.pgCopyVal(c, r)
+		}
+		if _, err := bw.WriteString(
+			strings.Join(vals, "\t") + "\n"); err != nil {
+			return dfWrapf(err, "cannot write row %d", r)
+		}
+	}
+
+	if _, err := bw.WriteString("\\.\n"); err != nil {
+		return dfWrapf(err, "cannot write the SQL COPY terminator")
+	}
+	if err := bw.Flush(); err != nil {
+		return dfWrapf(err, "cannot write the SQL COPY command")
+	}
+	return nil
+}
+
+// WriteSQLInsert writes the dataframe to the io.Writer as SQL INSERT
+// statements into the named table. The options are applied to a new
+// DFWriter, see DFWriter.WriteSQLInsert for details.
+func (df *DF) WriteSQLInsert(w io.Writer, table string,
+	opts ...DFWriterOpt,
+) error {
+	dfw, err := NewDFWriter(opts...)
+	if err != nil {
+		return err
+	}
+	return dfw.WriteSQLInsert(w, df, table)
+}
+
+// WriteSQLCopy writes the dataframe to the io.Writer as a PostgreSQL COPY
+// command copying the rows into the named table, see DFWriter.WriteSQLCopy
+// for details.
+func (df *DF) WriteSQLCopy(w io.Writer, table string) error {
+	var dfw DFWriter
+	return dfw.WriteSQLCopy(w, df, table)
+}
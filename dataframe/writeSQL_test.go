@@ -0,0 +1,86 @@
+package dataframe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func mkSQLTestDF(t *testing.T) *dataframe.DF {
+	t.Helper()
+
+	return mkTestDF(t,
+		"b|i|f|s\n"+
+			"true|1|1.5|it's\n"+
+			"false|2|NA|a\\b\tc\n"+
+			"NA|NA|-0.25|NA\n",
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.SplitPattern(`\|`),
+		dataframe.DFRColTypes(dataframe.ColTypeBool, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+}
+
+func TestWriteSQLInsert(t *testing.T) {
+	df := mkSQLTestDF(t)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		table string
+		opts  []dataframe.DFWriterOpt
+		exp   string
+	}{
+		{
+			ID:    testhelper.MkID("single batch"),
+			table: "tbl",
+			exp: `INSERT INTO "tbl" ("b", "i", "f", "s") VALUES` + "\n" +
+				`  (TRUE, 1, 1.5, 'it''s'),` + "\n" +
+				"  (FALSE, 2, NULL, 'a\\b\tc'),\n" +
+				`  (NULL, NULL, -0.25, 'NA');` + "\n",
+		},
+		{
+			ID:    testhelper.MkID("batches of 2, schema"),
+			table: "sch.tbl",
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWSQLBatchSize(2),
+			},
+			exp: `INSERT INTO "sch"."tbl" ("b", "i", "f", "s") VALUES` +
+				"\n" +
+				`  (TRUE, 1, 1.5, 'it''s'),` + "\n" +
+				"  (FALSE, 2, NULL, 'a\\b\tc');\n" +
+				`INSERT INTO "sch"."tbl" ("b", "i", "f", "s") VALUES` +
+				"\n" +
+				`  (NULL, NULL, -0.25, 'NA');` + "\n",
+		},
+		{
+			ID:     testhelper.MkID("bad table name"),
+			ExpErr: testhelper.MkExpErr(`bad SQL table name: "sch."`),
+			table:  "sch.",
+		},
+	}
+
+	for _, tc := range testCases {
+		var sb strings.Builder
+		err := df.WriteSQLInsert(&sb, tc.table, tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+		}
+	}
+}
+
+func TestWriteSQLCopy(t *testing.T) {
+	df := mkSQLTestDF(t)
+
+	var sb strings.Builder
+	if err := df.WriteSQLCopy(&sb, "tbl"); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	testhelper.DiffString(t, "WriteSQLCopy", "output", sb.String(),
+		`COPY "tbl" ("b", "i", "f", "s") FROM stdin;`+"\n"+
+			"t\t1\t1.5\tit's\n"+
+			"f\t2\t\\N\ta\\\\b\\tc\n"+
+			"\\N\t\\N\t-0.25\tNA\n"+
+			"\\.\n")
+}
@@ -0,0 +1,545 @@
+package dataframe
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// colFormat holds the formatting details for a column. The zero value
+// gives the default format for each type
+type colFormat struct {
+	hasFloatFmt bool
+	floatFmt    byte
+	floatPrec   int
+
+	intBase int
+
+	hasBoolStrs bool
+	trueStr     string
+	falseStr    string
+}
+
+// DefaultNAString is the string written by a DFWriter for an NA value
+// unless DFWNAString is given
+const DefaultNAString = "NA"
+
+// DFWriter holds the configurable options for writing a dataframe to an
+// io.Writer
+type DFWriter struct {
+	noHeader  bool
+	quoteAll  bool
+	roundTrip bool
+	hasNAStr  bool
+	sep       string
+	naStr     string
+	lineEnd   string
+
+	sqlBatchSize int
+	headTail     int
+
+	colFmts map[string]*colFormat
+}
+
+type DFWriterOpt func(*DFWriter) error
+
+// NewDFWriter creates a new DFWriter applying the options and returning an
+// error if any of the option functions fails
+func NewDFWriter(opts ...DFWriterOpt) (*DFWriter, error) {
+	dfw := &DFWriter{
+		lineEnd:      "\n",
+		sqlBatchSize: DefaultSQLBatchSize,
+		colFmts:      make(map[string]*colFormat),
+	}
+	for _, o := range opts {
+		err := o(dfw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dfw, nil
+}
+
+// colFmt returns the colFormat for the named column, creating it if
+// necessary
+func (dfw *DFWriter) colFmt(name string) *colFormat {
+	cf, ok := dfw.colFmts[name]
+	if !ok {
+		cf = &colFormat{}
+		dfw.colFmts[name] = cf
+	}
+	return cf
+}
+
+// DFWNoHeader will cause the DFWriter to not write the line of column names
+// before the data
+func DFWNoHeader(dfw *DFWriter) error {
+	dfw.noHeader = true
+	return nil
+}
+
+// DFWCRLF will cause the DFWriter to end each line with a carriage return
+// and a newline rather than just a newline
+func DFWCRLF(dfw *DFWriter) error {
+	dfw.lineEnd = "\r\n"
+	return nil
+}
+
+// DFWQuoteAll will cause every field written as CSV to be quoted rather
+// than just those that need it. It has no effect on other formats.
+func DFWQuoteAll(dfw *DFWriter) error {
+	dfw.quoteAll = true
+	return nil
+}
+
+// DFWSeparator returns a function which will set the string written between
+// the columns. The default depends on the format: a single space for Write
+// and a comma for WriteCSV.
+func DFWSeparator(sep string) DFWriterOpt {
+	return func(dfw *DFWriter) error {
+		if sep == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the column separator must not be empty")
+		}
+		dfw.sep = sep
+		return nil
+	}
+}
+
+// DFWHeadTail returns a function which will limit the rows written by
+// WriteAligned to the first and last n rows, with a separator row between
+// them showing that rows have been left out. All the rows are written if
+// there are no more than 2*n rows or if n is 0 (the default).
+func DFWHeadTail(n int) DFWriterOpt {
+	return func(dfw *DFWriter) error {
+		if n < 0 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the number of head and tail rows (%d)"+
+					" must not be negative", n)
+		}
+		dfw.headTail = n
+		return nil
+	}
+}
+
+// DFWNAString returns a function which will set the string written for NA
+// values, for instance "" for a spreadsheet, "NULL" for SQL or `\N` for a
+// database bulk loader. The default depends on the format:
+// DefaultNAString for Write and an empty string for WriteCSV. The string may
+// be empty but it must not contain a newline.
+func DFWNAString(s string) DFWriterOpt {
+	return func(dfw *DFWriter) error {
+		if strings.ContainsRune(s, '\n') {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"the NA string must not contain a newline: %q", s)
+		}
+		dfw.naStr = s
+		dfw.hasNAStr = true
+		return nil
+	}
+}
+
+// DFWFloatFormat returns a function which will set the format used to write
+// the values of the named float column. The format and precision have the
+// same meaning as for strconv.FormatFloat; a precision of -1 uses the
+// smallest number of digits needed to represent the value exactly. The
+// default is 'g' with a precision of -1.
+func DFWFloatFormat(name string, format byte, prec int) DFWriterOpt {
+	return func(dfw *DFWriter) error {
+		if !strings.ContainsRune("beEfgGxX", rune(format)) {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"column %q: bad float format: %q", name, format)
+		}
+		if prec < -1 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"column %q: the float precision must be >= -1: %d",
+				name, prec)
+		}
+		cf := dfw.colFmt(name)
+		cf.hasFloatFmt = true
+		cf.floatFmt = format
+		cf.floatPrec = prec
+		return nil
+	}
+}
+
+// DFWIntBase returns a function which will set the base in which the values
+// of the named int column are written. The base must be between 2 and 36,
+// the default is 10. Note that no prefix (such as 0x) is written.
+func DFWIntBase(name string, base int) DFWriterOpt {
+	return func(dfw *DFWriter) error {
+		if base < 2 || base > 36 {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"column %q: the int base must be in [2,36]: %d",
+				name, base)
+		}
+		dfw.colFmt(name).intBase = base
+		return nil
+	}
+}
+
+// DFWBoolStrings returns a function which will set the strings written for
+// true and false values of the named bool column, for instance "Y" and
+// "N". The default is "true" and "false".
+func DFWBoolStrings(name, trueStr, falseStr string) DFWriterOpt {
+	return func(dfw *DFWriter) error {
+		if trueStr == "" || falseStr == "" {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"column %q: the bool strings must not be empty",
+				name)
+		}
+		if trueStr == falseStr {
+			return dfCodeErrorf(ErrCodeBadOption,
+				"column %q: the bool strings must differ, both are %q",
+				name, trueStr)
+		}
+		cf := dfw.colFmt(name)
+		cf.hasBoolStrs = true
+		cf.trueStr = trueStr
+		cf.falseStr = falseStr
+		return nil
+	}
+}
+
+// checkColFmts checks that every column with a format is one of the
+// columns being written and has the right type for the format
+func (dfw DFWriter) checkColFmts(mci MultiColInfo) error {
+	for name, cf := range dfw.colFmts {
+		idx, ok := mci.nameToCol[name]
+		if !ok {
+			return dfWrapf(errUnknownColName(name), "bad column format")
+		}
+		ci := mci.info[idx]
+		if cf.hasFloatFmt {
+			if err := assertTypeByName(ci.colType, ColTypeFloat,
+				name); err != nil {
+				return dfWrapf(err, "bad float format")
+			}
+		}
+		if cf.intBase != 0 {
+			if err := assertTypeByName(ci.colType, ColTypeInt,
+				name); err != nil {
+				return dfWrapf(err, "bad int base")
+			}
+		}
+		if cf.hasBoolStrs {
+			if err := assertTypeByName(ci.colType, ColTypeBool,
+				name); err != nil {
+				return dfWrapf(err, "bad bool strings")
+			}
+		}
+	}
+	return nil
+}
+
+// lineFormat holds the details of how the fields are written which can
+// vary between formats
+type lineFormat struct {
+	sep   string
+	na    string
+	quote func(string) string
+
+	// strQuote, if not nil, is used in place of quote for the column
+	// names and for string values which are not NA
+	strQuote func(string) string
+	// types, if true, causes a line of column types to be written after
+	// the column names
+	types bool
+}
+
+// lineFormat returns the lineFormat for the DFWriter, using the given
+// defaults for any values not set by the options
+func (dfw DFWriter) lineFormat(sep, na string) lineFormat {
+	lf := lineFormat{
+		sep:   sep,
+		na:    na,
+		quote: func(s string) string { return s },
+	}
+	if dfw.sep != "" {
+		lf.sep = dfw.sep
+	}
+	if dfw.hasNAStr {
+		lf.na = dfw.naStr
+	}
+	return lf
+}
+
+// formatVal returns the text for the value which is from the named
+// column, na is returned for an NA value. The value must be one of the Val
+// types.
+func (dfw DFWriter) formatVal(name string, v any, na string) string {
+	cf := dfw.colFmts[name]
+	if cf == nil {
+		cf = &colFormat{}
+	}
+
+	switch v := v.(type) {
+	case BoolVal:
+		if v.IsNA {
+			return na
+		}
+		if cf.hasBoolStrs {
+			if v.Val {
+				return cf.trueStr
+			}
+			return cf.falseStr
+		}
+		return strconv.FormatBool(v.Val)
+	case IntVal:
+		if v.IsNA {
+			return na
+		}
+		base := 10
+		if cf.intBase != 0 {
+			base = cf.intBase
+		}
+		return strconv.FormatInt(v.Val, base)
+	case FloatVal:
+		if v.IsNA {
+			return na
+		}
+		if cf.hasFloatFmt {
+			return strconv.FormatFloat(v.Val, cf.floatFmt, cf.floatPrec, 64)
+		}
+		return strconv.FormatFloat(v.Val, 'g', -1, 64)
+	case StringVal:
+		if v.IsNA {
+			return na
+		}
+		return v.Val
+	}
+	panic(dfErrorf("Unexpected value type: %T", v))
+}
+
+// RowWriter writes rows one at a time to an io.Writer in the format of the
+// DFWriter that created it. The rows need not come from a dataframe so it
+// can be used to write data that is too large to hold in memory. The
+// output is buffered and so Flush must be called after the last row has
+// been written.
+type RowWriter struct {
+	dfw      *DFWriter
+	bw       *bufio.Writer
+	mci      MultiColInfo
+	lf       lineFormat
+	vals     []string
+	rowCount int
+}
+
+// newRowWriter creates a RowWriter for rows with the given columns and
+// writes the header line, if any
+func (dfw *DFWriter) newRowWriter(w io.Writer, mci MultiColInfo,
+	lf lineFormat,
+) (*RowWriter, error) {
+	if err := dfw.checkColFmts(mci); err != nil {
+		return nil, err
+	}
+
+	rw := &RowWriter{
+		dfw:  dfw,
+		bw:   bufio.NewWriter(w),
+		mci:  mci,
+		lf:   lf,
+		vals: make([]string, len(mci.info)),
+	}
+
+	if !dfw.noHeader && len(mci.info) > 0 {
+		for c, ci := range mci.info {
+			rw.vals[c] = rw.quoteStr(ci.name)
+		}
+		if err := rw.writeVals(); err != nil {
+			return nil, dfWrapf(err, "cannot write the column names")
+		}
+	}
+
+	if lf.types && len(mci.info) > 0 {
+		for c, ci := range mci.info {
+			rw.vals[c] = ci.colType.String()
+		}
+		if err := rw.writeVals(); err != nil {
+			return nil, dfWrapf(err, "cannot write the column types")
+		}
+	}
+
+	return rw, nil
+}
+
+// quoteStr quotes a string value or column name
+func (rw *RowWriter) quoteStr(s string) string {
+	if rw.lf.strQuote != nil {
+		return rw.lf.strQuote(s)
+	}
+	return rw.lf.quote(s)
+}
+
+// fmtVal formats and quotes the value which is from the named column
+func (rw *RowWriter) fmtVal(name string, v any) string {
+	s := rw.dfw.formatVal(name, v, rw.lf.na)
+	if sv, ok := v.(StringVal); ok && !sv.IsNA {
+		return rw.quoteStr(s)
+	}
+	return rw.lf.quote(s)
+}
+
+// writeVals writes the formatted values as a line
+func (rw *RowWriter) writeVals() error {
+	_, err := rw.bw.WriteString(
+		strings.Join(rw.vals, rw.lf.sep) + rw.dfw.lineEnd)
+	return err
+}
+
+// WriteRow writes the row. The row must have the same columns, in the same
+// order, as those given when the RowWriter was created.
+func (rw *RowWriter) WriteRow(r *Row) error {
+	if err := rw.mci.Match(r.mci); err != nil {
+		return err
+	}
+
+	for c, ci := range rw.mci.info {
+		v, _, err := r.ValByIdx(c)
+		if err != nil {
+			return err
+		}
+		rw.vals[c] = rw.fmtVal(ci.name, v)
+	}
+	if err := rw.writeVals(); err != nil {
+		return dfWrapf(err, "cannot write row %d", rw.rowCount)
+	}
+	rw.rowCount++
+	return nil
+}
+
+// writeDFRow writes the i'th row of the dataframe which must have the same
+// columns as the RowWriter
+func (rw *RowWriter) writeDFRow(df *DF, i int) error {
+	for c, ci := range rw.mci.info {
+		v, _ := df.valAt(c, i)
+		rw.vals[c] = rw.fmtVal(ci.name, v)
+	}
+	if err := rw.writeVals(); err != nil {
+		return dfWrapf(err, "cannot write row %d", rw.rowCount)
+	}
+	rw.rowCount++
+	return nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer
+func (rw *RowWriter) Flush() error {
+	if err := rw.bw.Flush(); err != nil {
+		return dfWrapf(err, "cannot flush the rows")
+	}
+	return nil
+}
+
+// textLineFormat returns the lineFormat for the plain text format
+func (dfw DFWriter) textLineFormat() (lineFormat, error) {
+	if dfw.roundTrip {
+		return dfw.roundTripLineFormat()
+	}
+
+	lf := dfw.lineFormat(" ", DefaultNAString)
+	if strings.Contains(lf.na, lf.sep) {
+		return lf, dfErrorf("the NA string (%q) contains the separator (%q)",
+			lf.na, lf.sep)
+	}
+	return lf, nil
+}
+
+// NewRowWriter returns a RowWriter which will write rows having the given
+// columns to the io.Writer in the same format as Write. The header line, if
+// any, is written immediately.
+func (dfw *DFWriter) NewRowWriter(w io.Writer, cis ...ColInfo,
+) (*RowWriter, error) {
+	mci, err := NewMultiColInfo(cis...)
+	if err != nil {
+		return nil, err
+	}
+	lf, err := dfw.textLineFormat()
+	if err != nil {
+		return nil, err
+	}
+	return dfw.newRowWriter(w, *mci, lf)
+}
+
+// Write writes the dataframe to the io.Writer, one line per row with the
+// columns separated by the separator and preceded by a line of column names
+// unless DFWNoHeader has been given. If DFWRoundTrip has been given the
+// output can be read back by a DFReader to give an identical dataframe, see
+// DFWRoundTrip for details.
+func (dfw *DFWriter) Write(w io.Writer, df *DF) error {
+	lf, err := dfw.textLineFormat()
+	if err != nil {
+		return err
+	}
+	return dfw.write(w, df, lf)
+}
+
+// write writes the dataframe to the io.Writer, formatting the lines
+// according to the lineFormat. Each row is formatted as it is written so
+// the memory needed doesn't grow with the size of the dataframe.
+func (dfw *DFWriter) write(w io.Writer, df *DF, lf lineFormat) error {
+	if lf.types {
+		if err := dfw.writeMetaLines(w, df); err != nil {
+			return err
+		}
+	}
+
+	rw, err := dfw.newRowWriter(w, df.mci, lf)
+	if err != nil {
+		return err
+	}
+
+	for r := 0; r < df.RowCount(); r++ {
+		if err := rw.writeDFRow(df, r); err != nil {
+			return err
+		}
+	}
+
+	return rw.Flush()
+}
+
+// WriteFile writes the dataframe to the named file, creating it if
+// necessary and truncating it if it already exists
+func (dfw *DFWriter) WriteFile(filename string, df *DF) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = dfw.Write(file, df)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteFile writes the dataframe to the named file, creating it if
+// necessary and truncating it if it already exists. If a format has been
+// registered (see RegisterFormat) for the file's extension the dataframe
+// is written in that format, otherwise it is written by a DFWriter as a
+// table of values. The options are passed to the format's Write function;
+// a format which is not written by a DFWriter may ignore them. It returns
+// an error if the registered format cannot be written.
+func WriteFile(filename string, df *DF, opts ...DFWriterOpt) error {
+	f, ok := FormatForFile(filename)
+	if !ok {
+		f, _ = FormatByName("table")
+	}
+	if f.Write == nil {
+		return dfErrorf("file: %s: the %q format cannot be written",
+			filename, f.Name)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = f.Write(file, df, opts...)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
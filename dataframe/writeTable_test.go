@@ -0,0 +1,249 @@
+package dataframe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+const writeTestData = "b i f s\n" +
+	"true 10 1.5 a\n" +
+	"false 255 0.125 b\n" +
+	"true NA NA c\n"
+
+func TestWriteTable(t *testing.T) {
+	df := mkTestDF(t, writeTestData, dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeBool, dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opts []dataframe.DFWriterOpt
+		exp  string
+	}{
+		{
+			ID:  testhelper.MkID("default"),
+			exp: writeTestData,
+		},
+		{
+			ID: testhelper.MkID("no header, comma separated"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWNoHeader,
+				dataframe.DFWSeparator(","),
+			},
+			exp: "true,10,1.5,a\n" +
+				"false,255,0.125,b\n" +
+				"true,NA,NA,c\n",
+		},
+		{
+			ID: testhelper.MkID("column formats"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWBoolStrings("b", "Y", "N"),
+				dataframe.DFWIntBase("i", 16),
+				dataframe.DFWFloatFormat("f", 'f', 2),
+			},
+			exp: "b i f s\n" +
+				"Y a 1.50 a\n" +
+				"N ff 0.12 b\n" +
+				"Y NA NA c\n",
+		},
+		{
+			ID: testhelper.MkID("NA strings"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWNoHeader,
+				dataframe.DFWSeparator("\t"),
+				dataframe.DFWNAString(`\N`),
+			},
+			exp: "true\t10\t1.5\ta\n" +
+				"false\t255\t0.125\tb\n" +
+				"true\t\\N\t\\N\tc\n",
+		},
+		{
+			ID: testhelper.MkID("empty NA string"),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWNoHeader,
+				dataframe.DFWSeparator(","),
+				dataframe.DFWNAString(""),
+			},
+			exp: "true,10,1.5,a\n" +
+				"false,255,0.125,b\n" +
+				"true,,,c\n",
+		},
+		{
+			ID: testhelper.MkID("NA string contains the separator"),
+			ExpErr: testhelper.MkExpErr(
+				`the NA string ("N A") contains the separator (" ")`),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWNAString("N A"),
+			},
+		},
+		{
+			ID: testhelper.MkID("format for an unknown column"),
+			ExpErr: testhelper.MkExpErr(
+				"bad column format",
+				`Unknown column name: "nonesuch"`),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWIntBase("nonesuch", 16),
+			},
+		},
+		{
+			ID: testhelper.MkID("format for the wrong column type"),
+			ExpErr: testhelper.MkExpErr(
+				"bad float format",
+				`The column named "i" is of type "Int" not "Float"`),
+			opts: []dataframe.DFWriterOpt{
+				dataframe.DFWFloatFormat("i", 'f', 2),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dfw, err := dataframe.NewDFWriter(tc.opts...)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot make the DFWriter: %s", err)
+			continue
+		}
+		var sb strings.Builder
+		err = dfw.Write(&sb, df)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+		}
+	}
+}
+
+func TestDFWriterOpts(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		opt dataframe.DFWriterOpt
+	}{
+		{
+			ID:  testhelper.MkID("good float format"),
+			opt: dataframe.DFWFloatFormat("f", 'e', -1),
+		},
+		{
+			ID:     testhelper.MkID("bad float format"),
+			ExpErr: testhelper.MkExpErr(`bad float format: 'q'`),
+			opt:    dataframe.DFWFloatFormat("f", 'q', 2),
+		},
+		{
+			ID:     testhelper.MkID("bad float precision"),
+			ExpErr: testhelper.MkExpErr("the float precision must be >= -1"),
+			opt:    dataframe.DFWFloatFormat("f", 'f', -2),
+		},
+		{
+			ID: testhelper.MkID("bad NA string"),
+			ExpErr: testhelper.MkExpErr(
+				"the NA string must not contain a newline"),
+			opt: dataframe.DFWNAString("N\nA"),
+		},
+		{
+			ID:     testhelper.MkID("bad int base"),
+			ExpErr: testhelper.MkExpErr("the int base must be in [2,36]: 37"),
+			opt:    dataframe.DFWIntBase("i", 37),
+		},
+		{
+			ID:     testhelper.MkID("empty bool string"),
+			ExpErr: testhelper.MkExpErr("the bool strings must not be empty"),
+			opt:    dataframe.DFWBoolStrings("b", "", "N"),
+		},
+		{
+			ID:     testhelper.MkID("same bool strings"),
+			ExpErr: testhelper.MkExpErr("the bool strings must differ"),
+			opt:    dataframe.DFWBoolStrings("b", "Y", "Y"),
+		},
+		{
+			ID: testhelper.MkID("empty separator"),
+			ExpErr: testhelper.MkExpErr(
+				"the column separator must not be empty"),
+			opt: dataframe.DFWSeparator(""),
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := dataframe.NewDFWriter(tc.opt)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestRowWriter(t *testing.T) {
+	cis := []dataframe.ColInfo{
+		dataframe.MustNewColInfo("i", dataframe.ColTypeInt),
+		dataframe.MustNewColInfo("s", dataframe.ColTypeString),
+	}
+	mkRow := func(i dataframe.IntVal, s dataframe.StringVal) *dataframe.Row {
+		r, err := dataframe.NewRow()
+		if err != nil {
+			t.Fatal("BAD TEST - cannot make the row: ", err)
+		}
+		if err := r.AddInt("i", i); err != nil {
+			t.Fatal("BAD TEST - cannot add the int: ", err)
+		}
+		if err := r.AddString("s", s); err != nil {
+			t.Fatal("BAD TEST - cannot add the string: ", err)
+		}
+		return r
+	}
+	rows := []*dataframe.Row{
+		mkRow(dataframe.IntVal{Val: 1}, dataframe.StringVal{Val: "a b"}),
+		mkRow(dataframe.IntVal{IsNA: true}, dataframe.StringVal{Val: "c"}),
+	}
+
+	dfw, err := dataframe.NewDFWriter()
+	if err != nil {
+		t.Fatal("cannot make the DFWriter: ", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		newRW func(*strings.Builder) (*dataframe.RowWriter, error)
+		exp   string
+	}{
+		{
+			ID: testhelper.MkID("text"),
+			newRW: func(sb *strings.Builder) (*dataframe.RowWriter, error) {
+				return dfw.NewRowWriter(sb, cis...)
+			},
+			exp: "i s\n1 a b\nNA c\n",
+		},
+		{
+			ID: testhelper.MkID("CSV"),
+			newRW: func(sb *strings.Builder) (*dataframe.RowWriter, error) {
+				return dfw.NewCSVRowWriter(sb, cis...)
+			},
+			exp: "i,s\n1,a b\n,c\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		var sb strings.Builder
+		rw, err := tc.newRW(&sb)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot make the RowWriter: %s", err)
+			continue
+		}
+		for _, r := range rows {
+			if err := rw.WriteRow(r); err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: cannot write the row: %s", err)
+			}
+		}
+		if err := rw.Flush(); err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: cannot flush the rows: %s", err)
+		}
+		testhelper.DiffString(t, tc.IDStr(), "output", sb.String(), tc.exp)
+
+		badRow, _ := dataframe.NewRow()
+		_ = badRow.AddInt("x", dataframe.IntVal{Val: 1})
+		err = rw.WriteRow(badRow)
+		testhelper.DiffBool(t, tc.IDStr(), "bad row error",
+			errors.Is(err, dataframe.ErrDimensionMismatch), true)
+	}
+}
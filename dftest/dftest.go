@@ -0,0 +1,229 @@
+/*
+Package dftest provides helpers for testing code which uses dataframes. It
+allows a test to compare an expected dataframe with the one it actually got
+and to report every difference in a readable form.
+*/
+package dftest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+)
+
+// DefaultMaxDiffs is the maximum number of differing cells reported by
+// AssertDFEqual unless MaxDiffs is given
+const DefaultMaxDiffs = 10
+
+// cmpOpts holds the settings controlling the comparison
+type cmpOpts struct {
+	maxDiffs       int
+	floatTol       float64
+	ignoreColOrder bool
+}
+
+// Opt is the type of an option function for AssertDFEqual
+type Opt func(*cmpOpts) error
+
+// MaxDiffs returns an option function which will set the maximum number of
+// differing cells to report. Any further differences are counted but not
+// shown.
+func MaxDiffs(n int) Opt {
+	return func(co *cmpOpts) error {
+		if n <= 0 {
+			return fmt.Errorf("the maximum number of diffs must be > 0: %d", n)
+		}
+		co.maxDiffs = n
+		return nil
+	}
+}
+
+// FloatTolerance returns an option function which will allow float values
+// to differ by up to the given amount and still be treated as equal
+func FloatTolerance(tol float64) Opt {
+	return func(co *cmpOpts) error {
+		if tol < 0 || math.IsNaN(tol) {
+			return fmt.Errorf("the float tolerance must be >= 0: %g", tol)
+		}
+		co.floatTol = tol
+		return nil
+	}
+}
+
+// IgnoreColOrder is an option function which will cause the columns to be
+// matched by name rather than by position so that dataframes with the same
+// columns in a different order can be equal. Any columns present in only
+// one of the dataframes are reported.
+func IgnoreColOrder(co *cmpOpts) error {
+	co.ignoreColOrder = true
+	return nil
+}
+
+// valStr returns the value formatted for a diff report. Strings are quoted
+// so that leading and trailing spaces are visible.
+func valStr(v any) string {
+	if sv, ok := v.(dataframe.StringVal); ok && !sv.IsNA {
+		return strconv.Quote(sv.Val)
+	}
+	return fmt.Sprint(v)
+}
+
+// valsEqual returns true if the two values are equal. Two NA values are
+// equal as are two NaN floats.
+func (co cmpOpts) valsEqual(want, got any) bool {
+	wf, ok := want.(dataframe.FloatVal)
+	if !ok {
+		return want == got
+	}
+	gf := got.(dataframe.FloatVal)
+	if wf.IsNA || gf.IsNA {
+		return wf.IsNA == gf.IsNA
+	}
+	if math.IsNaN(wf.Val) || math.IsNaN(gf.Val) {
+		return math.IsNaN(wf.Val) == math.IsNaN(gf.Val)
+	}
+	return wf.Val == gf.Val || math.Abs(wf.Val-gf.Val) <= co.floatTol
+}
+
+// colDiffs returns a description of each difference between the columns
+// of the two dataframes and, for each column of want, the index of the
+// matching column of got
+func (co cmpOpts) colDiffs(want, got *dataframe.DF) ([]string, []int) {
+	if co.ignoreColOrder {
+		return colDiffsByName(want, got)
+	}
+
+	wCols, gCols := want.Columns(), got.Columns()
+	if len(wCols) != len(gCols) {
+		return []string{fmt.Sprintf("expected %d columns, got %d: %v != %v",
+			len(wCols), len(gCols), wCols, gCols)}, nil
+	}
+
+	var diffs []string
+	gotIdx := make([]int, len(wCols))
+	for i, wc := range wCols {
+		if gc := gCols[i]; wc.Name() != gc.Name() ||
+			wc.ColType() != gc.ColType() {
+			diffs = append(diffs,
+				fmt.Sprintf("column %d: expected %s, got %s", i, wc, gc))
+		}
+		gotIdx[i] = i
+	}
+	return diffs, gotIdx
+}
+
+// colDiffsByName returns the differences between the columns of the two
+// dataframes, matching the columns by name, as for colDiffs
+func colDiffsByName(want, got *dataframe.DF) ([]string, []int) {
+	gCols := got.Columns()
+	gIdx := make(map[string]int, len(gCols))
+	for i, gc := range gCols {
+		gIdx[gc.Name()] = i
+	}
+
+	var diffs []string
+	wCols := want.Columns()
+	wNames := make(map[string]bool, len(wCols))
+	gotIdx := make([]int, len(wCols))
+	for i, wc := range wCols {
+		wNames[wc.Name()] = true
+		j, ok := gIdx[wc.Name()]
+		if !ok {
+			diffs = append(diffs,
+				fmt.Sprintf("column %q: expected but not found", wc.Name()))
+			continue
+		}
+		if gc := gCols[j]; wc.ColType() != gc.ColType() {
+			diffs = append(diffs,
+				fmt.Sprintf("column %q: expected %s, got %s",
+					wc.Name(), wc, gc))
+		}
+		gotIdx[i] = j
+	}
+	for _, gc := range gCols {
+		if !wNames[gc.Name()] {
+			diffs = append(diffs,
+				fmt.Sprintf("column %q: not expected", gc.Name()))
+		}
+	}
+	return diffs, gotIdx
+}
+
+// cellDiffs returns a description of each differing cell in the rows
+// common to both dataframes (up to the maximum) and the total count of
+// differing cells. The gotIdx gives the column of got matching each column
+// of want.
+func (co cmpOpts) cellDiffs(want, got *dataframe.DF, gotIdx []int) (
+	[]string, int,
+) {
+	rows := want.RowCount()
+	if got.RowCount() < rows {
+		rows = got.RowCount()
+	}
+	cols := want.Columns()
+
+	var diffs []string
+	count := 0
+	for r := 0; r < rows; r++ {
+		wRow, gRow := want.Row(r), got.Row(r)
+		for c, ci := range cols {
+			wv, _, _ := wRow.ValByIdx(c)
+			gv, _, _ := gRow.ValByIdx(gotIdx[c])
+			if co.valsEqual(wv, gv) {
+				continue
+			}
+			count++
+			if count <= co.maxDiffs {
+				diffs = append(diffs,
+					fmt.Sprintf("row %d, column %q: expected %s, got %s",
+						r, ci.Name(), valStr(wv), valStr(gv)))
+			}
+		}
+	}
+	return diffs, count
+}
+
+// AssertDFEqual compares the two dataframes and reports an error through t
+// if they differ, returning true if they are equal. The columns must have
+// the same names and types in the same order (or in any order if
+// IgnoreColOrder is given); if they do then each cell of the rows in
+// common is compared and the differing cells are listed with
+// their row index and column name. NA values are equal to each other.
+func AssertDFEqual(t testing.TB, want, got *dataframe.DF, opts ...Opt) bool {
+	t.Helper()
+
+	co := cmpOpts{maxDiffs: DefaultMaxDiffs}
+	for _, o := range opts {
+		if err := o(&co); err != nil {
+			t.Fatal("dftest: bad option: ", err)
+		}
+	}
+
+	diffs, gotIdx := co.colDiffs(want, got)
+	if len(diffs) > 0 {
+		t.Errorf("dataframes have different columns:\n\t%s",
+			strings.Join(diffs, "\n\t"))
+		return false
+	}
+
+	if want.RowCount() != got.RowCount() {
+		diffs = append(diffs, fmt.Sprintf("expected %d rows, got %d",
+			want.RowCount(), got.RowCount()))
+	}
+	cells, count := co.cellDiffs(want, got, gotIdx)
+	diffs = append(diffs, cells...)
+	if count > len(cells) {
+		diffs = append(diffs,
+			fmt.Sprintf("... and %d more differing cells", count-len(cells)))
+	}
+
+	if len(diffs) > 0 {
+		t.Errorf("dataframes differ:\n\t%s", strings.Join(diffs, "\n\t"))
+		return false
+	}
+	return true
+}
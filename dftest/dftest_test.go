@@ -0,0 +1,160 @@
+package dftest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/dataframe.mod/dataframe"
+	"github.com/nickwells/dataframe.mod/dftest"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// recordingTB records the errors reported through it rather than failing
+// the test
+type recordingTB struct {
+	testing.TB
+	errs []string
+}
+
+func (rtb *recordingTB) Helper() {}
+
+func (rtb *recordingTB) Errorf(format string, args ...any) {
+	rtb.errs = append(rtb.errs, fmt.Sprintf(format, args...))
+}
+
+// mkDF reads the content into a new dataframe. Float values which cannot
+// be parsed are NA
+func mkDF(t *testing.T, content string) *dataframe.DF {
+	t.Helper()
+
+	dfr, err := dataframe.NewDFReader(
+		dataframe.HasHeader, dataframe.AllowErrors,
+		dataframe.DFRColTypes(dataframe.ColTypeInt,
+			dataframe.ColTypeFloat, dataframe.ColTypeString))
+	if err != nil {
+		t.Fatal("BAD TEST - cannot make the DFReader: ", err)
+	}
+	df, err := dfr.Read(strings.NewReader(content), "test data")
+	if err != nil {
+		t.Fatal("BAD TEST - cannot read the test data: ", err)
+	}
+	return df
+}
+
+// mustSelect returns a dataframe with just the named columns of df, in
+// the order given
+func mustSelect(t *testing.T, df *dataframe.DF, names ...string) *dataframe.DF {
+	t.Helper()
+
+	rval, err := df.Select(names...)
+	if err != nil {
+		t.Fatal("BAD TEST - cannot select the columns: ", err)
+	}
+	return rval
+}
+
+func TestAssertDFEqual(t *testing.T) {
+	want := mkDF(t, "i f s\n1 1.5 a\n2 NA b\n3 3.5 c\n")
+	diffCells := mkDF(t, "i f s\n1 1.5 a\n9 2.5 b\n3 3.5 c\n")
+
+	testCases := []struct {
+		testhelper.ID
+		got     *dataframe.DF
+		opts    []dftest.Opt
+		expOK   bool
+		expErrs []string
+	}{
+		{
+			ID:    testhelper.MkID("equal"),
+			got:   mkDF(t, "i f s\n1 1.5 a\n2 NA b\n3 3.5 c\n"),
+			expOK: true,
+		},
+		{
+			ID:    testhelper.MkID("within tolerance"),
+			got:   mkDF(t, "i f s\n1 1.51 a\n2 NA b\n3 3.5 c\n"),
+			opts:  []dftest.Opt{dftest.FloatTolerance(0.1)},
+			expOK: true,
+		},
+		{
+			ID:  testhelper.MkID("cells differ"),
+			got: mkDF(t, "i f s\n1 1.5 a\n9 2.5 b\n3 3.5 C\n"),
+			expErrs: []string{
+				"dataframes differ:\n" +
+					"\trow 1, column \"i\": expected 2, got 9\n" +
+					"\trow 1, column \"f\": expected NA, got 2.5\n" +
+					"\trow 2, column \"s\": expected \"c\", got \"C\"",
+			},
+		},
+		{
+			ID:   testhelper.MkID("diffs limited"),
+			got:  mkDF(t, "i f s\n1 1.5 a\n9 2.5 b\n3 3.5 c\n"),
+			opts: []dftest.Opt{dftest.MaxDiffs(1)},
+			expErrs: []string{
+				"dataframes differ:\n" +
+					"\trow 1, column \"i\": expected 2, got 9\n" +
+					"\t... and 1 more differing cells",
+			},
+		},
+		{
+			ID:  testhelper.MkID("rows differ"),
+			got: mkDF(t, "i f s\n1 1.5 a\n"),
+			expErrs: []string{
+				"dataframes differ:\n" +
+					"\texpected 3 rows, got 1",
+			},
+		},
+		{
+			ID:  testhelper.MkID("columns differ"),
+			got: mkDF(t, "i f t\n1 1.5 a\n"),
+			expErrs: []string{
+				"dataframes have different columns:\n" +
+					"\tcolumn 2: expected s(String), got t(String)",
+			},
+		},
+		{
+			ID:  testhelper.MkID("columns reordered"),
+			got: mustSelect(t, want, "s", "i", "f"),
+			expErrs: []string{
+				"dataframes have different columns:\n" +
+					"\tcolumn 0: expected i(Int), got s(String)\n" +
+					"\tcolumn 1: expected f(Float), got i(Int)\n" +
+					"\tcolumn 2: expected s(String), got f(Float)",
+			},
+		},
+		{
+			ID:    testhelper.MkID("columns reordered, order ignored"),
+			got:   mustSelect(t, want, "s", "i", "f"),
+			opts:  []dftest.Opt{dftest.IgnoreColOrder},
+			expOK: true,
+		},
+		{
+			ID:   testhelper.MkID("cells differ, order ignored"),
+			got:  mustSelect(t, diffCells, "f", "s", "i"),
+			opts: []dftest.Opt{dftest.IgnoreColOrder},
+			expErrs: []string{
+				"dataframes differ:\n" +
+					"\trow 1, column \"i\": expected 2, got 9\n" +
+					"\trow 1, column \"f\": expected NA, got 2.5",
+			},
+		},
+		{
+			ID:   testhelper.MkID("columns differ, order ignored"),
+			got:  mkDF(t, "i f t\n1 1.5 a\n"),
+			opts: []dftest.Opt{dftest.IgnoreColOrder},
+			expErrs: []string{
+				"dataframes have different columns:\n" +
+					"\tcolumn \"s\": expected but not found\n" +
+					"\tcolumn \"t\": not expected",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		rtb := &recordingTB{TB: t}
+		ok := dftest.AssertDFEqual(rtb, want, tc.got, tc.opts...)
+		testhelper.DiffBool(t, tc.IDStr(), "equal", ok, tc.expOK)
+		testhelper.DiffStringSlice(t, tc.IDStr(), "errors",
+			rtb.errs, tc.expErrs)
+	}
+}